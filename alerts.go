@@ -0,0 +1,247 @@
+package twitch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type AlertKind string
+
+const (
+	AlertFollow      AlertKind = "follow"
+	AlertSubscribe   AlertKind = "subscribe"
+	AlertResubscribe AlertKind = "resubscribe"
+	AlertGift        AlertKind = "gift"
+	AlertCheer       AlertKind = "cheer"
+	AlertRaid        AlertKind = "raid"
+)
+
+// Alert is an event an overlay shows, like a follow or a raid.
+type Alert struct {
+	// ID is the message ID of the notification.
+	ID   string
+	Kind AlertKind
+	// User is who the alert is about, the raiding broadcaster for raids. It is zero for
+	// anonymous gifts and cheers.
+	User Identity
+	// Amount is the months of resubscriptions, the subscriptions gifted, the bits
+	// cheered, or the viewers of raids.
+	Amount  int
+	Tier    string
+	Message string
+	// Event is the event of the notification, like EventChannelCheer.
+	Event      any
+	ReceivedAt time.Time
+}
+
+// AlertQueue turns follows, subscriptions, gifts, cheers, and raids into alerts shown
+// one at a time, in the order they came. An alert is shown until the overlay
+// acknowledges it and its minimum duration is over, or until its maximum duration is
+// over when the overlay never does. It is safe to use from concurrent callbacks.
+type AlertQueue struct {
+	mu           sync.Mutex
+	clock        Clock
+	minDurations map[AlertKind]time.Duration
+	maxDuration  time.Duration
+	pending      []Alert
+	current      *Alert
+	shownAt      time.Time
+	acked        bool
+	changed      chan struct{}
+}
+
+func NewAlertQueue() *AlertQueue {
+	return &AlertQueue{
+		clock:        SystemClock{},
+		minDurations: make(map[AlertKind]time.Duration),
+		maxDuration:  30 * time.Second,
+		changed:      make(chan struct{}),
+	}
+}
+
+func (q *AlertQueue) SetClock(clock Clock) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.clock = clock
+}
+
+// SetMinDuration sets how long alerts of a kind are shown at least, even when
+// acknowledged sooner. Defaults to 5 seconds.
+func (q *AlertQueue) SetMinDuration(kind AlertKind, d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.minDurations[kind] = d
+}
+
+// SetMaxDuration sets how long an alert waits to be acknowledged before the next one is
+// shown, so a disconnected overlay doesn't stall the queue. Defaults to 30 seconds.
+func (q *AlertQueue) SetMaxDuration(d time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.maxDuration = d
+}
+
+// Add queues the alert of an event, reporting whether it is one. Subscriptions gifted
+// by someone else are left to the alert of their gift. It is an OnEvent callback, so
+// one queue can take every event:
+//
+//	client.OnEvent(func(event any, payloadContext twitch.PayloadContext) {
+//		queue.Add(event, payloadContext)
+//	})
+func (q *AlertQueue) Add(event any, payloadContext PayloadContext) bool {
+	alert := Alert{
+		ID:         payloadContext.Metadata.MessageID,
+		Event:      event,
+		ReceivedAt: payloadContext.Metadata.MessageTimestamp,
+	}
+	switch e := event.(type) {
+	case EventChannelFollow:
+		alert.Kind = AlertFollow
+		alert.User = e.UserIdentity()
+	case EventChannelSubscribe:
+		if e.IsGift {
+			return false
+		}
+		alert.Kind = AlertSubscribe
+		alert.User = e.UserIdentity()
+		alert.Tier = e.Tier
+	case EventChannelSubscriptionMessage:
+		alert.Kind = AlertResubscribe
+		alert.User = e.UserIdentity()
+		alert.Tier = e.Tier
+		alert.Amount = e.CumulativeMonths
+		alert.Message = e.Message.Text
+	case EventChannelSubscriptionGift:
+		alert.Kind = AlertGift
+		if !e.IsAnonymous {
+			alert.User = e.UserIdentity()
+		}
+		alert.Tier = e.Tier
+		alert.Amount = e.Total
+	case EventChannelCheer:
+		alert.Kind = AlertCheer
+		if !e.IsAnonymous {
+			alert.User = e.UserIdentity()
+		}
+		alert.Amount = e.Bits
+		alert.Message = e.Message
+	case EventChannelRaid:
+		alert.Kind = AlertRaid
+		alert.User = Identity{ID: e.FromBroadcasterUserId, Login: e.FromBroadcasterUserLogin, Name: e.FromBroadcasterUserName}
+		alert.Amount = e.Viewers
+	default:
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, alert)
+	q.notify()
+	return true
+}
+
+// Next waits until the current alert is done, then returns the next one, which becomes
+// current.
+func (q *AlertQueue) Next(ctx context.Context) (Alert, error) {
+	for {
+		q.mu.Lock()
+		now := q.clock.Now()
+		if q.current != nil && q.doneAt(now) {
+			q.current = nil
+		}
+		if q.current == nil && len(q.pending) > 0 {
+			alert := q.pending[0]
+			q.pending = q.pending[1:]
+			q.current = &alert
+			q.shownAt = now
+			q.acked = false
+			q.mu.Unlock()
+			return alert, nil
+		}
+
+		var timer <-chan time.Time
+		if q.current != nil {
+			timer = q.clock.After(q.nextCheck().Sub(now))
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Alert{}, ctx.Err()
+		case <-changed:
+		case <-timer:
+		}
+	}
+}
+
+// doneAt reports whether the current alert is done being shown.
+func (q *AlertQueue) doneAt(now time.Time) bool {
+	if q.acked && !now.Before(q.shownAt.Add(q.minDuration(q.current.Kind))) {
+		return true
+	}
+	return q.maxDuration > 0 && !now.Before(q.shownAt.Add(q.maxDuration))
+}
+
+// nextCheck returns when the current alert may be done.
+func (q *AlertQueue) nextCheck() time.Time {
+	if q.acked {
+		return q.shownAt.Add(q.minDuration(q.current.Kind))
+	}
+	if q.maxDuration > 0 {
+		return q.shownAt.Add(q.maxDuration)
+	}
+	// Only an acknowledgement ends the alert, waking Next up.
+	return q.shownAt.Add(24 * time.Hour)
+}
+
+func (q *AlertQueue) minDuration(kind AlertKind) time.Duration {
+	if d, ok := q.minDurations[kind]; ok {
+		return d
+	}
+	return 5 * time.Second
+}
+
+// Ack acknowledges that the overlay finished showing the alert with the ID, reporting
+// whether it is the current alert.
+func (q *AlertQueue) Ack(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.current == nil || q.current.ID != id || q.acked {
+		return false
+	}
+	q.acked = true
+	q.notify()
+	return true
+}
+
+// Current returns the alert being shown, if any.
+func (q *AlertQueue) Current() (Alert, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.current == nil {
+		return Alert{}, false
+	}
+	return *q.current, true
+}
+
+// Pending returns the alerts waiting to be shown, in order.
+func (q *AlertQueue) Pending() []Alert {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]Alert(nil), q.pending...)
+}
+
+// notify wakes up the calls of Next waiting for a change.
+func (q *AlertQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
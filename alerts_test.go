@@ -0,0 +1,78 @@
+package twitch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func alertContext(id string) twitch.PayloadContext {
+	return twitch.PayloadContext{Metadata: twitch.MessageMetadata{MessageID: id}}
+}
+
+func TestAlertQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	queue := twitch.NewAlertQueue()
+	queue.SetClock(clock)
+	queue.SetMinDuration(twitch.AlertRaid, 10*time.Second)
+
+	assert.True(t, queue.Add(twitch.EventChannelRaid{FromBroadcasterUserId: "2", FromBroadcasterUserLogin: "raider", Viewers: 50}, alertContext("raid")))
+	assert.False(t, queue.Add(twitch.EventChannelSubscribe{IsGift: true}, alertContext("gifted")))
+	assert.True(t, queue.Add(twitch.EventChannelCheer{IsAnonymous: true, Bits: 100}, alertContext("cheer")))
+	assert.False(t, queue.Add(twitch.EventStreamOnline{}, alertContext("online")))
+	assert.Len(t, queue.Pending(), 2)
+
+	alert, err := queue.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, twitch.AlertRaid, alert.Kind)
+	assert.Equal(t, "raider", alert.User.Login)
+	assert.Equal(t, 50, alert.Amount)
+
+	next := make(chan twitch.Alert, 1)
+	go func() {
+		alert, err := queue.Next(ctx)
+		assert.NoError(t, err)
+		next <- alert
+	}()
+
+	// Acknowledged too soon, the raid is shown for its minimum duration.
+	assert.False(t, queue.Ack("cheer"))
+	assert.True(t, queue.Ack("raid"))
+	select {
+	case alert := <-next:
+		t.Fatalf("%s shown before the raid was done", alert.ID)
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(10 * time.Second)
+	select {
+	case alert := <-next:
+		assert.Equal(t, twitch.AlertCheer, alert.Kind)
+		assert.True(t, alert.User.IsZero())
+	case <-ctx.Done():
+		t.Fatal("cheer not shown")
+	}
+
+	// Never acknowledged, the cheer is done after the maximum duration.
+	clock.Advance(30 * time.Second)
+	queue.Add(twitch.EventChannelFollow{}, alertContext("follow"))
+	alert, err = queue.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "follow", alert.ID)
+	current, ok := queue.Current()
+	assert.True(t, ok)
+	assert.Equal(t, "follow", current.ID)
+
+	canceled, cancelNext := context.WithCancel(ctx)
+	cancelNext()
+	_, err = queue.Next(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,329 @@
+// Package archive writes EventSub notifications to newline-delimited JSON files, one
+// notification in the twitch.PublishEnvelope format per line, rotated by size and age
+// and optionally gzipped, ready to ship to object storage and load into data
+// warehouses. Files are written with a .part suffix and renamed once complete.
+//
+//	archiver, err := archive.NewArchiver(archive.Options{Dir: "archive", Gzip: true})
+//	archiver.OnRotate(func(path string) { upload(path) })
+//	client.SetPublishBridge(archiver.Bridge())
+//	defer archiver.Close()
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// PartSuffix is the suffix of the file being written.
+const PartSuffix = ".part"
+
+var ErrClosed = errors.New("archive: archiver closed")
+
+type Options struct {
+	// Dir is where files are written, created if needed.
+	Dir string
+	// Prefix starts the file names, followed by when the file was opened, like
+	// eventsub-20240102T030405.000Z.jsonl. Defaults to eventsub.
+	Prefix string
+	// MaxSize is how many bytes of JSON a file holds before rotating, before
+	// compression. Defaults to 100 MiB.
+	MaxSize int64
+	// MaxAge is how long a file is written to before rotating. Defaults to 1 hour.
+	MaxAge time.Duration
+	// Gzip compresses the files, named with a .jsonl.gz extension.
+	Gzip bool
+}
+
+// Archiver appends notifications to the current file. It implements twitch.Publisher
+// and is safe for concurrent use.
+type Archiver struct {
+	options Options
+
+	mu       sync.Mutex
+	file     *os.File
+	gz       *gzip.Writer
+	w        *bufio.Writer
+	path     string
+	size     int64
+	openedAt time.Time
+	closed   bool
+	// dirty is set when the gzip writer holds data not flushed to the file.
+	dirty    bool
+	onRotate func(path string)
+	onError  func(err error)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewArchiver returns an archiver writing to the directory of the options. Files are
+// opened on the first notification, and rotated once too old even when no
+// notification comes.
+func NewArchiver(options Options) (*Archiver, error) {
+	if options.Prefix == "" {
+		options.Prefix = "eventsub"
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = 100 << 20
+	}
+	if options.MaxAge <= 0 {
+		options.MaxAge = time.Hour
+	}
+	err := os.MkdirAll(options.Dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create archive directory: %w", err)
+	}
+
+	a := &Archiver{
+		options: options,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go a.rotateOld()
+	return a, nil
+}
+
+// OnRotate is called with the path of every completed file, from the goroutine that
+// completed it, without holding the lock of the archiver.
+func (a *Archiver) OnRotate(callback func(path string)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.onRotate = callback
+}
+
+// OnError is called with the errors of the rotations of files which got too old, which
+// no call returns.
+func (a *Archiver) OnError(callback func(err error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.onError = callback
+}
+
+// rotated calls the OnRotate callback with the path of a completed file, if any. The
+// caller does not hold a.mu.
+func (a *Archiver) rotated(path string) {
+	a.mu.Lock()
+	onRotate := a.onRotate
+	a.mu.Unlock()
+	if path != "" && onRotate != nil {
+		onRotate(path)
+	}
+}
+
+// Bridge returns a publish bridge sending every notification to the archiver.
+func (a *Archiver) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{Publisher: a, Format: twitch.PublishEnvelope}
+}
+
+// Publish appends the data as a line, compacted in case it spans several.
+func (a *Archiver) Publish(_ context.Context, _ string, data []byte) error {
+	var line bytes.Buffer
+	err := json.Compact(&line, data)
+	if err != nil {
+		return fmt.Errorf("could not compact notification: %w", err)
+	}
+	line.WriteByte('\n')
+
+	a.mu.Lock()
+	completed, err := a.publishLocked(line.Bytes())
+	a.mu.Unlock()
+
+	a.rotated(completed)
+	return err
+}
+
+// publishLocked writes the line, returning the path of the file it completed to make
+// room for it, if any.
+func (a *Archiver) publishLocked(line []byte) (completed string, err error) {
+	if a.closed {
+		return "", ErrClosed
+	}
+	if a.file != nil && a.size+int64(len(line)) > a.options.MaxSize && a.size > 0 {
+		completed, err = a.rotateLocked()
+		if err != nil {
+			return "", err
+		}
+	}
+	if a.file == nil {
+		if err := a.open(time.Now()); err != nil {
+			return completed, err
+		}
+	}
+
+	n, err := a.w.Write(line)
+	a.size += int64(n)
+	if err == nil {
+		// Hand the line to the file, or to the gzip writer, which is flushed by
+		// rotateOld so it still compresses across lines.
+		err = a.w.Flush()
+	}
+	if err != nil {
+		return completed, fmt.Errorf("could not write %s: %w", a.path, err)
+	}
+	a.dirty = a.gz != nil
+	return completed, nil
+}
+
+// flush writes the buffered data to the file.
+func (a *Archiver) flush() error {
+	err := a.w.Flush()
+	if err == nil && a.gz != nil && a.dirty {
+		err = a.gz.Flush()
+		a.dirty = false
+	}
+	if err != nil {
+		return fmt.Errorf("could not write %s: %w", a.path, err)
+	}
+	return nil
+}
+
+func (a *Archiver) open(now time.Time) error {
+	extension := ".jsonl"
+	if a.options.Gzip {
+		extension += ".gz"
+	}
+	base := filepath.Join(a.options.Dir, a.options.Prefix+"-"+now.UTC().Format("20060102T150405.000Z"))
+
+	// Files opened within the same millisecond are numbered.
+	var file *os.File
+	var path string
+	for i := 0; ; i++ {
+		path = base + extension
+		if i > 0 {
+			path = fmt.Sprintf("%s-%d%s", base, i, extension)
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		var err error
+		file, err = os.OpenFile(path+PartSuffix, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("could not create archive file: %w", err)
+		}
+	}
+
+	var w io.Writer = file
+	a.gz = nil
+	if a.options.Gzip {
+		a.gz = gzip.NewWriter(file)
+		w = a.gz
+	}
+	a.file = file
+	a.w = bufio.NewWriter(w)
+	a.path = path
+	a.size = 0
+	a.openedAt = now
+	return nil
+}
+
+// Rotate completes the current file, if any. The next notification opens a new one.
+func (a *Archiver) Rotate() error {
+	a.mu.Lock()
+	completed, err := a.rotateLocked()
+	a.mu.Unlock()
+
+	a.rotated(completed)
+	return err
+}
+
+// rotateLocked completes the current file, returning its path for the OnRotate
+// callback, which the caller calls once it released a.mu.
+func (a *Archiver) rotateLocked() (string, error) {
+	if a.file == nil {
+		return "", nil
+	}
+
+	err := a.w.Flush()
+	if err == nil && a.gz != nil {
+		err = a.gz.Close()
+	}
+	if err == nil {
+		err = a.file.Sync()
+	}
+	closeErr := a.file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	a.file = nil
+	a.dirty = false
+	if err != nil {
+		return "", fmt.Errorf("could not complete %s: %w", a.path, err)
+	}
+
+	err = os.Rename(a.path+PartSuffix, a.path)
+	if err != nil {
+		return "", fmt.Errorf("could not complete %s: %w", a.path, err)
+	}
+	return a.path, nil
+}
+
+// rotateOld rotates files once too old, and flushes the gzip writer, so a crash loses
+// at most the data compressed since the last tick.
+func (a *Archiver) rotateOld() {
+	defer close(a.done)
+
+	interval := a.options.MaxAge / 10
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var completed string
+			var err error
+			a.mu.Lock()
+			if a.file != nil && time.Since(a.openedAt) >= a.options.MaxAge {
+				completed, err = a.rotateLocked()
+			} else if a.file != nil {
+				err = a.flush()
+			}
+			onError := a.onError
+			a.mu.Unlock()
+
+			a.rotated(completed)
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// Close completes the current file and stops the archiver.
+func (a *Archiver) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.stop)
+	completed, err := a.rotateLocked()
+	a.mu.Unlock()
+
+	<-a.done
+	a.rotated(completed)
+	return err
+}
@@ -0,0 +1,179 @@
+package archive_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/archive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string) []twitch.PublishedNotification {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		r = gz
+	}
+
+	var notifications []twitch.PublishedNotification
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var published twitch.PublishedNotification
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &published))
+		notifications = append(notifications, published)
+	}
+	require.NoError(t, scanner.Err())
+	return notifications
+}
+
+func TestArchiver(t *testing.T) {
+	t.Parallel()
+
+	for _, compressed := range []bool{false, true} {
+		dir := t.TempDir()
+		archiver, err := archive.NewArchiver(archive.Options{Dir: dir, Gzip: compressed})
+		require.NoError(t, err)
+		var rotated []string
+		archiver.OnRotate(func(path string) { rotated = append(rotated, path) })
+
+		client := twitch.NewClient()
+		client.SetPublishBridge(archiver.Bridge())
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+		require.NoError(t, client.FlushPublished(context.Background()))
+
+		parts, err := filepath.Glob(filepath.Join(dir, "*"+archive.PartSuffix))
+		require.NoError(t, err)
+		require.Len(t, parts, 1)
+
+		require.NoError(t, archiver.Rotate())
+		require.Len(t, rotated, 1)
+		assert.True(t, strings.HasPrefix(filepath.Base(rotated[0]), "eventsub-"))
+		if compressed {
+			assert.True(t, strings.HasSuffix(rotated[0], ".jsonl.gz"))
+		} else {
+			assert.True(t, strings.HasSuffix(rotated[0], ".jsonl"))
+		}
+
+		notifications := readLines(t, rotated[0])
+		require.Len(t, notifications, 2)
+		assert.Equal(t, twitch.SubChannelRaid, notifications[0].Subscription.Type)
+		assert.Contains(t, string(notifications[1].Event), `"viewers":7`)
+
+		require.NoError(t, archiver.Close())
+		assert.ErrorIs(t, archiver.Publish(context.Background(), "", []byte(`{}`)), archive.ErrClosed)
+	}
+}
+
+func TestArchiverMaxSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archiver, err := archive.NewArchiver(archive.Options{Dir: dir, MaxSize: 100})
+	require.NoError(t, err)
+
+	line := []byte(`{"metadata":{"message_id":"` + strings.Repeat("a", 60) + `"}}`)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, archiver.Publish(context.Background(), "", line))
+	}
+	require.NoError(t, archiver.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, file := range files {
+		assert.Len(t, readLines(t, file), 1)
+	}
+}
+
+func TestArchiverOnRotateUnlocked(t *testing.T) {
+	t.Parallel()
+
+	archiver, err := archive.NewArchiver(archive.Options{Dir: t.TempDir(), MaxSize: 10})
+	require.NoError(t, err)
+	defer archiver.Close()
+	var rotated []string
+	archiver.OnRotate(func(path string) {
+		// Callbacks can use the archiver, like to record where a file was shipped.
+		rotated = append(rotated, path)
+		assert.NoError(t, archiver.Rotate())
+	})
+
+	require.NoError(t, archiver.Publish(context.Background(), "", []byte(`{"first":true}`)))
+	require.NoError(t, archiver.Publish(context.Background(), "", []byte(`{"second":true}`)))
+	assert.Len(t, rotated, 2)
+}
+
+func TestArchiverReportsRotateErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	archiver, err := archive.NewArchiver(archive.Options{Dir: dir, MaxAge: 20 * time.Millisecond})
+	require.NoError(t, err)
+	defer archiver.Close()
+	errs := make(chan error, 16)
+	archiver.OnError(func(err error) { errs <- err })
+
+	require.NoError(t, archiver.Publish(context.Background(), "", []byte(`{}`)))
+	// The file cannot be renamed once its directory is gone.
+	require.NoError(t, os.RemoveAll(dir))
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "could not complete")
+	case <-time.After(5 * time.Second):
+		t.Fatal("rotation error not reported")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archiver, err := archive.NewArchiver(archive.Options{Dir: dir, Gzip: true})
+	require.NoError(t, err)
+	var paths []string
+	archiver.OnRotate(func(path string) { paths = append(paths, path) })
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(archiver.Bridge())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, archiver.Rotate())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, archiver.Close())
+	require.Len(t, paths, 2)
+	archived := append(readLines(t, paths[0]), readLines(t, paths[1])...)
+
+	replayClient := twitch.NewClient()
+	replayClient.SetSynchronousDispatch(true)
+	replayClient.OnError(func(err error) {
+		t.Errorf("replay registered an error: %v", err)
+	})
+	var viewers []int
+	replayClient.OnEventChannelRaid(func(event twitch.EventChannelRaid, payloadContext twitch.PayloadContext) {
+		published := archived[len(viewers)]
+		assert.Equal(t, published.Metadata.MessageID, payloadContext.Metadata.MessageID)
+		assert.Equal(t, published.Subscription.ID, payloadContext.Subscription.ID)
+		viewers = append(viewers, event.Viewers)
+	})
+
+	require.NoError(t, archive.Replay(context.Background(), replayClient, 0, paths...))
+	assert.Equal(t, []int{42, 7}, viewers)
+}
@@ -0,0 +1,50 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// Replay feeds the notifications of archived files through the client, in the order of
+// the paths, which sort by when the files were opened. Gzipped files are read by their
+// .gz extension. The speed is the same as for twitch.Replay: 1 keeps the original pacing
+// and 0 replays as fast as possible.
+func Replay(ctx context.Context, client *twitch.Client, speed float64, paths ...string) error {
+	for _, path := range paths {
+		err := replayFile(ctx, client, speed, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replayFile(ctx context.Context, client *twitch.Client, speed float64, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open archive file: %w", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(strings.TrimSuffix(path, PartSuffix), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	err = twitch.ReplayPublished(ctx, client, r, speed)
+	if err != nil {
+		return fmt.Errorf("could not replay %s: %w", path, err)
+	}
+	return nil
+}
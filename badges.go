@@ -0,0 +1,74 @@
+package twitch
+
+import "strconv"
+
+// Badge set IDs of the global chat badges.
+const (
+	BadgeBroadcaster   = "broadcaster"
+	BadgeLeadModerator = "lead_moderator"
+	BadgeModerator     = "moderator"
+	BadgeVIP           = "vip"
+	BadgeSubscriber    = "subscriber"
+	BadgeFounder       = "founder"
+	BadgeSubGifter     = "sub-gifter"
+	BadgeBits          = "bits"
+	BadgeArtist        = "artist-badge"
+	BadgePartner       = "partner"
+	BadgeStaff         = "staff"
+	BadgeAdmin         = "admin"
+	BadgeGlobalMod     = "global_mod"
+	BadgeTurbo         = "turbo"
+	BadgePrime         = "premium"
+)
+
+// ChatBadges are the badges of a chatter.
+type ChatBadges []ChatMessageUserBadge
+
+// Get returns the badge of the set, if the chatter has one.
+func (b ChatBadges) Get(setID string) (ChatMessageUserBadge, bool) {
+	for _, badge := range b {
+		if badge.SetId == setID {
+			return badge, true
+		}
+	}
+	return ChatMessageUserBadge{}, false
+}
+
+// Has reports whether the chatter has a badge of the set.
+func (b ChatBadges) Has(setID string) bool {
+	_, ok := b.Get(setID)
+	return ok
+}
+
+func (b ChatBadges) IsBroadcaster() bool {
+	return b.Has(BadgeBroadcaster)
+}
+
+// IsModerator reports whether the chatter is a moderator, including lead moderators.
+// The broadcaster has no moderator badge.
+func (b ChatBadges) IsModerator() bool {
+	return b.Has(BadgeModerator) || b.Has(BadgeLeadModerator)
+}
+
+func (b ChatBadges) IsVIP() bool {
+	return b.Has(BadgeVIP)
+}
+
+// IsSubscriber reports whether the chatter is subscribed, founders included.
+func (b ChatBadges) IsSubscriber() bool {
+	return b.Has(BadgeSubscriber) || b.Has(BadgeFounder)
+}
+
+// SubscriberMonths returns the cumulative months subscribed from the info of the
+// subscriber or founder badge, or 0 if the chatter has neither.
+func (b ChatBadges) SubscriberMonths() int {
+	for _, setID := range []string{BadgeSubscriber, BadgeFounder} {
+		if badge, ok := b.Get(setID); ok {
+			months, err := strconv.Atoi(badge.Info)
+			if err == nil {
+				return months
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package twitch
+
+import "testing"
+
+func TestChatBadges(t *testing.T) {
+	badges := ChatBadges{
+		{SetId: BadgeLeadModerator, Id: "1"},
+		{SetId: BadgeSubscriber, Id: "3012", Info: "16"},
+	}
+
+	if badges.IsBroadcaster() || badges.IsVIP() {
+		t.Error("expected no broadcaster or vip badge")
+	}
+	if !badges.IsModerator() {
+		t.Error("expected lead moderators to be moderators")
+	}
+	if !badges.IsSubscriber() {
+		t.Error("expected a subscriber")
+	}
+	if months := badges.SubscriberMonths(); months != 16 {
+		t.Errorf("expected 16 months got %d", months)
+	}
+
+	founder := ChatBadges{{SetId: BadgeFounder, Id: "0", Info: "3"}}
+	if months := founder.SubscriberMonths(); months != 3 {
+		t.Errorf("expected 3 founder months got %d", months)
+	}
+	if months := (ChatBadges{}).SubscriberMonths(); months != 0 {
+		t.Errorf("expected 0 months without badges got %d", months)
+	}
+}
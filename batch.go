@@ -0,0 +1,113 @@
+package twitch
+
+import (
+	"reflect"
+	"time"
+)
+
+// BatchedEvent is an event delivered by EventsBatched, with the context its handler
+// would have received.
+type BatchedEvent struct {
+	Event          any
+	PayloadContext PayloadContext
+}
+
+// EventsBatched returns a channel receiving the decoded events of every subscription in
+// batches of up to max events, sent once max is reached or window has passed since the
+// first event of the batch, amortizing channel operations and downstream writes. A
+// window of 0 or less only sends full batches. Events are the same values OnEvent
+// receives and arrive in the order they were read.
+//
+// The read loop blocks while a batch cannot be sent, so the channel must be drained.
+// The last batch is sent and the channel is closed when the connection ends; call it
+// again before reconnecting with Connect.
+func (c *Client) EventsBatched(max int, window time.Duration) <-chan []BatchedEvent {
+	if max < 1 {
+		max = 1
+	}
+	batcher := &eventBatcher{
+		client: c,
+		max:    max,
+		window: window,
+		in:     make(chan BatchedEvent, max),
+		out:    make(chan []BatchedEvent),
+	}
+	go batcher.run()
+
+	c.batchersMu.Lock()
+	defer c.batchersMu.Unlock()
+	c.batchers = append(c.batchers, batcher)
+	return batcher.out
+}
+
+type eventBatcher struct {
+	client *Client
+	max    int
+	window time.Duration
+	in     chan BatchedEvent
+	out    chan []BatchedEvent
+}
+
+func (b *eventBatcher) run() {
+	defer close(b.out)
+
+	var batch []BatchedEvent
+	var timeout <-chan time.Time
+	flush := func() {
+		if len(batch) > 0 {
+			b.out <- batch
+			batch = nil
+		}
+		timeout = nil
+	}
+
+	for {
+		select {
+		case event, ok := <-b.in:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, event)
+			if len(batch) == 1 && b.window > 0 {
+				timeout = b.client.after(b.window)
+			}
+			if len(batch) >= b.max {
+				flush()
+			}
+		case <-timeout:
+			flush()
+		}
+	}
+}
+
+func (c *Client) hasBatchers() bool {
+	c.batchersMu.RLock()
+	defer c.batchersMu.RUnlock()
+	return len(c.batchers) > 0
+}
+
+func (c *Client) batchEvent(event any, payloadContext PayloadContext) {
+	c.batchersMu.RLock()
+	defer c.batchersMu.RUnlock()
+	for _, batcher := range c.batchers {
+		batcher.in <- BatchedEvent{Event: event, PayloadContext: payloadContext}
+	}
+}
+
+// closeBatchers sends the pending batches and closes the channels of EventsBatched.
+func (c *Client) closeBatchers() {
+	c.batchersMu.Lock()
+	defer c.batchersMu.Unlock()
+	for _, batcher := range c.batchers {
+		close(batcher.in)
+	}
+	c.batchers = nil
+}
+
+// eventValue returns the event the catch-all callbacks receive, dereferencing the
+// decoded struct.
+func eventValue(event any) any {
+	return reflect.ValueOf(event).Elem().Interface()
+}
@@ -0,0 +1,170 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+	"time"
+)
+
+var dispatchTable = flag.Bool("dispatch-table", false, "log a table comparing dispatch modes in TestDispatchModeTable")
+
+const entitlementBatchSize = 100
+
+type benchPayload struct {
+	name  string
+	frame []byte
+}
+
+func benchPayloads(b testing.TB) []benchPayload {
+	payloads := loadFixtures(b)
+
+	var grants []json.RawMessage
+	if err := json.Unmarshal(payloads[string(SubDropEntitlementGrant)], &grants); err != nil || len(grants) == 0 {
+		b.Fatalf("could not load entitlement fixture: %v", err)
+	}
+	batch := make([]json.RawMessage, entitlementBatchSize)
+	for i := range batch {
+		batch[i] = grants[i%len(grants)]
+	}
+	batchPayload, err := json.Marshal(batch)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return []benchPayload{
+		{"chat_message", notificationFrame(b, SubChannelChatMessage, payloads[string(SubChannelChatMessage)])},
+		{"cheer", notificationFrame(b, SubChannelCheer, payloads[string(SubChannelCheer)])},
+		{"entitlement_batch", notificationFrame(b, SubDropEntitlementGrant, batchPayload)},
+	}
+}
+
+func notificationFrame(b testing.TB, sub EventSubscription, payload json.RawMessage) []byte {
+	var message NotificationMessage
+	message.Metadata = MessageMetadata{
+		MessageID:           "befa7b53-d79d-478f-86b9-120f112b044e",
+		MessageType:         "notification",
+		MessageTimestamp:    time.Now(),
+		SubscriptionType:    sub,
+		SubscriptionVersion: sub.Version(),
+	}
+	message.Payload.Subscription.Type = sub
+	message.Payload.Subscription.Version = sub.Version()
+	message.Payload.Event = &payload
+
+	frame, err := json.Marshal(message)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return frame
+}
+
+type dispatchMode struct {
+	name      string
+	configure func(c *Client)
+}
+
+var dispatchModes = []dispatchMode{
+	{"synchronous", func(c *Client) { c.SetSynchronousDispatch(true) }},
+	{"worker_pool", func(c *Client) { c.SetMaxHandlerGoroutines(256) }},
+	{"goroutine_per_call", func(c *Client) {}},
+	{"high_throughput", func(c *Client) { c.WithHighThroughput() }},
+}
+
+// benchClient returns a client handling the benchmarked events, and a wait group done
+// once per handled event.
+func benchClient(mode dispatchMode) (*Client, *sync.WaitGroup) {
+	var wg sync.WaitGroup
+	client := NewClient()
+	mode.configure(client)
+	client.OnError(func(err error) { panic(err) })
+	client.OnEventChannelChatMessage(func(event EventChannelChatMessage, _ PayloadContext) { wg.Done() })
+	client.OnEventChannelCheer(func(event EventChannelCheer, _ PayloadContext) { wg.Done() })
+	client.OnEventDropEntitlementGrant(func(event EventDropEntitlementGrantBatch, _ PayloadContext) { wg.Done() })
+	return client, &wg
+}
+
+func benchmarkHandleMessage(b *testing.B, mode dispatchMode, frame []byte) {
+	client, wg := benchClient(mode)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(frame)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		if err := client.handleMessage(frame); err != nil {
+			b.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
+
+func BenchmarkHandleMessage(b *testing.B) {
+	for _, payload := range benchPayloads(b) {
+		for _, mode := range dispatchModes {
+			payload, mode := payload, mode
+			b.Run(payload.name+"/"+mode.name, func(b *testing.B) {
+				benchmarkHandleMessage(b, mode, payload.frame)
+			})
+		}
+	}
+}
+
+func BenchmarkHandleNotification(b *testing.B) {
+	for _, payload := range benchPayloads(b) {
+		payload := payload
+		b.Run(payload.name, func(b *testing.B) {
+			client, wg := benchClient(dispatchModes[0])
+			decoded := client.decodeMessage(payload.frame, time.Now())
+			if decoded.err != nil {
+				b.Fatal(decoded.err)
+			}
+			message := decoded.message.(*NotificationMessage)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				// A nil event makes handleNotification decode the raw event itself.
+				wg.Add(1)
+				event := &notificationEvent{raw: *message.Payload.Event}
+				if err := client.handleNotification(*message, event, decoded.receivedAt, "", &client.stats); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestDispatchModeTable logs the throughput of each dispatch mode per payload. It only
+// runs with -dispatch-table, e.g. go test -run TestDispatchModeTable -dispatch-table -v.
+func TestDispatchModeTable(t *testing.T) {
+	if !*dispatchTable {
+		t.Skip("run with -dispatch-table to compare dispatch modes")
+	}
+
+	var out bytes.Buffer
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{"payload"}
+	for _, mode := range dispatchModes {
+		header = append(header, mode.name)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t")+"\t")
+
+	for _, payload := range benchPayloads(t) {
+		row := []string{payload.name}
+		for _, mode := range dispatchModes {
+			result := testing.Benchmark(func(b *testing.B) {
+				benchmarkHandleMessage(b, mode, payload.frame)
+			})
+			row = append(row, fmt.Sprintf("%d ns/op %d allocs/op", result.NsPerOp(), result.AllocsPerOp()))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+	}
+	w.Flush()
+	t.Log("\n" + out.String())
+}
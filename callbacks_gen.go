@@ -0,0 +1,392 @@
+// Code generated by specgen from eventsub.json. DO NOT EDIT.
+
+package twitch
+
+// eventCallbacks holds the typed event callbacks, embedded in Client.
+type eventCallbacks struct {
+	onEventChannelUpdate                                    func(event EventChannelUpdate, payloadContext PayloadContext)
+	onEventChannelFollow                                    func(event EventChannelFollow, payloadContext PayloadContext)
+	onEventChannelSubscribe                                 func(event EventChannelSubscribe, payloadContext PayloadContext)
+	onEventChannelSubscriptionEnd                           func(event EventChannelSubscriptionEnd, payloadContext PayloadContext)
+	onEventChannelSubscriptionGift                          func(event EventChannelSubscriptionGift, payloadContext PayloadContext)
+	onEventChannelSubscriptionMessage                       func(event EventChannelSubscriptionMessage, payloadContext PayloadContext)
+	onEventChannelCheer                                     func(event EventChannelCheer, payloadContext PayloadContext)
+	onEventChannelRaid                                      func(event EventChannelRaid, payloadContext PayloadContext)
+	onEventChannelBan                                       func(event EventChannelBan, payloadContext PayloadContext)
+	onEventChannelUnban                                     func(event EventChannelUnban, payloadContext PayloadContext)
+	onEventChannelModeratorAdd                              func(event EventChannelModeratorAdd, payloadContext PayloadContext)
+	onEventChannelModeratorRemove                           func(event EventChannelModeratorRemove, payloadContext PayloadContext)
+	onEventChannelVIPAdd                                    func(event EventChannelVIPAdd, payloadContext PayloadContext)
+	onEventChannelVIPRemove                                 func(event EventChannelVIPRemove, payloadContext PayloadContext)
+	onEventChannelChannelPointsCustomRewardAdd              func(event EventChannelChannelPointsCustomRewardAdd, payloadContext PayloadContext)
+	onEventChannelChannelPointsCustomRewardUpdate           func(event EventChannelChannelPointsCustomRewardUpdate, payloadContext PayloadContext)
+	onEventChannelChannelPointsCustomRewardRemove           func(event EventChannelChannelPointsCustomRewardRemove, payloadContext PayloadContext)
+	onEventChannelChannelPointsCustomRewardRedemptionAdd    func(event EventChannelChannelPointsCustomRewardRedemptionAdd, payloadContext PayloadContext)
+	onEventChannelChannelPointsCustomRewardRedemptionUpdate func(event EventChannelChannelPointsCustomRewardRedemptionUpdate, payloadContext PayloadContext)
+	onEventChannelChannelPointsAutomaticRewardRedemptionAdd func(event EventChannelChannelPointsAutomaticRewardRedemptionAdd, payloadContext PayloadContext)
+	onEventChannelPollBegin                                 func(event EventChannelPollBegin, payloadContext PayloadContext)
+	onEventChannelPollProgress                              func(event EventChannelPollProgress, payloadContext PayloadContext)
+	onEventChannelPollEnd                                   func(event EventChannelPollEnd, payloadContext PayloadContext)
+	onEventChannelPredictionBegin                           func(event EventChannelPredictionBegin, payloadContext PayloadContext)
+	onEventChannelPredictionProgress                        func(event EventChannelPredictionProgress, payloadContext PayloadContext)
+	onEventChannelPredictionLock                            func(event EventChannelPredictionLock, payloadContext PayloadContext)
+	onEventChannelPredictionEnd                             func(event EventChannelPredictionEnd, payloadContext PayloadContext)
+	onEventDropEntitlementGrant                             func(event EventDropEntitlementGrantBatch, payloadContext PayloadContext)
+	onEventExtensionBitsTransactionCreate                   func(event EventExtensionBitsTransactionCreate, payloadContext PayloadContext)
+	onEventChannelGoalBegin                                 func(event EventChannelGoalBegin, payloadContext PayloadContext)
+	onEventChannelGoalProgress                              func(event EventChannelGoalProgress, payloadContext PayloadContext)
+	onEventChannelGoalEnd                                   func(event EventChannelGoalEnd, payloadContext PayloadContext)
+	onEventChannelHypeTrainBegin                            func(event EventChannelHypeTrainBegin, payloadContext PayloadContext)
+	onEventChannelHypeTrainProgress                         func(event EventChannelHypeTrainProgress, payloadContext PayloadContext)
+	onEventChannelHypeTrainEnd                              func(event EventChannelHypeTrainEnd, payloadContext PayloadContext)
+	onEventStreamOnline                                     func(event EventStreamOnline, payloadContext PayloadContext)
+	onEventStreamOffline                                    func(event EventStreamOffline, payloadContext PayloadContext)
+	onEventUserAuthorizationGrant                           func(event EventUserAuthorizationGrant, payloadContext PayloadContext)
+	onEventUserAuthorizationRevoke                          func(event EventUserAuthorizationRevoke, payloadContext PayloadContext)
+	onEventUserUpdate                                       func(event EventUserUpdate, payloadContext PayloadContext)
+	onEventChannelCharityCampaignDonate                     func(event EventChannelCharityCampaignDonate, payloadContext PayloadContext)
+	onEventChannelCharityCampaignStart                      func(event EventChannelCharityCampaignStart, payloadContext PayloadContext)
+	onEventChannelCharityCampaignProgress                   func(event EventChannelCharityCampaignProgress, payloadContext PayloadContext)
+	onEventChannelCharityCampaignStop                       func(event EventChannelCharityCampaignStop, payloadContext PayloadContext)
+	onEventChannelShieldModeBegin                           func(event EventChannelShieldModeBegin, payloadContext PayloadContext)
+	onEventChannelShieldModeEnd                             func(event EventChannelShieldModeEnd, payloadContext PayloadContext)
+	onEventChannelShoutoutCreate                            func(event EventChannelShoutoutCreate, payloadContext PayloadContext)
+	onEventChannelShoutoutReceive                           func(event EventChannelShoutoutReceive, payloadContext PayloadContext)
+	onEventChannelModerate                                  func(event EventChannelModerate, payloadContext PayloadContext)
+	onEventChannelAdBreakBegin                              func(event EventChannelAdBreakBegin, payloadContext PayloadContext)
+	onEventChannelWarningAcknowledge                        func(event EventChannelWarningAcknowledge, payloadContext PayloadContext)
+	onEventChannelWarningSend                               func(event EventChannelWarningSend, payloadContext PayloadContext)
+	onEventChannelUnbanRequestCreate                        func(event EventChannelUnbanRequestCreate, payloadContext PayloadContext)
+	onEventChannelUnbanRequestResolve                       func(event EventChannelUnbanRequestResolve, payloadContext PayloadContext)
+	onEventAutomodMessageHold                               func(event EventAutomodMessageHold, payloadContext PayloadContext)
+	onEventAutomodMessageUpdate                             func(event EventAutomodMessageUpdate, payloadContext PayloadContext)
+	onEventAutomodSettingsUpdate                            func(event EventAutomodSettingsUpdate, payloadContext PayloadContext)
+	onEventAutomodTermsUpdate                               func(event EventAutomodTermsUpdate, payloadContext PayloadContext)
+	onEventChannelChatUserMessageHold                       func(event EventChannelChatUserMessageHold, payloadContext PayloadContext)
+	onEventChannelChatUserMessageUpdate                     func(event EventChannelChatUserMessageUpdate, payloadContext PayloadContext)
+	onEventChannelChatClear                                 func(event EventChannelChatClear, payloadContext PayloadContext)
+	onEventChannelChatClearUserMessages                     func(event EventChannelChatClearUserMessages, payloadContext PayloadContext)
+	onEventChannelChatMessage                               func(event EventChannelChatMessage, payloadContext PayloadContext)
+	onEventChannelChatMessageDelete                         func(event EventChannelChatMessageDelete, payloadContext PayloadContext)
+	onEventChannelChatNotification                          func(event EventChannelChatNotification, payloadContext PayloadContext)
+	onEventChannelChatSettingsUpdate                        func(event EventChannelChatSettingsUpdate, payloadContext PayloadContext)
+	onEventChannelSuspiciousUserMessage                     func(event EventChannelSuspiciousUserMessage, payloadContext PayloadContext)
+	onEventChannelSuspiciousUserUpdate                      func(event EventChannelSuspiciousUserUpdate, payloadContext PayloadContext)
+	onEventChannelSharedChatBegin                           func(event EventChannelSharedChatBegin, payloadContext PayloadContext)
+	onEventChannelSharedChatUpdate                          func(event EventChannelSharedChatUpdate, payloadContext PayloadContext)
+	onEventChannelSharedChatEnd                             func(event EventChannelSharedChatEnd, payloadContext PayloadContext)
+	onEventChannelGuestStarSessionBegin                     func(event EventChannelGuestStarSessionBegin, payloadContext PayloadContext)
+	onEventChannelGuestStarSessionEnd                       func(event EventChannelGuestStarSessionEnd, payloadContext PayloadContext)
+	onEventChannelGuestStarGuestUpdate                      func(event EventChannelGuestStarGuestUpdate, payloadContext PayloadContext)
+	onEventChannelGuestStarSettingsUpdate                   func(event EventChannelGuestStarSettingsUpdate, payloadContext PayloadContext)
+	onEventUserWhisperMessage                               func(event EventUserWhisperMessage, payloadContext PayloadContext)
+	onEventConduitShardDisabled                             func(event EventConduitShardDisabled, payloadContext PayloadContext)
+}
+
+func (c *Client) OnEventChannelUpdate(callback func(event EventChannelUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelUpdate = callback
+}
+
+func (c *Client) OnEventChannelFollow(callback func(event EventChannelFollow, payloadContext PayloadContext)) {
+	c.onEventChannelFollow = callback
+}
+
+func (c *Client) OnEventChannelSubscribe(callback func(event EventChannelSubscribe, payloadContext PayloadContext)) {
+	c.onEventChannelSubscribe = callback
+}
+
+func (c *Client) OnEventChannelSubscriptionEnd(callback func(event EventChannelSubscriptionEnd, payloadContext PayloadContext)) {
+	c.onEventChannelSubscriptionEnd = callback
+}
+
+func (c *Client) OnEventChannelSubscriptionGift(callback func(event EventChannelSubscriptionGift, payloadContext PayloadContext)) {
+	c.onEventChannelSubscriptionGift = callback
+}
+
+func (c *Client) OnEventChannelSubscriptionMessage(callback func(event EventChannelSubscriptionMessage, payloadContext PayloadContext)) {
+	c.onEventChannelSubscriptionMessage = callback
+}
+
+func (c *Client) OnEventChannelCheer(callback func(event EventChannelCheer, payloadContext PayloadContext)) {
+	c.onEventChannelCheer = callback
+}
+
+func (c *Client) OnEventChannelRaid(callback func(event EventChannelRaid, payloadContext PayloadContext)) {
+	c.onEventChannelRaid = callback
+}
+
+func (c *Client) OnEventChannelBan(callback func(event EventChannelBan, payloadContext PayloadContext)) {
+	c.onEventChannelBan = callback
+}
+
+func (c *Client) OnEventChannelUnban(callback func(event EventChannelUnban, payloadContext PayloadContext)) {
+	c.onEventChannelUnban = callback
+}
+
+func (c *Client) OnEventChannelModeratorAdd(callback func(event EventChannelModeratorAdd, payloadContext PayloadContext)) {
+	c.onEventChannelModeratorAdd = callback
+}
+
+func (c *Client) OnEventChannelModeratorRemove(callback func(event EventChannelModeratorRemove, payloadContext PayloadContext)) {
+	c.onEventChannelModeratorRemove = callback
+}
+
+func (c *Client) OnEventChannelVIPAdd(callback func(event EventChannelVIPAdd, payloadContext PayloadContext)) {
+	c.onEventChannelVIPAdd = callback
+}
+
+func (c *Client) OnEventChannelVIPRemove(callback func(event EventChannelVIPRemove, payloadContext PayloadContext)) {
+	c.onEventChannelVIPRemove = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsCustomRewardAdd(callback func(event EventChannelChannelPointsCustomRewardAdd, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsCustomRewardAdd = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsCustomRewardUpdate(callback func(event EventChannelChannelPointsCustomRewardUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsCustomRewardUpdate = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsCustomRewardRemove(callback func(event EventChannelChannelPointsCustomRewardRemove, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsCustomRewardRemove = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsCustomRewardRedemptionAdd(callback func(event EventChannelChannelPointsCustomRewardRedemptionAdd, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsCustomRewardRedemptionAdd = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsCustomRewardRedemptionUpdate(callback func(event EventChannelChannelPointsCustomRewardRedemptionUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsCustomRewardRedemptionUpdate = callback
+}
+
+func (c *Client) OnEventChannelChannelPointsAutomaticRewardRedemptionAdd(callback func(event EventChannelChannelPointsAutomaticRewardRedemptionAdd, payloadContext PayloadContext)) {
+	c.onEventChannelChannelPointsAutomaticRewardRedemptionAdd = callback
+}
+
+func (c *Client) OnEventChannelPollBegin(callback func(event EventChannelPollBegin, payloadContext PayloadContext)) {
+	c.onEventChannelPollBegin = callback
+}
+
+func (c *Client) OnEventChannelPollProgress(callback func(event EventChannelPollProgress, payloadContext PayloadContext)) {
+	c.onEventChannelPollProgress = callback
+}
+
+func (c *Client) OnEventChannelPollEnd(callback func(event EventChannelPollEnd, payloadContext PayloadContext)) {
+	c.onEventChannelPollEnd = callback
+}
+
+func (c *Client) OnEventChannelPredictionBegin(callback func(event EventChannelPredictionBegin, payloadContext PayloadContext)) {
+	c.onEventChannelPredictionBegin = callback
+}
+
+func (c *Client) OnEventChannelPredictionProgress(callback func(event EventChannelPredictionProgress, payloadContext PayloadContext)) {
+	c.onEventChannelPredictionProgress = callback
+}
+
+func (c *Client) OnEventChannelPredictionLock(callback func(event EventChannelPredictionLock, payloadContext PayloadContext)) {
+	c.onEventChannelPredictionLock = callback
+}
+
+func (c *Client) OnEventChannelPredictionEnd(callback func(event EventChannelPredictionEnd, payloadContext PayloadContext)) {
+	c.onEventChannelPredictionEnd = callback
+}
+
+func (c *Client) OnEventDropEntitlementGrant(callback func(event EventDropEntitlementGrantBatch, payloadContext PayloadContext)) {
+	c.onEventDropEntitlementGrant = callback
+}
+
+func (c *Client) OnEventExtensionBitsTransactionCreate(callback func(event EventExtensionBitsTransactionCreate, payloadContext PayloadContext)) {
+	c.onEventExtensionBitsTransactionCreate = callback
+}
+
+func (c *Client) OnEventChannelGoalBegin(callback func(event EventChannelGoalBegin, payloadContext PayloadContext)) {
+	c.onEventChannelGoalBegin = callback
+}
+
+func (c *Client) OnEventChannelGoalProgress(callback func(event EventChannelGoalProgress, payloadContext PayloadContext)) {
+	c.onEventChannelGoalProgress = callback
+}
+
+func (c *Client) OnEventChannelGoalEnd(callback func(event EventChannelGoalEnd, payloadContext PayloadContext)) {
+	c.onEventChannelGoalEnd = callback
+}
+
+func (c *Client) OnEventChannelHypeTrainBegin(callback func(event EventChannelHypeTrainBegin, payloadContext PayloadContext)) {
+	c.onEventChannelHypeTrainBegin = callback
+}
+
+func (c *Client) OnEventChannelHypeTrainProgress(callback func(event EventChannelHypeTrainProgress, payloadContext PayloadContext)) {
+	c.onEventChannelHypeTrainProgress = callback
+}
+
+func (c *Client) OnEventChannelHypeTrainEnd(callback func(event EventChannelHypeTrainEnd, payloadContext PayloadContext)) {
+	c.onEventChannelHypeTrainEnd = callback
+}
+
+func (c *Client) OnEventStreamOnline(callback func(event EventStreamOnline, payloadContext PayloadContext)) {
+	c.onEventStreamOnline = callback
+}
+
+func (c *Client) OnEventStreamOffline(callback func(event EventStreamOffline, payloadContext PayloadContext)) {
+	c.onEventStreamOffline = callback
+}
+
+func (c *Client) OnEventUserAuthorizationGrant(callback func(event EventUserAuthorizationGrant, payloadContext PayloadContext)) {
+	c.onEventUserAuthorizationGrant = callback
+}
+
+func (c *Client) OnEventUserAuthorizationRevoke(callback func(event EventUserAuthorizationRevoke, payloadContext PayloadContext)) {
+	c.onEventUserAuthorizationRevoke = callback
+}
+
+func (c *Client) OnEventUserUpdate(callback func(event EventUserUpdate, payloadContext PayloadContext)) {
+	c.onEventUserUpdate = callback
+}
+
+func (c *Client) OnEventChannelCharityCampaignDonate(callback func(event EventChannelCharityCampaignDonate, payloadContext PayloadContext)) {
+	c.onEventChannelCharityCampaignDonate = callback
+}
+
+func (c *Client) OnEventChannelCharityCampaignStart(callback func(event EventChannelCharityCampaignStart, payloadContext PayloadContext)) {
+	c.onEventChannelCharityCampaignStart = callback
+}
+
+func (c *Client) OnEventChannelCharityCampaignProgress(callback func(event EventChannelCharityCampaignProgress, payloadContext PayloadContext)) {
+	c.onEventChannelCharityCampaignProgress = callback
+}
+
+func (c *Client) OnEventChannelCharityCampaignStop(callback func(event EventChannelCharityCampaignStop, payloadContext PayloadContext)) {
+	c.onEventChannelCharityCampaignStop = callback
+}
+
+func (c *Client) OnEventChannelShieldModeBegin(callback func(event EventChannelShieldModeBegin, payloadContext PayloadContext)) {
+	c.onEventChannelShieldModeBegin = callback
+}
+
+func (c *Client) OnEventChannelShieldModeEnd(callback func(event EventChannelShieldModeEnd, payloadContext PayloadContext)) {
+	c.onEventChannelShieldModeEnd = callback
+}
+
+func (c *Client) OnEventChannelShoutoutCreate(callback func(event EventChannelShoutoutCreate, payloadContext PayloadContext)) {
+	c.onEventChannelShoutoutCreate = callback
+}
+
+func (c *Client) OnEventChannelShoutoutReceive(callback func(event EventChannelShoutoutReceive, payloadContext PayloadContext)) {
+	c.onEventChannelShoutoutReceive = callback
+}
+
+func (c *Client) OnEventChannelModerate(callback func(event EventChannelModerate, payloadContext PayloadContext)) {
+	c.onEventChannelModerate = callback
+}
+
+func (c *Client) OnEventChannelAdBreakBegin(callback func(event EventChannelAdBreakBegin, payloadContext PayloadContext)) {
+	c.onEventChannelAdBreakBegin = callback
+}
+
+func (c *Client) OnEventChannelWarningAcknowledge(callback func(event EventChannelWarningAcknowledge, payloadContext PayloadContext)) {
+	c.onEventChannelWarningAcknowledge = callback
+}
+
+func (c *Client) OnEventChannelWarningSend(callback func(event EventChannelWarningSend, payloadContext PayloadContext)) {
+	c.onEventChannelWarningSend = callback
+}
+
+func (c *Client) OnEventChannelUnbanRequestCreate(callback func(event EventChannelUnbanRequestCreate, payloadContext PayloadContext)) {
+	c.onEventChannelUnbanRequestCreate = callback
+}
+
+func (c *Client) OnEventChannelUnbanRequestResolve(callback func(event EventChannelUnbanRequestResolve, payloadContext PayloadContext)) {
+	c.onEventChannelUnbanRequestResolve = callback
+}
+
+func (c *Client) OnEventAutomodMessageHold(callback func(event EventAutomodMessageHold, payloadContext PayloadContext)) {
+	c.onEventAutomodMessageHold = callback
+}
+
+func (c *Client) OnEventAutomodMessageUpdate(callback func(event EventAutomodMessageUpdate, payloadContext PayloadContext)) {
+	c.onEventAutomodMessageUpdate = callback
+}
+
+func (c *Client) OnEventAutomodSettingsUpdate(callback func(event EventAutomodSettingsUpdate, payloadContext PayloadContext)) {
+	c.onEventAutomodSettingsUpdate = callback
+}
+
+func (c *Client) OnEventAutomodTermsUpdate(callback func(event EventAutomodTermsUpdate, payloadContext PayloadContext)) {
+	c.onEventAutomodTermsUpdate = callback
+}
+
+func (c *Client) OnEventChannelChatUserMessageHold(callback func(event EventChannelChatUserMessageHold, payloadContext PayloadContext)) {
+	c.onEventChannelChatUserMessageHold = callback
+}
+
+func (c *Client) OnEventChannelChatUserMessageUpdate(callback func(event EventChannelChatUserMessageUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelChatUserMessageUpdate = callback
+}
+
+func (c *Client) OnEventChannelChatClear(callback func(event EventChannelChatClear, payloadContext PayloadContext)) {
+	c.onEventChannelChatClear = callback
+}
+
+func (c *Client) OnEventChannelChatClearUserMessages(callback func(event EventChannelChatClearUserMessages, payloadContext PayloadContext)) {
+	c.onEventChannelChatClearUserMessages = callback
+}
+
+func (c *Client) OnEventChannelChatMessage(callback func(event EventChannelChatMessage, payloadContext PayloadContext)) {
+	c.onEventChannelChatMessage = callback
+}
+
+func (c *Client) OnEventChannelChatMessageDelete(callback func(event EventChannelChatMessageDelete, payloadContext PayloadContext)) {
+	c.onEventChannelChatMessageDelete = callback
+}
+
+func (c *Client) OnEventChannelChatNotification(callback func(event EventChannelChatNotification, payloadContext PayloadContext)) {
+	c.onEventChannelChatNotification = callback
+}
+
+func (c *Client) OnEventChannelChatSettingsUpdate(callback func(event EventChannelChatSettingsUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelChatSettingsUpdate = callback
+}
+
+func (c *Client) OnEventChannelSuspiciousUserMessage(callback func(event EventChannelSuspiciousUserMessage, payloadContext PayloadContext)) {
+	c.onEventChannelSuspiciousUserMessage = callback
+}
+
+func (c *Client) OnEventChannelSuspiciousUserUpdate(callback func(event EventChannelSuspiciousUserUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelSuspiciousUserUpdate = callback
+}
+
+func (c *Client) OnEventChannelSharedChatBegin(callback func(event EventChannelSharedChatBegin, payloadContext PayloadContext)) {
+	c.onEventChannelSharedChatBegin = callback
+}
+
+func (c *Client) OnEventChannelSharedChatUpdate(callback func(event EventChannelSharedChatUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelSharedChatUpdate = callback
+}
+
+func (c *Client) OnEventChannelSharedChatEnd(callback func(event EventChannelSharedChatEnd, payloadContext PayloadContext)) {
+	c.onEventChannelSharedChatEnd = callback
+}
+
+func (c *Client) OnEventChannelGuestStarSessionBegin(callback func(event EventChannelGuestStarSessionBegin, payloadContext PayloadContext)) {
+	c.onEventChannelGuestStarSessionBegin = callback
+}
+
+func (c *Client) OnEventChannelGuestStarSessionEnd(callback func(event EventChannelGuestStarSessionEnd, payloadContext PayloadContext)) {
+	c.onEventChannelGuestStarSessionEnd = callback
+}
+
+func (c *Client) OnEventChannelGuestStarGuestUpdate(callback func(event EventChannelGuestStarGuestUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelGuestStarGuestUpdate = callback
+}
+
+func (c *Client) OnEventChannelGuestStarSettingsUpdate(callback func(event EventChannelGuestStarSettingsUpdate, payloadContext PayloadContext)) {
+	c.onEventChannelGuestStarSettingsUpdate = callback
+}
+
+func (c *Client) OnEventUserWhisperMessage(callback func(event EventUserWhisperMessage, payloadContext PayloadContext)) {
+	c.onEventUserWhisperMessage = callback
+}
+
+func (c *Client) OnEventConduitShardDisabled(callback func(event EventConduitShardDisabled, payloadContext PayloadContext)) {
+	c.onEventConduitShardDisabled = callback
+}
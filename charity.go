@@ -0,0 +1,96 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// CharityCampaign is the state of a charity campaign built from its events.
+type CharityCampaign struct {
+	EventChannelCharityCampaignProgress
+
+	StartedAt time.Time
+	// StoppedAt is zero until the campaign stops.
+	StoppedAt time.Time
+	// Donations counts the donations received since the campaign was first seen.
+	Donations int
+}
+
+func (c CharityCampaign) IsActive() bool {
+	return c.StoppedAt.IsZero()
+}
+
+// CharityCampaigns correlates the channel.charity_campaign events by campaign ID. It is
+// safe to use from concurrent callbacks.
+type CharityCampaigns struct {
+	mu        sync.Mutex
+	campaigns map[string]*CharityCampaign
+}
+
+func NewCharityCampaigns() *CharityCampaigns {
+	return &CharityCampaigns{campaigns: make(map[string]*CharityCampaign)}
+}
+
+func (c *CharityCampaigns) Start(event EventChannelCharityCampaignStart) CharityCampaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	campaign := c.campaign(event.ID)
+	campaign.EventChannelCharityCampaignProgress = event.EventChannelCharityCampaignProgress
+	campaign.StartedAt = event.StartedAt
+	return *campaign
+}
+
+func (c *CharityCampaigns) Progress(event EventChannelCharityCampaignProgress) CharityCampaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	campaign := c.campaign(event.ID)
+	campaign.EventChannelCharityCampaignProgress = event
+	return *campaign
+}
+
+// Donate counts the donation. The amounts are only updated by progress events, which
+// follow every donation.
+func (c *CharityCampaigns) Donate(event EventChannelCharityCampaignDonate) CharityCampaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	campaign := c.campaign(event.CampaignID)
+	campaign.Donations++
+	return *campaign
+}
+
+// Stop ends the campaign and forgets it, returning its final state.
+func (c *CharityCampaigns) Stop(event EventChannelCharityCampaignStop) CharityCampaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	campaign := c.campaign(event.ID)
+	campaign.EventChannelCharityCampaignProgress = event.EventChannelCharityCampaignProgress
+	campaign.StoppedAt = event.StoppedAt
+	delete(c.campaigns, event.ID)
+	return *campaign
+}
+
+// Get returns the campaign with the ID if it has not stopped.
+func (c *CharityCampaigns) Get(id string) (CharityCampaign, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	campaign, ok := c.campaigns[id]
+	if !ok {
+		return CharityCampaign{}, false
+	}
+	return *campaign, true
+}
+
+func (c *CharityCampaigns) campaign(id string) *CharityCampaign {
+	campaign, ok := c.campaigns[id]
+	if !ok {
+		campaign = &CharityCampaign{}
+		campaign.ID = id
+		c.campaigns[id] = campaign
+	}
+	return campaign
+}
@@ -0,0 +1,41 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCharityCampaigns(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := GoalAmount{Value: 150000, DecimalPlaces: 2, Currency: "USD"}
+	campaigns := NewCharityCampaigns()
+
+	campaigns.Start(EventChannelCharityCampaignStart{
+		EventChannelCharityCampaignProgress: EventChannelCharityCampaignProgress{ID: "campaign", TargetAmount: target},
+		StartedAt:                           startedAt,
+	})
+	campaigns.Donate(EventChannelCharityCampaignDonate{CampaignID: "campaign"})
+	campaign := campaigns.Progress(EventChannelCharityCampaignProgress{
+		ID:            "campaign",
+		CurrentAmount: GoalAmount{Value: 75000, DecimalPlaces: 2, Currency: "USD"},
+		TargetAmount:  target,
+	})
+
+	if !campaign.IsActive() || campaign.Donations != 1 || !campaign.StartedAt.Equal(startedAt) {
+		t.Errorf("unexpected campaign %+v", campaign)
+	}
+	if percent := campaign.PercentComplete(); percent != 50 {
+		t.Errorf("expected 50%% complete got %v", percent)
+	}
+
+	campaign = campaigns.Stop(EventChannelCharityCampaignStop{
+		EventChannelCharityCampaignProgress: campaign.EventChannelCharityCampaignProgress,
+		StoppedAt:                           startedAt.Add(time.Hour),
+	})
+	if campaign.IsActive() || campaign.Donations != 1 {
+		t.Errorf("unexpected stopped campaign %+v", campaign)
+	}
+	if _, ok := campaigns.Get("campaign"); ok {
+		t.Error("expected the stopped campaign to be forgotten")
+	}
+}
@@ -0,0 +1,318 @@
+// Package chatbot answers chat commands. A Bot routes channel.chat.message events to
+// the handlers of commands by prefix, with per-user and global cooldowns, and sends
+// messages, replies, and announcements with the Twitch API.
+//
+//	bot := chatbot.New(chatbot.Options{ClientID: clientID, AccessToken: token, BotUserID: botID})
+//	bot.Handle(chatbot.Command{
+//		Name:     "dice",
+//		Cooldown: 30 * time.Second,
+//		Handler: func(c *chatbot.Context) error {
+//			return c.Reply(fmt.Sprintf("you rolled %d", rand.Intn(6)+1))
+//		},
+//	})
+//	client.OnEventChannelChatMessage(bot.HandleMessage)
+package chatbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API messages are sent with.
+const DefaultHelixURL = helix.DefaultURL
+
+type Options struct {
+	ClientID string
+	// AccessToken is a user access token of the bot with the user:write:chat scope, and
+	// moderator:manage:announcements to announce.
+	AccessToken string
+	// BotUserID is the user sending the messages. Its own messages are ignored.
+	BotUserID string
+
+	// Prefix starts commands. Defaults to !.
+	Prefix string
+	// SharedChat handles the commands sent in other channels of a shared chat session,
+	// which are ignored by default so they aren't answered twice.
+	SharedChat bool
+	// Timeout bounds each handler, with the messages it sends. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	// HelixURL defaults to DefaultHelixURL.
+	HelixURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock times the cooldowns. Defaults to the system clock.
+	Clock twitch.Clock
+}
+
+// Command is a command like !dice, handled when a chatter sends its name or one of its
+// aliases after the prefix.
+type Command struct {
+	Name    string
+	Aliases []string
+
+	// Cooldown is how long a chatter waits between uses of the command.
+	Cooldown time.Duration
+	// GlobalCooldown is how long everyone waits after anyone used the command.
+	GlobalCooldown time.Duration
+	// ModeratorsOnly lets only moderators and the broadcaster use the command. They
+	// skip the cooldowns of every command.
+	ModeratorsOnly bool
+
+	Handler func(c *Context) error
+}
+
+// Context is a command being handled.
+type Context struct {
+	context.Context
+	Bot     *Bot
+	Message twitch.EventChannelChatMessage
+	// Command is the name or alias used, without the prefix.
+	Command string
+	// Args are the words following the command, and Text the text following it.
+	Args []string
+	Text string
+}
+
+// Say sends a message to the channel of the command.
+func (c *Context) Say(message string) error {
+	_, err := c.Bot.Send(c, c.Message.BroadcasterUserId, message)
+	return err
+}
+
+// Reply sends a message replying to the command.
+func (c *Context) Reply(message string) error {
+	_, err := c.Bot.Reply(c, c.Message.BroadcasterUserId, c.Message.MessageId, message)
+	return err
+}
+
+// Announce highlights a message in the channel of the command, with a color of
+// blue, green, orange, purple, or primary, the default when empty.
+func (c *Context) Announce(message, color string) error {
+	return c.Bot.Announce(c, c.Message.BroadcasterUserId, message, color)
+}
+
+// DropError is returned when Twitch accepts a message but does not send it to chat,
+// like when AutoMod holds it or it is a duplicate.
+type DropError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *DropError) Error() string {
+	return fmt.Sprintf("chatbot: message dropped: %s: %s", e.Code, e.Message)
+}
+
+// Bot handles the commands of chat messages. It is safe for concurrent use.
+type Bot struct {
+	options Options
+
+	mu       sync.Mutex
+	commands map[string]*Command
+	used     map[string]time.Time
+	onError  func(err error)
+
+	helix helix.Client
+}
+
+func New(options Options) *Bot {
+	if options.Prefix == "" {
+		options.Prefix = "!"
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.HelixURL == "" {
+		options.HelixURL = DefaultHelixURL
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	return &Bot{
+		options:  options,
+		commands: make(map[string]*Command),
+		used:     make(map[string]time.Time),
+		helix: helix.Client{
+			URL:         options.HelixURL,
+			ClientID:    options.ClientID,
+			AccessToken: options.AccessToken,
+			HTTPClient:  options.HTTPClient,
+		},
+	}
+}
+
+// Handle adds a command, replacing the ones with the same names. Names are case
+// insensitive.
+func (b *Bot) Handle(command Command) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, name := range append([]string{command.Name}, command.Aliases...) {
+		b.commands[strings.ToLower(name)] = &command
+	}
+}
+
+// OnError is called with the errors of handlers.
+func (b *Bot) OnError(callback func(err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onError = callback
+}
+
+func (b *Bot) reportError(err error) {
+	b.mu.Lock()
+	onError := b.onError
+	b.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+func (b *Bot) now() time.Time {
+	if b.options.Clock != nil {
+		return b.options.Clock.Now()
+	}
+	return time.Now()
+}
+
+// HandleMessage runs the handler of the command of a message, if it is one the chatter
+// may use and its cooldowns are over. It is an OnEventChannelChatMessage callback.
+func (b *Bot) HandleMessage(message twitch.EventChannelChatMessage, _ twitch.PayloadContext) {
+	if message.ChatterUserId == b.options.BotUserID {
+		return
+	}
+	if message.IsFromSharedChat() && !b.options.SharedChat {
+		return
+	}
+	text := strings.TrimSpace(message.Message.Text)
+	if !strings.HasPrefix(text, b.options.Prefix) {
+		return
+	}
+	text = text[len(b.options.Prefix):]
+	name, rest, _ := strings.Cut(text, " ")
+	name = strings.ToLower(name)
+
+	command, ok := b.take(name, message)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.options.Timeout)
+	defer cancel()
+	err := command.Handler(&Context{
+		Context: ctx,
+		Bot:     b,
+		Message: message,
+		Command: name,
+		Args:    strings.Fields(rest),
+		Text:    strings.TrimSpace(rest),
+	})
+	if err != nil {
+		b.reportError(fmt.Errorf("command %s%s: %w", b.options.Prefix, name, err))
+	}
+}
+
+// take returns the command if the chatter may use it now, starting its cooldowns.
+func (b *Bot) take(name string, message twitch.EventChannelChatMessage) (*Command, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	command, ok := b.commands[name]
+	if !ok || command.Handler == nil {
+		return nil, false
+	}
+	privileged := message.Badges.IsBroadcaster() || message.Badges.IsModerator()
+	if command.ModeratorsOnly && !privileged {
+		return nil, false
+	}
+	if privileged {
+		return command, true
+	}
+
+	now := b.now()
+	channel := message.BroadcasterUserId + "/" + strings.ToLower(command.Name)
+	user := channel + "/" + message.ChatterUserId
+	if now.Before(b.used[channel].Add(command.GlobalCooldown)) || now.Before(b.used[user].Add(command.Cooldown)) {
+		return nil, false
+	}
+	if command.GlobalCooldown > 0 {
+		b.used[channel] = now
+	}
+	if command.Cooldown > 0 {
+		b.used[user] = now
+	}
+
+	// Forget the cooldowns that are over once many chatters used commands.
+	if len(b.used) > 10000 {
+		for key, at := range b.used {
+			if now.Sub(at) > time.Hour {
+				delete(b.used, key)
+			}
+		}
+	}
+	return command, true
+}
+
+// Send sends a message to the chat of a broadcaster, returning its ID.
+func (b *Bot) Send(ctx context.Context, broadcasterID, message string) (string, error) {
+	return b.Reply(ctx, broadcasterID, "", message)
+}
+
+// Reply sends a message replying to another one, returning its ID.
+func (b *Bot) Reply(ctx context.Context, broadcasterID, parentID, message string) (string, error) {
+	request := struct {
+		BroadcasterID string `json:"broadcaster_id"`
+		SenderID      string `json:"sender_id"`
+		Message       string `json:"message"`
+		ParentID      string `json:"reply_parent_message_id,omitempty"`
+	}{broadcasterID, b.options.BotUserID, message, parentID}
+
+	var response struct {
+		Data []struct {
+			MessageID  string     `json:"message_id"`
+			IsSent     bool       `json:"is_sent"`
+			DropReason *DropError `json:"drop_reason"`
+		} `json:"data"`
+	}
+	err := b.helix.Do(ctx, http.MethodPost, "/chat/messages", request, &response)
+	if err != nil {
+		return "", fmt.Errorf("could not send chat message: %w", err)
+	}
+	if len(response.Data) == 0 {
+		return "", fmt.Errorf("could not send chat message: empty response")
+	}
+	sent := response.Data[0]
+	if !sent.IsSent {
+		if sent.DropReason == nil {
+			return "", &DropError{Code: "unknown"}
+		}
+		return "", sent.DropReason
+	}
+	return sent.MessageID, nil
+}
+
+// Announce highlights a message in the chat of a broadcaster the bot moderates, with a
+// color of blue, green, orange, purple, or primary, the default when empty.
+func (b *Bot) Announce(ctx context.Context, broadcasterID, message, color string) error {
+	request := struct {
+		Message string `json:"message"`
+		Color   string `json:"color,omitempty"`
+	}{message, color}
+
+	query := url.Values{"broadcaster_id": {broadcasterID}, "moderator_id": {b.options.BotUserID}}
+	err := b.helix.Do(ctx, http.MethodPost, "/chat/announcements?"+query.Encode(), request, nil)
+	if err != nil {
+		return fmt.Errorf("could not send announcement: %w", err)
+	}
+	return nil
+}
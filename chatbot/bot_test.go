@@ -0,0 +1,139 @@
+package chatbot_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/chatbot"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHelix returns a mock of the API sending chat messages, or dropping them while drop
+// is set.
+func newHelix(t *testing.T, drop *atomic.Bool) *twitchtest.Helix {
+	helix := twitchtest.NewHelix(t)
+	helix.Handle(http.MethodPost, "/chat/messages", func(w http.ResponseWriter, r *http.Request) {
+		if drop != nil && drop.Load() {
+			fmt.Fprint(w, `{"data":[{"message_id":"","is_sent":false,"drop_reason":{"code":"msg_duplicate","message":"duplicate"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"message_id":"sent","is_sent":true}]}`)
+	})
+	return helix
+}
+
+func message(chatter, text string, badges ...string) twitch.EventChannelChatMessage {
+	event := twitchtest.NewChannelChatMessage(
+		twitchtest.WithBroadcaster[twitch.EventChannelChatMessage]("1", "streamer", "Streamer"),
+		twitchtest.WithChatter[twitch.EventChannelChatMessage](chatter, "user"+chatter, "User"+chatter),
+		twitchtest.WithChatText(text),
+	)
+	event.MessageId = "message-" + chatter
+	event.Badges = nil
+	for _, badge := range badges {
+		event.Badges = append(event.Badges, twitch.ChatMessageUserBadge{SetId: badge, Id: "1"})
+	}
+	return event
+}
+
+func TestBotCommands(t *testing.T) {
+	t.Parallel()
+
+	helix := newHelix(t, nil)
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	bot := chatbot.New(chatbot.Options{
+		ClientID:    "client",
+		AccessToken: "token",
+		BotUserID:   "bot",
+		HelixURL:    helix.URL,
+		Clock:       clock,
+	})
+	bot.OnError(func(err error) {
+		t.Error(err)
+	})
+	var args [][]string
+	bot.Handle(chatbot.Command{
+		Name:     "echo",
+		Aliases:  []string{"say"},
+		Cooldown: time.Minute,
+		Handler: func(c *chatbot.Context) error {
+			args = append(args, c.Args)
+			return c.Reply(c.Text)
+		},
+	})
+	bot.Handle(chatbot.Command{
+		Name:           "hype",
+		ModeratorsOnly: true,
+		Handler: func(c *chatbot.Context) error {
+			return c.Announce("HYPE", "purple")
+		},
+	})
+
+	bot.HandleMessage(message("2", "!ECHO hello  there"), twitch.PayloadContext{})
+	assert.Equal(t, [][]string{{"hello", "there"}}, args)
+	assert.Equal(t, []string{
+		`POST /chat/messages {"broadcaster_id":"1","sender_id":"bot","message":"hello  there","reply_parent_message_id":"message-2"}`,
+	}, helix.Requests())
+
+	// The chatter waits for the cooldown, others don't, and moderators skip it.
+	bot.HandleMessage(message("2", "!say again"), twitch.PayloadContext{})
+	bot.HandleMessage(message("3", "!say hi"), twitch.PayloadContext{})
+	bot.HandleMessage(message("3", "!say hi", twitch.BadgeModerator), twitch.PayloadContext{})
+	assert.Len(t, args, 3)
+	clock.Advance(time.Minute)
+	bot.HandleMessage(message("2", "!say again"), twitch.PayloadContext{})
+	assert.Len(t, args, 4)
+
+	// Messages of the bot, other text, and unknown commands are ignored.
+	bot.HandleMessage(message("bot", "!echo loop"), twitch.PayloadContext{})
+	bot.HandleMessage(message("4", "echo"), twitch.PayloadContext{})
+	bot.HandleMessage(message("4", "!unknown"), twitch.PayloadContext{})
+	assert.Len(t, args, 4)
+	helix.Requests()
+
+	bot.HandleMessage(message("4", "!hype"), twitch.PayloadContext{})
+	assert.Empty(t, helix.Requests())
+	bot.HandleMessage(message("1", "!hype", twitch.BadgeBroadcaster), twitch.PayloadContext{})
+	assert.Equal(t, []string{
+		`POST /chat/announcements?broadcaster_id=1&moderator_id=bot {"message":"HYPE","color":"purple"}`,
+	}, helix.Requests())
+}
+
+func TestBotSend(t *testing.T) {
+	t.Parallel()
+
+	var drop atomic.Bool
+	helix := newHelix(t, &drop)
+	bot := chatbot.New(chatbot.Options{ClientID: "client", AccessToken: "token", BotUserID: "bot", HelixURL: helix.URL})
+
+	ctx := context.Background()
+	id, err := bot.Send(ctx, "1", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "sent", id)
+	request, err := helix.WaitForRequest(ctx, http.MethodPost, "/chat/messages")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", request.Authorization)
+	assert.Equal(t, "client", request.ClientID)
+
+	drop.Store(true)
+	_, err = bot.Send(ctx, "1", "hi")
+	var dropped *chatbot.DropError
+	require.ErrorAs(t, err, &dropped)
+	assert.Equal(t, "msg_duplicate", dropped.Code)
+
+	var errs []string
+	bot.OnError(func(err error) { errs = append(errs, err.Error()) })
+	bot.Handle(chatbot.Command{Name: "ping", Handler: func(c *chatbot.Context) error { return c.Say("pong") }})
+	bot.HandleMessage(message("2", "!ping"), twitch.PayloadContext{})
+	if assert.Len(t, errs, 1) {
+		assert.True(t, strings.HasPrefix(errs[0], "command !ping: chatbot: message dropped"))
+	}
+}
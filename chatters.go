@@ -0,0 +1,216 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChatterStats is what is known of a chatter of a channel.
+type ChatterStats struct {
+	Identity
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Messages counts the chat messages of the chatter since it was first seen.
+	Messages int
+}
+
+type chatChannel struct {
+	chatters map[string]*ChatterStats
+	// messages are the times of the messages within the window, oldest first.
+	messages []time.Time
+}
+
+// Chatters follows who chats in channels from channel.chat.message and
+// channel.chat.notification events, for greeting first time chatters and counting the
+// active ones. Activity is measured over a sliding window. It is safe to use from
+// concurrent callbacks.
+type Chatters struct {
+	mu          sync.Mutex
+	window      time.Duration
+	clock       Clock
+	channels    map[string]*chatChannel
+	onFirstChat func(broadcasterID string, chatter Identity, event any)
+}
+
+// NewChatters measures activity over the window, 10 minutes when not positive.
+func NewChatters(window time.Duration) *Chatters {
+	if window <= 0 {
+		window = 10 * time.Minute
+	}
+	return &Chatters{window: window, clock: SystemClock{}, channels: make(map[string]*chatChannel)}
+}
+
+// SetClock sets the clock giving the time of events without a message timestamp.
+func (c *Chatters) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock
+}
+
+// OnFirstChat is called when a chatter is seen in a channel for the first time, with the
+// event it was seen in.
+func (c *Chatters) OnFirstChat(callback func(broadcasterID string, chatter Identity, event any)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onFirstChat = callback
+}
+
+// Seed marks users as seen in the channel before, like the chatters of past streams
+// loaded from storage, so they are not greeted as first time chatters.
+func (c *Chatters) Seed(broadcasterID string, userIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channel := c.channel(broadcasterID)
+	for _, id := range userIDs {
+		if _, ok := channel.chatters[id]; !ok {
+			channel.chatters[id] = &ChatterStats{Identity: Identity{ID: id}}
+		}
+	}
+}
+
+// Observe records the chatter of a chat message or notification at the timestamp of the
+// notification, reporting whether it is the first time the chatter is seen in the
+// channel. Other events, anonymous notifications, and messages sent in other channels of
+// a shared chat session are ignored. It is an OnEvent callback.
+func (c *Chatters) Observe(event any, payloadContext PayloadContext) bool {
+	var broadcasterID string
+	var chatter Identity
+	var message bool
+	switch e := event.(type) {
+	case EventChannelChatMessage:
+		if e.IsFromSharedChat() {
+			return false
+		}
+		broadcasterID, chatter, message = e.BroadcasterUserId, e.ChatterIdentity(), true
+	case EventChannelChatNotification:
+		if e.ChatterIsAnonymous || isFromSharedChat(e.Broadcaster, e.SourceBroadcaster) {
+			return false
+		}
+		broadcasterID, chatter = e.BroadcasterUserId, e.ChatterIdentity()
+	default:
+		return false
+	}
+	c.mu.Lock()
+	at := payloadContext.Metadata.MessageTimestamp
+	if at.IsZero() {
+		at = c.clock.Now()
+	}
+	channel := c.channel(broadcasterID)
+	stats, seen := channel.chatters[chatter.ID]
+	if !seen {
+		stats = &ChatterStats{Identity: chatter, FirstSeen: at}
+		channel.chatters[chatter.ID] = stats
+	}
+	if stats.FirstSeen.IsZero() {
+		stats.FirstSeen = at
+	}
+	// Seeded chatters are only known by ID.
+	stats.Identity = chatter
+	if at.After(stats.LastSeen) {
+		stats.LastSeen = at
+	}
+	if message {
+		stats.Messages++
+		channel.add(at)
+		channel.prune(at.Add(-c.window))
+	}
+	onFirstChat := c.onFirstChat
+	c.mu.Unlock()
+
+	if !seen && onFirstChat != nil {
+		onFirstChat(broadcasterID, chatter, event)
+	}
+	return !seen
+}
+
+func (c *Chatters) channel(broadcasterID string) *chatChannel {
+	channel, ok := c.channels[broadcasterID]
+	if !ok {
+		channel = &chatChannel{chatters: make(map[string]*ChatterStats)}
+		c.channels[broadcasterID] = channel
+	}
+	return channel
+}
+
+// add inserts the time of a message in order, since notifications may come out of
+// order.
+func (c *chatChannel) add(at time.Time) {
+	i := sort.Search(len(c.messages), func(i int) bool {
+		return c.messages[i].After(at)
+	})
+	c.messages = append(c.messages, time.Time{})
+	copy(c.messages[i+1:], c.messages[i:])
+	c.messages[i] = at
+}
+
+// prune forgets the messages before the start of the window.
+func (c *chatChannel) prune(start time.Time) {
+	i := sort.Search(len(c.messages), func(i int) bool {
+		return !c.messages[i].Before(start)
+	})
+	c.messages = append(c.messages[:0], c.messages[i:]...)
+}
+
+// Chatter returns what is known of the chatter in the channel.
+func (c *Chatters) Chatter(broadcasterID, userID string) (ChatterStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channel, ok := c.channels[broadcasterID]
+	if !ok {
+		return ChatterStats{}, false
+	}
+	stats, ok := channel.chatters[userID]
+	if !ok {
+		return ChatterStats{}, false
+	}
+	return *stats, true
+}
+
+// Active returns the chatters seen in the channel within the window before now, most
+// recently seen first.
+func (c *Chatters) Active(broadcasterID string, now time.Time) []ChatterStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channel, ok := c.channels[broadcasterID]
+	if !ok {
+		return nil
+	}
+	start := now.Add(-c.window)
+	var active []ChatterStats
+	for _, stats := range channel.chatters {
+		if !stats.LastSeen.Before(start) && !stats.LastSeen.After(now) {
+			active = append(active, *stats)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].LastSeen.After(active[j].LastSeen)
+	})
+	return active
+}
+
+// MessageRate returns the messages per minute sent in the channel within the window
+// before now.
+func (c *Chatters) MessageRate(broadcasterID string, now time.Time) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channel, ok := c.channels[broadcasterID]
+	if !ok {
+		return 0
+	}
+	start := now.Add(-c.window)
+	var count int
+	for _, at := range channel.messages {
+		if !at.Before(start) && !at.After(now) {
+			count++
+		}
+	}
+	return float64(count) / c.window.Minutes()
+}
@@ -0,0 +1,90 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChatters(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) PayloadContext {
+		return PayloadContext{Metadata: MessageMetadata{MessageTimestamp: start.Add(d)}}
+	}
+	message := func(chatter string) EventChannelChatMessage {
+		return EventChannelChatMessage{
+			Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+			Chatter:     Chatter{ChatterUserId: chatter, ChatterUserLogin: "user" + chatter},
+		}
+	}
+
+	chatters := NewChatters(time.Minute)
+	chatters.Seed("1", "regular")
+	var greeted []string
+	chatters.OnFirstChat(func(broadcasterID string, chatter Identity, _ any) {
+		greeted = append(greeted, broadcasterID+"/"+chatter.Login)
+	})
+
+	if !chatters.Observe(message("2"), at(0)) {
+		t.Error("expected chatter 2 to be new")
+	}
+	if chatters.Observe(message("2"), at(30*time.Second)) {
+		t.Error("expected chatter 2 to be known")
+	}
+	if chatters.Observe(message("regular"), at(20*time.Second)) {
+		t.Error("expected the seeded chatter to be known")
+	}
+	shared := message("3")
+	shared.SourceBroadcasterUserId = "other"
+	chatters.Observe(shared, at(time.Second))
+	chatters.Observe(EventChannelChatNotification{
+		Broadcaster:        Broadcaster{BroadcasterUserId: "1"},
+		Chatter:            Chatter{ChatterUserId: "anonymous"},
+		ChatterIsAnonymous: true,
+	}, at(time.Second))
+	chatters.Observe(EventChannelChatNotification{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		Chatter:     Chatter{ChatterUserId: "4", ChatterUserLogin: "user4"},
+	}, at(10*time.Second))
+
+	if len(greeted) != 2 || greeted[0] != "1/user2" || greeted[1] != "1/user4" {
+		t.Errorf("unexpected first chats %v", greeted)
+	}
+	if stats, ok := chatters.Chatter("1", "2"); !ok || stats.Messages != 2 || !stats.FirstSeen.Equal(start) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats, _ := chatters.Chatter("1", "regular"); stats.Login != "userregular" || !stats.FirstSeen.Equal(start.Add(20*time.Second)) {
+		t.Errorf("unexpected seeded stats %+v", stats)
+	}
+
+	active := chatters.Active("1", start.Add(71*time.Second))
+	if len(active) != 2 || active[0].ID != "2" || active[1].ID != "regular" {
+		t.Errorf("unexpected active chatters %+v", active)
+	}
+	if rate := chatters.MessageRate("1", start.Add(30*time.Second)); rate != 3 {
+		t.Errorf("expected 3 messages per minute got %v", rate)
+	}
+	if rate := chatters.MessageRate("1", start.Add(85*time.Second)); rate != 1 {
+		t.Errorf("expected 1 message per minute got %v", rate)
+	}
+}
+
+// fixedClock is a Clock stopped at a time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                         { return time.Time(c) }
+func (c fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func TestChattersClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	chatters := NewChatters(time.Minute)
+	chatters.SetClock(fixedClock(now))
+
+	// Injected events have no message timestamp.
+	chatters.Observe(EventChannelChatMessage{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		Chatter:     Chatter{ChatterUserId: "2", ChatterUserLogin: "user2"},
+	}, PayloadContext{})
+	if stats, ok := chatters.Chatter("1", "2"); !ok || !stats.FirstSeen.Equal(now) || !stats.LastSeen.Equal(now) {
+		t.Errorf("expected the chatter to be seen at the time of the clock got %+v", stats)
+	}
+}
@@ -0,0 +1,96 @@
+package twitch
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultCheermotePrefixes are the prefixes of the global cheermotes, used to find
+// cheermotes in plain text when no prefixes are given. Channels can add their own,
+// which can be listed with the Get Cheermotes API.
+var DefaultCheermotePrefixes = []string{
+	"Cheer", "DoodleCheer", "BibleThump", "cheerwhal", "Corgo", "Scoops", "uni",
+	"ShowLove", "Party", "SeemsGood", "Pride", "Kappa", "FrankerZ", "HeyGuys",
+	"DansGame", "EleGiggle", "TriHard", "Kreygasm", "4Head", "SwiftRage",
+	"NotLikeThis", "FailFish", "VoHiYo", "PJSalt", "MrDestructoid", "bday",
+	"RIPCheer", "Shamrock",
+}
+
+var cheermoteTiers = []int{10000, 5000, 1000, 100, 1}
+
+// CheermoteTier returns the tier of a cheermote for the bits cheered, which is the
+// least bits of the tier: 1, 100, 1000, 5000, or 10000.
+func CheermoteTier(bits int) int {
+	for _, tier := range cheermoteTiers {
+		if bits >= tier {
+			return tier
+		}
+	}
+	return 0
+}
+
+// ParseCheermotes finds the cheermotes in text, words made of a prefix followed by the
+// bits cheered like Cheer100. Prefixes match case-insensitively and default to
+// DefaultCheermotePrefixes. The returned prefixes are spelled as in the text.
+func ParseCheermotes(text string, prefixes []string) []ChatMessageFragmentCheermote {
+	if prefixes == nil {
+		prefixes = DefaultCheermotePrefixes
+	}
+
+	var cheermotes []ChatMessageFragmentCheermote
+	for _, word := range strings.Fields(text) {
+		digits := len(word)
+		for digits > 0 && word[digits-1] >= '0' && word[digits-1] <= '9' {
+			digits--
+		}
+		if digits == 0 || digits == len(word) {
+			continue
+		}
+
+		prefix := word[:digits]
+		if !hasCheermotePrefix(prefix, prefixes) {
+			continue
+		}
+		bits, err := strconv.Atoi(word[digits:])
+		if err != nil || bits <= 0 {
+			continue
+		}
+		cheermotes = append(cheermotes, ChatMessageFragmentCheermote{Prefix: prefix, Bits: bits, Tier: CheermoteTier(bits)})
+	}
+	return cheermotes
+}
+
+func hasCheermotePrefix(prefix string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.EqualFold(prefix, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalBits sums the bits of the cheermotes.
+func TotalBits(cheermotes []ChatMessageFragmentCheermote) int {
+	var total int
+	for _, cheermote := range cheermotes {
+		total += cheermote.Bits
+	}
+	return total
+}
+
+// Cheermotes returns the cheermotes of the cheermote fragments.
+func (m ChatMessage) Cheermotes() []ChatMessageFragmentCheermote {
+	var cheermotes []ChatMessageFragmentCheermote
+	for _, fragment := range m.Fragments {
+		if fragment.Cheermote != nil {
+			cheermotes = append(cheermotes, *fragment.Cheermote)
+		}
+	}
+	return cheermotes
+}
+
+// Cheermotes parses the cheermotes in the message with DefaultCheermotePrefixes. Use
+// ParseCheermotes to include the custom cheermotes of the channel.
+func (e EventChannelCheer) Cheermotes() []ChatMessageFragmentCheermote {
+	return ParseCheermotes(e.Message, nil)
+}
@@ -0,0 +1,38 @@
+package twitch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCheermotes(t *testing.T) {
+	cheer := EventChannelCheer{Message: "cheer100 great stream! Kappa5000 Cheer kappa12a 100 Custom50"}
+
+	expected := []ChatMessageFragmentCheermote{
+		{Prefix: "cheer", Bits: 100, Tier: 100},
+		{Prefix: "Kappa", Bits: 5000, Tier: 5000},
+	}
+	if actual := cheer.Cheermotes(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v got %v", expected, actual)
+	}
+	if total := TotalBits(cheer.Cheermotes()); total != 5100 {
+		t.Errorf("expected 5100 bits got %d", total)
+	}
+
+	custom := ParseCheermotes(cheer.Message, []string{"Custom"})
+	if len(custom) != 1 || custom[0].Bits != 50 || custom[0].Tier != 1 {
+		t.Errorf("expected the custom cheermote got %v", custom)
+	}
+}
+
+func TestChatMessageCheermotes(t *testing.T) {
+	message := ChatMessage{Fragments: []ChatMessageFragment{
+		{Type: "text", Text: "hi "},
+		{Type: "cheermote", Text: "pogchamp1000", Cheermote: &ChatMessageFragmentCheermote{Prefix: "pogchamp", Bits: 1000, Tier: 1000}},
+		{Type: "cheermote", Text: "cheer1", Cheermote: &ChatMessageFragmentCheermote{Prefix: "cheer", Bits: 1, Tier: 1}},
+	}}
+
+	if total := TotalBits(message.Cheermotes()); total != 1001 {
+		t.Errorf("expected 1001 bits got %d", total)
+	}
+}
@@ -0,0 +1,28 @@
+package twitch
+
+import "time"
+
+// Clock is the source of time for liveness checks, latency, lifecycle timestamps,
+// traffic windows, and replay pacing. It can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the system, which clients use unless SetClock is called.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time                         { return time.Now() }
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (c *Client) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+func (c *Client) now() time.Time {
+	return c.clock.Now()
+}
+
+func (c *Client) after(d time.Duration) <-chan time.Time {
+	return c.clock.After(d)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/config"
+)
+
+// daemon keeps a client set up from a configuration file connected, and serves the
+// admin API controlling it.
+type daemon struct {
+	client *config.Client
+	token  string
+	// reconnecting is set when the admin API closed the connection, to connect again
+	// without backoff.
+	reconnecting atomic.Bool
+}
+
+// runDaemon runs the client of a configuration file as a sidecar, publishing to the
+// sinks of the configuration until interrupted.
+func runDaemon(args []string) {
+	flags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	path := flags.String("config", "eventsub.yaml", "configuration file of the client, see the config package")
+	admin := flags.String("admin", "127.0.0.1:8081", "address of the admin HTTP API; empty disables it")
+	token := flags.String("admin-token", os.Getenv("EVENTSUB_ADMIN_TOKEN"), "bearer token the admin API requires; defaults to $EVENTSUB_ADMIN_TOKEN")
+	watch := flags.Duration("watch", 10*time.Second, "how often the configuration file is checked for changes; 0 reloads it on SIGHUP only")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s daemon [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	log.SetFlags(log.LstdFlags)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := cfg.NewClient(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client.OnError(func(err error) {
+		log.Print(err)
+	})
+	go client.Watch(ctx, *path, *watch)
+
+	d := &daemon{client: client, token: *token}
+	var server *http.Server
+	if *admin != "" {
+		server = &http.Server{Addr: *admin, Handler: d.handler(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			err := server.ListenAndServe()
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("admin API: %v", err)
+			}
+		}()
+		log.Printf("admin API listening on %s", *admin)
+	}
+
+	d.run(ctx)
+
+	if server != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		server.Shutdown(shutdownCtx)
+		cancel()
+	}
+	if err := client.Close(); err != nil {
+		log.Print(err)
+	}
+}
+
+// run connects the client again with backoff when its connection fails, until ctx is
+// done.
+func (d *daemon) run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		connectedAt := time.Now()
+		err := d.client.ConnectWithContext(ctx)
+		d.client.Client.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		if d.reconnecting.Swap(false) {
+			log.Print("reconnecting")
+			continue
+		}
+		if err == nil {
+			err = errors.New("connection closed")
+		}
+		if time.Since(connectedAt) > time.Minute {
+			backoff = time.Second
+		}
+		log.Printf("disconnected: %v; connecting again in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// handler serves the admin API:
+//
+//	GET  /session             the current session
+//	GET  /subscriptions       the subscriptions of the current session
+//	GET  /stats               the stats of the client
+//	GET  /muted               the muted subscription types
+//	POST /mute?type=...       mutes subscription types, see twitch.Client.Mute
+//	POST /unmute?type=...     unmutes subscription types
+//	POST /reconnect           closes the connection and connects again
+//	GET  /healthz, /readyz    the liveness and readiness probes of the client
+func (d *daemon) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/session", d.get(func() any {
+		return d.client.Session()
+	}))
+	mux.Handle("/subscriptions", d.get(func() any {
+		session := d.client.Session().ID
+		subscriptions := []twitch.PayloadSubscription{}
+		for _, subscription := range d.client.Subscriptions() {
+			// Subscriptions of previous sessions are kept until revoked.
+			if subscription.Transport.SessionID == "" || subscription.Transport.SessionID == session {
+				subscriptions = append(subscriptions, subscription)
+			}
+		}
+		return subscriptions
+	}))
+	mux.Handle("/stats", d.get(func() any {
+		return d.client.Stats()
+	}))
+	mux.Handle("/muted", d.get(func() any {
+		return d.client.Muted()
+	}))
+	mux.Handle("/mute", d.post(func(r *http.Request) (any, error) {
+		types, err := subscriptionTypes(r)
+		if err != nil {
+			return nil, err
+		}
+		d.client.Mute(types...)
+		log.Printf("muted %v", types)
+		return d.client.Muted(), nil
+	}))
+	mux.Handle("/unmute", d.post(func(r *http.Request) (any, error) {
+		types, err := subscriptionTypes(r)
+		if err != nil {
+			return nil, err
+		}
+		d.client.Unmute(types...)
+		log.Printf("unmuted %v", types)
+		return d.client.Muted(), nil
+	}))
+	mux.Handle("/reconnect", d.post(func(*http.Request) (any, error) {
+		d.reconnecting.Store(true)
+		return "reconnecting", d.client.Client.Close()
+	}))
+	mux.Handle("/healthz", twitch.ProbeHandler(d.client.Live))
+	mux.Handle("/readyz", twitch.ProbeHandler(d.client.Ready))
+	return mux
+}
+
+// subscriptionTypes returns the type parameters of the request, which can be given more
+// than once.
+func subscriptionTypes(r *http.Request) ([]twitch.EventSubscription, error) {
+	var types []twitch.EventSubscription
+	for _, t := range r.URL.Query()["type"] {
+		event := twitch.EventSubscription(t)
+		if event.Version() == "" {
+			return nil, fmt.Errorf("unknown subscription type %s", t)
+		}
+		types = append(types, event)
+	}
+	if len(types) == 0 {
+		return nil, errors.New("no type parameter")
+	}
+	return types, nil
+}
+
+func (d *daemon) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if d.token == "" {
+		return true
+	}
+	expected := []byte("Bearer " + d.token)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) == 1 {
+		return true
+	}
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+	return false
+}
+
+func (d *daemon) get(value func() any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if d.authorized(w, r) {
+			writeJSON(w, value())
+		}
+	})
+}
+
+func (d *daemon) post(action func(r *http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !d.authorized(w, r) {
+			return
+		}
+		value, err := action(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, value)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/isabelcoolaf/go-twitch-eventsub/cmd/eventsub
+
+go 1.19
+
+require (
+	github.com/isabelcoolaf/go-twitch-eventsub v0.0.0
+	github.com/isabelcoolaf/go-twitch-eventsub/config v0.0.0
+)
+
+require (
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/klauspost/compress v1.10.3 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	nhooyr.io/websocket v1.8.7 // indirect
+)
+
+replace (
+	github.com/isabelcoolaf/go-twitch-eventsub => ../../
+	github.com/isabelcoolaf/go-twitch-eventsub/config => ../../config
+)
@@ -0,0 +1,463 @@
+// Command eventsub connects to EventSub, subscribes to the given subscription types for
+// the given channels, and prints the events it receives, for debugging scopes and
+// payloads without writing a program.
+//
+//	eventsub -token $TOKEN -channel twitchdev channel.follow channel.chat.message
+//
+// Events are printed as indented JSON under a line naming their type and channel, or
+// with -json as one enveloped notification per line, which twitch.ReplayPublished reads
+// back. With -tui, they scroll by as a feed of one line per event in the terminal,
+// colored by type under a counter per type, with hotkeys to hide types, search, and
+// pause. Filters keep the events whose field at a dotted path has a value, like
+// -filter chatter_user_login=twitchdev, or whose JSON contains a text, like
+// -filter Kappa.
+//
+// The client ID and the user the token belongs to are looked up by validating the
+// token, unless given with -client-id and -user. Subscriptions are created with the
+// condition their type needs, filled from the channel and user, and -condition adds or
+// overrides keys.
+//
+// The soak command runs a client against a mock server for hours instead, with
+// scheduled reconnects, silences of the connection, and bursts of notifications, and
+// reports dropped notifications, latency, and leaked goroutines:
+//
+//	eventsub soak -duration 6h -reconnect-every 10m -gap-every 15m -gap 30s
+//
+// The daemon command runs the client of a configuration file of the config package as
+// a sidecar, publishing notifications to its sinks for applications in other languages,
+// reconnecting when the connection fails. An admin HTTP API reports the session, the
+// subscriptions, and the stats, mutes and unmutes subscription types, and triggers
+// reconnects:
+//
+//	eventsub daemon -config eventsub.yaml -admin 127.0.0.1:8081
+//	curl -X POST '127.0.0.1:8081/mute?type=channel.chat.message'
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+const validateUrl = "https://id.twitch.tv/oauth2/validate"
+
+// list is a flag that can be given more than once, or as a comma separated list.
+type list []string
+
+func (l *list) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *list) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+type options struct {
+	token      string
+	clientID   string
+	userID     string
+	channels   list
+	conditions list
+	filters    list
+	json       bool
+	tui        bool
+	apiUrl     string
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "soak":
+			soak(os.Args[2:])
+			return
+		case "daemon":
+			runDaemon(os.Args[2:])
+			return
+		}
+	}
+
+	var opts options
+	flag.StringVar(&opts.token, "token", os.Getenv("TWITCH_TOKEN"), "user access token; defaults to $TWITCH_TOKEN")
+	flag.StringVar(&opts.clientID, "client-id", os.Getenv("TWITCH_CLIENT_ID"), "client ID of the token; defaults to $TWITCH_CLIENT_ID, or the one of the token")
+	flag.StringVar(&opts.userID, "user", "", "ID of the user of the token, for the user and moderator conditions; defaults to the one of the token")
+	flag.Var(&opts.channels, "channel", "logins or IDs of the channels to subscribe to; defaults to the user of the token")
+	flag.Var(&opts.conditions, "condition", "key=value added to the condition of every subscription")
+	flag.Var(&opts.filters, "filter", "field=value or text events must match to be printed")
+	flag.BoolVar(&opts.json, "json", false, "print enveloped notifications as JSON lines")
+	flag.BoolVar(&opts.tui, "tui", false, "show a live feed of events with counters and filter hotkeys")
+	flag.StringVar(&opts.apiUrl, "api-url", "https://api.twitch.tv/helix", "Twitch API, like the mock API of the Twitch CLI")
+	websocketUrl := flag.String("url", "wss://eventsub.wss.twitch.tv/ws", "EventSub websocket, like the one of the Twitch CLI")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] subscription-type...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(log.Ltime)
+
+	types := flag.Args()
+	if len(types) == 0 || opts.token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, t := range types {
+		if twitch.EventSubscription(t).Version() == "" {
+			log.Fatalf("unknown subscription type %s", t)
+		}
+	}
+	filters, err := parseFilters(opts.filters)
+	if err != nil {
+		log.Fatal(err)
+	}
+	extra, err := parseCondition(opts.conditions)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = resolveUser(ctx, &opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	channels, err := resolveChannels(ctx, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := twitch.NewClientWithUrl(*websocketUrl)
+	client.SubscriptionAddress = strings.TrimSuffix(opts.apiUrl, "/") + "/eventsub/subscriptions"
+	client.OnError(func(err error) {
+		log.Print(err)
+	})
+	var once sync.Once
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		once.Do(func() {
+			go subscribe(ctx, client, opts, types, channels, extra)
+		})
+	})
+	client.OnRevoke(func(message twitch.RevokeMessage, _ twitch.MessageMetadata) {
+		subscription := message.Payload.Subscription
+		log.Printf("subscription to %s was revoked: %s", subscription.Type, subscription.Status)
+	})
+
+	show := (&printer{w: os.Stdout, json: opts.json}).print
+	restore := func() {}
+	if opts.tui {
+		viewer := newViewer(os.Stdout)
+		restore, err = startTerminal()
+		if err != nil {
+			log.Fatalf("could not start terminal UI: %v", err)
+		}
+		log.SetOutput(viewer)
+		go viewer.readKeys(os.Stdin, stop)
+		go viewer.run(ctx)
+		show = viewer.add
+	}
+	client.OnRawEvent(func(event string, metadata twitch.MessageMetadata, subscription twitch.PayloadSubscription) {
+		if matchAll(filters, event) {
+			show(event, metadata, subscription)
+		}
+	})
+
+	err = client.ConnectWithContext(ctx)
+	restore()
+	log.SetOutput(os.Stderr)
+	if err != nil && ctx.Err() == nil {
+		log.Fatal(err)
+	}
+}
+
+func subscribe(ctx context.Context, client *twitch.Client, opts options, types []string, channels []string, extra map[string]string) {
+	for _, t := range types {
+		event := twitch.EventSubscription(t)
+		subscribed := make(map[string]bool)
+		for _, channel := range channels {
+			condition := conditionFor(event, channel, opts.userID, opts.clientID)
+			for key, value := range extra {
+				condition[key] = value
+			}
+			// User subscriptions are the same for every channel.
+			key := fmt.Sprint(condition)
+			if subscribed[key] {
+				continue
+			}
+			subscribed[key] = true
+
+			_, err := client.Subscribe(ctx, twitch.SubscribeRequest{
+				ClientID:    opts.clientID,
+				AccessToken: opts.token,
+				Event:       event,
+				Condition:   condition,
+			})
+			if err != nil {
+				log.Printf("could not subscribe to %s %v: %v", event, condition, err)
+				continue
+			}
+			log.Printf("subscribed to %s %v", event, condition)
+		}
+	}
+}
+
+// conditionFor returns the condition a subscription type needs for a channel.
+func conditionFor(event twitch.EventSubscription, channel, userID, clientID string) map[string]string {
+	t := string(event)
+	switch {
+	case t == "channel.raid":
+		return map[string]string{"to_broadcaster_user_id": channel}
+	case strings.HasPrefix(t, "user.authorization."), t == "conduit.shard.disabled":
+		return map[string]string{"client_id": clientID}
+	case strings.HasPrefix(t, "user."):
+		return map[string]string{"user_id": userID}
+	case strings.HasPrefix(t, "channel.chat."), t == "channel.chat_settings.update":
+		return map[string]string{"broadcaster_user_id": channel, "user_id": userID}
+	case t == "channel.follow", t == "channel.moderate",
+		strings.HasPrefix(t, "channel.shield_mode."),
+		strings.HasPrefix(t, "channel.shoutout."),
+		strings.HasPrefix(t, "channel.warning."),
+		strings.HasPrefix(t, "channel.unban_request."),
+		strings.HasPrefix(t, "channel.suspicious_user."),
+		strings.HasPrefix(t, "channel.guest_star_"),
+		strings.HasPrefix(t, "automod."):
+		return map[string]string{"broadcaster_user_id": channel, "moderator_user_id": userID}
+	default:
+		return map[string]string{"broadcaster_user_id": channel}
+	}
+}
+
+func parseCondition(pairs []string) (map[string]string, error) {
+	condition := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("condition %q is not key=value", pair)
+		}
+		condition[key] = value
+	}
+	return condition, nil
+}
+
+// filter matches events whose field at path has value, or whose JSON contains text
+// when path is empty.
+type filter struct {
+	path  []string
+	value string
+}
+
+func parseFilters(values []string) ([]filter, error) {
+	filters := make([]filter, 0, len(values))
+	for _, value := range values {
+		path, want, ok := strings.Cut(value, "=")
+		if !ok {
+			filters = append(filters, filter{value: strings.ToLower(value)})
+			continue
+		}
+		if path == "" {
+			return nil, fmt.Errorf("filter %q has no field", value)
+		}
+		filters = append(filters, filter{path: strings.Split(path, "."), value: want})
+	}
+	return filters, nil
+}
+
+func (f filter) match(event string, decoded any) bool {
+	if f.path == nil {
+		return strings.Contains(strings.ToLower(event), f.value)
+	}
+
+	value := decoded
+	for _, key := range f.path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		value, ok = object[key]
+		if !ok {
+			return false
+		}
+	}
+	switch value := value.(type) {
+	case string:
+		return value == f.value
+	case nil:
+		return f.value == "null"
+	default:
+		data, _ := json.Marshal(value)
+		return string(data) == f.value
+	}
+}
+
+func matchAll(filters []filter, event string) bool {
+	var decoded any
+	if len(filters) > 0 {
+		_ = json.Unmarshal([]byte(event), &decoded)
+	}
+	for _, f := range filters {
+		if !f.match(event, decoded) {
+			return false
+		}
+	}
+	return true
+}
+
+type printer struct {
+	w    io.Writer
+	json bool
+
+	mu sync.Mutex
+}
+
+func (p *printer) print(event string, metadata twitch.MessageMetadata, subscription twitch.PayloadSubscription) {
+	var buf bytes.Buffer
+	if p.json {
+		data, err := json.Marshal(twitch.PublishedNotification{
+			Metadata:     metadata,
+			Subscription: subscription,
+			Event:        json.RawMessage(event),
+		})
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	} else {
+		fmt.Fprintf(&buf, "%s %s v%s", metadata.MessageTimestamp.Local().Format("15:04:05"), subscription.Type, subscription.Version)
+		if channel := channelOf(subscription); channel != "" {
+			buf.WriteString(" " + channel)
+		}
+		buf.WriteString("\n  ")
+		if json.Indent(&buf, []byte(event), "  ", "  ") != nil {
+			buf.WriteString(event)
+		}
+		buf.WriteString("\n\n")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.w.Write(buf.Bytes())
+}
+
+func channelOf(subscription twitch.PayloadSubscription) string {
+	for _, key := range []string{"broadcaster_user_id", "to_broadcaster_user_id", "user_id", "client_id"} {
+		if value := subscription.Condition[key]; value != "" {
+			return key + "=" + value
+		}
+	}
+	return ""
+}
+
+// resolveUser fills in the client ID and user of the token by validating it.
+func resolveUser(ctx context.Context, opts *options) error {
+	if opts.clientID != "" && opts.userID != "" {
+		return nil
+	}
+
+	var validation struct {
+		ClientID string `json:"client_id"`
+		UserID   string `json:"user_id"`
+	}
+	err := get(ctx, validateUrl, "OAuth "+opts.token, "", &validation)
+	if err != nil {
+		return fmt.Errorf("could not validate token: %w", err)
+	}
+	if opts.clientID == "" {
+		opts.clientID = validation.ClientID
+	}
+	if opts.userID == "" {
+		opts.userID = validation.UserID
+	}
+	return nil
+}
+
+// resolveChannels returns the IDs of the channels, looking up the ones given by login.
+func resolveChannels(ctx context.Context, opts options) ([]string, error) {
+	if len(opts.channels) == 0 {
+		if opts.userID == "" {
+			return nil, fmt.Errorf("no channel given and the token has no user")
+		}
+		return []string{opts.userID}, nil
+	}
+
+	ids := make([]string, len(opts.channels))
+	query := url.Values{}
+	for i, channel := range opts.channels {
+		if strings.Trim(channel, "0123456789") == "" {
+			ids[i] = channel
+			continue
+		}
+		query.Add("login", strings.ToLower(channel))
+	}
+	if len(query) == 0 {
+		return ids, nil
+	}
+
+	var users struct {
+		Data []struct {
+			ID    string `json:"id"`
+			Login string `json:"login"`
+		} `json:"data"`
+	}
+	err := get(ctx, strings.TrimSuffix(opts.apiUrl, "/")+"/users?"+query.Encode(), "Bearer "+opts.token, opts.clientID, &users)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up channels: %w", err)
+	}
+	for i, channel := range opts.channels {
+		if ids[i] != "" {
+			continue
+		}
+		for _, user := range users.Data {
+			if strings.EqualFold(user.Login, channel) {
+				ids[i] = user.ID
+			}
+		}
+		if ids[i] == "" {
+			return nil, fmt.Errorf("no channel named %s", channel)
+		}
+	}
+	return ids, nil
+}
+
+func get(ctx context.Context, url, authorization, clientID string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	if clientID != "" {
+		req.Header.Set("Client-Id", clientID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	return json.Unmarshal(body, v)
+}
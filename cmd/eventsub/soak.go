@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+)
+
+// soak runs the soak test of the client against the mock server of twitchtest, and
+// exits with status 1 when it fails.
+func soak(args []string) {
+	var config twitchtest.SoakConfig
+	var events list
+	flags := flag.NewFlagSet("soak", flag.ExitOnError)
+	flags.DurationVar(&config.Duration, "duration", time.Hour, "how long the run lasts")
+	flags.IntVar(&config.Rate, "rate", 10, "notifications sent per second")
+	flags.Var(&events, "event", "subscription types of the notifications sent; defaults to channel.chat.message")
+	flags.DurationVar(&config.KeepaliveInterval, "keepalive", 5*time.Second, "interval between keepalives")
+	flags.DurationVar(&config.ReconnectEvery, "reconnect-every", 10*time.Minute, "interval between session reconnects; 0 disables them")
+	flags.DurationVar(&config.GapEvery, "gap-every", 15*time.Minute, "interval between silences of the connection; 0 disables them")
+	flags.DurationVar(&config.Gap, "gap", 30*time.Second, "how long silences last")
+	flags.DurationVar(&config.BurstEvery, "burst-every", 5*time.Minute, "interval between bursts; 0 disables them")
+	flags.IntVar(&config.Burst, "burst", 1000, "notifications sent at once in a burst")
+	flags.DurationVar(&config.SampleEvery, "sample-every", time.Minute, "interval between samples of goroutines and heap, printed as progress")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s soak [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	log.SetFlags(log.Ltime)
+	for _, event := range events {
+		config.Events = append(config.Events, twitch.EventSubscription(event))
+	}
+	config.Progress = func(report twitchtest.SoakReport) {
+		sample := report.Samples[len(report.Samples)-1]
+		log.Printf("%s: sent=%d handled=%d reconnects=%d goroutines=%d heap=%d", sample.Elapsed.Round(time.Second), report.Sent, report.Handled, report.Reconnects, sample.Goroutines, sample.HeapAlloc)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := twitchtest.RunSoak(ctx, config)
+	if err != nil && ctx.Err() == nil {
+		log.Fatal(err)
+	}
+	fmt.Print(report)
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
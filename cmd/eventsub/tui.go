@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+const (
+	maxFeedLines = 1000
+	hotkeys      = "1-9 hide type  a all  / search  p pause  c clear  q quit"
+)
+
+// colors are the ANSI foreground colors of subscription types.
+var colors = []string{"31", "32", "33", "34", "35", "36", "91", "92", "93", "94", "95", "96"}
+
+type feedLine struct {
+	at      time.Time
+	event   twitch.EventSubscription
+	summary string
+}
+
+// viewer shows events as a feed scrolling up the terminal, under a counter per
+// subscription type. It is a writer for log output, shown on the status line.
+type viewer struct {
+	out io.Writer
+
+	mu      sync.Mutex
+	feed    []feedLine
+	pending []feedLine
+	counts  map[twitch.EventSubscription]int
+	types   []twitch.EventSubscription
+	hidden  map[twitch.EventSubscription]bool
+	search  string
+	typing  bool
+	paused  bool
+	status  string
+	rows    int
+	cols    int
+	dirty   bool
+}
+
+func newViewer(out io.Writer) *viewer {
+	return &viewer{
+		out:    out,
+		counts: make(map[twitch.EventSubscription]int),
+		hidden: make(map[twitch.EventSubscription]bool),
+		rows:   24,
+		cols:   80,
+		dirty:  true,
+	}
+}
+
+func (v *viewer) add(event string, metadata twitch.MessageMetadata, subscription twitch.PayloadSubscription) {
+	line := feedLine{
+		at:      metadata.MessageTimestamp.Local(),
+		event:   subscription.Type,
+		summary: summarize(event),
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.counts[line.event]; !ok {
+		v.types = append(v.types, line.event)
+	}
+	v.counts[line.event]++
+	if v.paused {
+		v.pending = append(v.pending, line)
+	} else {
+		v.feed = appendFeed(v.feed, line)
+	}
+	v.dirty = true
+}
+
+func appendFeed(feed []feedLine, lines ...feedLine) []feedLine {
+	feed = append(feed, lines...)
+	if len(feed) > maxFeedLines {
+		feed = append(feed[:0], feed[len(feed)-maxFeedLines:]...)
+	}
+	return feed
+}
+
+// Write shows the last line of log output on the status line.
+func (v *viewer) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lines := strings.Split(strings.TrimSpace(string(p)), "\n")
+	v.status = lines[len(lines)-1]
+	v.dirty = true
+	return len(p), nil
+}
+
+// readKeys handles the hotkeys typed until r fails, calling quit on q.
+func (v *viewer) readKeys(r io.Reader, quit func()) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		keys := buf[:n]
+		// Arrow and function keys send escape sequences, which are ignored.
+		if len(keys) > 1 && keys[0] == 0x1b {
+			continue
+		}
+		for _, key := range keys {
+			if v.key(key) {
+				quit()
+				return
+			}
+		}
+	}
+}
+
+// key handles a key, reporting whether it quits.
+func (v *viewer) key(key byte) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.dirty = true
+	if v.typing {
+		switch key {
+		case '\r', '\n':
+			v.typing = false
+		case 0x1b:
+			v.typing = false
+			v.search = ""
+		case 0x7f, '\b':
+			if len(v.search) > 0 {
+				_, size := utf8.DecodeLastRuneInString(v.search)
+				v.search = v.search[:len(v.search)-size]
+			}
+		default:
+			if key >= ' ' {
+				v.search += string([]byte{key})
+			}
+		}
+		return false
+	}
+
+	switch {
+	case key == 'q':
+		return true
+	case key == 'p':
+		v.paused = !v.paused
+		if !v.paused {
+			v.feed = appendFeed(v.feed, v.pending...)
+			v.pending = nil
+		}
+	case key == 'c':
+		v.feed = nil
+		v.pending = nil
+	case key == 'a':
+		v.hidden = make(map[twitch.EventSubscription]bool)
+	case key == '/':
+		v.typing = true
+		v.search = ""
+	case key == 0x1b:
+		v.search = ""
+	case key >= '1' && key <= '9':
+		if i := int(key - '1'); i < len(v.types) {
+			event := v.types[i]
+			v.hidden[event] = !v.hidden[event]
+		}
+	}
+	return false
+}
+
+// run redraws the screen while it changes, until ctx is done.
+func (v *viewer) run(ctx context.Context) {
+	redraw := time.NewTicker(50 * time.Millisecond)
+	defer redraw.Stop()
+	resize := time.NewTicker(time.Second)
+	defer resize.Stop()
+
+	v.resize()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resize.C:
+			v.resize()
+		case <-redraw.C:
+			v.mu.Lock()
+			if v.dirty {
+				v.dirty = false
+				v.out.Write(v.render())
+			}
+			v.mu.Unlock()
+		}
+	}
+}
+
+func (v *viewer) resize() {
+	size, err := stty("size")
+	if err != nil {
+		return
+	}
+	fields := strings.Fields(size)
+	if len(fields) != 2 {
+		return
+	}
+	rows, _ := strconv.Atoi(fields[0])
+	cols, _ := strconv.Atoi(fields[1])
+	if rows <= 0 || cols <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if rows != v.rows || cols != v.cols {
+		v.rows, v.cols = rows, cols
+		v.dirty = true
+	}
+}
+
+// render returns the escape sequences drawing the screen: the counters, the status
+// line, and the feed filling the rest, newest at the bottom.
+func (v *viewer) render() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H")
+
+	var counters strings.Builder
+	width := 0
+	for i, event := range v.types {
+		text := fmt.Sprintf("%s %d", event, v.counts[event])
+		if i < 9 {
+			text = fmt.Sprintf("[%d] %s", i+1, text)
+		}
+		if width > 0 && width+2+len(text) > v.cols {
+			break
+		}
+		if width > 0 {
+			counters.WriteString("  ")
+			width += 2
+		}
+		style := colorOf(event)
+		if v.hidden[event] {
+			style = "2"
+		}
+		counters.WriteString("\x1b[" + style + "m" + text + "\x1b[0m")
+		width += len(text)
+	}
+	if len(v.types) == 0 {
+		counters.WriteString("waiting for events")
+	}
+	buf.WriteString(counters.String() + "\x1b[K\r\n")
+
+	status := hotkeys
+	switch {
+	case v.typing:
+		status = "search: " + v.search + "_"
+	case v.status != "":
+		status = v.status
+	}
+	if v.search != "" && !v.typing {
+		status = "search: " + v.search + "  " + status
+	}
+	if v.paused {
+		status = fmt.Sprintf("paused, %d new  %s", len(v.pending), status)
+	}
+	buf.WriteString("\x1b[7m" + pad(truncate(status, v.cols), v.cols) + "\x1b[0m\r\n")
+
+	height := v.rows - 2
+	var visible []feedLine
+	search := strings.ToLower(v.search)
+	for i := len(v.feed) - 1; i >= 0 && len(visible) < height; i-- {
+		line := v.feed[i]
+		if v.hidden[line.event] {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(string(line.event)+" "+line.summary), search) {
+			continue
+		}
+		visible = append(visible, line)
+	}
+	for i := 0; i < height; i++ {
+		// visible holds the newest line first, shown at the bottom.
+		if j := height - 1 - i; j < len(visible) {
+			line := visible[j]
+			prefix := line.at.Format("15:04:05") + " "
+			text := truncate(prefix+string(line.event)+" "+line.summary, v.cols)
+			text = strings.Replace(text, string(line.event), "\x1b["+colorOf(line.event)+"m"+string(line.event)+"\x1b[0m", 1)
+			buf.WriteString(text)
+		}
+		buf.WriteString("\x1b[K")
+		if i < height-1 {
+			buf.WriteString("\r\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// summarize returns the fields of an event on one line, leaving out IDs and logins
+// for the names next to them.
+func summarize(event string) string {
+	decoder := json.NewDecoder(strings.NewReader(event))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return event
+	}
+
+	var fields []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if decoder.Decode(&value) != nil {
+			break
+		}
+		if strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_login") || string(value) == "null" {
+			continue
+		}
+		var text string
+		if json.Unmarshal(value, &text) != nil {
+			text = string(value)
+		}
+		fields = append(fields, key+"="+text)
+	}
+	return strings.Join(fields, " ")
+}
+
+func colorOf(event twitch.EventSubscription) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(event))
+	return colors[hash.Sum32()%uint32(len(colors))]
+}
+
+func truncate(text string, width int) string {
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return ' '
+		}
+		return r
+	}, text)
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width])
+}
+
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// startTerminal switches the terminal to an alternate screen reading keys as they are
+// typed, returning a function restoring it. It needs stty, so it works on Unix.
+func startTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	_, err = stty("cbreak", "-echo")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l\x1b[2J")
+
+	return func() {
+		fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
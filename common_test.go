@@ -12,54 +12,22 @@ import (
 	"testing"
 	"time"
 
-	_ "embed"
-
 	"github.com/google/uuid"
 	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
 	"nhooyr.io/websocket"
 )
 
-//go:embed testEvents.json
-var testEvents []byte
-
 type messageDataGenerator func() ([][]byte, bool, error)
 
 func getTestEventData(eventType twitch.EventSubscription, suffixes ...string) messageDataGenerator {
 	return func() ([][]byte, bool, error) {
-		var events map[string]json.RawMessage
-		if err := json.Unmarshal(testEvents, &events); err != nil {
-			return nil, false, fmt.Errorf("could not parse event json file: %w", err)
-		}
-
-		key := strings.Join(append([]string{string(eventType)}, suffixes...), "-")
-		eventData, ok := events[key]
+		payload, ok := twitchtest.LookupPayload(eventType, suffixes...)
 		if !ok {
-			return nil, false, fmt.Errorf("could not find %s in testEvents", key)
+			return nil, false, fmt.Errorf("could not find %s in payload fixtures", strings.Join(append([]string{string(eventType)}, suffixes...), "-"))
 		}
 
-		data, err := json.Marshal(twitch.NotificationMessage{
-			Metadata: newMetadata("notification"),
-			Payload: struct {
-				Subscription twitch.PayloadSubscription "json:\"subscription\""
-				Event        *json.RawMessage           "json:\"event\""
-			}{
-				Event: &eventData,
-				Subscription: twitch.PayloadSubscription{
-					SubscriptionRequest: twitch.SubscriptionRequest{
-						Type:      eventType,
-						Version:   "1",
-						Condition: map[string]string{},
-						Transport: twitch.SubscriptionTransport{
-							Method:    "websocket",
-							SessionID: "",
-						},
-					},
-					Status:    "enabled",
-					Cost:      1,
-					CreatedAt: time.Now(),
-				},
-			},
-		})
+		data, err := json.Marshal(twitchtest.NewNotification(eventType, payload))
 		return [][]byte{data}, true, err
 	}
 }
@@ -153,7 +121,7 @@ func (s *TestServer) handleSubscription(w http.ResponseWriter, r *http.Request)
 
 func (s *TestServer) sendWelcome(ctx context.Context) error {
 	welcome := twitch.WelcomeMessage{
-		Metadata: newMetadata("session_welcome"),
+		Metadata: twitchtest.NewMetadata("session_welcome"),
 		Payload: struct {
 			Session twitch.PayloadSession `json:"session"`
 		}{
@@ -175,14 +143,6 @@ func (s *TestServer) sendWelcome(ctx context.Context) error {
 	return s.conn.Write(ctx, websocket.MessageText, data)
 }
 
-func newMetadata(msgType string) twitch.MessageMetadata {
-	return twitch.MessageMetadata{
-		MessageID:        uuid.NewString(),
-		MessageType:      msgType,
-		MessageTimestamp: time.Now(),
-	}
-}
-
 func newClient(t *testing.T, gen messageDataGenerator) *twitch.Client {
 	server, err := newTestServer(gen)
 	if err != nil {
@@ -0,0 +1,689 @@
+// Package config sets up clients from YAML or JSON files, so deployments change what
+// they subscribe to and where notifications go without recompiling.
+//
+//	websocket_url: wss://eventsub.wss.twitch.tv/ws
+//	client_id: ${TWITCH_CLIENT_ID}
+//	access_token: ${TWITCH_TOKEN}
+//	subscriptions:
+//	  - type: channel.follow
+//	    condition: {broadcaster_user_id: "1337", moderator_user_id: "1337"}
+//	dispatch:
+//	  synchronous: true
+//	publish:
+//	  format: envelope
+//	  sinks:
+//	    - nats: {address: "localhost:4222"}
+//
+// Values can refer to environment variables as ${NAME}, or ${NAME:-default} to fall
+// back to a default when NAME is unset.
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/archive"
+	"github.com/isabelcoolaf/go-twitch-eventsub/mqtt"
+	"github.com/isabelcoolaf/go-twitch-eventsub/nats"
+	"github.com/isabelcoolaf/go-twitch-eventsub/redis"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	// WebsocketURL defaults to the EventSub websocket of Twitch.
+	WebsocketURL string `yaml:"websocket_url"`
+	// SubscriptionURL defaults to the EventSub subscriptions endpoint of Twitch.
+	SubscriptionURL string `yaml:"subscription_url"`
+
+	ClientID    string `yaml:"client_id"`
+	AccessToken string `yaml:"access_token"`
+	// AccessTokenFile is read for the access token when AccessToken is empty, like a
+	// mounted secret.
+	AccessTokenFile string `yaml:"access_token_file"`
+
+	Subscriptions []Subscription `yaml:"subscriptions"`
+	Dispatch      Dispatch       `yaml:"dispatch"`
+	Publish       *Publish       `yaml:"publish"`
+}
+
+type Subscription struct {
+	Type twitch.EventSubscription `yaml:"type"`
+	// Version defaults to the version of the type the module decodes.
+	Version   string            `yaml:"version"`
+	Condition map[string]string `yaml:"condition"`
+	// UserID subscribes with the access token of the user from the token source of the
+	// client, for broadcasters who authorized the application themselves. Defaults to
+	// the access token of the configuration.
+	UserID string `yaml:"user_id"`
+}
+
+// TokenSource returns the access tokens of users, like a tokens.Refresher.
+type TokenSource interface {
+	AccessToken(ctx context.Context, userID string) (string, error)
+}
+
+// Dispatch holds the settings of the client's setters of the same names.
+type Dispatch struct {
+	Synchronous          bool          `yaml:"synchronous"`
+	MaxHandlerGoroutines *int          `yaml:"max_handler_goroutines"`
+	DecodeWorkers        int           `yaml:"decode_workers"`
+	GeneratedDecoders    bool          `yaml:"generated_decoders"`
+	EventPooling         bool          `yaml:"event_pooling"`
+	StringInterning      bool          `yaml:"string_interning"`
+	RelaxedValidation    bool          `yaml:"relaxed_validation"`
+	SlowHandlerThreshold time.Duration `yaml:"slow_handler_threshold"`
+	MaxReadBuffer        *int          `yaml:"max_read_buffer"`
+}
+
+// Publish sets up a publish bridge to every sink. They get the same data, on the
+// subjects of twitch.DefaultPublishSubject.
+type Publish struct {
+	// Format is raw, envelope, or msgpack. Defaults to envelope.
+	Format string `yaml:"format"`
+	// Timeout defaults to 5 seconds.
+	Timeout time.Duration `yaml:"timeout"`
+	// QueueSize is how many notifications wait to be published before new ones are
+	// dropped. Defaults to 1000.
+	QueueSize int    `yaml:"queue_size"`
+	Sinks     []Sink `yaml:"sinks"`
+}
+
+// Sink has the settings of exactly one of the publishers.
+type Sink struct {
+	NATS    *NATSSink    `yaml:"nats"`
+	Redis   *RedisSink   `yaml:"redis"`
+	MQTT    *MQTTSink    `yaml:"mqtt"`
+	Archive *ArchiveSink `yaml:"archive"`
+}
+
+type NATSSink struct {
+	Address  string `yaml:"address"`
+	Name     string `yaml:"name"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"`
+}
+
+type RedisSink struct {
+	Address string `yaml:"address"`
+	// Mode is pubsub or stream. Defaults to pubsub.
+	Mode         string `yaml:"mode"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	DB           int    `yaml:"db"`
+	StreamMaxLen int64  `yaml:"stream_max_len"`
+}
+
+type MQTTSink struct {
+	Address  string `yaml:"address"`
+	ClientID string `yaml:"client_id"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	QoS      byte   `yaml:"qos"`
+	Retain   bool   `yaml:"retain"`
+}
+
+type ArchiveSink struct {
+	Dir     string        `yaml:"dir"`
+	Prefix  string        `yaml:"prefix"`
+	MaxSize int64         `yaml:"max_size"`
+	MaxAge  time.Duration `yaml:"max_age"`
+	Gzip    bool          `yaml:"gzip"`
+}
+
+var formats = map[string]twitch.PublishFormat{
+	"raw":      twitch.PublishRaw,
+	"envelope": twitch.PublishEnvelope,
+	"msgpack":  twitch.PublishMsgpack,
+}
+
+var variable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Load reads and validates the configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse interpolates the environment variables of a YAML or JSON configuration, then
+// decodes and validates it. Unknown fields are errors, so typos don't go unnoticed.
+func Parse(data []byte) (*Config, error) {
+	var missing []string
+	data = variable.ReplaceAllFunc(data, func(match []byte) []byte {
+		groups := variable.FindSubmatch(match)
+		value, ok := os.LookupEnv(string(groups[1]))
+		if !ok {
+			if groups[2] == nil {
+				missing = append(missing, string(groups[1]))
+			}
+			return groups[3]
+		}
+		return []byte(value)
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("config: environment variables not set: %s", strings.Join(missing, ", "))
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("could not decode config: %w", err)
+	}
+
+	if config.AccessToken == "" && config.AccessTokenFile != "" {
+		token, err := os.ReadFile(config.AccessTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read access token: %w", err)
+		}
+		config.AccessToken = strings.TrimSpace(string(token))
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate reports every problem of the configuration at once.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	needsToken := false
+	for _, subscription := range c.Subscriptions {
+		needsToken = needsToken || subscription.UserID == ""
+	}
+	if len(c.Subscriptions) > 0 && (c.ClientID == "" || needsToken && c.AccessToken == "") {
+		add("subscriptions need a client_id and access_token")
+	}
+	seen := make(map[string]bool)
+	for i, subscription := range c.Subscriptions {
+		switch {
+		case subscription.Type == "":
+			add("subscription %d has no type", i)
+		case subscription.Type.Version() == "" && !c.Dispatch.RelaxedValidation:
+			add("subscription %d has unknown type %s", i, subscription.Type)
+		case subscription.Type.Version() == "" && subscription.Version == "":
+			add("subscription %d of unknown type %s has no version", i, subscription.Type)
+		}
+		if len(subscription.Condition) == 0 {
+			add("subscription %d has no condition", i)
+		}
+		key := subscription.key()
+		if seen[key] {
+			add("subscription %d is a duplicate", i)
+		}
+		seen[key] = true
+	}
+
+	if c.Dispatch.DecodeWorkers < 0 {
+		add("dispatch.decode_workers is negative")
+	}
+	if c.Dispatch.SlowHandlerThreshold < 0 {
+		add("dispatch.slow_handler_threshold is negative")
+	}
+
+	if c.Publish != nil {
+		if _, ok := formats[c.Publish.Format]; !ok && c.Publish.Format != "" {
+			add("publish.format %q is not raw, envelope, or msgpack", c.Publish.Format)
+		}
+		if len(c.Publish.Sinks) == 0 {
+			add("publish has no sinks")
+		}
+		for i, sink := range c.Publish.Sinks {
+			var set []string
+			if sink.NATS != nil {
+				set = append(set, "nats")
+				if sink.NATS.Address == "" {
+					add("sink %d has no nats.address", i)
+				}
+			}
+			if sink.Redis != nil {
+				set = append(set, "redis")
+				if sink.Redis.Address == "" {
+					add("sink %d has no redis.address", i)
+				}
+				if mode := sink.Redis.Mode; mode != "" && mode != "pubsub" && mode != "stream" {
+					add("sink %d has redis.mode %q, not pubsub or stream", i, mode)
+				}
+			}
+			if sink.MQTT != nil {
+				set = append(set, "mqtt")
+				if sink.MQTT.Address == "" {
+					add("sink %d has no mqtt.address", i)
+				}
+				if sink.MQTT.QoS > 2 {
+					add("sink %d has mqtt.qos %d, not 0, 1, or 2", i, sink.MQTT.QoS)
+				}
+			}
+			if sink.Archive != nil {
+				set = append(set, "archive")
+				if sink.Archive.Dir == "" {
+					add("sink %d has no archive.dir", i)
+				}
+			}
+			if len(set) != 1 {
+				add("sink %d needs exactly one of nats, redis, mqtt, or archive, got %d", i, len(set))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// key identifies the subscription, whether its version is left to the default or not.
+func (s Subscription) key() string {
+	version := s.Version
+	if version == "" {
+		version = s.Type.Version()
+	}
+	return fmt.Sprintf("%s/%s/%v", s.Type, version, s.Condition)
+}
+
+type subscribed struct {
+	id     string
+	userID string
+}
+
+// Client is a client set up from a configuration, with the sinks it publishes to.
+type Client struct {
+	*twitch.Client
+
+	sinks []io.Closer
+
+	mu      sync.Mutex
+	config  *Config
+	tokens  TokenSource
+	onError func(err error)
+
+	// reconcile serializes subscribing, so a reload and a welcome don't both create a
+	// subscription.
+	reconcile sync.Mutex
+	session   string
+	// connCtx is the context of the connection, and cancelWelcome cancels subscribing
+	// on the last welcome.
+	connCtx       context.Context
+	cancelWelcome context.CancelFunc
+	// subscribed holds the subscriptions of the session by key.
+	subscribed map[string]subscribed
+}
+
+// NewClient returns a client set up as configured, connecting to the sinks with ctx. It
+// subscribes to the subscriptions on every welcome until the connection ends or the
+// next welcome, passing failures to OnError. Set OnWelcome to replace that, calling
+// SubscribeAll from it. Reload and Watch change the subscriptions without reconnecting.
+func (c *Config) NewClient(ctx context.Context) (*Client, error) {
+	client := &Client{config: c}
+	client.Client = twitch.NewClient()
+	if c.WebsocketURL != "" {
+		client.Address = c.WebsocketURL
+	}
+	if c.SubscriptionURL != "" {
+		client.SubscriptionAddress = c.SubscriptionURL
+	}
+
+	dispatch := c.Dispatch
+	client.SetSynchronousDispatch(dispatch.Synchronous)
+	if dispatch.MaxHandlerGoroutines != nil {
+		client.SetMaxHandlerGoroutines(*dispatch.MaxHandlerGoroutines)
+	}
+	client.SetDecodeWorkers(dispatch.DecodeWorkers)
+	client.SetGeneratedDecoders(dispatch.GeneratedDecoders)
+	client.SetEventPooling(dispatch.EventPooling)
+	client.SetStringInterning(dispatch.StringInterning)
+	client.SetRelaxedValidation(dispatch.RelaxedValidation)
+	client.SetSlowHandlerThreshold(dispatch.SlowHandlerThreshold)
+	if dispatch.MaxReadBuffer != nil {
+		client.SetMaxReadBuffer(*dispatch.MaxReadBuffer)
+	}
+
+	if c.Publish != nil {
+		bridge, err := client.dialSinks(ctx, c.Publish)
+		if err != nil {
+			client.closeSinks()
+			return nil, err
+		}
+		client.SetPublishBridge(bridge)
+	}
+
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		ctx := client.welcomeContext()
+		go func() {
+			err := client.SubscribeAll(ctx)
+			if err != nil && ctx.Err() == nil {
+				client.reportError(err)
+			}
+		}()
+	})
+	return client, nil
+}
+
+// Connect connects like ConnectWithContext without a context.
+func (c *Client) Connect() error {
+	return c.ConnectWithContext(context.Background())
+}
+
+// ConnectWithContext connects like twitch.Client.ConnectWithContext. Subscribing on
+// welcome stops when it returns.
+func (c *Client) ConnectWithContext(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.mu.Lock()
+	c.connCtx = ctx
+	c.mu.Unlock()
+	return c.Client.ConnectWithContext(ctx)
+}
+
+// welcomeContext returns the context of subscribing on a welcome, canceling the one of
+// the last welcome, whose session is gone.
+func (c *Client) welcomeContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancelWelcome != nil {
+		c.cancelWelcome()
+	}
+	parent := c.connCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	c.cancelWelcome = cancel
+	return ctx
+}
+
+// SetTokenSource sets where the access tokens of the subscriptions with a user ID come
+// from, so one client can subscribe to the events of many broadcasters with their own
+// tokens.
+func (c *Client) SetTokenSource(source TokenSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens = source
+}
+
+// OnError is called with the errors of the client and of subscribing.
+func (c *Client) OnError(callback func(err error)) {
+	c.mu.Lock()
+	c.onError = callback
+	c.mu.Unlock()
+	c.Client.OnError(callback)
+}
+
+func (c *Client) reportError(err error) {
+	c.mu.Lock()
+	onError := c.onError
+	c.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// SubscribeAll brings the subscriptions of the current session in line with the
+// configuration, subscribing to the ones missing and deleting the ones no longer
+// configured. It tries all of them before returning the failures. Without a session,
+// it does nothing until the welcome.
+func (c *Client) SubscribeAll(ctx context.Context) error {
+	c.reconcile.Lock()
+	defer c.reconcile.Unlock()
+
+	session := c.Session().ID
+	if session == "" {
+		return nil
+	}
+	// Websocket subscriptions end with their session.
+	if session != c.session {
+		c.session = session
+		c.subscribed = make(map[string]subscribed)
+	}
+
+	c.mu.Lock()
+	config := c.config
+	tokens := c.tokens
+	c.mu.Unlock()
+	accessToken := func(userID string) (string, error) {
+		if userID == "" {
+			return config.AccessToken, nil
+		}
+		if tokens == nil {
+			return "", fmt.Errorf("no token source for user %s", userID)
+		}
+		return tokens.AccessToken(ctx, userID)
+	}
+
+	configured := make(map[string]bool, len(config.Subscriptions))
+	for _, subscription := range config.Subscriptions {
+		configured[subscription.key()] = true
+	}
+
+	var failures []string
+	for key, sub := range c.subscribed {
+		if configured[key] {
+			continue
+		}
+		token, err := accessToken(sub.userID)
+		if err == nil {
+			err = c.Unsubscribe(ctx, twitch.UnsubscribeRequest{
+				ClientID:    config.ClientID,
+				AccessToken: token,
+				ID:          sub.id,
+			})
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		delete(c.subscribed, key)
+	}
+
+	for _, subscription := range config.Subscriptions {
+		key := subscription.key()
+		if _, ok := c.subscribed[key]; ok {
+			continue
+		}
+		token, err := accessToken(subscription.UserID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", subscription.Type, err))
+			continue
+		}
+		response, err := c.Subscribe(ctx, twitch.SubscribeRequest{
+			ClientID:        config.ClientID,
+			AccessToken:     token,
+			VersionOverride: subscription.Version,
+			Event:           subscription.Type,
+			Condition:       subscription.Condition,
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", subscription.Type, err))
+			continue
+		}
+		if len(response.Data) > 0 {
+			c.subscribed[key] = subscribed{id: response.Data[0].ID, userID: subscription.UserID}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("could not reconcile subscriptions: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Reload switches to a new configuration without reconnecting, subscribing to the
+// added subscriptions and deleting the removed ones. Only the credentials and the
+// subscriptions change; the other settings need a new client.
+func (c *Client) Reload(ctx context.Context, config *Config) error {
+	c.mu.Lock()
+	c.config = config
+	c.mu.Unlock()
+
+	return c.SubscribeAll(ctx)
+}
+
+// Watch reloads the configuration file at path when the process gets SIGHUP, and when
+// its contents change if interval is positive, checking that often, until ctx is done.
+// A file failing to load is passed to OnError, keeping the previous configuration.
+func (c *Client) Watch(ctx context.Context, path string, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	// The first check reloads, catching changes made since the file was loaded.
+	// Reloading what is already subscribed does nothing.
+	var last []byte
+	reload := func(always bool) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			c.reportError(fmt.Errorf("could not read config: %w", err))
+			return
+		}
+		if !always && last != nil && bytes.Equal(data, last) {
+			return
+		}
+		last = data
+
+		config, err := Parse(data)
+		if err != nil {
+			c.reportError(fmt.Errorf("could not reload config: %w", err))
+			return
+		}
+		err = c.Reload(ctx, config)
+		if err != nil {
+			c.reportError(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			reload(true)
+		case <-tick:
+			reload(false)
+		}
+	}
+}
+
+// Close closes the connection, waits up to 5 seconds for the notifications still
+// queued to be published, and closes the sinks.
+func (c *Client) Close() error {
+	err := c.Client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.FlushPublished(ctx)
+
+	sinkErr := c.closeSinks()
+	if err != nil {
+		return err
+	}
+	return sinkErr
+}
+
+func (c *Client) closeSinks() error {
+	var first error
+	for _, sink := range c.sinks {
+		err := sink.Close()
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	c.sinks = nil
+	return first
+}
+
+func (c *Client) dialSinks(ctx context.Context, publish *Publish) (*twitch.PublishBridge, error) {
+	format := twitch.PublishEnvelope
+	if publish.Format != "" {
+		format = formats[publish.Format]
+	}
+
+	var publishers twitch.MultiPublisher
+	for i, sink := range publish.Sinks {
+		var publisher interface {
+			twitch.Publisher
+			io.Closer
+		}
+		var err error
+		switch {
+		case sink.NATS != nil:
+			publisher, err = nats.Dial(ctx, sink.NATS.Address, nats.Options{
+				Name:     sink.NATS.Name,
+				User:     sink.NATS.User,
+				Password: sink.NATS.Password,
+				Token:    sink.NATS.Token,
+			})
+		case sink.Redis != nil:
+			mode := redis.PubSub
+			if sink.Redis.Mode == "stream" {
+				mode = redis.Stream
+			}
+			publisher, err = redis.Dial(ctx, sink.Redis.Address, redis.Options{
+				Mode:         mode,
+				Username:     sink.Redis.Username,
+				Password:     sink.Redis.Password,
+				DB:           sink.Redis.DB,
+				StreamMaxLen: sink.Redis.StreamMaxLen,
+			})
+		case sink.MQTT != nil:
+			publisher, err = mqtt.Dial(ctx, sink.MQTT.Address, mqtt.Options{
+				ClientID: sink.MQTT.ClientID,
+				Username: sink.MQTT.Username,
+				Password: sink.MQTT.Password,
+				QoS:      sink.MQTT.QoS,
+				Retain:   sink.MQTT.Retain,
+			})
+		case sink.Archive != nil:
+			publisher, err = archive.NewArchiver(archive.Options{
+				Dir:     sink.Archive.Dir,
+				Prefix:  sink.Archive.Prefix,
+				MaxSize: sink.Archive.MaxSize,
+				MaxAge:  sink.Archive.MaxAge,
+				Gzip:    sink.Archive.Gzip,
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to sink %d: %w", i, err)
+		}
+		c.sinks = append(c.sinks, publisher)
+		publishers = append(publishers, publisher)
+	}
+
+	return &twitch.PublishBridge{
+		Publisher: publishers,
+		Format:    format,
+		Timeout:   publish.Timeout,
+		QueueSize: publish.QueueSize,
+	}, nil
+}
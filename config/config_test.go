@@ -0,0 +1,299 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/config"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Setenv("TEST_TWITCH_TOKEN", "token")
+	dir := t.TempDir()
+
+	cfg, err := config.Parse([]byte(`
+client_id: ${TEST_TWITCH_CLIENT_ID:-client}
+access_token: ${TEST_TWITCH_TOKEN}
+subscriptions:
+  - type: channel.follow
+    condition: {broadcaster_user_id: "1337", moderator_user_id: "1337"}
+  - type: channel.raid
+    version: "1"
+    condition: {to_broadcaster_user_id: "1337"}
+dispatch:
+  synchronous: true
+  max_handler_goroutines: 0
+  slow_handler_threshold: 250ms
+publish:
+  format: msgpack
+  sinks:
+    - archive: {dir: "` + dir + `", max_age: 1h}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "client", cfg.ClientID)
+	assert.Equal(t, "token", cfg.AccessToken)
+	assert.Len(t, cfg.Subscriptions, 2)
+	assert.Equal(t, "1", cfg.Subscriptions[1].Version)
+	assert.True(t, cfg.Dispatch.Synchronous)
+	assert.Equal(t, 0, *cfg.Dispatch.MaxHandlerGoroutines)
+	assert.Nil(t, cfg.Dispatch.MaxReadBuffer)
+	assert.Equal(t, 250*time.Millisecond, cfg.Dispatch.SlowHandlerThreshold)
+	assert.Equal(t, time.Hour, cfg.Publish.Sinks[0].Archive.MaxAge)
+
+	// JSON is YAML too.
+	cfg, err = config.Parse([]byte(`{"websocket_url": "ws://localhost:8080/ws"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "ws://localhost:8080/ws", cfg.WebsocketURL)
+
+	token := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(token, []byte("secret\n"), 0o600))
+	cfg, err = config.Parse([]byte("access_token_file: " + token))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", cfg.AccessToken)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := config.Parse([]byte(`access_token: ${TEST_TWITCH_UNSET}`))
+	assert.ErrorContains(t, err, "environment variables not set: TEST_TWITCH_UNSET")
+
+	_, err = config.Parse([]byte(`acess_token: typo`))
+	assert.ErrorContains(t, err, "field acess_token not found")
+
+	_, err = config.Parse([]byte(`
+subscriptions:
+  - type: channel.nope
+    condition: {broadcaster_user_id: "1"}
+  - type: channel.follow
+publish:
+  format: xml
+  sinks:
+    - {}
+    - nats: {address: "localhost:4222"}
+      redis: {address: "localhost:6379", mode: queue}
+`))
+	require.Error(t, err)
+	for _, problem := range []string{
+		"subscriptions need a client_id and access_token",
+		"subscription 0 has unknown type channel.nope",
+		"subscription 1 has no condition",
+		`publish.format "xml" is not raw, envelope, or msgpack`,
+		"sink 0 needs exactly one of nats, redis, mqtt, or archive, got 0",
+		`sink 1 has redis.mode "queue", not pubsub or stream`,
+		"sink 1 needs exactly one of nats, redis, mqtt, or archive, got 2",
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+type tokenSource map[string]string
+
+func (s tokenSource) AccessToken(_ context.Context, userID string) (string, error) {
+	return s[userID], nil
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	requests := make(chan twitch.SubscriptionRequest, 4)
+	tokens := make(chan string, 4)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request twitch.SubscriptionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- request
+		tokens <- r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"data":[{"id":"1","status":"enabled"}]}`))
+	}))
+	defer api.Close()
+
+	dir := t.TempDir()
+	cfg, err := config.Parse([]byte(`
+websocket_url: ` + server.URL + `
+subscription_url: ` + api.URL + `
+client_id: client
+access_token: token
+subscriptions:
+  - type: channel.raid
+    condition: {to_broadcaster_user_id: "1337"}
+  - type: channel.raid
+    condition: {to_broadcaster_user_id: "42"}
+    user_id: "42"
+dispatch: {synchronous: true}
+publish:
+  sinks:
+    - archive: {dir: "` + dir + `"}
+`))
+	require.NoError(t, err)
+
+	client, err := cfg.NewClient(ctx)
+	require.NoError(t, err)
+	client.SetTokenSource(tokenSource{"42": "broadcaster"})
+	client.OnError(func(err error) {
+		t.Error(err)
+	})
+	raids := twitchtest.ExpectEvent[twitch.EventChannelRaid](t, client.Client)
+	go client.ConnectWithContext(ctx)
+
+	conn, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+	authorizations := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case request := <-requests:
+			assert.Equal(t, twitch.SubChannelRaid, request.Type)
+			assert.Equal(t, client.Session().ID, request.Transport.SessionID)
+			authorizations[request.Condition["to_broadcaster_user_id"]] = <-tokens
+		case <-ctx.Done():
+			t.Fatal("did not subscribe")
+		}
+	}
+	assert.Equal(t, map[string]string{"1337": "Bearer token", "42": "Bearer broadcaster"}, authorizations)
+
+	require.NoError(t, conn.SendNotification(ctx, twitch.SubChannelRaid))
+	select {
+	case <-raids:
+	case <-ctx.Done():
+		t.Fatal("no raid dispatched")
+	}
+	require.NoError(t, client.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"type":"channel.raid"`))
+}
+
+func TestNewClientSubscribesAfterContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := twitchtest.NewServer()
+	defer server.Close()
+	helix := twitchtest.NewHelix(t)
+
+	cfg, err := config.Parse([]byte(`
+websocket_url: ` + server.URL + `
+subscription_url: ` + helix.URL + `/eventsub/subscriptions
+client_id: client
+access_token: token
+subscriptions:
+  - type: channel.raid
+    condition: {to_broadcaster_user_id: "1337"}
+`))
+	require.NoError(t, err)
+
+	// The context of NewClient only connects to the sinks.
+	newCtx, cancelNew := context.WithCancel(ctx)
+	client, err := cfg.NewClient(newCtx)
+	cancelNew()
+	require.NoError(t, err)
+	defer client.Close()
+	client.OnError(func(err error) {
+		t.Error(err)
+	})
+	go client.ConnectWithContext(ctx)
+
+	_, err = helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+	require.NoError(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	requests := make(chan string, 8)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			requests <- "DELETE " + r.URL.Query().Get("id")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var request twitch.SubscriptionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- "POST " + string(request.Type)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"data":[{"id":"` + string(request.Type) + `","status":"enabled"}]}`))
+	}))
+	defer api.Close()
+
+	write := func(path string, subscriptions string) {
+		require.NoError(t, os.WriteFile(path, []byte(`
+websocket_url: `+server.URL+`
+subscription_url: `+api.URL+`
+client_id: client
+access_token: token
+subscriptions:
+`+subscriptions), 0o600))
+	}
+	receive := func() string {
+		select {
+		case request := <-requests:
+			return request
+		case <-ctx.Done():
+			t.Fatal("no request")
+			return ""
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "eventsub.yaml")
+	write(path, `
+  - type: channel.raid
+    condition: {to_broadcaster_user_id: "1337"}
+  - type: stream.online
+    condition: {broadcaster_user_id: "1337"}
+`)
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	client, err := cfg.NewClient(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+	client.OnError(func(err error) {
+		t.Error(err)
+	})
+	go client.ConnectWithContext(ctx)
+	_, err = server.WaitForConnection(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "POST channel.raid", receive())
+	assert.Equal(t, "POST stream.online", receive())
+
+	go client.Watch(ctx, path, 10*time.Millisecond)
+	// The default version of stream.online is the same subscription.
+	write(path, `
+  - type: stream.online
+    version: "1"
+    condition: {broadcaster_user_id: "1337"}
+  - type: stream.offline
+    condition: {broadcaster_user_id: "1337"}
+`)
+	assert.Equal(t, "DELETE channel.raid", receive())
+	assert.Equal(t, "POST stream.offline", receive())
+	select {
+	case request := <-requests:
+		t.Errorf("unexpected request %s", request)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Len(t, client.Subscriptions(), 2)
+}
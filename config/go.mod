@@ -0,0 +1,19 @@
+module github.com/isabelcoolaf/go-twitch-eventsub/config
+
+go 1.19
+
+require (
+	github.com/isabelcoolaf/go-twitch-eventsub v0.0.0
+	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/klauspost/compress v1.10.3 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	nhooyr.io/websocket v1.8.7 // indirect
+)
+
+replace github.com/isabelcoolaf/go-twitch-eventsub => ../
@@ -1,10 +1,15 @@
 package twitch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"nhooyr.io/websocket"
 )
@@ -32,21 +37,80 @@ func zeroPtrGen[T any]() func() any {
 	}
 }
 
-func callFunc[T any, C any](f func(T, C), v T, c C) {
-	if f != nil {
-		go f(v, c)
+func callFunc[T any, C any](client *Client, f func(T, C), v T, c C) bool {
+	if f == nil {
+		return false
 	}
+
+	queued := time.Now()
+	client.stats.running.Add(1)
+	call := func() {
+		start := time.Now()
+		f(v, c)
+		client.observeHandler(f, c, start.Sub(queued), time.Since(start))
+		client.stats.running.Add(-1)
+	}
+	if client.synchronousDispatch {
+		call()
+	} else if client.dispatcher != nil {
+		client.dispatcher.dispatch(call)
+	} else {
+		go call()
+	}
+	return true
 }
 
 type Client struct {
-	Address   string
-	ws        *websocket.Conn
-	connected bool
-	ctx       context.Context
+	// Address is the websocket URL the client connects to. A reconnect requested by
+	// Twitch changes it, so read it with DebugState while connected.
+	Address             string
+	SubscriptionAddress string
+	ws                  *websocket.Conn
+	connected           atomic.Bool
+	ctx                 context.Context
 
 	reconnecting bool
 	reconnected  chan struct{}
 
+	mu            sync.Mutex
+	session       PayloadSession
+	subscriptions map[string]PayloadSubscription
+	lastMessageAt time.Time
+	// muted holds the map[EventSubscription]bool of the muted types, replaced under mu
+	// on change so notifications read it without locking.
+	muted atomic.Value
+
+	stats        clientStats
+	webhookStats clientStats
+	traffic      *trafficTracker
+
+	// deliverMu serializes the messages of the read loop and of webhook relays.
+	deliverMu sync.Mutex
+
+	slowHandlerThreshold time.Duration
+	metrics              Metrics
+	debugLogger          *log.Logger
+	correlationIDFunc    func(metadata MessageMetadata) string
+	relaxedValidation    bool
+	recorder             *Recorder
+	clock                Clock
+	synchronousDispatch  bool
+	eventPooling         bool
+	dispatcher           *dispatcher
+	maxReadBuffer        int
+	generatedDecoders    bool
+	pointerHandlers      map[EventSubscription]func(event any, payloadContext PayloadContext)
+	interner             *interner
+	decodeWorkers        int
+	batchersMu           sync.RWMutex
+	batchers             []*eventBatcher
+	eventListenersMu     sync.RWMutex
+	eventListeners       []eventListener
+	nextEventListener    int
+	stageTimer           *stageTimer
+	publishBridge        *PublishBridge
+	publishQueue         publishQueue
+
 	// Responses
 	onError        func(err error)
 	onWelcome      func(message WelcomeMessage, metadata MessageMetadata)
@@ -54,82 +118,14 @@ type Client struct {
 	onNotification func(message NotificationMessage, metadata MessageMetadata)
 	onReconnect    func(message ReconnectMessage, metadata MessageMetadata)
 	onRevoke       func(message RevokeMessage, metadata MessageMetadata)
+	onLatency      func(latency time.Duration, payloadContext PayloadContext)
+	onLifecycle    func(event LifecycleEvent)
+	onSlowHandler  func(warning SlowHandlerWarning)
 
 	// Events
-	onRawEvent                                              func(event string, metadata MessageMetadata, subscription PayloadSubscription)
-	onEventChannelUpdate                                    func(event EventChannelUpdate, payloadContext PayloadContext)
-	onEventChannelFollow                                    func(event EventChannelFollow, payloadContext PayloadContext)
-	onEventChannelSubscribe                                 func(event EventChannelSubscribe, payloadContext PayloadContext)
-	onEventChannelSubscriptionEnd                           func(event EventChannelSubscriptionEnd, payloadContext PayloadContext)
-	onEventChannelSubscriptionGift                          func(event EventChannelSubscriptionGift, payloadContext PayloadContext)
-	onEventChannelSubscriptionMessage                       func(event EventChannelSubscriptionMessage, payloadContext PayloadContext)
-	onEventChannelCheer                                     func(event EventChannelCheer, payloadContext PayloadContext)
-	onEventChannelRaid                                      func(event EventChannelRaid, payloadContext PayloadContext)
-	onEventChannelBan                                       func(event EventChannelBan, payloadContext PayloadContext)
-	onEventChannelUnban                                     func(event EventChannelUnban, payloadContext PayloadContext)
-	onEventChannelModeratorAdd                              func(event EventChannelModeratorAdd, payloadContext PayloadContext)
-	onEventChannelModeratorRemove                           func(event EventChannelModeratorRemove, payloadContext PayloadContext)
-	onEventChannelVIPAdd                                    func(event EventChannelVIPAdd, payloadContext PayloadContext)
-	onEventChannelVIPRemove                                 func(event EventChannelVIPRemove, payloadContext PayloadContext)
-	onEventChannelChannelPointsCustomRewardAdd              func(event EventChannelChannelPointsCustomRewardAdd, payloadContext PayloadContext)
-	onEventChannelChannelPointsCustomRewardUpdate           func(event EventChannelChannelPointsCustomRewardUpdate, payloadContext PayloadContext)
-	onEventChannelChannelPointsCustomRewardRemove           func(event EventChannelChannelPointsCustomRewardRemove, payloadContext PayloadContext)
-	onEventChannelChannelPointsCustomRewardRedemptionAdd    func(event EventChannelChannelPointsCustomRewardRedemptionAdd, payloadContext PayloadContext)
-	onEventChannelChannelPointsCustomRewardRedemptionUpdate func(event EventChannelChannelPointsCustomRewardRedemptionUpdate, payloadContext PayloadContext)
-	onEventChannelChannelPointsAutomaticRewardRedemptionAdd func(event EventChannelChannelPointsAutomaticRewardRedemptionAdd, payloadContext PayloadContext)
-	onEventChannelPollBegin                                 func(event EventChannelPollBegin, payloadContext PayloadContext)
-	onEventChannelPollProgress                              func(event EventChannelPollProgress, payloadContext PayloadContext)
-	onEventChannelPollEnd                                   func(event EventChannelPollEnd, payloadContext PayloadContext)
-	onEventChannelPredictionBegin                           func(event EventChannelPredictionBegin, payloadContext PayloadContext)
-	onEventChannelPredictionProgress                        func(event EventChannelPredictionProgress, payloadContext PayloadContext)
-	onEventChannelPredictionLock                            func(event EventChannelPredictionLock, payloadContext PayloadContext)
-	onEventChannelPredictionEnd                             func(event EventChannelPredictionEnd, payloadContext PayloadContext)
-	onEventDropEntitlementGrant                             func(event []EventDropEntitlementGrant, payloadContext PayloadContext)
-	onEventExtensionBitsTransactionCreate                   func(event EventExtensionBitsTransactionCreate, payloadContext PayloadContext)
-	onEventChannelGoalBegin                                 func(event EventChannelGoalBegin, payloadContext PayloadContext)
-	onEventChannelGoalProgress                              func(event EventChannelGoalProgress, payloadContext PayloadContext)
-	onEventChannelGoalEnd                                   func(event EventChannelGoalEnd, payloadContext PayloadContext)
-	onEventChannelHypeTrainBegin                            func(event EventChannelHypeTrainBegin, payloadContext PayloadContext)
-	onEventChannelHypeTrainProgress                         func(event EventChannelHypeTrainProgress, payloadContext PayloadContext)
-	onEventChannelHypeTrainEnd                              func(event EventChannelHypeTrainEnd, payloadContext PayloadContext)
-	onEventStreamOnline                                     func(event EventStreamOnline, payloadContext PayloadContext)
-	onEventStreamOffline                                    func(event EventStreamOffline, payloadContext PayloadContext)
-	onEventUserAuthorizationGrant                           func(event EventUserAuthorizationGrant, payloadContext PayloadContext)
-	onEventUserAuthorizationRevoke                          func(event EventUserAuthorizationRevoke, payloadContext PayloadContext)
-	onEventUserUpdate                                       func(event EventUserUpdate, payloadContext PayloadContext)
-	onEventChannelCharityCampaignDonate                     func(event EventChannelCharityCampaignDonate, payloadContext PayloadContext)
-	onEventChannelCharityCampaignProgress                   func(event EventChannelCharityCampaignProgress, payloadContext PayloadContext)
-	onEventChannelCharityCampaignStart                      func(event EventChannelCharityCampaignStart, payloadContext PayloadContext)
-	onEventChannelCharityCampaignStop                       func(event EventChannelCharityCampaignStop, payloadContext PayloadContext)
-	onEventChannelShieldModeBegin                           func(event EventChannelShieldModeBegin, payloadContext PayloadContext)
-	onEventChannelShieldModeEnd                             func(event EventChannelShieldModeEnd, payloadContext PayloadContext)
-	onEventChannelShoutoutCreate                            func(event EventChannelShoutoutCreate, payloadContext PayloadContext)
-	onEventChannelShoutoutReceive                           func(event EventChannelShoutoutReceive, payloadContext PayloadContext)
-	onEventChannelModerate                                  func(event EventChannelModerate, payloadContext PayloadContext)
-	onEventChannelAdBreakBegin                              func(event EventChannelAdBreakBegin, payloadContext PayloadContext)
-	onEventChannelWarningAcknowledge                        func(event EventChannelWarningAcknowledge, payloadContext PayloadContext)
-	onEventChannelWarningSend                               func(event EventChannelWarningSend, payloadContext PayloadContext)
-	onEventChannelUnbanRequestCreate                        func(event EventChannelUnbanRequestCreate, payloadContext PayloadContext)
-	onEventChannelUnbanRequestResolve                       func(event EventChannelUnbanRequestResolve, payloadContext PayloadContext)
-	onEventAutomodMessageHold                               func(event EventAutomodMessageHold, payloadContext PayloadContext)
-	onEventAutomodMessageUpdate                             func(event EventAutomodMessageUpdate, payloadContext PayloadContext)
-	onEventAutomodSettingsUpdate                            func(event EventAutomodSettingsUpdate, payloadContext PayloadContext)
-	onEventAutomodTermsUpdate                               func(event EventAutomodTermsUpdate, payloadContext PayloadContext)
-	onEventChannelChatUserMessageHold                       func(event EventChannelChatUserMessageHold, payloadContext PayloadContext)
-	onEventChannelChatUserMessageUpdate                     func(event EventChannelChatUserMessageUpdate, payloadContext PayloadContext)
-	onEventChannelChatClear                                 func(event EventChannelChatClear, payloadContext PayloadContext)
-	onEventChannelChatClearUserMessages                     func(event EventChannelChatClearUserMessages, payloadContext PayloadContext)
-	onEventChannelChatMessage                               func(event EventChannelChatMessage, payloadContext PayloadContext)
-	onEventChannelChatMessageDelete                         func(event EventChannelChatMessageDelete, payloadContext PayloadContext)
-	onEventChannelChatNotification                          func(event EventChannelChatNotification, payloadContext PayloadContext)
-	onEventChannelChatSettingsUpdate                        func(event EventChannelChatSettingsUpdate, payloadContext PayloadContext)
-	onEventChannelSuspiciousUserMessage                     func(event EventChannelSuspiciousUserMessage, payloadContext PayloadContext)
-	onEventChannelSuspiciousUserUpdate                      func(event EventChannelSuspiciousUserUpdate, payloadContext PayloadContext)
-	onEventChannelSharedChatBegin                           func(event EventChannelSharedChatBegin, payloadContext PayloadContext)
-	onEventChannelSharedChatUpdate                          func(event EventChannelSharedChatUpdate, payloadContext PayloadContext)
-	onEventChannelSharedChatEnd                             func(event EventChannelSharedChatEnd, payloadContext PayloadContext)
-	onEventUserWhisperMessage                               func(event EventUserWhisperMessage, payloadContext PayloadContext)
-	onEventConduitShardDisabled                             func(event EventConduitShardDisabled, payloadContext PayloadContext)
+	onRawEvent func(event string, metadata MessageMetadata, subscription PayloadSubscription)
+	onEvent    func(event any, payloadContext PayloadContext)
+	eventCallbacks
 }
 
 func NewClient() *Client {
@@ -138,9 +134,13 @@ func NewClient() *Client {
 
 func NewClientWithUrl(url string) *Client {
 	return &Client{
-		Address:     url,
-		reconnected: make(chan struct{}),
-		onError:     func(err error) { fmt.Printf("ERROR: %v\n", err) },
+		Address:             url,
+		SubscriptionAddress: twitchEventSubUrl,
+		reconnected:         make(chan struct{}),
+		subscriptions:       make(map[string]PayloadSubscription),
+		clock:               SystemClock{},
+		maxReadBuffer:       defaultMaxReadBuffer,
+		onError:             func(err error) { fmt.Printf("ERROR: %v\n", err) },
 	}
 }
 
@@ -159,10 +159,26 @@ func (c *Client) ConnectWithContext(ctx context.Context) error {
 		return err
 	}
 	c.ws = ws
-	c.connected = true
+	c.connected.Store(true)
+	c.emitLifecycle(LifecycleEvent{Type: LifecycleConnected})
+
+	err = c.readLoop(ctx)
+	c.forgetSession()
+	c.closeBatchers()
+	c.emitLifecycle(LifecycleEvent{Type: LifecycleDisconnected, Err: err})
+	return err
+}
+
+func (c *Client) readLoop(ctx context.Context) error {
+	var pipeline *decodePipeline
+	if c.decodeWorkers > 0 {
+		pipeline = newDecodePipeline(c, c.decodeWorkers)
+		defer pipeline.close()
+	}
 
+	var buf bytes.Buffer
 	for {
-		_, data, err := c.ws.Read(ctx)
+		data, err := c.readFrame(ctx, &buf)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				return nil
@@ -180,19 +196,23 @@ func (c *Client) ConnectWithContext(ctx context.Context) error {
 			return fmt.Errorf("could not read message: %w", err)
 		}
 
-		err = c.handleMessage(data)
+		c.record(data)
+		if pipeline != nil {
+			pipeline.submit(data, c.receiveMessage())
+			continue
+		}
+		err = c.deliverSerialized(c.decodeMessage(data, c.receiveMessage()))
 		if err != nil {
-			c.onError(err)
+			c.reportError(err)
 		}
 	}
 }
 
 func (c *Client) Close() error {
 	defer func() { c.ws = nil }()
-	if !c.connected {
+	if !c.connected.Swap(false) {
 		return nil
 	}
-	c.connected = false
 
 	err := c.ws.Close(websocket.StatusNormalClosure, "Stopping Connection")
 
@@ -204,44 +224,127 @@ func (c *Client) Close() error {
 }
 
 func (c *Client) handleMessage(data []byte) error {
-	metadata, err := parseBaseMessage(data)
+	return c.deliverMessage(c.decodeMessage(data, c.receiveMessage()))
+}
+
+// deliverSerialized delivers a message read from a transport, one at a time, so messages
+// of the websocket and of webhook relays are never handled concurrently.
+func (c *Client) deliverSerialized(decoded decodedMessage) error {
+	c.deliverMu.Lock()
+	defer c.deliverMu.Unlock()
+	return c.deliverMessage(decoded)
+}
+
+func (c *Client) receiveMessage() time.Time {
+	receivedAt := c.now()
+	c.stats.incr(statMessages)
+	c.setLastMessage(receivedAt)
+	return receivedAt
+}
+
+// decodedMessage is a frame decoded by decodeMessage, waiting to be delivered.
+type decodedMessage struct {
+	receivedAt time.Time
+	metadata   MessageMetadata
+	message    any
+	event      *notificationEvent
+	err        error
+	// webhook is set for messages relayed from webhook callbacks, counted in the
+	// webhook stats.
+	webhook bool
+}
+
+// decodeMessage only decodes the frame, leaving the client state untouched, so frames
+// can be decoded concurrently as long as they are delivered in order.
+func (c *Client) decodeMessage(data []byte, receivedAt time.Time) decodedMessage {
+	decoded := decodedMessage{receivedAt: receivedAt}
+
+	timer := c.stageTimer
+	start := timer.start()
+	base, err := parseBaseMessage(data)
+	timer.observe(stageParse, start)
 	if err != nil {
-		return err
+		decoded.err = err
+		return decoded
 	}
+	metadata := base.Metadata
+	decoded.metadata = metadata
 
 	messageType := metadata.MessageType
 	genMessage, ok := messageTypeMap[messageType]
 	if !ok {
-		return fmt.Errorf("unknown message type %s: %s", messageType, string(data))
+		if !c.relaxedValidation {
+			decoded.err = c.newMessageError(metadata, nil, fmt.Errorf("unknown message type %s: %s", messageType, string(data)))
+		}
+		return decoded
 	}
 
-	message := genMessage()
-	err = json.Unmarshal(data, message)
+	if messageType == "notification" {
+		start = timer.start()
+		decoded.message, decoded.event, err = c.decodeNotification(base)
+		timer.observe(stageDecode, start)
+	} else {
+		decoded.message = genMessage()
+		err = decodePayload(decoded.message, base)
+	}
 	if err != nil {
-		return fmt.Errorf("could not unmarshal message into %s: %w", messageType, err)
+		decoded.message = nil
+		decoded.err = c.newMessageError(metadata, nil, fmt.Errorf("could not unmarshal message into %s: %w", messageType, err))
+	}
+	return decoded
+}
+
+func (c *Client) deliverMessage(decoded decodedMessage) error {
+	if decoded.err != nil || decoded.message == nil {
+		return decoded.err
+	}
+	metadata := decoded.metadata
+	receivedAt := decoded.receivedAt
+	event := decoded.event
+	stats := &c.stats
+	if decoded.webhook {
+		stats = &c.webhookStats
 	}
 
-	switch msg := message.(type) {
+	var err error
+	switch msg := decoded.message.(type) {
 	case *WelcomeMessage:
-		callFunc(c.onWelcome, *msg, metadata)
+		c.setSession(msg.Payload.Session)
+		c.emitLifecycle(LifecycleEvent{Type: LifecycleWelcomeReceived})
+		callFunc(c, c.onWelcome, *msg, metadata)
 	case *KeepAliveMessage:
-		callFunc(c.onKeepAlive, *msg, metadata)
+		stats.incr(statKeepAlives)
+		c.emitLifecycle(LifecycleEvent{Type: LifecycleKeepAlive})
+		callFunc(c, c.onKeepAlive, *msg, metadata)
 	case *NotificationMessage:
-		callFunc(c.onNotification, *msg, metadata)
+		callFunc(c, c.onNotification, *msg, metadata)
 
-		err = c.handleNotification(*msg)
+		correlationID := c.newCorrelationID(metadata)
+		err = c.handleNotification(*msg, event, receivedAt, correlationID, stats)
 		if err != nil {
-			return fmt.Errorf("could not handle notification: %w", err)
+			messageErr := c.newMessageError(metadata, &msg.Payload.Subscription, fmt.Errorf("could not handle notification: %w", err))
+			messageErr.CorrelationID = correlationID
+			return messageErr
 		}
 	case *ReconnectMessage:
-		callFunc(c.onReconnect, *msg, metadata)
+		stats.incr(statReconnects)
+		if c.metrics != nil {
+			c.metrics.Reconnect()
+		}
+		c.emitLifecycle(LifecycleEvent{Type: LifecycleReconnectRequested})
+		callFunc(c, c.onReconnect, *msg, metadata)
 
-		err = c.reconnect(*msg)
-		if err != nil {
-			return fmt.Errorf("could not handle reconnect: %w", err)
+		if c.connected.Load() {
+			err = c.reconnect(*msg)
+			if err != nil {
+				return c.newMessageError(metadata, nil, fmt.Errorf("could not handle reconnect: %w", err))
+			}
 		}
 	case *RevokeMessage:
-		callFunc(c.onRevoke, *msg, metadata)
+		stats.incr(statRevocations)
+		c.removeSubscription(msg.Payload.Subscription.ID)
+		c.emitLifecycle(LifecycleEvent{Type: LifecycleRevoked, Subscription: &msg.Payload.Subscription})
+		callFunc(c, c.onRevoke, *msg, metadata)
 	default:
 		return fmt.Errorf("unhandled %T message: %v", msg, msg)
 	}
@@ -250,7 +353,9 @@ func (c *Client) handleMessage(data []byte) error {
 }
 
 func (c *Client) reconnect(message ReconnectMessage) error {
+	c.mu.Lock()
 	c.Address = message.Payload.Session.ReconnectUrl
+	c.mu.Unlock()
 	ws, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("could not dial to reconnect")
@@ -259,231 +364,161 @@ func (c *Client) reconnect(message ReconnectMessage) error {
 	go func() {
 		_, data, err := ws.Read(c.ctx)
 		if err != nil {
-			c.onError(fmt.Errorf("reconnect failed: could not read reconnect websocket for welcome: %w", err))
+			c.reportError(fmt.Errorf("reconnect failed: could not read reconnect websocket for welcome: %w", err))
+			return
 		}
+		c.record(data)
 
-		metadata, err := parseBaseMessage(data)
+		var welcome WelcomeMessage
+		err = json.Unmarshal(data, &welcome)
 		if err != nil {
-			c.onError(fmt.Errorf("reconnect failed: could parse base message: %w", err))
+			c.reportError(fmt.Errorf("reconnect failed: could not parse welcome message: %w", err))
+			return
 		}
 
-		if metadata.MessageType != "session_welcome" {
-			c.onError(fmt.Errorf("reconnect failed: did not get a session_welcome message first: got message %s", metadata.MessageType))
+		if welcome.Metadata.MessageType != "session_welcome" {
+			c.reportError(fmt.Errorf("reconnect failed: did not get a session_welcome message first: got message %s", welcome.Metadata.MessageType))
 			return
 		}
 
 		c.reconnecting = true
 		c.ws.Close(websocket.StatusNormalClosure, "Stopping Connection")
 		c.ws = ws
+		c.setSession(welcome.Payload.Session)
+		c.emitLifecycle(LifecycleEvent{Type: LifecycleReconnectCompleted})
 		c.reconnected <- struct{}{}
 	}()
 
 	return nil
 }
 
-func (c *Client) handleNotification(message NotificationMessage) error {
-	data, err := message.Payload.Event.MarshalJSON()
-	if err != nil {
-		return fmt.Errorf("could not get event json: %w", err)
+func (c *Client) handleNotification(message NotificationMessage, event *notificationEvent, receivedAt time.Time, correlationID string, stats *clientStats) error {
+	latency := receivedAt.Sub(message.Metadata.MessageTimestamp)
+	stats.addNotification(latency)
+	if c.metrics != nil {
+		c.metrics.Notification(message.Payload.Subscription.Type, latency)
+	}
+
+	data := json.RawMessage("null")
+	if message.Payload.Event != nil {
+		data = *message.Payload.Event
 	}
 
 	subscription := message.Payload.Subscription
-	metadata, ok := subMetadata[subscription.Type]
-	if !ok {
+	c.trackSubscription(subscription)
+	if c.traffic != nil {
+		c.traffic.add(receivedAt, subscription)
+	}
+	if c.isMuted(subscription.Type) {
+		return nil
+	}
+	_, known := subMetadata[subscription.Type]
+	if !known && !c.relaxedValidation {
 		return fmt.Errorf("unknown subscription type %s", subscription.Type)
 	}
 
 	if c.onRawEvent != nil {
 		c.onRawEvent(string(data), message.Metadata, subscription)
 	}
+	c.publish(data, message.Metadata, subscription)
+	if !known {
+		return nil
+	}
 
-	var newEvent any
-	if metadata.EventGen != nil {
-		newEvent = metadata.EventGen()
-		err = json.Unmarshal(data, newEvent)
-		if err != nil {
-			return fmt.Errorf("could not unmarshal %s into %T: %w", subscription.Type, newEvent, err)
+	payloadContext := PayloadContext{
+		Metadata:      message.Metadata,
+		Subscription:  message.Payload.Subscription,
+		CorrelationID: correlationID,
+	}
+	if c.interner != nil {
+		c.interner.internContext(&payloadContext)
+	}
+	callFunc(c, c.onLatency, latency, payloadContext)
+
+	if !c.hasListener(subscription.Type) {
+		if c.debugLogger != nil {
+			c.logDispatch(payloadContext, false)
 		}
+		return nil
 	}
-	payloadContext := PayloadContext{
-		Metadata:     message.Metadata,
-		Subscription: message.Payload.Subscription,
-	}
-
-	switch event := newEvent.(type) {
-	case *EventChannelUpdate:
-		callFunc(c.onEventChannelUpdate, *event, payloadContext)
-	case *EventChannelFollow:
-		callFunc(c.onEventChannelFollow, *event, payloadContext)
-	case *EventChannelSubscribe:
-		callFunc(c.onEventChannelSubscribe, *event, payloadContext)
-	case *EventChannelSubscriptionEnd:
-		callFunc(c.onEventChannelSubscriptionEnd, *event, payloadContext)
-	case *EventChannelSubscriptionGift:
-		callFunc(c.onEventChannelSubscriptionGift, *event, payloadContext)
-	case *EventChannelSubscriptionMessage:
-		callFunc(c.onEventChannelSubscriptionMessage, *event, payloadContext)
-	case *EventChannelCheer:
-		callFunc(c.onEventChannelCheer, *event, payloadContext)
-	case *EventChannelRaid:
-		callFunc(c.onEventChannelRaid, *event, payloadContext)
-	case *EventChannelBan:
-		callFunc(c.onEventChannelBan, *event, payloadContext)
-	case *EventChannelUnban:
-		callFunc(c.onEventChannelUnban, *event, payloadContext)
-	case *EventChannelModeratorAdd:
-		callFunc(c.onEventChannelModeratorAdd, *event, payloadContext)
-	case *EventChannelModeratorRemove:
-		callFunc(c.onEventChannelModeratorRemove, *event, payloadContext)
-	case *EventChannelVIPAdd:
-		callFunc(c.onEventChannelVIPAdd, *event, payloadContext)
-	case *EventChannelVIPRemove:
-		callFunc(c.onEventChannelVIPRemove, *event, payloadContext)
-	case *EventChannelChannelPointsCustomRewardAdd:
-		callFunc(c.onEventChannelChannelPointsCustomRewardAdd, *event, payloadContext)
-	case *EventChannelChannelPointsCustomRewardUpdate:
-		callFunc(c.onEventChannelChannelPointsCustomRewardUpdate, *event, payloadContext)
-	case *EventChannelChannelPointsCustomRewardRemove:
-		callFunc(c.onEventChannelChannelPointsCustomRewardRemove, *event, payloadContext)
-	case *EventChannelChannelPointsCustomRewardRedemptionAdd:
-		callFunc(c.onEventChannelChannelPointsCustomRewardRedemptionAdd, *event, payloadContext)
-	case *EventChannelChannelPointsCustomRewardRedemptionUpdate:
-		callFunc(c.onEventChannelChannelPointsCustomRewardRedemptionUpdate, *event, payloadContext)
-	case *EventChannelChannelPointsAutomaticRewardRedemptionAdd:
-		callFunc(c.onEventChannelChannelPointsAutomaticRewardRedemptionAdd, *event, payloadContext)
-	case *EventChannelPollBegin:
-		callFunc(c.onEventChannelPollBegin, *event, payloadContext)
-	case *EventChannelPollProgress:
-		callFunc(c.onEventChannelPollProgress, *event, payloadContext)
-	case *EventChannelPollEnd:
-		callFunc(c.onEventChannelPollEnd, *event, payloadContext)
-	case *EventChannelPredictionBegin:
-		callFunc(c.onEventChannelPredictionBegin, *event, payloadContext)
-	case *EventChannelPredictionProgress:
-		callFunc(c.onEventChannelPredictionProgress, *event, payloadContext)
-	case *EventChannelPredictionLock:
-		callFunc(c.onEventChannelPredictionLock, *event, payloadContext)
-	case *EventChannelPredictionEnd:
-		callFunc(c.onEventChannelPredictionEnd, *event, payloadContext)
-	case *[]EventDropEntitlementGrant:
-		callFunc(c.onEventDropEntitlementGrant, *event, payloadContext)
-	case *EventExtensionBitsTransactionCreate:
-		callFunc(c.onEventExtensionBitsTransactionCreate, *event, payloadContext)
-	case *EventChannelGoalBegin:
-		callFunc(c.onEventChannelGoalBegin, *event, payloadContext)
-	case *EventChannelGoalProgress:
-		callFunc(c.onEventChannelGoalProgress, *event, payloadContext)
-	case *EventChannelGoalEnd:
-		callFunc(c.onEventChannelGoalEnd, *event, payloadContext)
-	case *EventChannelHypeTrainBegin:
-		callFunc(c.onEventChannelHypeTrainBegin, *event, payloadContext)
-	case *EventChannelHypeTrainProgress:
-		callFunc(c.onEventChannelHypeTrainProgress, *event, payloadContext)
-	case *EventChannelHypeTrainEnd:
-		callFunc(c.onEventChannelHypeTrainEnd, *event, payloadContext)
-	case *EventStreamOnline:
-		callFunc(c.onEventStreamOnline, *event, payloadContext)
-	case *EventStreamOffline:
-		callFunc(c.onEventStreamOffline, *event, payloadContext)
-	case *EventUserAuthorizationGrant:
-		callFunc(c.onEventUserAuthorizationGrant, *event, payloadContext)
-	case *EventUserAuthorizationRevoke:
-		callFunc(c.onEventUserAuthorizationRevoke, *event, payloadContext)
-	case *EventUserUpdate:
-		callFunc(c.onEventUserUpdate, *event, payloadContext)
-	case *EventChannelCharityCampaignDonate:
-		callFunc(c.onEventChannelCharityCampaignDonate, *event, payloadContext)
-	case *EventChannelCharityCampaignProgress:
-		callFunc(c.onEventChannelCharityCampaignProgress, *event, payloadContext)
-	case *EventChannelCharityCampaignStart:
-		callFunc(c.onEventChannelCharityCampaignStart, *event, payloadContext)
-	case *EventChannelCharityCampaignStop:
-		callFunc(c.onEventChannelCharityCampaignStop, *event, payloadContext)
-	case *EventChannelShieldModeBegin:
-		callFunc(c.onEventChannelShieldModeBegin, *event, payloadContext)
-	case *EventChannelShieldModeEnd:
-		callFunc(c.onEventChannelShieldModeEnd, *event, payloadContext)
-	case *EventChannelShoutoutCreate:
-		callFunc(c.onEventChannelShoutoutCreate, *event, payloadContext)
-	case *EventChannelShoutoutReceive:
-		callFunc(c.onEventChannelShoutoutReceive, *event, payloadContext)
-	case *EventChannelModerate:
-		callFunc(c.onEventChannelModerate, *event, payloadContext)
-	case *EventChannelAdBreakBegin:
-		callFunc(c.onEventChannelAdBreakBegin, *event, payloadContext)
-	case *EventChannelWarningAcknowledge:
-		callFunc(c.onEventChannelWarningAcknowledge, *event, payloadContext)
-	case *EventChannelWarningSend:
-		callFunc(c.onEventChannelWarningSend, *event, payloadContext)
-	case *EventChannelUnbanRequestCreate:
-		callFunc(c.onEventChannelUnbanRequestCreate, *event, payloadContext)
-	case *EventChannelUnbanRequestResolve:
-		callFunc(c.onEventChannelUnbanRequestResolve, *event, payloadContext)
-	case *EventAutomodMessageHold:
-		callFunc(c.onEventAutomodMessageHold, *event, payloadContext)
-	case *EventAutomodMessageUpdate:
-		callFunc(c.onEventAutomodMessageUpdate, *event, payloadContext)
-	case *EventAutomodSettingsUpdate:
-		callFunc(c.onEventAutomodSettingsUpdate, *event, payloadContext)
-	case *EventAutomodTermsUpdate:
-		callFunc(c.onEventAutomodTermsUpdate, *event, payloadContext)
-	case *EventChannelChatUserMessageHold:
-		callFunc(c.onEventChannelChatUserMessageHold, *event, payloadContext)
-	case *EventChannelChatUserMessageUpdate:
-		callFunc(c.onEventChannelChatUserMessageUpdate, *event, payloadContext)
-	case *EventChannelChatClear:
-		callFunc(c.onEventChannelChatClear, *event, payloadContext)
-	case *EventChannelChatClearUserMessages:
-		callFunc(c.onEventChannelChatClearUserMessages, *event, payloadContext)
-	case *EventChannelChatMessage:
-		callFunc(c.onEventChannelChatMessage, *event, payloadContext)
-	case *EventChannelChatMessageDelete:
-		callFunc(c.onEventChannelChatMessageDelete, *event, payloadContext)
-	case *EventChannelChatNotification:
-		callFunc(c.onEventChannelChatNotification, *event, payloadContext)
-	case *EventChannelChatSettingsUpdate:
-		callFunc(c.onEventChannelChatSettingsUpdate, *event, payloadContext)
-	case *EventChannelSuspiciousUserMessage:
-		callFunc(c.onEventChannelSuspiciousUserMessage, *event, payloadContext)
-	case *EventChannelSuspiciousUserUpdate:
-		callFunc(c.onEventChannelSuspiciousUserUpdate, *event, payloadContext)
-	case *EventChannelSharedChatBegin:
-		callFunc(c.onEventChannelSharedChatBegin, *event, payloadContext)
-	case *EventChannelSharedChatUpdate:
-		callFunc(c.onEventChannelSharedChatUpdate, *event, payloadContext)
-	case *EventChannelSharedChatEnd:
-		callFunc(c.onEventChannelSharedChatEnd, *event, payloadContext)
-	case *EventUserWhisperMessage:
-		callFunc(c.onEventUserWhisperMessage, *event, payloadContext)
-	case *EventConduitShardDisabled:
-		callFunc(c.onEventConduitShardDisabled, *event, payloadContext)
-	default:
-		c.onError(fmt.Errorf("unknown event type %s", subscription.Type))
+
+	timer := c.stageTimer
+	start := timer.start()
+	newEvent, err := c.decodeEvent(event, subscription.Type)
+	timer.extend(stageDecode, start)
+	if err != nil {
+		return err
+	}
+
+	start = timer.start()
+	if c.interner != nil {
+		c.interner.internEvent(newEvent)
+	}
+
+	dispatched, knownEvent := c.dispatchEvent(newEvent, payloadContext)
+	if !knownEvent {
+		messageErr := c.newMessageError(message.Metadata, &subscription, fmt.Errorf("unknown event type %s", subscription.Type))
+		messageErr.CorrelationID = correlationID
+		c.reportError(messageErr)
+	}
+	pointerHandler := c.pointerHandlers[subscription.Type]
+	if pointerHandler != nil {
+		dispatched = callFunc(c, pointerHandler, newEvent, payloadContext) || dispatched
+	}
+	if newEvent != nil && (c.onEvent != nil || c.hasBatchers() || c.hasEventListeners()) {
+		value := eventValue(newEvent)
+		callFunc(c, c.onEvent, value, payloadContext)
+		c.callEventListeners(value, payloadContext)
+		c.batchEvent(value, payloadContext)
+	}
+	if pointerHandler == nil {
+		c.releaseEvent(subscription.Type, newEvent)
+	}
+	timer.observe(stageDispatch, start)
+
+	if c.debugLogger != nil {
+		c.logDispatch(payloadContext, dispatched)
 	}
 
 	return nil
 }
 
+func (c *Client) reportError(err error) {
+	c.reportErrorTo(&c.stats, err)
+}
+
+func (c *Client) reportErrorTo(stats *clientStats, err error) {
+	stats.incr(statErrors)
+	if c.metrics != nil {
+		c.metrics.Error()
+	}
+	c.onError(err)
+}
+
 func (c *Client) dial() (*websocket.Conn, error) {
-	ws, _, err := websocket.Dial(c.ctx, c.Address, nil)
+	address := c.address()
+	ws, _, err := websocket.Dial(c.ctx, address, nil)
 	if err != nil {
-		return nil, fmt.Errorf("could not dial %s: %w", c.Address, err)
+		return nil, fmt.Errorf("could not dial %s: %w", address, err)
 	}
 	return ws, nil
 }
 
-func parseBaseMessage(data []byte) (MessageMetadata, error) {
-	type BaseMessage struct {
-		Metadata MessageMetadata `json:"metadata"`
-	}
+// address returns the Address, which the read loop changes on reconnects.
+func (c *Client) address() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Address
+}
 
-	var baseMessage BaseMessage
-	err := json.Unmarshal(data, &baseMessage)
+func parseBaseMessage(data []byte) (baseMessage, error) {
+	var message baseMessage
+	err := json.Unmarshal(data, &message)
 	if err != nil {
-		return MessageMetadata{}, fmt.Errorf("could not unmarshal basemessage to get message type: %w", err)
+		return baseMessage{}, fmt.Errorf("could not unmarshal basemessage to get message type: %w", err)
 	}
 
-	return baseMessage.Metadata, nil
+	return message, nil
 }
 
 func (c *Client) OnError(callback func(err error)) {
@@ -510,298 +545,16 @@ func (c *Client) OnRevoke(callback func(message RevokeMessage, metadata MessageM
 	c.onRevoke = callback
 }
 
-func (c *Client) OnRawEvent(callback func(event string, metadata MessageMetadata, subscription PayloadSubscription)) {
-	c.onRawEvent = callback
-}
-
-func (c *Client) OnEventChannelUpdate(callback func(event EventChannelUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelUpdate = callback
-}
-
-func (c *Client) OnEventChannelFollow(callback func(event EventChannelFollow, payloadContext PayloadContext)) {
-	c.onEventChannelFollow = callback
-}
-
-func (c *Client) OnEventChannelSubscribe(callback func(event EventChannelSubscribe, payloadContext PayloadContext)) {
-	c.onEventChannelSubscribe = callback
-}
-
-func (c *Client) OnEventChannelSubscriptionEnd(callback func(event EventChannelSubscriptionEnd, payloadContext PayloadContext)) {
-	c.onEventChannelSubscriptionEnd = callback
-}
-
-func (c *Client) OnEventChannelSubscriptionGift(callback func(event EventChannelSubscriptionGift, payloadContext PayloadContext)) {
-	c.onEventChannelSubscriptionGift = callback
-}
-
-func (c *Client) OnEventChannelSubscriptionMessage(callback func(event EventChannelSubscriptionMessage, payloadContext PayloadContext)) {
-	c.onEventChannelSubscriptionMessage = callback
-}
-
-func (c *Client) OnEventChannelCheer(callback func(event EventChannelCheer, payloadContext PayloadContext)) {
-	c.onEventChannelCheer = callback
-}
-
-func (c *Client) OnEventChannelRaid(callback func(event EventChannelRaid, payloadContext PayloadContext)) {
-	c.onEventChannelRaid = callback
-}
-
-func (c *Client) OnEventChannelBan(callback func(event EventChannelBan, payloadContext PayloadContext)) {
-	c.onEventChannelBan = callback
-}
-
-func (c *Client) OnEventChannelUnban(callback func(event EventChannelUnban, payloadContext PayloadContext)) {
-	c.onEventChannelUnban = callback
-}
-
-func (c *Client) OnEventChannelModeratorAdd(callback func(event EventChannelModeratorAdd, payloadContext PayloadContext)) {
-	c.onEventChannelModeratorAdd = callback
-}
-
-func (c *Client) OnEventChannelModeratorRemove(callback func(event EventChannelModeratorRemove, payloadContext PayloadContext)) {
-	c.onEventChannelModeratorRemove = callback
-}
-
-func (c *Client) OnEventChannelVIPAdd(callback func(event EventChannelVIPAdd, payloadContext PayloadContext)) {
-	c.onEventChannelVIPAdd = callback
-}
-
-func (c *Client) OnEventChannelVIPRemove(callback func(event EventChannelVIPRemove, payloadContext PayloadContext)) {
-	c.onEventChannelVIPRemove = callback
-}
-
-func (c *Client) OnEventChannelChannelPointsCustomRewardAdd(callback func(event EventChannelChannelPointsCustomRewardAdd, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsCustomRewardAdd = callback
-}
-
-func (c *Client) OnEventChannelChannelPointsCustomRewardUpdate(callback func(event EventChannelChannelPointsCustomRewardUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsCustomRewardUpdate = callback
-}
-
-func (c *Client) OnEventChannelChannelPointsCustomRewardRemove(callback func(event EventChannelChannelPointsCustomRewardRemove, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsCustomRewardRemove = callback
-}
-
-func (c *Client) OnEventChannelChannelPointsCustomRewardRedemptionAdd(callback func(event EventChannelChannelPointsCustomRewardRedemptionAdd, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsCustomRewardRedemptionAdd = callback
-}
-
-func (c *Client) OnEventChannelChannelPointsCustomRewardRedemptionUpdate(callback func(event EventChannelChannelPointsCustomRewardRedemptionUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsCustomRewardRedemptionUpdate = callback
+func (c *Client) OnLatency(callback func(latency time.Duration, payloadContext PayloadContext)) {
+	c.onLatency = callback
 }
 
-func (c *Client) OnEventChannelChannelPointsAutomaticRewardRedemptionAdd(callback func(event EventChannelChannelPointsAutomaticRewardRedemptionAdd, payloadContext PayloadContext)) {
-	c.onEventChannelChannelPointsAutomaticRewardRedemptionAdd = callback
-}
-
-func (c *Client) OnEventChannelPollBegin(callback func(event EventChannelPollBegin, payloadContext PayloadContext)) {
-	c.onEventChannelPollBegin = callback
-}
-
-func (c *Client) OnEventChannelPollProgress(callback func(event EventChannelPollProgress, payloadContext PayloadContext)) {
-	c.onEventChannelPollProgress = callback
-}
-
-func (c *Client) OnEventChannelPollEnd(callback func(event EventChannelPollEnd, payloadContext PayloadContext)) {
-	c.onEventChannelPollEnd = callback
-}
-
-func (c *Client) OnEventChannelPredictionBegin(callback func(event EventChannelPredictionBegin, payloadContext PayloadContext)) {
-	c.onEventChannelPredictionBegin = callback
-}
-
-func (c *Client) OnEventChannelPredictionProgress(callback func(event EventChannelPredictionProgress, payloadContext PayloadContext)) {
-	c.onEventChannelPredictionProgress = callback
-}
-
-func (c *Client) OnEventChannelPredictionLock(callback func(event EventChannelPredictionLock, payloadContext PayloadContext)) {
-	c.onEventChannelPredictionLock = callback
-}
-
-func (c *Client) OnEventChannelPredictionEnd(callback func(event EventChannelPredictionEnd, payloadContext PayloadContext)) {
-	c.onEventChannelPredictionEnd = callback
-}
-
-func (c *Client) OnEventDropEntitlementGrant(callback func(event []EventDropEntitlementGrant, payloadContext PayloadContext)) {
-	c.onEventDropEntitlementGrant = callback
-}
-
-func (c *Client) OnEventExtensionBitsTransactionCreate(callback func(event EventExtensionBitsTransactionCreate, payloadContext PayloadContext)) {
-	c.onEventExtensionBitsTransactionCreate = callback
-}
-
-func (c *Client) OnEventChannelGoalBegin(callback func(event EventChannelGoalBegin, payloadContext PayloadContext)) {
-	c.onEventChannelGoalBegin = callback
-}
-
-func (c *Client) OnEventChannelGoalProgress(callback func(event EventChannelGoalProgress, payloadContext PayloadContext)) {
-	c.onEventChannelGoalProgress = callback
-}
-
-func (c *Client) OnEventChannelGoalEnd(callback func(event EventChannelGoalEnd, payloadContext PayloadContext)) {
-	c.onEventChannelGoalEnd = callback
-}
-
-func (c *Client) OnEventChannelHypeTrainBegin(callback func(event EventChannelHypeTrainBegin, payloadContext PayloadContext)) {
-	c.onEventChannelHypeTrainBegin = callback
-}
-
-func (c *Client) OnEventChannelHypeTrainProgress(callback func(event EventChannelHypeTrainProgress, payloadContext PayloadContext)) {
-	c.onEventChannelHypeTrainProgress = callback
-}
-
-func (c *Client) OnEventChannelHypeTrainEnd(callback func(event EventChannelHypeTrainEnd, payloadContext PayloadContext)) {
-	c.onEventChannelHypeTrainEnd = callback
-}
-
-func (c *Client) OnEventStreamOnline(callback func(event EventStreamOnline, payloadContext PayloadContext)) {
-	c.onEventStreamOnline = callback
-}
-
-func (c *Client) OnEventStreamOffline(callback func(event EventStreamOffline, payloadContext PayloadContext)) {
-	c.onEventStreamOffline = callback
-}
-
-func (c *Client) OnEventUserAuthorizationGrant(callback func(event EventUserAuthorizationGrant, payloadContext PayloadContext)) {
-	c.onEventUserAuthorizationGrant = callback
-}
-
-func (c *Client) OnEventUserAuthorizationRevoke(callback func(event EventUserAuthorizationRevoke, payloadContext PayloadContext)) {
-	c.onEventUserAuthorizationRevoke = callback
-}
-
-func (c *Client) OnEventUserUpdate(callback func(event EventUserUpdate, payloadContext PayloadContext)) {
-	c.onEventUserUpdate = callback
-}
-
-func (c *Client) OnEventChannelCharityCampaignDonate(callback func(event EventChannelCharityCampaignDonate, payloadContext PayloadContext)) {
-	c.onEventChannelCharityCampaignDonate = callback
-}
-
-func (c *Client) OnEventChannelCharityCampaignProgress(callback func(event EventChannelCharityCampaignProgress, payloadContext PayloadContext)) {
-	c.onEventChannelCharityCampaignProgress = callback
-}
-
-func (c *Client) OnEventChannelCharityCampaignStart(callback func(event EventChannelCharityCampaignStart, payloadContext PayloadContext)) {
-	c.onEventChannelCharityCampaignStart = callback
-}
-
-func (c *Client) OnEventChannelCharityCampaignStop(callback func(event EventChannelCharityCampaignStop, payloadContext PayloadContext)) {
-	c.onEventChannelCharityCampaignStop = callback
-}
-
-func (c *Client) OnEventChannelShieldModeBegin(callback func(event EventChannelShieldModeBegin, payloadContext PayloadContext)) {
-	c.onEventChannelShieldModeBegin = callback
-}
-
-func (c *Client) OnEventChannelShieldModeEnd(callback func(event EventChannelShieldModeEnd, payloadContext PayloadContext)) {
-	c.onEventChannelShieldModeEnd = callback
-}
-
-func (c *Client) OnEventChannelShoutoutCreate(callback func(event EventChannelShoutoutCreate, payloadContext PayloadContext)) {
-	c.onEventChannelShoutoutCreate = callback
-}
-
-func (c *Client) OnEventChannelShoutoutReceive(callback func(event EventChannelShoutoutReceive, payloadContext PayloadContext)) {
-	c.onEventChannelShoutoutReceive = callback
-}
-
-func (c *Client) OnEventChannelModerate(callback func(event EventChannelModerate, payloadContext PayloadContext)) {
-	c.onEventChannelModerate = callback
-}
-
-func (c *Client) OnEventChannelAdBreakBegin(callback func(event EventChannelAdBreakBegin, payloadContext PayloadContext)) {
-	c.onEventChannelAdBreakBegin = callback
-}
-
-func (c *Client) OnEventChannelWarningAcknowledge(callback func(event EventChannelWarningAcknowledge, payloadContext PayloadContext)) {
-	c.onEventChannelWarningAcknowledge = callback
-}
-
-func (c *Client) OnEventChannelWarningSend(callback func(event EventChannelWarningSend, payloadContext PayloadContext)) {
-	c.onEventChannelWarningSend = callback
-}
-
-func (c *Client) OnEventChannelUnbanRequestCreate(callback func(event EventChannelUnbanRequestCreate, payloadContext PayloadContext)) {
-	c.onEventChannelUnbanRequestCreate = callback
-}
-
-func (c *Client) OnEventChannelUnbanRequestResolve(callback func(event EventChannelUnbanRequestResolve, payloadContext PayloadContext)) {
-	c.onEventChannelUnbanRequestResolve = callback
-}
-
-func (c *Client) OnEventAutomodMessageHold(callback func(event EventAutomodMessageHold, payloadContext PayloadContext)) {
-	c.onEventAutomodMessageHold = callback
-}
-
-func (c *Client) OnEventAutomodMessageUpdate(callback func(event EventAutomodMessageUpdate, payloadContext PayloadContext)) {
-	c.onEventAutomodMessageUpdate = callback
-}
-
-func (c *Client) OnEventAutomodSettingsUpdate(callback func(event EventAutomodSettingsUpdate, payloadContext PayloadContext)) {
-	c.onEventAutomodSettingsUpdate = callback
-}
-
-func (c *Client) OnEventAutomodTermsUpdate(callback func(event EventAutomodTermsUpdate, payloadContext PayloadContext)) {
-	c.onEventAutomodTermsUpdate = callback
-}
-
-func (c *Client) OnEventChannelChatUserMessageHold(callback func(event EventChannelChatUserMessageHold, payloadContext PayloadContext)) {
-	c.onEventChannelChatUserMessageHold = callback
-}
-
-func (c *Client) OnEventChannelChatUserMessageUpdate(callback func(event EventChannelChatUserMessageUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelChatUserMessageUpdate = callback
-}
-
-func (c *Client) OnEventChannelChatClear(callback func(event EventChannelChatClear, payloadContext PayloadContext)) {
-	c.onEventChannelChatClear = callback
-}
-
-func (c *Client) OnEventChannelChatClearUserMessages(callback func(event EventChannelChatClearUserMessages, payloadContext PayloadContext)) {
-	c.onEventChannelChatClearUserMessages = callback
-}
-
-func (c *Client) OnEventChannelChatMessage(callback func(event EventChannelChatMessage, payloadContext PayloadContext)) {
-	c.onEventChannelChatMessage = callback
-}
-
-func (c *Client) OnEventChannelChatMessageDelete(callback func(event EventChannelChatMessageDelete, payloadContext PayloadContext)) {
-	c.onEventChannelChatMessageDelete = callback
-}
-
-func (c *Client) OnEventChannelChatNotification(callback func(event EventChannelChatNotification, payloadContext PayloadContext)) {
-	c.onEventChannelChatNotification = callback
-}
-
-func (c *Client) OnEventChannelChatSettingsUpdate(callback func(event EventChannelChatSettingsUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelChatSettingsUpdate = callback
-}
-
-func (c *Client) OnEventChannelSuspiciousUserMessage(callback func(event EventChannelSuspiciousUserMessage, payloadContext PayloadContext)) {
-	c.onEventChannelSuspiciousUserMessage = callback
-}
-
-func (c *Client) OnEventChannelSuspiciousUserUpdate(callback func(event EventChannelSuspiciousUserUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelSuspiciousUserUpdate = callback
-}
-
-func (c *Client) OnEventChannelSharedChatBegin(callback func(event EventChannelSharedChatBegin, payloadContext PayloadContext)) {
-	c.onEventChannelSharedChatBegin = callback
-}
-
-func (c *Client) OnEventChannelSharedChatUpdate(callback func(event EventChannelSharedChatUpdate, payloadContext PayloadContext)) {
-	c.onEventChannelSharedChatUpdate = callback
-}
-
-func (c *Client) OnEventChannelSharedChatEnd(callback func(event EventChannelSharedChatEnd, payloadContext PayloadContext)) {
-	c.onEventChannelSharedChatEnd = callback
-}
-
-func (c *Client) OnEventUserWhisperMessage(callback func(event EventUserWhisperMessage, payloadContext PayloadContext)) {
-	c.onEventUserWhisperMessage = callback
+func (c *Client) OnRawEvent(callback func(event string, metadata MessageMetadata, subscription PayloadSubscription)) {
+	c.onRawEvent = callback
 }
 
-func (c *Client) OnEventConduitShardDisabled(callback func(event EventConduitShardDisabled, payloadContext PayloadContext)) {
-	c.onEventConduitShardDisabled = callback
+// OnEvent is called with every decoded event, in addition to its typed callback. The
+// event is the value type, e.g. EventChannelFollow.
+func (c *Client) OnEvent(callback func(event any, payloadContext PayloadContext)) {
+	c.onEvent = callback
 }
@@ -2,13 +2,38 @@ package twitch_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
 )
 
-func assertSpecificEventOccurred(t *testing.T, register func(client *twitch.Client, ch chan struct{}), event twitch.EventSubscription, suffixes ...string) {
+// expectEventOccurred connects a client to a server sending the notification of the
+// subscription and returns the event the client decodes from it.
+func expectEventOccurred[T any](t *testing.T, subscription twitch.EventSubscription, suffixes ...string) T {
+	t.Helper()
+	client := newClientWithWelcome(t, "", subscription, getTestEventData(subscription, suffixes...))
+	events := twitchtest.ExpectEvent[T](t, client)
+	go connect(t, client)
+
+	select {
+	case event := <-events:
+		assert.NotZero(t, event, "%s was not decoded", subscription)
+		return event
+	case <-time.After(time.Second):
+		var zero T
+		t.Fatalf("%T did not occur", zero)
+		return zero
+	}
+}
+
+// assertCallbackCalled connects a client to a server sending the notification of the
+// subscription, after register sets the callback which closes ch. Events are expected
+// with expectEventOccurred instead.
+func assertCallbackCalled(t *testing.T, register func(client *twitch.Client, ch chan struct{}), subscription twitch.EventSubscription, suffixes ...string) {
 	assertEventOccured(t, func(ch chan struct{}) {
-		client := newClientWithWelcome(t, "", event, getTestEventData(event, suffixes...))
+		client := newClientWithWelcome(t, "", subscription, getTestEventData(subscription, suffixes...))
 		register(client, ch)
 		go connect(t, client)
 	})
@@ -17,7 +42,7 @@ func assertSpecificEventOccurred(t *testing.T, register func(client *twitch.Clie
 func TestNotification(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
 		client.OnNotification(func(message twitch.NotificationMessage, _ twitch.MessageMetadata) {
 			close(ch)
 		})
@@ -27,7 +52,7 @@ func TestNotification(t *testing.T) {
 func TestUnknownSubscription(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
 		client.OnError(func(err error) {
 			close(ch)
 		})
@@ -37,779 +62,491 @@ func TestUnknownSubscription(t *testing.T) {
 func TestEventChannelUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelUpdate(func(event twitch.EventChannelUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelUpdate)
+	expectEventOccurred[twitch.EventChannelUpdate](t, twitch.SubChannelUpdate)
 }
 
 func TestEventChannelFollow(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelFollow(func(event twitch.EventChannelFollow, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelFollow)
+	expectEventOccurred[twitch.EventChannelFollow](t, twitch.SubChannelFollow)
 }
 
 func TestEventChannelSubscribe(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscribe(func(event twitch.EventChannelSubscribe, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscribe)
+	expectEventOccurred[twitch.EventChannelSubscribe](t, twitch.SubChannelSubscribe)
 }
 
 func TestEventChannelSubscriptionEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscriptionEnd(func(event twitch.EventChannelSubscriptionEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscriptionEnd)
+	expectEventOccurred[twitch.EventChannelSubscriptionEnd](t, twitch.SubChannelSubscriptionEnd)
 }
 
 func TestEventChannelSubscriptionGift(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscriptionGift(func(event twitch.EventChannelSubscriptionGift, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscriptionGift)
+	expectEventOccurred[twitch.EventChannelSubscriptionGift](t, twitch.SubChannelSubscriptionGift)
 }
 
 func TestEventChannelSubscriptionGiftAnon(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscriptionGift(func(event twitch.EventChannelSubscriptionGift, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscriptionGift, "anon")
+	expectEventOccurred[twitch.EventChannelSubscriptionGift](t, twitch.SubChannelSubscriptionGift, "anon")
 }
 
 func TestEventChannelSubscriptionMessage(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscriptionMessage(func(event twitch.EventChannelSubscriptionMessage, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscriptionMessage)
+	expectEventOccurred[twitch.EventChannelSubscriptionMessage](t, twitch.SubChannelSubscriptionMessage)
 }
 
 func TestEventChannelSubscriptionMessageNoStreak(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSubscriptionMessage(func(event twitch.EventChannelSubscriptionMessage, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSubscriptionMessage, "nostreak")
+	expectEventOccurred[twitch.EventChannelSubscriptionMessage](t, twitch.SubChannelSubscriptionMessage, "nostreak")
 }
 
 func TestEventChannelCheer(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCheer(func(event twitch.EventChannelCheer, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCheer)
+	expectEventOccurred[twitch.EventChannelCheer](t, twitch.SubChannelCheer)
 }
 
 func TestEventChannelCheerAnon(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCheer(func(event twitch.EventChannelCheer, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCheer, "anon")
+	expectEventOccurred[twitch.EventChannelCheer](t, twitch.SubChannelCheer, "anon")
 }
 
 func TestEventChannelRaid(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelRaid)
+	expectEventOccurred[twitch.EventChannelRaid](t, twitch.SubChannelRaid)
 }
 
 func TestEventChannelBan(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelBan(func(event twitch.EventChannelBan, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelBan)
+	expectEventOccurred[twitch.EventChannelBan](t, twitch.SubChannelBan)
 }
 
 func TestEventChannelUnban(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelUnban(func(event twitch.EventChannelUnban, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelUnban)
+	expectEventOccurred[twitch.EventChannelUnban](t, twitch.SubChannelUnban)
 }
 
 func TestEventChannelModeratorAdd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelModeratorAdd(func(event twitch.EventChannelModeratorAdd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelModeratorAdd)
+	expectEventOccurred[twitch.EventChannelModeratorAdd](t, twitch.SubChannelModeratorAdd)
 }
 
 func TestEventChannelModeratorRemove(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelModeratorRemove(func(event twitch.EventChannelModeratorRemove, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelModeratorRemove)
+	expectEventOccurred[twitch.EventChannelModeratorRemove](t, twitch.SubChannelModeratorRemove)
 }
 
 func TestEventChannelVIPAdd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelVIPAdd(func(event twitch.EventChannelVIPAdd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelVIPAdd)
+	expectEventOccurred[twitch.EventChannelVIPAdd](t, twitch.SubChannelVIPAdd)
 }
 
 func TestEventChannelVIPRemove(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelVIPRemove(func(event twitch.EventChannelVIPRemove, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelVIPRemove)
+	expectEventOccurred[twitch.EventChannelVIPRemove](t, twitch.SubChannelVIPRemove)
 }
 
 func TestEventChannelChannelPointsCustomRewardAdd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsCustomRewardAdd(func(event twitch.EventChannelChannelPointsCustomRewardAdd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsCustomRewardAdd)
+	expectEventOccurred[twitch.EventChannelChannelPointsCustomRewardAdd](t, twitch.SubChannelChannelPointsCustomRewardAdd)
 }
 
 func TestEventChannelChannelPointsCustomRewardUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsCustomRewardUpdate(func(event twitch.EventChannelChannelPointsCustomRewardUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsCustomRewardUpdate)
+	expectEventOccurred[twitch.EventChannelChannelPointsCustomRewardUpdate](t, twitch.SubChannelChannelPointsCustomRewardUpdate)
 }
 
 func TestEventChannelChannelPointsCustomRewardRemove(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsCustomRewardRemove(func(event twitch.EventChannelChannelPointsCustomRewardRemove, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsCustomRewardRemove)
+	expectEventOccurred[twitch.EventChannelChannelPointsCustomRewardRemove](t, twitch.SubChannelChannelPointsCustomRewardRemove)
 }
 
 func TestEventChannelChannelPointsCustomRewardRedemptionAdd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsCustomRewardRedemptionAdd(func(event twitch.EventChannelChannelPointsCustomRewardRedemptionAdd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsCustomRewardRedemptionAdd)
+	expectEventOccurred[twitch.EventChannelChannelPointsCustomRewardRedemptionAdd](t, twitch.SubChannelChannelPointsCustomRewardRedemptionAdd)
 }
 
 func TestEventChannelChannelPointsCustomRewardRedemptionUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsCustomRewardRedemptionUpdate(func(event twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsCustomRewardRedemptionUpdate)
+	expectEventOccurred[twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate](t, twitch.SubChannelChannelPointsCustomRewardRedemptionUpdate)
 }
 
 func TestEventChannelChannelPointsAutomaticRewardRedemptionAdd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChannelPointsAutomaticRewardRedemptionAdd(func(event twitch.EventChannelChannelPointsAutomaticRewardRedemptionAdd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChannelPointsAutomaticRewardRedemptionAdd)
+	expectEventOccurred[twitch.EventChannelChannelPointsAutomaticRewardRedemptionAdd](t, twitch.SubChannelChannelPointsAutomaticRewardRedemptionAdd)
 }
 
 func TestEventChannelPollBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPollBegin(func(event twitch.EventChannelPollBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPollBegin)
+	expectEventOccurred[twitch.EventChannelPollBegin](t, twitch.SubChannelPollBegin)
 }
 
 func TestEventChannelPollProgress(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPollProgress(func(event twitch.EventChannelPollProgress, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPollProgress)
+	expectEventOccurred[twitch.EventChannelPollProgress](t, twitch.SubChannelPollProgress)
 }
 
 func TestEventChannelPollEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPollEnd(func(event twitch.EventChannelPollEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPollEnd)
+	expectEventOccurred[twitch.EventChannelPollEnd](t, twitch.SubChannelPollEnd)
 }
 
 func TestEventChannelPredictionBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPredictionBegin(func(event twitch.EventChannelPredictionBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPredictionBegin)
+	expectEventOccurred[twitch.EventChannelPredictionBegin](t, twitch.SubChannelPredictionBegin)
 }
 
 func TestEventChannelPredictionProgress(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPredictionProgress(func(event twitch.EventChannelPredictionProgress, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPredictionProgress)
+	expectEventOccurred[twitch.EventChannelPredictionProgress](t, twitch.SubChannelPredictionProgress)
 }
 
 func TestEventChannelPredictionLock(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPredictionLock(func(event twitch.EventChannelPredictionLock, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPredictionLock)
+	expectEventOccurred[twitch.EventChannelPredictionLock](t, twitch.SubChannelPredictionLock)
 }
 
 func TestEventChannelPredictionEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelPredictionEnd(func(event twitch.EventChannelPredictionEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelPredictionEnd)
+	expectEventOccurred[twitch.EventChannelPredictionEnd](t, twitch.SubChannelPredictionEnd)
 }
 
 func TestEventDropEntitlementGrant(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventDropEntitlementGrant(func(event []twitch.EventDropEntitlementGrant, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubDropEntitlementGrant)
+	expectEventOccurred[twitch.EventDropEntitlementGrantBatch](t, twitch.SubDropEntitlementGrant)
 }
 
 func TestEventExtensionBitsTransactionCreate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventExtensionBitsTransactionCreate(func(event twitch.EventExtensionBitsTransactionCreate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubExtensionBitsTransactionCreate)
+	expectEventOccurred[twitch.EventExtensionBitsTransactionCreate](t, twitch.SubExtensionBitsTransactionCreate)
 }
 
 func TestEventChannelGoalBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelGoalBegin(func(event twitch.EventChannelGoalBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelGoalBegin)
+	expectEventOccurred[twitch.EventChannelGoalBegin](t, twitch.SubChannelGoalBegin)
 }
 
 func TestEventChannelGoalProgress(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelGoalProgress(func(event twitch.EventChannelGoalProgress, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelGoalProgress)
+	expectEventOccurred[twitch.EventChannelGoalProgress](t, twitch.SubChannelGoalProgress)
 }
 
 func TestEventChannelGoalEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelGoalEnd(func(event twitch.EventChannelGoalEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelGoalEnd)
+	expectEventOccurred[twitch.EventChannelGoalEnd](t, twitch.SubChannelGoalEnd)
 }
 
 func TestEventChannelHypeTrainBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelHypeTrainBegin(func(event twitch.EventChannelHypeTrainBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelHypeTrainBegin)
+	expectEventOccurred[twitch.EventChannelHypeTrainBegin](t, twitch.SubChannelHypeTrainBegin)
 }
 
 func TestEventChannelHypeTrainProgress(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelHypeTrainProgress(func(event twitch.EventChannelHypeTrainProgress, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelHypeTrainProgress)
+	expectEventOccurred[twitch.EventChannelHypeTrainProgress](t, twitch.SubChannelHypeTrainProgress)
 }
 
 func TestEventChannelHypeTrainEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelHypeTrainEnd(func(event twitch.EventChannelHypeTrainEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelHypeTrainEnd)
+	expectEventOccurred[twitch.EventChannelHypeTrainEnd](t, twitch.SubChannelHypeTrainEnd)
 }
 
 func TestEventStreamOnline(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubStreamOnline)
+	expectEventOccurred[twitch.EventStreamOnline](t, twitch.SubStreamOnline)
 }
 
 func TestEventStreamOffline(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventStreamOffline(func(event twitch.EventStreamOffline, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubStreamOffline)
+	expectEventOccurred[twitch.EventStreamOffline](t, twitch.SubStreamOffline)
 }
 
 func TestEventUserAuthorizationGrant(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserAuthorizationGrant(func(event twitch.EventUserAuthorizationGrant, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserAuthorizationGrant)
+	expectEventOccurred[twitch.EventUserAuthorizationGrant](t, twitch.SubUserAuthorizationGrant)
 }
 
 func TestEventUserAuthorizationRevoke(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserAuthorizationRevoke(func(event twitch.EventUserAuthorizationRevoke, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserAuthorizationRevoke)
+	expectEventOccurred[twitch.EventUserAuthorizationRevoke](t, twitch.SubUserAuthorizationRevoke)
 }
 
 func TestEventUserAuthorizationRevokeNoUser(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserAuthorizationRevoke(func(event twitch.EventUserAuthorizationRevoke, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserAuthorizationRevoke, "nouser")
+	expectEventOccurred[twitch.EventUserAuthorizationRevoke](t, twitch.SubUserAuthorizationRevoke, "nouser")
 }
 
 func TestEventUserUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserUpdate(func(event twitch.EventUserUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserUpdate)
+	expectEventOccurred[twitch.EventUserUpdate](t, twitch.SubUserUpdate)
 }
 
 func TestEventUserUpdateNoEmail(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserUpdate(func(event twitch.EventUserUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserUpdate, "noemail")
+	expectEventOccurred[twitch.EventUserUpdate](t, twitch.SubUserUpdate, "noemail")
 }
 
 func TestEventChannelCharityCampaignDonate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCharityCampaignDonate(func(event twitch.EventChannelCharityCampaignDonate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCharityCampaignDonate)
+	expectEventOccurred[twitch.EventChannelCharityCampaignDonate](t, twitch.SubChannelCharityCampaignDonate)
 }
 
 func TestEventChannelCharityCampaignProgress(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCharityCampaignProgress(func(event twitch.EventChannelCharityCampaignProgress, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCharityCampaignProgress)
+	expectEventOccurred[twitch.EventChannelCharityCampaignProgress](t, twitch.SubChannelCharityCampaignProgress)
 }
 
 func TestEventChannelCharityCampaignStart(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCharityCampaignStart(func(event twitch.EventChannelCharityCampaignStart, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCharityCampaignStart)
+	expectEventOccurred[twitch.EventChannelCharityCampaignStart](t, twitch.SubChannelCharityCampaignStart)
 }
 
 func TestEventChannelCharityCampaignStop(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelCharityCampaignStop(func(event twitch.EventChannelCharityCampaignStop, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelCharityCampaignStop)
+	expectEventOccurred[twitch.EventChannelCharityCampaignStop](t, twitch.SubChannelCharityCampaignStop)
 }
 
 func TestEventChannelShieldModeBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelShieldModeBegin(func(event twitch.EventChannelShieldModeBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelShieldModeBegin)
+	expectEventOccurred[twitch.EventChannelShieldModeBegin](t, twitch.SubChannelShieldModeBegin)
 }
 
 func TestEventChannelShieldModeEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelShieldModeEnd(func(event twitch.EventChannelShieldModeEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelShieldModeEnd)
+	expectEventOccurred[twitch.EventChannelShieldModeEnd](t, twitch.SubChannelShieldModeEnd)
 }
 
 func TestEventChannelShoutoutCreate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelShoutoutCreate(func(event twitch.EventChannelShoutoutCreate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelShoutoutCreate)
+	expectEventOccurred[twitch.EventChannelShoutoutCreate](t, twitch.SubChannelShoutoutCreate)
 }
 
 func TestEventChannelShoutoutReceive(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelShoutoutReceive(func(event twitch.EventChannelShoutoutReceive, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelShoutoutReceive)
+	expectEventOccurred[twitch.EventChannelShoutoutReceive](t, twitch.SubChannelShoutoutReceive)
 }
 
 func TestEventChannelModerate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelModerate(func(event twitch.EventChannelModerate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelModerate)
+	expectEventOccurred[twitch.EventChannelModerate](t, twitch.SubChannelModerate)
 }
 
 func TestEventChannelAdBreakBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelAdBreakBegin(func(event twitch.EventChannelAdBreakBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelAdBreakBegin)
+	expectEventOccurred[twitch.EventChannelAdBreakBegin](t, twitch.SubChannelAdBreakBegin)
 }
 
 func TestEventChannelWarningAcknowledge(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelWarningAcknowledge(func(event twitch.EventChannelWarningAcknowledge, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelWarningAcknowledge)
+	expectEventOccurred[twitch.EventChannelWarningAcknowledge](t, twitch.SubChannelWarningAcknowledge)
 }
 
 func TestEventChannelWarningSend(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelWarningSend(func(event twitch.EventChannelWarningSend, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelWarningSend)
+	expectEventOccurred[twitch.EventChannelWarningSend](t, twitch.SubChannelWarningSend)
 }
 
 func TestEventChannelUnbanRequestCreate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelUnbanRequestCreate(func(event twitch.EventChannelUnbanRequestCreate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelUnbanRequestCreate)
+	expectEventOccurred[twitch.EventChannelUnbanRequestCreate](t, twitch.SubChannelUnbanRequestCreate)
 }
 
 func TestEventChannelUnbanRequestResolve(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelUnbanRequestResolve(func(event twitch.EventChannelUnbanRequestResolve, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelUnbanRequestResolve)
+	expectEventOccurred[twitch.EventChannelUnbanRequestResolve](t, twitch.SubChannelUnbanRequestResolve)
 }
 
 func TestEventAutomodMessageHold(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventAutomodMessageHold(func(event twitch.EventAutomodMessageHold, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubAutomodMessageHold)
+	expectEventOccurred[twitch.EventAutomodMessageHold](t, twitch.SubAutomodMessageHold)
 }
 
 func TestEventAutomodMessageUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventAutomodMessageUpdate(func(event twitch.EventAutomodMessageUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubAutomodMessageUpdate)
+	expectEventOccurred[twitch.EventAutomodMessageUpdate](t, twitch.SubAutomodMessageUpdate)
 }
 
 func TestEventAutomodSettingsUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventAutomodSettingsUpdate(func(event twitch.EventAutomodSettingsUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubAutomodSettingsUpdate)
+	expectEventOccurred[twitch.EventAutomodSettingsUpdate](t, twitch.SubAutomodSettingsUpdate)
 }
 
 func TestEventAutomodTermsUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventAutomodTermsUpdate(func(event twitch.EventAutomodTermsUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubAutomodTermsUpdate)
+	expectEventOccurred[twitch.EventAutomodTermsUpdate](t, twitch.SubAutomodTermsUpdate)
 }
 
 func TestEventChannelChatUserMessageHold(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatUserMessageHold(func(event twitch.EventChannelChatUserMessageHold, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatUserMessageHold)
+	expectEventOccurred[twitch.EventChannelChatUserMessageHold](t, twitch.SubChannelChatUserMessageHold)
 }
 
 func TestEventChannelChatUserMessageUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatUserMessageUpdate(func(event twitch.EventChannelChatUserMessageUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatUserMessageUpdate)
+	expectEventOccurred[twitch.EventChannelChatUserMessageUpdate](t, twitch.SubChannelChatUserMessageUpdate)
 }
 
 func TestEventChannelChatClear(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatClear(func(event twitch.EventChannelChatClear, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatClear)
+	expectEventOccurred[twitch.EventChannelChatClear](t, twitch.SubChannelChatClear)
 }
 
 func TestEventChannelChatClearUserMessages(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatClearUserMessages(func(event twitch.EventChannelChatClearUserMessages, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatClearUserMessages)
+	expectEventOccurred[twitch.EventChannelChatClearUserMessages](t, twitch.SubChannelChatClearUserMessages)
 }
 
 func TestEventChannelChatMessage(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatMessage(func(event twitch.EventChannelChatMessage, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatMessage)
+	expectEventOccurred[twitch.EventChannelChatMessage](t, twitch.SubChannelChatMessage)
 }
 
 func TestEventChannelChatMessageDelete(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatMessageDelete(func(event twitch.EventChannelChatMessageDelete, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatMessageDelete)
+	expectEventOccurred[twitch.EventChannelChatMessageDelete](t, twitch.SubChannelChatMessageDelete)
 }
 
 func TestEventChannelChatNotification(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatNotification(func(event twitch.EventChannelChatNotification, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatNotification)
+	expectEventOccurred[twitch.EventChannelChatNotification](t, twitch.SubChannelChatNotification)
 }
 
 func TestEventChannelChatSettingsUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelChatSettingsUpdate(func(event twitch.EventChannelChatSettingsUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelChatSettingsUpdate)
+	expectEventOccurred[twitch.EventChannelChatSettingsUpdate](t, twitch.SubChannelChatSettingsUpdate)
 }
 
 func TestEventChannelSuspiciousUserMessage(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSuspiciousUserMessage(func(event twitch.EventChannelSuspiciousUserMessage, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSuspiciousUserMessage)
+	expectEventOccurred[twitch.EventChannelSuspiciousUserMessage](t, twitch.SubChannelSuspiciousUserMessage)
 }
 
 func TestEventChannelSuspiciousUserUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSuspiciousUserUpdate(func(event twitch.EventChannelSuspiciousUserUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSuspiciousUserUpdate)
+	expectEventOccurred[twitch.EventChannelSuspiciousUserUpdate](t, twitch.SubChannelSuspiciousUserUpdate)
 }
 
 func TestEventChannelSharedChatBegin(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSharedChatBegin(func(event twitch.EventChannelSharedChatBegin, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSharedChatBegin)
+	expectEventOccurred[twitch.EventChannelSharedChatBegin](t, twitch.SubChannelSharedChatBegin)
 }
 
 func TestEventChannelSharedChatUpdate(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSharedChatUpdate(func(event twitch.EventChannelSharedChatUpdate, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSharedChatUpdate)
+	expectEventOccurred[twitch.EventChannelSharedChatUpdate](t, twitch.SubChannelSharedChatUpdate)
 }
 
 func TestEventChannelSharedChatEnd(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventChannelSharedChatEnd(func(event twitch.EventChannelSharedChatEnd, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubChannelSharedChatEnd)
+	expectEventOccurred[twitch.EventChannelSharedChatEnd](t, twitch.SubChannelSharedChatEnd)
+}
+
+func TestEventChannelGuestStarSessionBegin(t *testing.T) {
+	t.Parallel()
+
+	expectEventOccurred[twitch.EventChannelGuestStarSessionBegin](t, twitch.SubChannelGuestStarSessionBegin)
+}
+
+func TestEventChannelGuestStarSessionEnd(t *testing.T) {
+	t.Parallel()
+
+	expectEventOccurred[twitch.EventChannelGuestStarSessionEnd](t, twitch.SubChannelGuestStarSessionEnd)
+}
+
+func TestEventChannelGuestStarGuestUpdate(t *testing.T) {
+	t.Parallel()
+
+	expectEventOccurred[twitch.EventChannelGuestStarGuestUpdate](t, twitch.SubChannelGuestStarGuestUpdate)
+}
+
+func TestEventChannelGuestStarSettingsUpdate(t *testing.T) {
+	t.Parallel()
+
+	expectEventOccurred[twitch.EventChannelGuestStarSettingsUpdate](t, twitch.SubChannelGuestStarSettingsUpdate)
 }
 
 func TestEventUserWhisperMessage(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventUserWhisperMessage(func(event twitch.EventUserWhisperMessage, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubUserWhisperMessage)
+	expectEventOccurred[twitch.EventUserWhisperMessage](t, twitch.SubUserWhisperMessage)
 }
 
 func TestEventConduitShardDisabled(t *testing.T) {
 	t.Parallel()
 
-	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
-		client.OnEventConduitShardDisabled(func(event twitch.EventConduitShardDisabled, _ twitch.PayloadContext) {
-			close(ch)
-		})
-	}, twitch.SubConduitShardDisabled)
+	expectEventOccurred[twitch.EventConduitShardDisabled](t, twitch.SubConduitShardDisabled)
 }
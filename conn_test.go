@@ -3,11 +3,19 @@ package twitch_test
 import (
 	"context"
 	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func noDataGen() ([][]byte, bool, error) {
@@ -207,20 +215,322 @@ func TestReconnectEvent(t *testing.T) {
 	}
 	reconnectUrl := fmt.Sprintf("http://%s/%s", reconnectServer.Address, "ws")
 
-	client := newClient(t, genReconnectGen(reconnectUrl, revokeGen))
+	// The revocation comes before the reconnect, since the client closes the old
+	// connection as soon as the new one is welcomed.
+	client := newClient(t, func() ([][]byte, bool, error) {
+		revoke, _, _ := revokeGen()
+		reconnect, _, _ := genReconnectGen(reconnectUrl)()
+		return append(revoke, reconnect...), false, nil
+	})
 
-	var keepAliveOccured bool
+	var keepAliveOccured atomic.Bool
 	client.OnKeepAlive(func(message twitch.KeepAliveMessage, _ twitch.MessageMetadata) {
-		keepAliveOccured = true
+		keepAliveOccured.Store(true)
 		client.Close()
 	})
 
-	var revokeOccured bool
-	client.OnRevoke(func(message twitch.RevokeMessage, _ twitch.MessageMetadata) { revokeOccured = true })
+	var revokeOccured atomic.Bool
+	client.OnRevoke(func(message twitch.RevokeMessage, _ twitch.MessageMetadata) { revokeOccured.Store(true) })
 
 	err = client.Connect()
 	assert.NoError(t, err)
-	assert.Equal(t, reconnectUrl, client.Address, "addresses should match")
-	assert.True(t, revokeOccured, "revoke did not fire")
-	assert.True(t, keepAliveOccured, "keepalive did not fire")
+	assert.Equal(t, reconnectUrl, client.DebugState().Address, "addresses should match")
+	assert.Eventually(t, revokeOccured.Load, time.Second, time.Millisecond, "revoke did not fire")
+	assert.True(t, keepAliveOccured.Load(), "keepalive did not fire")
+}
+
+func TestOnLatency(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnLatency(func(latency time.Duration, payloadContext twitch.PayloadContext) {
+			stats := client.Stats()
+			assert.Equal(t, int64(1), stats.Notifications)
+			assert.Equal(t, latency, stats.LastLatency)
+			close(ch)
+		})
+	}, twitch.SubStreamOnline)
+}
+
+func TestOnLifecycle(t *testing.T) {
+	t.Parallel()
+	client := newClient(t, noDataGen)
+
+	var events []twitch.LifecycleEventType
+	client.OnLifecycle(func(event twitch.LifecycleEvent) {
+		events = append(events, event.Type)
+		if event.Type == twitch.LifecycleWelcomeReceived {
+			assert.NotEmpty(t, event.SessionID)
+			go client.Close()
+		}
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err)
+	assert.Equal(t, []twitch.LifecycleEventType{
+		twitch.LifecycleConnected,
+		twitch.LifecycleWelcomeReceived,
+		twitch.LifecycleDisconnected,
+	}, events)
+}
+
+func TestOnSlowHandler(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.SetSlowHandlerThreshold(10 * time.Millisecond)
+		client.OnSlowHandler(func(warning twitch.SlowHandlerWarning) {
+			assert.Equal(t, string(twitch.SubStreamOnline), warning.Type)
+			assert.Contains(t, warning.Handler, "TestOnSlowHandler")
+			assert.GreaterOrEqual(t, warning.Duration, 10*time.Millisecond)
+			close(ch)
+		})
+		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, _ twitch.PayloadContext) {
+			time.Sleep(20 * time.Millisecond)
+		})
+	}, twitch.SubStreamOnline)
+}
+
+func TestMessageError(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnError(func(err error) {
+			var messageErr *twitch.MessageError
+			if assert.ErrorAs(t, err, &messageErr) {
+				assert.Equal(t, twitch.EventSubscription("unknown"), messageErr.SubscriptionType)
+				assert.Equal(t, "notification", messageErr.MessageType)
+				assert.NotEmpty(t, messageErr.MessageID)
+				assert.NotEmpty(t, messageErr.SessionID)
+			}
+			close(ch)
+		})
+	}, "unknown")
+}
+
+type signalWriter struct {
+	mu     sync.Mutex
+	signal func(line string)
+}
+
+func (w *signalWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.signal(string(p))
+	return len(p), nil
+}
+
+func TestDebugLogger(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		var dispatched, handled bool
+		client.SetDebugLogger(log.New(&signalWriter{signal: func(line string) {
+			if strings.Contains(line, "dispatched stream.online") {
+				assert.Contains(t, line, "handlers=1")
+				dispatched = true
+			}
+			if strings.Contains(line, "handled stream.online") {
+				handled = true
+			}
+			if dispatched && handled {
+				dispatched = false
+				close(ch)
+			}
+		}}, "", 0))
+		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, _ twitch.PayloadContext) {})
+	}, twitch.SubStreamOnline)
+}
+
+func TestProbes(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t, noDataGen)
+	assert.False(t, client.Live())
+	assert.False(t, client.Ready())
+
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		defer client.Close()
+
+		assert.True(t, client.Live())
+		assert.False(t, client.Ready())
+
+		recorder := httptest.NewRecorder()
+		twitch.ProbeHandler(client.Ready).ServeHTTP(recorder, httptest.NewRequest("GET", "/readyz", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err)
+}
+
+func TestReadyWithHelpers(t *testing.T) {
+	t.Parallel()
+
+	helix := twitchtest.NewHelix(t)
+	url := helix.URL + "/eventsub/subscriptions"
+	client := newClient(t, noDataGen)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		defer client.Close()
+
+		// Subscriptions of other sessions don't count.
+		_, err := twitch.SubscribeEventUrl(twitch.SubscribeRequest{SessionID: "other", Event: twitch.SubStreamOffline}, url)
+		require.NoError(t, err)
+		assert.False(t, client.Ready())
+
+		// Subscriptions created for the session count without Subscribe.
+		_, err = twitch.SubscribeEventUrl(twitch.SubscribeRequest{
+			SessionID: message.Payload.Session.ID,
+			Event:     twitch.SubStreamOnline,
+			Condition: map[string]string{"broadcaster_user_id": "1337"},
+		}, url)
+		require.NoError(t, err)
+		assert.True(t, client.Ready())
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err)
+}
+
+func TestSubscriptionsOfHelpers(t *testing.T) {
+	t.Parallel()
+
+	helix := twitchtest.NewHelix(t)
+	url := helix.URL + "/eventsub/subscriptions"
+	client := newClient(t, noDataGen)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		defer client.Close()
+
+		response, err := twitch.SubscribeEventUrl(twitch.SubscribeRequest{
+			SessionID: message.Payload.Session.ID,
+			Event:     twitch.SubStreamOnline,
+			Condition: map[string]string{"broadcaster_user_id": "1337"},
+		}, url)
+		require.NoError(t, err)
+		assert.Equal(t, response.Data, client.Subscriptions())
+
+		err = twitch.UnsubscribeEventUrlWithContext(context.Background(), twitch.UnsubscribeRequest{ID: response.Data[0].ID}, url)
+		require.NoError(t, err)
+		assert.Empty(t, client.Subscriptions())
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err)
+}
+
+func TestCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.SetCorrelationIDFunc(func(metadata twitch.MessageMetadata) string {
+			return "trace-" + metadata.MessageID
+		})
+		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, payloadContext twitch.PayloadContext) {
+			assert.Equal(t, "trace-"+payloadContext.Metadata.MessageID, payloadContext.CorrelationID)
+			close(ch)
+		})
+	}, twitch.SubStreamOnline)
+}
+
+func TestCorrelationIDDefault(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, payloadContext twitch.PayloadContext) {
+			assert.NotEmpty(t, payloadContext.CorrelationID)
+			assert.Equal(t, payloadContext.Metadata.MessageID, payloadContext.CorrelationID)
+			close(ch)
+		})
+	}, twitch.SubStreamOnline)
+}
+
+func TestRelaxedValidation(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.SetRelaxedValidation(true)
+		client.OnRawEvent(func(event string, metadata twitch.MessageMetadata, subscription twitch.PayloadSubscription) {
+			assert.Equal(t, twitch.EventSubscription("unknown"), subscription.Type)
+			close(ch)
+		})
+	}, "unknown")
+}
+
+func TestLiveWithFakeClock(t *testing.T) {
+	t.Parallel()
+
+	clock := twitchtest.NewFakeClock(time.Now())
+	client := newClient(t, noDataGen)
+	client.SetClock(clock)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		defer client.Close()
+
+		assert.True(t, client.Live())
+		clock.Advance(time.Duration(message.Payload.Session.KeepaliveTimeoutSeconds) * time.Second)
+		assert.True(t, client.Live())
+		clock.Advance(time.Second)
+		assert.False(t, client.Live())
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err)
+}
+
+func TestReadBufferReuse(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	const count = 50
+	raids := make(chan twitch.EventChannelRaid, count)
+	raws := make(chan string, count)
+
+	client := twitch.NewClientWithUrl(server.URL)
+	client.SetMaxReadBuffer(1024)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {})
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
+		time.Sleep(time.Millisecond)
+		raids <- event
+	})
+	client.OnRawEvent(func(event string, _ twitch.MessageMetadata, _ twitch.PayloadSubscription) {
+		raws <- event
+	})
+	go client.ConnectWithContext(ctx)
+
+	conn, err := server.WaitForConnection(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	padding := strings.Repeat("x", 2048)
+	for i := 0; i < count; i++ {
+		// every other frame is larger than the retained buffer
+		login := "raider"
+		if i%2 == 0 {
+			login += padding
+		}
+		event := fmt.Sprintf(`{"from_broadcaster_user_login":%q,"viewers":%d}`, login, i)
+		notification := twitchtest.NewNotification(twitch.SubChannelRaid, []byte(event))
+		if err := conn.Send(ctx, notification); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < count; i++ {
+		select {
+		case raid := <-raids:
+			assert.Equal(t, raid.Viewers%2 == 0, strings.HasSuffix(raid.FromBroadcasterUserLogin, padding))
+			seen[raid.Viewers] = true
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for raids")
+		}
+		assert.True(t, strings.HasPrefix(<-raws, `{"from_broadcaster_user_login":"raider`))
+	}
+	assert.Len(t, seen, count)
 }
@@ -0,0 +1,60 @@
+//go:build integration
+
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestCLIContract decodes the payload `twitch event trigger` generates for every
+// supported subscription type and asserts no field is lost. Run with
+// go test -tags integration; TWITCH_CLI overrides the binary path.
+func TestCLIContract(t *testing.T) {
+	binary := os.Getenv("TWITCH_CLI")
+	if binary == "" {
+		binary = "twitch"
+	}
+	if _, err := exec.LookPath(binary); err != nil {
+		t.Skip("twitch cli is not installed")
+	}
+
+	var subscriptions []EventSubscription
+	for sub := range subMetadata {
+		subscriptions = append(subscriptions, sub)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i] < subscriptions[j] })
+
+	for _, sub := range subscriptions {
+		sub := sub
+		metadata := subMetadata[sub]
+		t.Run(string(sub), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			output, err := exec.CommandContext(ctx, binary, "event", "trigger", string(sub), "--version", metadata.Version).Output()
+			if err != nil {
+				t.Skipf("twitch cli cannot generate %s v%s: %v", sub, metadata.Version, err)
+			}
+
+			var payload struct {
+				Subscription PayloadSubscription `json:"subscription"`
+				Event        json.RawMessage     `json:"event"`
+			}
+			err = json.Unmarshal(output, &payload)
+			if err != nil {
+				t.Fatalf("could not parse cli output: %v\n%s", err, output)
+			}
+			if payload.Subscription.Type != sub {
+				t.Fatalf("cli generated %s", payload.Subscription.Type)
+			}
+
+			assertRoundTrip(t, metadata, payload.Event)
+		})
+	}
+}
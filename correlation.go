@@ -0,0 +1,23 @@
+package twitch
+
+import "github.com/google/uuid"
+
+// newCorrelationID returns the correlation ID of a notification. It is the message ID
+// unless SetCorrelationIDFunc overrides it, so no ID is made for most notifications.
+func (c *Client) newCorrelationID(metadata MessageMetadata) string {
+	if c.correlationIDFunc != nil {
+		return c.correlationIDFunc(metadata)
+	}
+	if metadata.MessageID != "" {
+		return metadata.MessageID
+	}
+	return uuid.NewString()
+}
+
+// SetCorrelationIDFunc overrides how correlation IDs are created for notifications,
+// e.g. to reuse an ID from an upstream system. By default the message ID of the
+// notification is used, which Twitch keeps when it sends a notification again, and a
+// random UUID only for messages without one.
+func (c *Client) SetCorrelationIDFunc(f func(metadata MessageMetadata) string) {
+	c.correlationIDFunc = f
+}
@@ -0,0 +1,52 @@
+package twitch
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+)
+
+type DebugState struct {
+	Address       string                `json:"address"`
+	Connected     bool                  `json:"connected"`
+	Session       PayloadSession        `json:"session"`
+	Subscriptions []PayloadSubscription `json:"subscriptions"`
+	Stats         Stats                 `json:"stats"`
+}
+
+func (c *Client) DebugState() DebugState {
+	return DebugState{
+		Address:       c.address(),
+		Connected:     c.connected.Load(),
+		Session:       c.Session(),
+		Subscriptions: c.Subscriptions(),
+		Stats:         c.Stats(),
+	}
+}
+
+// PublishExpvar publishes the client stats under name. expvar names are global, so each
+// client needs its own name.
+func (c *Client) PublishExpvar(name string) error {
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %s is already published", name)
+	}
+
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+	return nil
+}
+
+func (c *Client) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(c.DebugState())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugHandler(t *testing.T) {
+	t.Parallel()
+
+	assertCallbackCalled(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnEventStreamOnline(func(event twitch.EventStreamOnline, _ twitch.PayloadContext) {
+			recorder := httptest.NewRecorder()
+			client.DebugHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+			var state twitch.DebugState
+			err := json.Unmarshal(recorder.Body.Bytes(), &state)
+			assert.NoError(t, err)
+			assert.True(t, state.Connected)
+			assert.NotEmpty(t, state.Session.ID)
+			assert.Len(t, state.Subscriptions, 1)
+			assert.Equal(t, int64(1), state.Stats.Notifications)
+			close(ch)
+		})
+	}, twitch.SubStreamOnline)
+}
+
+func TestPublishExpvar(t *testing.T) {
+	name := "twitch_" + uuid.NewString()
+	client := twitch.NewClient()
+	assert.NoError(t, client.PublishExpvar(name))
+	assert.Error(t, client.PublishExpvar(name))
+}
@@ -0,0 +1,133 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//go:generate go run ./internal/cmd/specgen
+//go:generate go run ./internal/cmd/eventgen
+//go:generate go run ./internal/cmd/protogen
+
+// SetGeneratedDecoders decodes the events on the hot path, like chat messages and
+// channel points redemptions, with generated decoders instead of encoding/json. They
+// avoid reflection but, unlike encoding/json, match field names case-sensitively.
+func (c *Client) SetGeneratedDecoders(enabled bool) {
+	c.generatedDecoders = enabled
+}
+
+// rawPayload references the payload inside the frame instead of copying it, so the
+// frame must not be modified until the payload is decoded.
+type rawPayload []byte
+
+func (p *rawPayload) UnmarshalJSON(data []byte) error {
+	*p = data
+	return nil
+}
+
+// baseMessage is decoded once per frame to get the metadata, leaving the payload to be
+// decoded into the message type it names.
+type baseMessage struct {
+	Metadata MessageMetadata `json:"metadata"`
+	Payload  rawPayload      `json:"payload"`
+}
+
+func decodePayload(message any, base baseMessage) error {
+	var payload any
+	switch msg := message.(type) {
+	case *WelcomeMessage:
+		msg.Metadata = base.Metadata
+		payload = &msg.Payload
+	case *KeepAliveMessage:
+		msg.Metadata = base.Metadata
+		payload = &msg.Payload
+	case *ReconnectMessage:
+		msg.Metadata = base.Metadata
+		payload = &msg.Payload
+	case *RevokeMessage:
+		msg.Metadata = base.Metadata
+		payload = &msg.Payload
+	default:
+		return fmt.Errorf("unsupported message %T", message)
+	}
+
+	if len(base.Payload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(base.Payload, payload)
+}
+
+// notificationEvent decodes the event of a notification in the same pass as the rest
+// of the frame, into the struct picked from the subscription type in the metadata.
+type notificationEvent struct {
+	raw              json.RawMessage
+	subscriptionType EventSubscription
+	target           any
+	err              error
+	generated        bool
+}
+
+func (e *notificationEvent) UnmarshalJSON(data []byte) error {
+	e.raw = append(e.raw[:0], data...)
+	if e.target != nil {
+		e.err = unmarshalEvent(data, e.target, e.generated)
+	}
+	return nil
+}
+
+func unmarshalEvent(data []byte, event any, generated bool) error {
+	if generated {
+		if ok, err := decodeGenerated(data, event); ok {
+			return err
+		}
+	}
+	return json.Unmarshal(data, event)
+}
+
+// decodeEvent returns the event decoded into the struct for the subscription type. It
+// only decodes again if the frame did not carry the subscription type in its metadata.
+func (c *Client) decodeEvent(e *notificationEvent, subscriptionType EventSubscription) (any, error) {
+	event := e.target
+	err := e.err
+	if event == nil || e.subscriptionType != subscriptionType {
+		c.releaseEvent(e.subscriptionType, e.target)
+		if subMetadata[subscriptionType].EventGen == nil {
+			return nil, nil
+		}
+		event = c.newEvent(subscriptionType)
+		err = unmarshalEvent(e.raw, event, c.generatedDecoders)
+	}
+
+	if err != nil {
+		c.releaseEvent(subscriptionType, event)
+		return nil, fmt.Errorf("could not unmarshal %s into %T: %w", subscriptionType, event, err)
+	}
+	return event, nil
+}
+
+func (c *Client) decodeNotification(base baseMessage) (*NotificationMessage, *notificationEvent, error) {
+	metadata := base.Metadata
+	event := &notificationEvent{subscriptionType: metadata.SubscriptionType, generated: c.generatedDecoders}
+	if sub, ok := subMetadata[metadata.SubscriptionType]; ok && sub.EventGen != nil && c.hasListener(metadata.SubscriptionType) {
+		event.target = c.newEvent(metadata.SubscriptionType)
+	}
+
+	var payload struct {
+		Subscription PayloadSubscription `json:"subscription"`
+		Event        *notificationEvent  `json:"event"`
+	}
+	payload.Event = event
+	if len(base.Payload) > 0 {
+		err := json.Unmarshal(base.Payload, &payload)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	message := &NotificationMessage{Metadata: metadata}
+	message.Payload.Subscription = payload.Subscription
+	if event.raw != nil {
+		message.Payload.Event = &event.raw
+	}
+	return message, event, nil
+}
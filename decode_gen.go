@@ -0,0 +1,2368 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+package twitch
+
+import (
+	"encoding/json"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/jsonscan"
+)
+
+var _ = json.Unmarshal
+
+// decodeGenerated decodes data into event without reflection if a decoder was
+// generated for its type.
+func decodeGenerated(data []byte, event any) (bool, error) {
+	switch event := event.(type) {
+	case *EventChannelChatMessage:
+		d := jsonscan.New(data)
+		if !d.Null() {
+			if err := decodeEventChannelChatMessage(&d, event); err != nil {
+				return true, err
+			}
+		}
+		return true, d.End()
+	case *EventChannelChatNotification:
+		d := jsonscan.New(data)
+		if !d.Null() {
+			if err := decodeEventChannelChatNotification(&d, event); err != nil {
+				return true, err
+			}
+		}
+		return true, d.End()
+	case *EventChannelCheer:
+		d := jsonscan.New(data)
+		if !d.Null() {
+			if err := decodeEventChannelCheer(&d, event); err != nil {
+				return true, err
+			}
+		}
+		return true, d.End()
+	case *EventChannelChannelPointsCustomRewardRedemptionAdd:
+		d := jsonscan.New(data)
+		if !d.Null() {
+			if err := decodeEventChannelChannelPointsCustomRewardRedemptionAdd(&d, event); err != nil {
+				return true, err
+			}
+		}
+		return true, d.End()
+	case *EventChannelChannelPointsAutomaticRewardRedemptionAdd:
+		d := jsonscan.New(data)
+		if !d.Null() {
+			if err := decodeEventChannelChannelPointsAutomaticRewardRedemptionAdd(&d, event); err != nil {
+				return true, err
+			}
+		}
+		return true, d.End()
+	}
+	return false, nil
+}
+
+func decodeEventChannelChatMessage(d *jsonscan.Decoder, v *EventChannelChatMessage) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "badges":
+			if d.Null() {
+				v.Badges = nil
+			} else {
+				x1 := v.Badges[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x2 ChatMessageUserBadge
+					if !d.Null() {
+						if err := decodeChatMessageUserBadge(d, &x2); err != nil {
+							return err
+						}
+					}
+					x1 = append(x1, x2)
+				}
+				if x1 == nil {
+					x1 = []ChatMessageUserBadge{}
+				}
+				v.Badges = x1
+			}
+		case "broadcaster_user_id":
+			if !d.Null() {
+				x3, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserId = x3
+			}
+		case "broadcaster_user_login":
+			if !d.Null() {
+				x4, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserLogin = x4
+			}
+		case "broadcaster_user_name":
+			if !d.Null() {
+				x5, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserName = x5
+			}
+		case "channel_points_animation_id":
+			if !d.Null() {
+				x6, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ChannelPointsAnimationId = x6
+			}
+		case "channel_points_custom_reward_id":
+			if !d.Null() {
+				x7, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ChannelPointsCustomRewardId = x7
+			}
+		case "chatter_user_id":
+			if !d.Null() {
+				x8, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserId = x8
+			}
+		case "chatter_user_login":
+			if !d.Null() {
+				x9, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserLogin = x9
+			}
+		case "chatter_user_name":
+			if !d.Null() {
+				x10, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserName = x10
+			}
+		case "cheer":
+			if d.Null() {
+				v.Cheer = nil
+			} else {
+				x11 := new(ChatMessageCheer)
+				if err := decodeChatMessageCheer(d, &(*x11)); err != nil {
+					return err
+				}
+				v.Cheer = x11
+			}
+		case "color":
+			if !d.Null() {
+				x12, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Color = x12
+			}
+		case "message":
+			if !d.Null() {
+				if err := decodeChatMessage(d, &v.Message); err != nil {
+					return err
+				}
+			}
+		case "message_id":
+			if !d.Null() {
+				x13, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.MessageId = x13
+			}
+		case "message_type":
+			if !d.Null() {
+				x14, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.MessageType = x14
+			}
+		case "reply":
+			if d.Null() {
+				v.Reply = nil
+			} else {
+				x15 := new(ChatMessageReply)
+				if err := decodeChatMessageReply(d, &(*x15)); err != nil {
+					return err
+				}
+				v.Reply = x15
+			}
+		case "source_badges":
+			if d.Null() {
+				v.SourceBadges = nil
+			} else {
+				x16 := new(ChatBadges)
+				x17 := (*x16)[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x18 ChatMessageUserBadge
+					if !d.Null() {
+						if err := decodeChatMessageUserBadge(d, &x18); err != nil {
+							return err
+						}
+					}
+					x17 = append(x17, x18)
+				}
+				if x17 == nil {
+					x17 = []ChatMessageUserBadge{}
+				}
+				(*x16) = x17
+				v.SourceBadges = x16
+			}
+		case "source_broadcaster_user_id":
+			if !d.Null() {
+				x19, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserId = x19
+			}
+		case "source_broadcaster_user_login":
+			if !d.Null() {
+				x20, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserLogin = x20
+			}
+		case "source_broadcaster_user_name":
+			if !d.Null() {
+				x21, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserName = x21
+			}
+		case "source_message_id":
+			if !d.Null() {
+				x22, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceMessageId = x22
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeEventChannelChatNotification(d *jsonscan.Decoder, v *EventChannelChatNotification) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "announcement":
+			if d.Null() {
+				v.Announcement = nil
+			} else {
+				x23 := new(ChatNotificationAnnouncement)
+				if err := decodeChatNotificationAnnouncement(d, &(*x23)); err != nil {
+					return err
+				}
+				v.Announcement = x23
+			}
+		case "badges":
+			if d.Null() {
+				v.Badges = nil
+			} else {
+				x24 := v.Badges[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x25 ChatMessageUserBadge
+					if !d.Null() {
+						if err := decodeChatMessageUserBadge(d, &x25); err != nil {
+							return err
+						}
+					}
+					x24 = append(x24, x25)
+				}
+				if x24 == nil {
+					x24 = []ChatMessageUserBadge{}
+				}
+				v.Badges = x24
+			}
+		case "bits_badge_tier":
+			if d.Null() {
+				v.BitsBadgeTier = nil
+			} else {
+				x26 := new(ChatNotificationBitsBadgeTier)
+				if err := decodeChatNotificationBitsBadgeTier(d, &(*x26)); err != nil {
+					return err
+				}
+				v.BitsBadgeTier = x26
+			}
+		case "broadcaster_user_id":
+			if !d.Null() {
+				x27, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserId = x27
+			}
+		case "broadcaster_user_login":
+			if !d.Null() {
+				x28, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserLogin = x28
+			}
+		case "broadcaster_user_name":
+			if !d.Null() {
+				x29, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserName = x29
+			}
+		case "charity_donation":
+			if d.Null() {
+				v.CharityDonation = nil
+			} else {
+				x30 := new(ChatNotificationCharityDonation)
+				if err := decodeChatNotificationCharityDonation(d, &(*x30)); err != nil {
+					return err
+				}
+				v.CharityDonation = x30
+			}
+		case "chatter_is_anonymous":
+			if !d.Null() {
+				x31, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.ChatterIsAnonymous = x31
+			}
+		case "chatter_user_id":
+			if !d.Null() {
+				x32, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserId = x32
+			}
+		case "chatter_user_login":
+			if !d.Null() {
+				x33, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserLogin = x33
+			}
+		case "chatter_user_name":
+			if !d.Null() {
+				x34, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Chatter.ChatterUserName = x34
+			}
+		case "color":
+			if !d.Null() {
+				x35, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Color = x35
+			}
+		case "community_sub_gift":
+			if d.Null() {
+				v.CommunitySubGift = nil
+			} else {
+				x36 := new(ChatNotificationCommunitySubGift)
+				if err := decodeChatNotificationCommunitySubGift(d, &(*x36)); err != nil {
+					return err
+				}
+				v.CommunitySubGift = x36
+			}
+		case "gift_paid_upgrade":
+			if d.Null() {
+				v.GiftPaidUpgrade = nil
+			} else {
+				x37 := new(ChatNotificationGiftPaidUpgrade)
+				if err := decodeChatNotificationGiftPaidUpgrade(d, &(*x37)); err != nil {
+					return err
+				}
+				v.GiftPaidUpgrade = x37
+			}
+		case "message":
+			if !d.Null() {
+				if err := decodeChatMessage(d, &v.Message); err != nil {
+					return err
+				}
+			}
+		case "message_id":
+			if !d.Null() {
+				x38, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.MessageId = x38
+			}
+		case "notice_type":
+			if !d.Null() {
+				x39, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.NoticeType = x39
+			}
+		case "pay_it_forward":
+			if d.Null() {
+				v.PayItForward = nil
+			} else {
+				x40 := new(ChatNotificationPayItForward)
+				if err := decodeChatNotificationPayItForward(d, &(*x40)); err != nil {
+					return err
+				}
+				v.PayItForward = x40
+			}
+		case "prime_paid_upgrade":
+			if d.Null() {
+				v.PrimePaidUpgrade = nil
+			} else {
+				x41 := new(ChatNotificationPrimePaidUpgrade)
+				if err := decodeChatNotificationPrimePaidUpgrade(d, &(*x41)); err != nil {
+					return err
+				}
+				v.PrimePaidUpgrade = x41
+			}
+		case "raid":
+			if d.Null() {
+				v.Raid = nil
+			} else {
+				x42 := new(ChatNotificationRaid)
+				if err := decodeChatNotificationRaid(d, &(*x42)); err != nil {
+					return err
+				}
+				v.Raid = x42
+			}
+		case "resub":
+			if d.Null() {
+				v.Resub = nil
+			} else {
+				x43 := new(ChatNotificationResub)
+				if err := decodeChatNotificationResub(d, &(*x43)); err != nil {
+					return err
+				}
+				v.Resub = x43
+			}
+		case "shared_chat_announcement":
+			if d.Null() {
+				v.SharedChatAnnouncement = nil
+			} else {
+				x44 := new(ChatNotificationAnnouncement)
+				if err := decodeChatNotificationAnnouncement(d, &(*x44)); err != nil {
+					return err
+				}
+				v.SharedChatAnnouncement = x44
+			}
+		case "shared_chat_community_sub_gift":
+			if d.Null() {
+				v.SharedChatCommunitySubGift = nil
+			} else {
+				x45 := new(ChatNotificationCommunitySubGift)
+				if err := decodeChatNotificationCommunitySubGift(d, &(*x45)); err != nil {
+					return err
+				}
+				v.SharedChatCommunitySubGift = x45
+			}
+		case "shared_chat_gift_paid_upgrade":
+			if d.Null() {
+				v.SharedChatGiftPaidUpgrade = nil
+			} else {
+				x46 := new(ChatNotificationGiftPaidUpgrade)
+				if err := decodeChatNotificationGiftPaidUpgrade(d, &(*x46)); err != nil {
+					return err
+				}
+				v.SharedChatGiftPaidUpgrade = x46
+			}
+		case "shared_chat_pay_it_forward":
+			if d.Null() {
+				v.SharedChatPayItForward = nil
+			} else {
+				x47 := new(ChatNotificationPayItForward)
+				if err := decodeChatNotificationPayItForward(d, &(*x47)); err != nil {
+					return err
+				}
+				v.SharedChatPayItForward = x47
+			}
+		case "shared_chat_prime_paid_upgrade":
+			if d.Null() {
+				v.SharedChatPrimePaidUpgrade = nil
+			} else {
+				x48 := new(ChatNotificationPrimePaidUpgrade)
+				if err := decodeChatNotificationPrimePaidUpgrade(d, &(*x48)); err != nil {
+					return err
+				}
+				v.SharedChatPrimePaidUpgrade = x48
+			}
+		case "shared_chat_raid":
+			if d.Null() {
+				v.SharedChatRaid = nil
+			} else {
+				x49 := new(ChatNotificationRaid)
+				if err := decodeChatNotificationRaid(d, &(*x49)); err != nil {
+					return err
+				}
+				v.SharedChatRaid = x49
+			}
+		case "shared_chat_resub":
+			if d.Null() {
+				v.SharedChatResub = nil
+			} else {
+				x50 := new(ChatNotificationResub)
+				if err := decodeChatNotificationResub(d, &(*x50)); err != nil {
+					return err
+				}
+				v.SharedChatResub = x50
+			}
+		case "shared_chat_sub":
+			if d.Null() {
+				v.SharedChatSub = nil
+			} else {
+				x51 := new(ChatNotificationSub)
+				if err := decodeChatNotificationSub(d, &(*x51)); err != nil {
+					return err
+				}
+				v.SharedChatSub = x51
+			}
+		case "shared_chat_sub_gift":
+			if d.Null() {
+				v.SharedChatSubGift = nil
+			} else {
+				x52 := new(ChatNotificationSubGift)
+				if err := decodeChatNotificationSubGift(d, &(*x52)); err != nil {
+					return err
+				}
+				v.SharedChatSubGift = x52
+			}
+		case "source_badges":
+			if d.Null() {
+				v.SourceBadges = nil
+			} else {
+				x53 := new(ChatBadges)
+				x54 := (*x53)[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x55 ChatMessageUserBadge
+					if !d.Null() {
+						if err := decodeChatMessageUserBadge(d, &x55); err != nil {
+							return err
+						}
+					}
+					x54 = append(x54, x55)
+				}
+				if x54 == nil {
+					x54 = []ChatMessageUserBadge{}
+				}
+				(*x53) = x54
+				v.SourceBadges = x53
+			}
+		case "source_broadcaster_user_id":
+			if !d.Null() {
+				x56, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserId = x56
+			}
+		case "source_broadcaster_user_login":
+			if !d.Null() {
+				x57, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserLogin = x57
+			}
+		case "source_broadcaster_user_name":
+			if !d.Null() {
+				x58, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceBroadcaster.SourceBroadcasterUserName = x58
+			}
+		case "source_message_id":
+			if !d.Null() {
+				x59, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SourceMessageId = x59
+			}
+		case "sub":
+			if d.Null() {
+				v.Sub = nil
+			} else {
+				x60 := new(ChatNotificationSub)
+				if err := decodeChatNotificationSub(d, &(*x60)); err != nil {
+					return err
+				}
+				v.Sub = x60
+			}
+		case "sub_gift":
+			if d.Null() {
+				v.SubGift = nil
+			} else {
+				x61 := new(ChatNotificationSubGift)
+				if err := decodeChatNotificationSubGift(d, &(*x61)); err != nil {
+					return err
+				}
+				v.SubGift = x61
+			}
+		case "system_message":
+			if !d.Null() {
+				x62, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SystemMessage = x62
+			}
+		case "unraid":
+			if d.Null() {
+				v.Unraid = nil
+			} else {
+				x63 := new(ChatNotificationUnraid)
+				if err := decodeChatNotificationUnraid(d, &(*x63)); err != nil {
+					return err
+				}
+				v.Unraid = x63
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeEventChannelCheer(d *jsonscan.Decoder, v *EventChannelCheer) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "bits":
+			if !d.Null() {
+				x64, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Bits = x64
+			}
+		case "broadcaster_user_id":
+			if !d.Null() {
+				x65, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserId = x65
+			}
+		case "broadcaster_user_login":
+			if !d.Null() {
+				x66, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserLogin = x66
+			}
+		case "broadcaster_user_name":
+			if !d.Null() {
+				x67, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserName = x67
+			}
+		case "is_anonymous":
+			if !d.Null() {
+				x68, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.IsAnonymous = x68
+			}
+		case "message":
+			if !d.Null() {
+				x69, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Message = x69
+			}
+		case "user_id":
+			if !d.Null() {
+				x70, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserID = x70
+			}
+		case "user_login":
+			if !d.Null() {
+				x71, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserLogin = x71
+			}
+		case "user_name":
+			if !d.Null() {
+				x72, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserName = x72
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeEventChannelChannelPointsCustomRewardRedemptionAdd(d *jsonscan.Decoder, v *EventChannelChannelPointsCustomRewardRedemptionAdd) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "broadcaster_user_id":
+			if !d.Null() {
+				x73, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserId = x73
+			}
+		case "broadcaster_user_login":
+			if !d.Null() {
+				x74, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserLogin = x74
+			}
+		case "broadcaster_user_name":
+			if !d.Null() {
+				x75, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserName = x75
+			}
+		case "id":
+			if !d.Null() {
+				x76, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ID = x76
+			}
+		case "redeemed_at":
+			if !d.Null() {
+				x77, err := d.Time()
+				if err != nil {
+					return err
+				}
+				v.RedeemedAt = x77
+			}
+		case "reward":
+			if !d.Null() {
+				if err := decodeCustomChannelPointReward(d, &v.Reward); err != nil {
+					return err
+				}
+			}
+		case "status":
+			if !d.Null() {
+				x78, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Status = RedemptionStatus(x78)
+			}
+		case "user_id":
+			if !d.Null() {
+				x79, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserID = x79
+			}
+		case "user_input":
+			if !d.Null() {
+				x80, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.UserInput = x80
+			}
+		case "user_login":
+			if !d.Null() {
+				x81, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserLogin = x81
+			}
+		case "user_name":
+			if !d.Null() {
+				x82, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserName = x82
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeEventChannelChannelPointsAutomaticRewardRedemptionAdd(d *jsonscan.Decoder, v *EventChannelChannelPointsAutomaticRewardRedemptionAdd) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "broadcaster_user_id":
+			if !d.Null() {
+				x83, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserId = x83
+			}
+		case "broadcaster_user_login":
+			if !d.Null() {
+				x84, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserLogin = x84
+			}
+		case "broadcaster_user_name":
+			if !d.Null() {
+				x85, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Broadcaster.BroadcasterUserName = x85
+			}
+		case "id":
+			if !d.Null() {
+				x86, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ID = x86
+			}
+		case "message":
+			if !d.Null() {
+				if err := decodeMessage(d, &v.Message); err != nil {
+					return err
+				}
+			}
+		case "redeemed_at":
+			if !d.Null() {
+				x87, err := d.Time()
+				if err != nil {
+					return err
+				}
+				v.RedeemedAt = x87
+			}
+		case "reward":
+			if !d.Null() {
+				if err := decodeAutomaticChannelPointReward(d, &v.Reward); err != nil {
+					return err
+				}
+			}
+		case "user_id":
+			if !d.Null() {
+				x88, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserID = x88
+			}
+		case "user_input":
+			if !d.Null() {
+				x89, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.UserInput = x89
+			}
+		case "user_login":
+			if !d.Null() {
+				x90, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserLogin = x90
+			}
+		case "user_name":
+			if !d.Null() {
+				x91, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserName = x91
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageUserBadge(d *jsonscan.Decoder, v *ChatMessageUserBadge) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "id":
+			if !d.Null() {
+				x92, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Id = x92
+			}
+		case "info":
+			if !d.Null() {
+				x93, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Info = x93
+			}
+		case "set_id":
+			if !d.Null() {
+				x94, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SetId = x94
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageCheer(d *jsonscan.Decoder, v *ChatMessageCheer) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "bits":
+			if !d.Null() {
+				x95, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Bits = x95
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessage(d *jsonscan.Decoder, v *ChatMessage) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "fragments":
+			if d.Null() {
+				v.Fragments = nil
+			} else {
+				x96 := v.Fragments[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x97 ChatMessageFragment
+					if !d.Null() {
+						if err := decodeChatMessageFragment(d, &x97); err != nil {
+							return err
+						}
+					}
+					x96 = append(x96, x97)
+				}
+				if x96 == nil {
+					x96 = []ChatMessageFragment{}
+				}
+				v.Fragments = x96
+			}
+		case "text":
+			if !d.Null() {
+				x98, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Text = x98
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageReply(d *jsonscan.Decoder, v *ChatMessageReply) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "parent_message_body":
+			if !d.Null() {
+				x99, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ParentMessageBody = x99
+			}
+		case "parent_message_id":
+			if !d.Null() {
+				x100, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ParentMessageId = x100
+			}
+		case "parent_user_id":
+			if !d.Null() {
+				x101, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ParentUserId = x101
+			}
+		case "parent_user_login":
+			if !d.Null() {
+				x102, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ParentUserLogin = x102
+			}
+		case "parent_user_name":
+			if !d.Null() {
+				x103, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ParentUserName = x103
+			}
+		case "thread_message_id":
+			if !d.Null() {
+				x104, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ThreadMessageId = x104
+			}
+		case "thread_user_id":
+			if !d.Null() {
+				x105, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ThreadUserId = x105
+			}
+		case "thread_user_login":
+			if !d.Null() {
+				x106, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ThreadUserLogin = x106
+			}
+		case "thread_user_name":
+			if !d.Null() {
+				x107, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ThreadUserName = x107
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationAnnouncement(d *jsonscan.Decoder, v *ChatNotificationAnnouncement) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "color":
+			if !d.Null() {
+				x108, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Color = x108
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationBitsBadgeTier(d *jsonscan.Decoder, v *ChatNotificationBitsBadgeTier) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "tier":
+			if !d.Null() {
+				x109, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Tier = x109
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationCharityDonation(d *jsonscan.Decoder, v *ChatNotificationCharityDonation) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "amount":
+			if !d.Null() {
+				if err := decodeChatNotificationCharityDonationAmount(d, &v.Amount); err != nil {
+					return err
+				}
+			}
+		case "charity_name":
+			if !d.Null() {
+				x110, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.CharityName = x110
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationCommunitySubGift(d *jsonscan.Decoder, v *ChatNotificationCommunitySubGift) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "cumulative_total":
+			if !d.Null() {
+				x111, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.CumulativeTotal = x111
+			}
+		case "id":
+			if !d.Null() {
+				x112, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Id = x112
+			}
+		case "sub_tier":
+			if !d.Null() {
+				x113, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubTier = x113
+			}
+		case "total":
+			if !d.Null() {
+				x114, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Total = x114
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationGiftPaidUpgrade(d *jsonscan.Decoder, v *ChatNotificationGiftPaidUpgrade) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "gifter_is_anonymous":
+			if !d.Null() {
+				x115, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.GifterIsAnonymous = x115
+			}
+		case "gifter_user_id":
+			if !d.Null() {
+				x116, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserId = x116
+			}
+		case "gifter_user_name":
+			if !d.Null() {
+				x117, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserName = x117
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationPayItForward(d *jsonscan.Decoder, v *ChatNotificationPayItForward) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "gifter_is_anonymous":
+			if !d.Null() {
+				x118, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.GifterIsAnonymous = x118
+			}
+		case "gifter_user_id":
+			if !d.Null() {
+				x119, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserId = x119
+			}
+		case "gifter_user_login":
+			if !d.Null() {
+				x120, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserLogin = x120
+			}
+		case "gifter_user_name":
+			if !d.Null() {
+				x121, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserName = x121
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationPrimePaidUpgrade(d *jsonscan.Decoder, v *ChatNotificationPrimePaidUpgrade) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "sub_tier":
+			if !d.Null() {
+				x122, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubTier = x122
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationRaid(d *jsonscan.Decoder, v *ChatNotificationRaid) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "profile_image_url":
+			if !d.Null() {
+				x123, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ProfileImageUrl = x123
+			}
+		case "user_id":
+			if !d.Null() {
+				x124, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserID = x124
+			}
+		case "user_login":
+			if !d.Null() {
+				x125, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserLogin = x125
+			}
+		case "user_name":
+			if !d.Null() {
+				x126, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.User.UserName = x126
+			}
+		case "viewer_count":
+			if !d.Null() {
+				x127, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ViewerCount = x127
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationResub(d *jsonscan.Decoder, v *ChatNotificationResub) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "cumulative_months":
+			if !d.Null() {
+				x128, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.CumulativeMonths = x128
+			}
+		case "duration_months":
+			if !d.Null() {
+				x129, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.DurationMonths = x129
+			}
+		case "gifter_is_anonymous":
+			if !d.Null() {
+				x130, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.GifterIsAnonymous = x130
+			}
+		case "gifter_user_id":
+			if !d.Null() {
+				x131, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserId = x131
+			}
+		case "gifter_user_login":
+			if !d.Null() {
+				x132, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserLogin = x132
+			}
+		case "gifter_user_name":
+			if !d.Null() {
+				x133, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.GifterUserName = x133
+			}
+		case "is_gift":
+			if !d.Null() {
+				x134, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.IsGift = x134
+			}
+		case "is_prime":
+			if !d.Null() {
+				x135, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.IsPrime = x135
+			}
+		case "streak_months":
+			if !d.Null() {
+				x136, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.StreakMonths = x136
+			}
+		case "sub_plan":
+			if !d.Null() {
+				x137, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubPlan = x137
+			}
+		case "sub_tier":
+			if !d.Null() {
+				x138, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubTier = x138
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationSub(d *jsonscan.Decoder, v *ChatNotificationSub) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "duration_months":
+			if !d.Null() {
+				x139, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.DurationMonths = x139
+			}
+		case "is_prime":
+			if !d.Null() {
+				x140, err := d.Bool()
+				if err != nil {
+					return err
+				}
+				v.IsPrime = x140
+			}
+		case "sub_tier":
+			if !d.Null() {
+				x141, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubTier = x141
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationSubGift(d *jsonscan.Decoder, v *ChatNotificationSubGift) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "community_gift_id":
+			if !d.Null() {
+				x142, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.CommunityGiftId = x142
+			}
+		case "cumulative_total":
+			if !d.Null() {
+				x143, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.CumulativeTotal = x143
+			}
+		case "duration_months":
+			if !d.Null() {
+				x144, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.DurationMonths = x144
+			}
+		case "recipient_user_id":
+			if !d.Null() {
+				x145, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.RecipientUserId = x145
+			}
+		case "recipient_user_login":
+			if !d.Null() {
+				x146, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.RecipientUserLogin = x146
+			}
+		case "recipient_user_name":
+			if !d.Null() {
+				x147, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.RecipientUserName = x147
+			}
+		case "sub_tier":
+			if !d.Null() {
+				x148, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.SubTier = x148
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationUnraid(d *jsonscan.Decoder, v *ChatNotificationUnraid) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeCustomChannelPointReward(d *jsonscan.Decoder, v *CustomChannelPointReward) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "cost":
+			if !d.Null() {
+				x149, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Cost = x149
+			}
+		case "id":
+			if !d.Null() {
+				x150, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ID = x150
+			}
+		case "prompt":
+			if !d.Null() {
+				x151, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Prompt = x151
+			}
+		case "title":
+			if !d.Null() {
+				x152, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Title = x152
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeMessage(d *jsonscan.Decoder, v *Message) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "emotes":
+			if d.Null() {
+				v.Emotes = nil
+			} else {
+				x153 := v.Emotes[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x154 Emote
+					if !d.Null() {
+						if err := decodeEmote(d, &x154); err != nil {
+							return err
+						}
+					}
+					x153 = append(x153, x154)
+				}
+				if x153 == nil {
+					x153 = []Emote{}
+				}
+				v.Emotes = x153
+			}
+		case "text":
+			if !d.Null() {
+				x155, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Text = x155
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeAutomaticChannelPointReward(d *jsonscan.Decoder, v *AutomaticChannelPointReward) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "cost":
+			if !d.Null() {
+				x156, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Cost = x156
+			}
+		case "type":
+			if !d.Null() {
+				x157, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Type = x157
+			}
+		case "unlocked_emote":
+			if d.Null() {
+				v.UnlockedEmote = nil
+			} else {
+				x158 := new(AutomaticChannelPointRewardUnlockedEmote)
+				if err := decodeAutomaticChannelPointRewardUnlockedEmote(d, &(*x158)); err != nil {
+					return err
+				}
+				v.UnlockedEmote = x158
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageFragment(d *jsonscan.Decoder, v *ChatMessageFragment) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "cheermote":
+			if d.Null() {
+				v.Cheermote = nil
+			} else {
+				x159 := new(ChatMessageFragmentCheermote)
+				if err := decodeChatMessageFragmentCheermote(d, &(*x159)); err != nil {
+					return err
+				}
+				v.Cheermote = x159
+			}
+		case "emote":
+			if d.Null() {
+				v.Emote = nil
+			} else {
+				x160 := new(ChatMessageFragmentEmote)
+				if err := decodeChatMessageFragmentEmote(d, &(*x160)); err != nil {
+					return err
+				}
+				v.Emote = x160
+			}
+		case "mention":
+			if d.Null() {
+				v.Mention = nil
+			} else {
+				x161 := new(ChatMessageFragmentMention)
+				if err := decodeUser(d, (*User)(&(*x161))); err != nil {
+					return err
+				}
+				v.Mention = x161
+			}
+		case "text":
+			if !d.Null() {
+				x162, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Text = x162
+			}
+		case "type":
+			if !d.Null() {
+				x163, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Type = x163
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatNotificationCharityDonationAmount(d *jsonscan.Decoder, v *ChatNotificationCharityDonationAmount) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "currency":
+			if !d.Null() {
+				x164, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Currency = x164
+			}
+		case "decimal_place":
+			if !d.Null() {
+				x165, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.DecimalPlace = x165
+			}
+		case "value":
+			if !d.Null() {
+				x166, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Value = x166
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeEmote(d *jsonscan.Decoder, v *Emote) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "begin":
+			if !d.Null() {
+				x167, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Begin = x167
+			}
+		case "end":
+			if !d.Null() {
+				x168, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.End = x168
+			}
+		case "id":
+			if !d.Null() {
+				x169, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ID = x169
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeAutomaticChannelPointRewardUnlockedEmote(d *jsonscan.Decoder, v *AutomaticChannelPointRewardUnlockedEmote) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "id":
+			if !d.Null() {
+				x170, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.ID = x170
+			}
+		case "name":
+			if !d.Null() {
+				x171, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Name = x171
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageFragmentCheermote(d *jsonscan.Decoder, v *ChatMessageFragmentCheermote) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "bits":
+			if !d.Null() {
+				x172, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Bits = x172
+			}
+		case "prefix":
+			if !d.Null() {
+				x173, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Prefix = x173
+			}
+		case "tier":
+			if !d.Null() {
+				x174, err := d.Int()
+				if err != nil {
+					return err
+				}
+				v.Tier = x174
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeChatMessageFragmentEmote(d *jsonscan.Decoder, v *ChatMessageFragmentEmote) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "emote_set_id":
+			if !d.Null() {
+				x175, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.EmoteSetId = x175
+			}
+		case "format":
+			if d.Null() {
+				v.Format = nil
+			} else {
+				x176 := v.Format[:0]
+				if err := d.ArrayStart(); err != nil {
+					return err
+				}
+				for {
+					more, err := d.NextElem()
+					if err != nil {
+						return err
+					}
+					if !more {
+						break
+					}
+					var x177 string
+					if !d.Null() {
+						x178, err := d.String()
+						if err != nil {
+							return err
+						}
+						x177 = x178
+					}
+					x176 = append(x176, x177)
+				}
+				if x176 == nil {
+					x176 = []string{}
+				}
+				v.Format = x176
+			}
+		case "id":
+			if !d.Null() {
+				x179, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.Id = x179
+			}
+		case "owner_id":
+			if !d.Null() {
+				x180, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.OwnerId = x180
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeUser(d *jsonscan.Decoder, v *User) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		switch string(key) {
+		case "user_id":
+			if !d.Null() {
+				x181, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.UserID = x181
+			}
+		case "user_login":
+			if !d.Null() {
+				x182, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.UserLogin = x182
+			}
+		case "user_name":
+			if !d.Null() {
+				x183, err := d.String()
+				if err != nil {
+					return err
+				}
+				v.UserName = x183
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
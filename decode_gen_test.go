@@ -0,0 +1,82 @@
+package twitch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadFixtures(t testing.TB) map[string]json.RawMessage {
+	data, err := os.ReadFile(filepath.Join("twitchtest", "payloads.json"))
+	require.NoError(t, err)
+
+	var payloads map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &payloads))
+	return payloads
+}
+
+func TestGeneratedDecodersMatchEncodingJSON(t *testing.T) {
+	var tested int
+	for key, payload := range loadFixtures(t) {
+		event, _, _ := strings.Cut(key, "-")
+		if _, ok := subMetadata[EventSubscription(event)]; !ok {
+			continue
+		}
+
+		want := newEventFor(EventSubscription(event))
+		got := newEventFor(EventSubscription(event))
+		ok, err := decodeGenerated(payload, got)
+		if !ok {
+			continue
+		}
+		tested++
+
+		require.NoError(t, err, key)
+		require.NoError(t, json.Unmarshal(payload, want), key)
+		assert.Equal(t, want, got, key)
+	}
+	assert.NotZero(t, tested)
+}
+
+func TestGeneratedDecoderErrors(t *testing.T) {
+	for _, payload := range []string{
+		`{"bits":"many"}`,
+		`{"bits":1`,
+		`{"bits":1}}`,
+		`[]`,
+	} {
+		ok, err := decodeGenerated([]byte(payload), &EventChannelCheer{})
+		assert.True(t, ok)
+		assert.Error(t, err, payload)
+		assert.Error(t, json.Unmarshal([]byte(payload), &EventChannelCheer{}), "encoding/json should agree on %s", payload)
+	}
+}
+
+func BenchmarkDecodeEvent(b *testing.B) {
+	payloads := loadFixtures(b)
+	for _, sub := range []EventSubscription{SubChannelChatMessage, SubChannelChatNotification, SubChannelChannelPointsCustomRewardRedemptionAdd} {
+		payload := payloads[string(sub)]
+
+		b.Run(string(sub)+"/encoding_json", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := json.Unmarshal(payload, newEventFor(sub)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(string(sub)+"/generated", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeGenerated(payload, newEventFor(sub)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
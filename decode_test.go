@@ -0,0 +1,66 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const raidFrame = `{"metadata":{"message_id":"1","message_type":"notification",%s"message_timestamp":"2024-01-01T00:00:00Z"},` +
+	`"payload":{"subscription":{"type":"channel.raid","version":"1"},"event":{"viewers":42}}}`
+
+func TestDecodeNotification(t *testing.T) {
+	for name, subscriptionMetadata := range map[string]string{
+		"single pass": `"subscription_type":"channel.raid","subscription_version":"1",`,
+		"fallback":    ``,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := []byte(fmt.Sprintf(raidFrame, subscriptionMetadata))
+			base, err := parseBaseMessage(data)
+			require.NoError(t, err)
+
+			client := NewClient()
+			client.OnEventChannelRaid(func(event EventChannelRaid, payloadContext PayloadContext) {})
+			message, event, err := client.decodeNotification(base)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"viewers":42}`, string(*message.Payload.Event))
+
+			decoded, err := client.decodeEvent(event, message.Payload.Subscription.Type)
+			require.NoError(t, err)
+			assert.Equal(t, 42, decoded.(*EventChannelRaid).Viewers)
+		})
+	}
+}
+
+func TestDecodeNotificationError(t *testing.T) {
+	data := []byte(`{"metadata":{"message_type":"notification","subscription_type":"channel.raid"},` +
+		`"payload":{"subscription":{"type":"channel.raid"},"event":{"viewers":"many"}}}`)
+	base, err := parseBaseMessage(data)
+	require.NoError(t, err)
+
+	client := NewClient()
+	client.OnEventChannelRaid(func(event EventChannelRaid, payloadContext PayloadContext) {})
+	message, event, err := client.decodeNotification(base)
+	require.NoError(t, err, "event errors are reported when handling the notification")
+	assert.NotNil(t, message.Payload.Event)
+
+	_, err = client.decodeEvent(event, SubChannelRaid)
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+func TestParseBaseMessage(t *testing.T) {
+	data := []byte(`{"metadata":{"message_id":"1","message_type":"session_keepalive"},"payload":{}}`)
+
+	base, err := parseBaseMessage(data)
+	require.NoError(t, err)
+	assert.Equal(t, "session_keepalive", base.Metadata.MessageType)
+	assert.Equal(t, `{}`, string(base.Payload))
+
+	var message KeepAliveMessage
+	assert.NoError(t, decodePayload(&message, base))
+	assert.Equal(t, "1", message.Metadata.MessageID)
+}
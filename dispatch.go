@@ -0,0 +1,70 @@
+package twitch
+
+import "time"
+
+const dispatcherIdleTimeout = time.Second
+
+// dispatcher runs handlers on a bounded set of reused goroutines. Workers are started
+// as needed and exit after being idle for a while. When every worker is busy, dispatch
+// blocks until one is free, so a burst slows the read loop down instead of piling up
+// goroutines.
+type dispatcher struct {
+	tasks   chan func()
+	workers chan struct{}
+}
+
+func newDispatcher(maxWorkers int) *dispatcher {
+	return &dispatcher{
+		tasks:   make(chan func()),
+		workers: make(chan struct{}, maxWorkers),
+	}
+}
+
+func (d *dispatcher) dispatch(task func()) {
+	select {
+	case d.tasks <- task:
+		return
+	default:
+	}
+
+	select {
+	case d.tasks <- task:
+	case d.workers <- struct{}{}:
+		go d.work(task)
+	}
+}
+
+func (d *dispatcher) work(task func()) {
+	defer func() { <-d.workers }()
+
+	idle := time.NewTimer(dispatcherIdleTimeout)
+	defer idle.Stop()
+	for {
+		task()
+
+		if !idle.Stop() {
+			select {
+			case <-idle.C:
+			default:
+			}
+		}
+		idle.Reset(dispatcherIdleTimeout)
+
+		select {
+		case task = <-d.tasks:
+		case <-idle.C:
+			return
+		}
+	}
+}
+
+// SetMaxHandlerGoroutines runs handlers on a pool of at most max reused goroutines.
+// Handlers beyond the limit wait for a running one to return, blocking the read loop
+// meanwhile. A limit of 0 or less starts a goroutine per handler call, the default.
+func (c *Client) SetMaxHandlerGoroutines(max int) {
+	if max <= 0 {
+		c.dispatcher = nil
+		return
+	}
+	c.dispatcher = newDispatcher(max)
+}
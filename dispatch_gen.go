@@ -0,0 +1,488 @@
+// Code generated by eventgen. DO NOT EDIT.
+
+package twitch
+
+// dispatchEvent calls the typed callback for a decoded event. known is false if the
+// event is not of a type the client has callbacks for.
+func (c *Client) dispatchEvent(event any, payloadContext PayloadContext) (dispatched bool, known bool) {
+	switch event := event.(type) {
+	case *EventChannelUpdate:
+		return callFunc(c, c.onEventChannelUpdate, *event, payloadContext), true
+	case *EventChannelFollow:
+		return callFunc(c, c.onEventChannelFollow, *event, payloadContext), true
+	case *EventChannelSubscribe:
+		return callFunc(c, c.onEventChannelSubscribe, *event, payloadContext), true
+	case *EventChannelSubscriptionEnd:
+		return callFunc(c, c.onEventChannelSubscriptionEnd, *event, payloadContext), true
+	case *EventChannelSubscriptionGift:
+		return callFunc(c, c.onEventChannelSubscriptionGift, *event, payloadContext), true
+	case *EventChannelSubscriptionMessage:
+		return callFunc(c, c.onEventChannelSubscriptionMessage, *event, payloadContext), true
+	case *EventChannelCheer:
+		return callFunc(c, c.onEventChannelCheer, *event, payloadContext), true
+	case *EventChannelRaid:
+		return callFunc(c, c.onEventChannelRaid, *event, payloadContext), true
+	case *EventChannelBan:
+		return callFunc(c, c.onEventChannelBan, *event, payloadContext), true
+	case *EventChannelUnban:
+		return callFunc(c, c.onEventChannelUnban, *event, payloadContext), true
+	case *EventChannelModeratorAdd:
+		return callFunc(c, c.onEventChannelModeratorAdd, *event, payloadContext), true
+	case *EventChannelModeratorRemove:
+		return callFunc(c, c.onEventChannelModeratorRemove, *event, payloadContext), true
+	case *EventChannelVIPAdd:
+		return callFunc(c, c.onEventChannelVIPAdd, *event, payloadContext), true
+	case *EventChannelVIPRemove:
+		return callFunc(c, c.onEventChannelVIPRemove, *event, payloadContext), true
+	case *EventChannelChannelPointsCustomRewardAdd:
+		return callFunc(c, c.onEventChannelChannelPointsCustomRewardAdd, *event, payloadContext), true
+	case *EventChannelChannelPointsCustomRewardUpdate:
+		return callFunc(c, c.onEventChannelChannelPointsCustomRewardUpdate, *event, payloadContext), true
+	case *EventChannelChannelPointsCustomRewardRemove:
+		return callFunc(c, c.onEventChannelChannelPointsCustomRewardRemove, *event, payloadContext), true
+	case *EventChannelChannelPointsCustomRewardRedemptionAdd:
+		return callFunc(c, c.onEventChannelChannelPointsCustomRewardRedemptionAdd, *event, payloadContext), true
+	case *EventChannelChannelPointsCustomRewardRedemptionUpdate:
+		return callFunc(c, c.onEventChannelChannelPointsCustomRewardRedemptionUpdate, *event, payloadContext), true
+	case *EventChannelChannelPointsAutomaticRewardRedemptionAdd:
+		return callFunc(c, c.onEventChannelChannelPointsAutomaticRewardRedemptionAdd, *event, payloadContext), true
+	case *EventChannelPollBegin:
+		return callFunc(c, c.onEventChannelPollBegin, *event, payloadContext), true
+	case *EventChannelPollProgress:
+		return callFunc(c, c.onEventChannelPollProgress, *event, payloadContext), true
+	case *EventChannelPollEnd:
+		return callFunc(c, c.onEventChannelPollEnd, *event, payloadContext), true
+	case *EventChannelPredictionBegin:
+		return callFunc(c, c.onEventChannelPredictionBegin, *event, payloadContext), true
+	case *EventChannelPredictionProgress:
+		return callFunc(c, c.onEventChannelPredictionProgress, *event, payloadContext), true
+	case *EventChannelPredictionLock:
+		return callFunc(c, c.onEventChannelPredictionLock, *event, payloadContext), true
+	case *EventChannelPredictionEnd:
+		return callFunc(c, c.onEventChannelPredictionEnd, *event, payloadContext), true
+	case *EventDropEntitlementGrantBatch:
+		return callFunc(c, c.onEventDropEntitlementGrant, *event, payloadContext), true
+	case *EventExtensionBitsTransactionCreate:
+		return callFunc(c, c.onEventExtensionBitsTransactionCreate, *event, payloadContext), true
+	case *EventChannelGoalBegin:
+		return callFunc(c, c.onEventChannelGoalBegin, *event, payloadContext), true
+	case *EventChannelGoalProgress:
+		return callFunc(c, c.onEventChannelGoalProgress, *event, payloadContext), true
+	case *EventChannelGoalEnd:
+		return callFunc(c, c.onEventChannelGoalEnd, *event, payloadContext), true
+	case *EventChannelHypeTrainBegin:
+		return callFunc(c, c.onEventChannelHypeTrainBegin, *event, payloadContext), true
+	case *EventChannelHypeTrainProgress:
+		return callFunc(c, c.onEventChannelHypeTrainProgress, *event, payloadContext), true
+	case *EventChannelHypeTrainEnd:
+		return callFunc(c, c.onEventChannelHypeTrainEnd, *event, payloadContext), true
+	case *EventStreamOnline:
+		return callFunc(c, c.onEventStreamOnline, *event, payloadContext), true
+	case *EventStreamOffline:
+		return callFunc(c, c.onEventStreamOffline, *event, payloadContext), true
+	case *EventUserAuthorizationGrant:
+		return callFunc(c, c.onEventUserAuthorizationGrant, *event, payloadContext), true
+	case *EventUserAuthorizationRevoke:
+		return callFunc(c, c.onEventUserAuthorizationRevoke, *event, payloadContext), true
+	case *EventUserUpdate:
+		return callFunc(c, c.onEventUserUpdate, *event, payloadContext), true
+	case *EventChannelCharityCampaignDonate:
+		return callFunc(c, c.onEventChannelCharityCampaignDonate, *event, payloadContext), true
+	case *EventChannelCharityCampaignStart:
+		return callFunc(c, c.onEventChannelCharityCampaignStart, *event, payloadContext), true
+	case *EventChannelCharityCampaignProgress:
+		return callFunc(c, c.onEventChannelCharityCampaignProgress, *event, payloadContext), true
+	case *EventChannelCharityCampaignStop:
+		return callFunc(c, c.onEventChannelCharityCampaignStop, *event, payloadContext), true
+	case *EventChannelShieldModeBegin:
+		return callFunc(c, c.onEventChannelShieldModeBegin, *event, payloadContext), true
+	case *EventChannelShieldModeEnd:
+		return callFunc(c, c.onEventChannelShieldModeEnd, *event, payloadContext), true
+	case *EventChannelShoutoutCreate:
+		return callFunc(c, c.onEventChannelShoutoutCreate, *event, payloadContext), true
+	case *EventChannelShoutoutReceive:
+		return callFunc(c, c.onEventChannelShoutoutReceive, *event, payloadContext), true
+	case *EventChannelModerate:
+		return callFunc(c, c.onEventChannelModerate, *event, payloadContext), true
+	case *EventChannelAdBreakBegin:
+		return callFunc(c, c.onEventChannelAdBreakBegin, *event, payloadContext), true
+	case *EventChannelWarningAcknowledge:
+		return callFunc(c, c.onEventChannelWarningAcknowledge, *event, payloadContext), true
+	case *EventChannelWarningSend:
+		return callFunc(c, c.onEventChannelWarningSend, *event, payloadContext), true
+	case *EventChannelUnbanRequestCreate:
+		return callFunc(c, c.onEventChannelUnbanRequestCreate, *event, payloadContext), true
+	case *EventChannelUnbanRequestResolve:
+		return callFunc(c, c.onEventChannelUnbanRequestResolve, *event, payloadContext), true
+	case *EventAutomodMessageHold:
+		return callFunc(c, c.onEventAutomodMessageHold, *event, payloadContext), true
+	case *EventAutomodMessageUpdate:
+		return callFunc(c, c.onEventAutomodMessageUpdate, *event, payloadContext), true
+	case *EventAutomodSettingsUpdate:
+		return callFunc(c, c.onEventAutomodSettingsUpdate, *event, payloadContext), true
+	case *EventAutomodTermsUpdate:
+		return callFunc(c, c.onEventAutomodTermsUpdate, *event, payloadContext), true
+	case *EventChannelChatUserMessageHold:
+		return callFunc(c, c.onEventChannelChatUserMessageHold, *event, payloadContext), true
+	case *EventChannelChatUserMessageUpdate:
+		return callFunc(c, c.onEventChannelChatUserMessageUpdate, *event, payloadContext), true
+	case *EventChannelChatClear:
+		return callFunc(c, c.onEventChannelChatClear, *event, payloadContext), true
+	case *EventChannelChatClearUserMessages:
+		return callFunc(c, c.onEventChannelChatClearUserMessages, *event, payloadContext), true
+	case *EventChannelChatMessage:
+		return callFunc(c, c.onEventChannelChatMessage, *event, payloadContext), true
+	case *EventChannelChatMessageDelete:
+		return callFunc(c, c.onEventChannelChatMessageDelete, *event, payloadContext), true
+	case *EventChannelChatNotification:
+		return callFunc(c, c.onEventChannelChatNotification, *event, payloadContext), true
+	case *EventChannelChatSettingsUpdate:
+		return callFunc(c, c.onEventChannelChatSettingsUpdate, *event, payloadContext), true
+	case *EventChannelSuspiciousUserMessage:
+		return callFunc(c, c.onEventChannelSuspiciousUserMessage, *event, payloadContext), true
+	case *EventChannelSuspiciousUserUpdate:
+		return callFunc(c, c.onEventChannelSuspiciousUserUpdate, *event, payloadContext), true
+	case *EventChannelSharedChatBegin:
+		return callFunc(c, c.onEventChannelSharedChatBegin, *event, payloadContext), true
+	case *EventChannelSharedChatUpdate:
+		return callFunc(c, c.onEventChannelSharedChatUpdate, *event, payloadContext), true
+	case *EventChannelSharedChatEnd:
+		return callFunc(c, c.onEventChannelSharedChatEnd, *event, payloadContext), true
+	case *EventChannelGuestStarSessionBegin:
+		return callFunc(c, c.onEventChannelGuestStarSessionBegin, *event, payloadContext), true
+	case *EventChannelGuestStarSessionEnd:
+		return callFunc(c, c.onEventChannelGuestStarSessionEnd, *event, payloadContext), true
+	case *EventChannelGuestStarGuestUpdate:
+		return callFunc(c, c.onEventChannelGuestStarGuestUpdate, *event, payloadContext), true
+	case *EventChannelGuestStarSettingsUpdate:
+		return callFunc(c, c.onEventChannelGuestStarSettingsUpdate, *event, payloadContext), true
+	case *EventUserWhisperMessage:
+		return callFunc(c, c.onEventUserWhisperMessage, *event, payloadContext), true
+	case *EventConduitShardDisabled:
+		return callFunc(c, c.onEventConduitShardDisabled, *event, payloadContext), true
+	}
+	return false, false
+}
+
+// hasTypedListener reports whether the typed callback for the subscription type is
+// registered. known is false for subscription types without a typed callback.
+func (c *Client) hasTypedListener(subscriptionType EventSubscription) (registered bool, known bool) {
+	switch subscriptionType {
+	case "channel.update":
+		return c.onEventChannelUpdate != nil, true
+	case "channel.follow":
+		return c.onEventChannelFollow != nil, true
+	case "channel.subscribe":
+		return c.onEventChannelSubscribe != nil, true
+	case "channel.subscription.end":
+		return c.onEventChannelSubscriptionEnd != nil, true
+	case "channel.subscription.gift":
+		return c.onEventChannelSubscriptionGift != nil, true
+	case "channel.subscription.message":
+		return c.onEventChannelSubscriptionMessage != nil, true
+	case "channel.cheer":
+		return c.onEventChannelCheer != nil, true
+	case "channel.raid":
+		return c.onEventChannelRaid != nil, true
+	case "channel.ban":
+		return c.onEventChannelBan != nil, true
+	case "channel.unban":
+		return c.onEventChannelUnban != nil, true
+	case "channel.moderator.add":
+		return c.onEventChannelModeratorAdd != nil, true
+	case "channel.moderator.remove":
+		return c.onEventChannelModeratorRemove != nil, true
+	case "channel.vip.add":
+		return c.onEventChannelVIPAdd != nil, true
+	case "channel.vip.remove":
+		return c.onEventChannelVIPRemove != nil, true
+	case "channel.channel_points_custom_reward.add":
+		return c.onEventChannelChannelPointsCustomRewardAdd != nil, true
+	case "channel.channel_points_custom_reward.update":
+		return c.onEventChannelChannelPointsCustomRewardUpdate != nil, true
+	case "channel.channel_points_custom_reward.remove":
+		return c.onEventChannelChannelPointsCustomRewardRemove != nil, true
+	case "channel.channel_points_custom_reward_redemption.add":
+		return c.onEventChannelChannelPointsCustomRewardRedemptionAdd != nil, true
+	case "channel.channel_points_custom_reward_redemption.update":
+		return c.onEventChannelChannelPointsCustomRewardRedemptionUpdate != nil, true
+	case "channel.channel_points_automatic_reward_redemption.add":
+		return c.onEventChannelChannelPointsAutomaticRewardRedemptionAdd != nil, true
+	case "channel.poll.begin":
+		return c.onEventChannelPollBegin != nil, true
+	case "channel.poll.progress":
+		return c.onEventChannelPollProgress != nil, true
+	case "channel.poll.end":
+		return c.onEventChannelPollEnd != nil, true
+	case "channel.prediction.begin":
+		return c.onEventChannelPredictionBegin != nil, true
+	case "channel.prediction.progress":
+		return c.onEventChannelPredictionProgress != nil, true
+	case "channel.prediction.lock":
+		return c.onEventChannelPredictionLock != nil, true
+	case "channel.prediction.end":
+		return c.onEventChannelPredictionEnd != nil, true
+	case "drop.entitlement.grant":
+		return c.onEventDropEntitlementGrant != nil, true
+	case "extension.bits_transaction.create":
+		return c.onEventExtensionBitsTransactionCreate != nil, true
+	case "channel.goal.begin":
+		return c.onEventChannelGoalBegin != nil, true
+	case "channel.goal.progress":
+		return c.onEventChannelGoalProgress != nil, true
+	case "channel.goal.end":
+		return c.onEventChannelGoalEnd != nil, true
+	case "channel.hype_train.begin":
+		return c.onEventChannelHypeTrainBegin != nil, true
+	case "channel.hype_train.progress":
+		return c.onEventChannelHypeTrainProgress != nil, true
+	case "channel.hype_train.end":
+		return c.onEventChannelHypeTrainEnd != nil, true
+	case "stream.online":
+		return c.onEventStreamOnline != nil, true
+	case "stream.offline":
+		return c.onEventStreamOffline != nil, true
+	case "user.authorization.grant":
+		return c.onEventUserAuthorizationGrant != nil, true
+	case "user.authorization.revoke":
+		return c.onEventUserAuthorizationRevoke != nil, true
+	case "user.update":
+		return c.onEventUserUpdate != nil, true
+	case "channel.charity_campaign.donate":
+		return c.onEventChannelCharityCampaignDonate != nil, true
+	case "channel.charity_campaign.start":
+		return c.onEventChannelCharityCampaignStart != nil, true
+	case "channel.charity_campaign.progress":
+		return c.onEventChannelCharityCampaignProgress != nil, true
+	case "channel.charity_campaign.stop":
+		return c.onEventChannelCharityCampaignStop != nil, true
+	case "channel.shield_mode.begin":
+		return c.onEventChannelShieldModeBegin != nil, true
+	case "channel.shield_mode.end":
+		return c.onEventChannelShieldModeEnd != nil, true
+	case "channel.shoutout.create":
+		return c.onEventChannelShoutoutCreate != nil, true
+	case "channel.shoutout.receive":
+		return c.onEventChannelShoutoutReceive != nil, true
+	case "channel.moderate":
+		return c.onEventChannelModerate != nil, true
+	case "channel.ad_break.begin":
+		return c.onEventChannelAdBreakBegin != nil, true
+	case "channel.warning.acknowledge":
+		return c.onEventChannelWarningAcknowledge != nil, true
+	case "channel.warning.send":
+		return c.onEventChannelWarningSend != nil, true
+	case "channel.unban_request.create":
+		return c.onEventChannelUnbanRequestCreate != nil, true
+	case "channel.unban_request.resolve":
+		return c.onEventChannelUnbanRequestResolve != nil, true
+	case "automod.message.hold":
+		return c.onEventAutomodMessageHold != nil, true
+	case "automod.message.update":
+		return c.onEventAutomodMessageUpdate != nil, true
+	case "automod.settings.update":
+		return c.onEventAutomodSettingsUpdate != nil, true
+	case "automod.terms.update":
+		return c.onEventAutomodTermsUpdate != nil, true
+	case "channel.chat.user_message_hold":
+		return c.onEventChannelChatUserMessageHold != nil, true
+	case "channel.chat.user_message_update":
+		return c.onEventChannelChatUserMessageUpdate != nil, true
+	case "channel.chat.clear":
+		return c.onEventChannelChatClear != nil, true
+	case "channel.chat.clear_user_messages":
+		return c.onEventChannelChatClearUserMessages != nil, true
+	case "channel.chat.message":
+		return c.onEventChannelChatMessage != nil, true
+	case "channel.chat.message_delete":
+		return c.onEventChannelChatMessageDelete != nil, true
+	case "channel.chat.notification":
+		return c.onEventChannelChatNotification != nil, true
+	case "channel.chat_settings.update":
+		return c.onEventChannelChatSettingsUpdate != nil, true
+	case "channel.suspicious_user.message":
+		return c.onEventChannelSuspiciousUserMessage != nil, true
+	case "channel.suspicious_user.update":
+		return c.onEventChannelSuspiciousUserUpdate != nil, true
+	case "channel.shared_chat.begin":
+		return c.onEventChannelSharedChatBegin != nil, true
+	case "channel.shared_chat.update":
+		return c.onEventChannelSharedChatUpdate != nil, true
+	case "channel.shared_chat.end":
+		return c.onEventChannelSharedChatEnd != nil, true
+	case "channel.guest_star_session.begin":
+		return c.onEventChannelGuestStarSessionBegin != nil, true
+	case "channel.guest_star_session.end":
+		return c.onEventChannelGuestStarSessionEnd != nil, true
+	case "channel.guest_star_guest.update":
+		return c.onEventChannelGuestStarGuestUpdate != nil, true
+	case "channel.guest_star_settings.update":
+		return c.onEventChannelGuestStarSettingsUpdate != nil, true
+	case "user.whisper.message":
+		return c.onEventUserWhisperMessage != nil, true
+	case "conduit.shard.disabled":
+		return c.onEventConduitShardDisabled != nil, true
+	}
+	return false, false
+}
+
+// newEventFor allocates the event struct for the subscription type.
+func newEventFor(subscriptionType EventSubscription) any {
+	switch subscriptionType {
+	case "channel.update":
+		return &EventChannelUpdate{}
+	case "channel.follow":
+		return &EventChannelFollow{}
+	case "channel.subscribe":
+		return &EventChannelSubscribe{}
+	case "channel.subscription.end":
+		return &EventChannelSubscriptionEnd{}
+	case "channel.subscription.gift":
+		return &EventChannelSubscriptionGift{}
+	case "channel.subscription.message":
+		return &EventChannelSubscriptionMessage{}
+	case "channel.cheer":
+		return &EventChannelCheer{}
+	case "channel.raid":
+		return &EventChannelRaid{}
+	case "channel.ban":
+		return &EventChannelBan{}
+	case "channel.unban":
+		return &EventChannelUnban{}
+	case "channel.moderator.add":
+		return &EventChannelModeratorAdd{}
+	case "channel.moderator.remove":
+		return &EventChannelModeratorRemove{}
+	case "channel.vip.add":
+		return &EventChannelVIPAdd{}
+	case "channel.vip.remove":
+		return &EventChannelVIPRemove{}
+	case "channel.channel_points_custom_reward.add":
+		return &EventChannelChannelPointsCustomRewardAdd{}
+	case "channel.channel_points_custom_reward.update":
+		return &EventChannelChannelPointsCustomRewardUpdate{}
+	case "channel.channel_points_custom_reward.remove":
+		return &EventChannelChannelPointsCustomRewardRemove{}
+	case "channel.channel_points_custom_reward_redemption.add":
+		return &EventChannelChannelPointsCustomRewardRedemptionAdd{}
+	case "channel.channel_points_custom_reward_redemption.update":
+		return &EventChannelChannelPointsCustomRewardRedemptionUpdate{}
+	case "channel.channel_points_automatic_reward_redemption.add":
+		return &EventChannelChannelPointsAutomaticRewardRedemptionAdd{}
+	case "channel.poll.begin":
+		return &EventChannelPollBegin{}
+	case "channel.poll.progress":
+		return &EventChannelPollProgress{}
+	case "channel.poll.end":
+		return &EventChannelPollEnd{}
+	case "channel.prediction.begin":
+		return &EventChannelPredictionBegin{}
+	case "channel.prediction.progress":
+		return &EventChannelPredictionProgress{}
+	case "channel.prediction.lock":
+		return &EventChannelPredictionLock{}
+	case "channel.prediction.end":
+		return &EventChannelPredictionEnd{}
+	case "drop.entitlement.grant":
+		return &EventDropEntitlementGrantBatch{}
+	case "extension.bits_transaction.create":
+		return &EventExtensionBitsTransactionCreate{}
+	case "channel.goal.begin":
+		return &EventChannelGoalBegin{}
+	case "channel.goal.progress":
+		return &EventChannelGoalProgress{}
+	case "channel.goal.end":
+		return &EventChannelGoalEnd{}
+	case "channel.hype_train.begin":
+		return &EventChannelHypeTrainBegin{}
+	case "channel.hype_train.progress":
+		return &EventChannelHypeTrainProgress{}
+	case "channel.hype_train.end":
+		return &EventChannelHypeTrainEnd{}
+	case "stream.online":
+		return &EventStreamOnline{}
+	case "stream.offline":
+		return &EventStreamOffline{}
+	case "user.authorization.grant":
+		return &EventUserAuthorizationGrant{}
+	case "user.authorization.revoke":
+		return &EventUserAuthorizationRevoke{}
+	case "user.update":
+		return &EventUserUpdate{}
+	case "channel.charity_campaign.donate":
+		return &EventChannelCharityCampaignDonate{}
+	case "channel.charity_campaign.start":
+		return &EventChannelCharityCampaignStart{}
+	case "channel.charity_campaign.progress":
+		return &EventChannelCharityCampaignProgress{}
+	case "channel.charity_campaign.stop":
+		return &EventChannelCharityCampaignStop{}
+	case "channel.shield_mode.begin":
+		return &EventChannelShieldModeBegin{}
+	case "channel.shield_mode.end":
+		return &EventChannelShieldModeEnd{}
+	case "channel.shoutout.create":
+		return &EventChannelShoutoutCreate{}
+	case "channel.shoutout.receive":
+		return &EventChannelShoutoutReceive{}
+	case "channel.moderate":
+		return &EventChannelModerate{}
+	case "channel.ad_break.begin":
+		return &EventChannelAdBreakBegin{}
+	case "channel.warning.acknowledge":
+		return &EventChannelWarningAcknowledge{}
+	case "channel.warning.send":
+		return &EventChannelWarningSend{}
+	case "channel.unban_request.create":
+		return &EventChannelUnbanRequestCreate{}
+	case "channel.unban_request.resolve":
+		return &EventChannelUnbanRequestResolve{}
+	case "automod.message.hold":
+		return &EventAutomodMessageHold{}
+	case "automod.message.update":
+		return &EventAutomodMessageUpdate{}
+	case "automod.settings.update":
+		return &EventAutomodSettingsUpdate{}
+	case "automod.terms.update":
+		return &EventAutomodTermsUpdate{}
+	case "channel.chat.user_message_hold":
+		return &EventChannelChatUserMessageHold{}
+	case "channel.chat.user_message_update":
+		return &EventChannelChatUserMessageUpdate{}
+	case "channel.chat.clear":
+		return &EventChannelChatClear{}
+	case "channel.chat.clear_user_messages":
+		return &EventChannelChatClearUserMessages{}
+	case "channel.chat.message":
+		return &EventChannelChatMessage{}
+	case "channel.chat.message_delete":
+		return &EventChannelChatMessageDelete{}
+	case "channel.chat.notification":
+		return &EventChannelChatNotification{}
+	case "channel.chat_settings.update":
+		return &EventChannelChatSettingsUpdate{}
+	case "channel.suspicious_user.message":
+		return &EventChannelSuspiciousUserMessage{}
+	case "channel.suspicious_user.update":
+		return &EventChannelSuspiciousUserUpdate{}
+	case "channel.shared_chat.begin":
+		return &EventChannelSharedChatBegin{}
+	case "channel.shared_chat.update":
+		return &EventChannelSharedChatUpdate{}
+	case "channel.shared_chat.end":
+		return &EventChannelSharedChatEnd{}
+	case "channel.guest_star_session.begin":
+		return &EventChannelGuestStarSessionBegin{}
+	case "channel.guest_star_session.end":
+		return &EventChannelGuestStarSessionEnd{}
+	case "channel.guest_star_guest.update":
+		return &EventChannelGuestStarGuestUpdate{}
+	case "channel.guest_star_settings.update":
+		return &EventChannelGuestStarSettingsUpdate{}
+	case "user.whisper.message":
+		return &EventUserWhisperMessage{}
+	case "conduit.shard.disabled":
+		return &EventConduitShardDisabled{}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package twitch
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatcherBoundsWorkers(t *testing.T) {
+	d := newDispatcher(4)
+
+	var running, maxRunning int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		d.dispatch(func() {
+			defer wg.Done()
+			n := atomic.AddInt64(&running, 1)
+			for {
+				max := atomic.LoadInt64(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt64(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+		})
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxRunning, int64(4))
+	assert.LessOrEqual(t, len(d.workers), 4)
+}
+
+func TestDispatcherWorkersExitWhenIdle(t *testing.T) {
+	d := newDispatcher(2)
+
+	done := make(chan struct{})
+	d.dispatch(func() { close(done) })
+	<-done
+
+	assert.Eventually(t, func() bool { return len(d.workers) == 0 }, 3*dispatcherIdleTimeout, 10*time.Millisecond)
+}
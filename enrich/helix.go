@@ -0,0 +1,300 @@
+// Package enrich looks up the users, stream, and game of notifications with the Twitch
+// API, caching the results, for the Enrich function of a publish bridge.
+//
+//	enricher := enrich.NewHelix(clientID, appAccessToken)
+//	client.SetPublishBridge(&twitch.PublishBridge{
+//		Publisher: publisher,
+//		Format:    twitch.PublishEnvelope,
+//		Enrich:    enricher.Enrich,
+//	})
+//
+// Published notifications then carry an enrichment like
+//
+//	{"users": {"1337": {"id": "1337", "login": "streamer", ...}}, "stream": {...}, "game": {...}}
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API looked up.
+const DefaultHelixURL = helix.DefaultURL
+
+// User is the profile of a user from Get Users.
+type User struct {
+	ID              string    `json:"id"`
+	Login           string    `json:"login"`
+	DisplayName     string    `json:"display_name"`
+	Type            string    `json:"type"`
+	BroadcasterType string    `json:"broadcaster_type"`
+	Description     string    `json:"description"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	OfflineImageURL string    `json:"offline_image_url"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Stream is the live stream of a broadcaster from Get Streams.
+type Stream struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	GameID       string    `json:"game_id"`
+	GameName     string    `json:"game_name"`
+	Type         string    `json:"type"`
+	Title        string    `json:"title"`
+	Tags         []string  `json:"tags"`
+	ViewerCount  int       `json:"viewer_count"`
+	StartedAt    time.Time `json:"started_at"`
+	Language     string    `json:"language"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	IsMature     bool      `json:"is_mature"`
+}
+
+// Game is a category from Get Games.
+type Game struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	BoxArtURL string `json:"box_art_url"`
+	IGDBID    string `json:"igdb_id"`
+}
+
+type cached struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Helix enriches notifications with the profiles of the users of the event, under
+// users, the stream of the broadcaster while live, under stream, and the game of the
+// stream or of the category the event names, under game. It is safe for concurrent
+// use.
+type Helix struct {
+	ClientID    string
+	AccessToken string
+	// URL defaults to DefaultHelixURL.
+	URL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+	// UserTTL is how long users are cached. Defaults to 1 hour.
+	UserTTL time.Duration
+	// StreamTTL is how long streams, and broadcasters being offline, are cached.
+	// Defaults to 1 minute.
+	StreamTTL time.Duration
+	// GameTTL is how long games are cached. Defaults to 24 hours.
+	GameTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cached
+	now   func() time.Time
+}
+
+// NewHelix returns an enricher calling the API with an app or user access token.
+func NewHelix(clientID, accessToken string) *Helix {
+	return &Helix{
+		ClientID:    clientID,
+		AccessToken: accessToken,
+		UserTTL:     time.Hour,
+		StreamTTL:   time.Minute,
+		GameTTL:     24 * time.Hour,
+		cache:       make(map[string]cached),
+		now:         time.Now,
+	}
+}
+
+// Enrich looks up what the notification refers to, from the cache when it can. It
+// returns what it found even when some lookups failed.
+func (h *Helix) Enrich(ctx context.Context, notification twitch.PublishedNotification) (map[string]any, error) {
+	var event map[string]any
+	// Batched events are arrays, which only get the stream of the broadcaster.
+	_ = json.Unmarshal(notification.Event, &event)
+
+	enrichment := make(map[string]any)
+	var errs []string
+
+	userIDs := eventUserIDs(event)
+	if len(userIDs) > 0 {
+		users, err := h.users(ctx, userIDs)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if len(users) > 0 {
+			enrichment["users"] = users
+		}
+	}
+
+	gameID, _ := event["category_id"].(string)
+	if broadcaster := broadcasterID(notification.Subscription, event); broadcaster != "" {
+		stream, err := h.stream(ctx, broadcaster)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if stream != nil {
+			enrichment["stream"] = stream
+			if gameID == "" {
+				gameID = stream.GameID
+			}
+		}
+	}
+
+	if gameID != "" {
+		game, err := h.game(ctx, gameID)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if game != nil {
+			enrichment["game"] = game
+		}
+	}
+
+	if len(errs) > 0 {
+		return enrichment, fmt.Errorf("enrich: %s", strings.Join(errs, "; "))
+	}
+	return enrichment, nil
+}
+
+// eventUserIDs returns the values of the fields of the event ending with user_id, like
+// user_id, broadcaster_user_id, or from_broadcaster_user_id, sorted.
+func eventUserIDs(event map[string]any) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for key, value := range event {
+		id, ok := value.(string)
+		if !ok || id == "" || !strings.HasSuffix(key, "user_id") || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func broadcasterID(subscription twitch.PayloadSubscription, event map[string]any) string {
+	for _, key := range []string{"broadcaster_user_id", "to_broadcaster_user_id"} {
+		if id := subscription.Condition[key]; id != "" {
+			return id
+		}
+		if id, ok := event[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func (h *Helix) lookup(key string) (any, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry, ok := h.cache[key]
+	if !ok || h.now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (h *Helix) store(key string, value any, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	// Drop expired entries once the cache grows, so it holds what is in use.
+	if len(h.cache) >= 10000 {
+		for key, entry := range h.cache {
+			if now.After(entry.expiresAt) {
+				delete(h.cache, key)
+			}
+		}
+	}
+	h.cache[key] = cached{value: value, expiresAt: now.Add(ttl)}
+}
+
+func (h *Helix) users(ctx context.Context, ids []string) (map[string]User, error) {
+	users := make(map[string]User, len(ids))
+	query := url.Values{}
+	for _, id := range ids {
+		if user, ok := h.lookup("user:" + id); ok {
+			if user != nil {
+				users[id] = user.(User)
+			}
+			continue
+		}
+		query.Add("id", id)
+	}
+	if len(query) == 0 {
+		return users, nil
+	}
+
+	var response struct {
+		Data []User `json:"data"`
+	}
+	err := h.get(ctx, "/users", query, &response)
+	if err != nil {
+		return users, fmt.Errorf("could not get users: %w", err)
+	}
+	for _, user := range response.Data {
+		h.store("user:"+user.ID, user, h.UserTTL)
+		users[user.ID] = user
+	}
+	// Users Twitch doesn't return, like deleted or suspended ones, are cached as nil so
+	// their events don't look them up every time.
+	for _, id := range query["id"] {
+		if _, ok := users[id]; !ok {
+			h.store("user:"+id, nil, h.UserTTL)
+		}
+	}
+	return users, nil
+}
+
+func (h *Helix) stream(ctx context.Context, broadcaster string) (*Stream, error) {
+	if stream, ok := h.lookup("stream:" + broadcaster); ok {
+		return stream.(*Stream), nil
+	}
+
+	var response struct {
+		Data []Stream `json:"data"`
+	}
+	err := h.get(ctx, "/streams", url.Values{"user_id": {broadcaster}}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stream: %w", err)
+	}
+	var stream *Stream
+	if len(response.Data) > 0 {
+		stream = &response.Data[0]
+	}
+	h.store("stream:"+broadcaster, stream, h.StreamTTL)
+	return stream, nil
+}
+
+func (h *Helix) game(ctx context.Context, id string) (*Game, error) {
+	if game, ok := h.lookup("game:" + id); ok {
+		return game.(*Game), nil
+	}
+
+	var response struct {
+		Data []Game `json:"data"`
+	}
+	err := h.get(ctx, "/games", url.Values{"id": {id}}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("could not get game: %w", err)
+	}
+	var game *Game
+	if len(response.Data) > 0 {
+		game = &response.Data[0]
+	}
+	h.store("game:"+id, game, h.GameTTL)
+	return game, nil
+}
+
+func (h *Helix) get(ctx context.Context, path string, query url.Values, v any) error {
+	client := helix.Client{URL: h.URL, ClientID: h.ClientID, AccessToken: h.AccessToken, HTTPClient: h.Client}
+	return client.Do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, v)
+}
@@ -0,0 +1,124 @@
+package enrich_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/enrich"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHelix returns a mock of the API answering the lookups of users, streams, and games.
+func newHelix(t *testing.T) *twitchtest.Helix {
+	helix := twitchtest.NewHelix(t)
+	helix.Handle(http.MethodGet, "/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[`)
+		for i, id := range r.URL.Query()["id"] {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id":%q,"login":"user%s","display_name":"User%s"}`, id, id, id)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	helix.Handle(http.MethodGet, "/streams", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("user_id") == "1" {
+			fmt.Fprint(w, `{"data":[{"id":"stream","user_id":"1","game_id":"509658","title":"hi","viewer_count":3}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	helix.Handle(http.MethodGet, "/games", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"id":%q,"name":"Just Chatting"}]}`, r.URL.Query().Get("id"))
+	})
+	return helix
+}
+
+func notification(event string, condition map[string]string) twitch.PublishedNotification {
+	return twitch.PublishedNotification{
+		Subscription: twitch.PayloadSubscription{SubscriptionRequest: twitch.SubscriptionRequest{
+			Type:      twitch.SubChannelRaid,
+			Condition: condition,
+		}},
+		Event: json.RawMessage(event),
+	}
+}
+
+func TestHelixEnrich(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newHelix(t)
+	helix := enrich.NewHelix("client", "token")
+	helix.URL = server.URL
+
+	raid := notification(`{"from_broadcaster_user_id":"2","to_broadcaster_user_id":"1","viewers":5}`, map[string]string{"to_broadcaster_user_id": "1"})
+	enrichment, err := helix.Enrich(context.Background(), raid)
+	require.NoError(t, err)
+
+	users := enrichment["users"].(map[string]enrich.User)
+	assert.Equal(t, "user1", users["1"].Login)
+	assert.Equal(t, "User2", users["2"].DisplayName)
+	stream := enrichment["stream"].(*enrich.Stream)
+	assert.Equal(t, "hi", stream.Title)
+	assert.Equal(t, "Just Chatting", enrichment["game"].(*enrich.Game).Name)
+
+	// Everything is cached now.
+	_, err = helix.Enrich(context.Background(), raid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /users?id=1&id=2", "GET /streams?user_id=1", "GET /games?id=509658"}, server.Requests())
+	request, err := server.WaitForRequest(ctx, http.MethodGet, "/users")
+	require.NoError(t, err)
+	assert.Equal(t, "client", request.ClientID)
+	assert.Equal(t, "Bearer token", request.Authorization)
+
+	// Offline broadcasters have no stream, and the category of the event names the game.
+	update := notification(`{"broadcaster_user_id":"3","category_id":"1"}`, map[string]string{"broadcaster_user_id": "3"})
+	enrichment, err = helix.Enrich(context.Background(), update)
+	require.NoError(t, err)
+	assert.NotContains(t, enrichment, "stream")
+	assert.Equal(t, "1", enrichment["game"].(*enrich.Game).ID)
+	assert.Equal(t, []string{"GET /users?id=3", "GET /streams?user_id=3", "GET /games?id=1"}, server.Requests())
+}
+
+func TestHelixEnrichError(t *testing.T) {
+	t.Parallel()
+
+	server := twitchtest.NewHelix(t)
+	server.Respond(http.MethodGet, "/users", http.StatusUnauthorized)
+	helix := enrich.NewHelix("client", "token")
+	helix.URL = server.URL
+
+	enrichment, err := helix.Enrich(context.Background(), notification(`{"user_id":"1"}`, nil))
+	assert.ErrorContains(t, err, "could not get users: 401 Unauthorized")
+	assert.Empty(t, enrichment)
+}
+
+func TestHelixEnrichUnknownUser(t *testing.T) {
+	t.Parallel()
+
+	server := twitchtest.NewHelix(t)
+	server.Handle(http.MethodGet, "/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":"1","login":"user1"}]}`)
+	})
+	helix := enrich.NewHelix("client", "token")
+	helix.URL = server.URL
+
+	follow := notification(`{"user_id":"1","moderator_user_id":"2"}`, nil)
+	enrichment, err := helix.Enrich(context.Background(), follow)
+	require.NoError(t, err)
+	users := enrichment["users"].(map[string]enrich.User)
+	assert.Equal(t, "user1", users["1"].Login)
+	assert.NotContains(t, users, "2")
+
+	// The user Twitch didn't return is cached too.
+	_, err = helix.Enrich(context.Background(), follow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /users?id=1&id=2"}, server.Requests())
+}
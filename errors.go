@@ -0,0 +1,36 @@
+package twitch
+
+// MessageError carries the context of the message which caused an error so error
+// aggregation can group by subscription or session instead of by message text.
+type MessageError struct {
+	Err error
+
+	MessageID        string
+	MessageType      string
+	SessionID        string
+	SubscriptionType EventSubscription
+	SubscriptionID   string
+	CorrelationID    string
+}
+
+func (e *MessageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MessageError) Unwrap() error {
+	return e.Err
+}
+
+func (c *Client) newMessageError(metadata MessageMetadata, subscription *PayloadSubscription, err error) *MessageError {
+	messageErr := &MessageError{
+		Err:         err,
+		MessageID:   metadata.MessageID,
+		MessageType: metadata.MessageType,
+		SessionID:   c.sessionID(),
+	}
+	if subscription != nil {
+		messageErr.SubscriptionType = subscription.Type
+		messageErr.SubscriptionID = subscription.ID
+	}
+	return messageErr
+}
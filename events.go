@@ -1,7 +1,11 @@
 package twitch
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -71,10 +75,45 @@ type SlowMode struct {
 	WaitTimeSeconds int `json:"wait_time_seconds"`
 }
 
+func warningDescription(reason string, rules ChatRules) string {
+	switch {
+	case len(rules) == 0:
+		return reason
+	case reason == "":
+		return "rules cited: " + rules.String()
+	}
+	return reason + " (rules cited: " + rules.String() + ")"
+}
+
+// Description is like EventChannelWarningSend.Description.
+func (w Warning) Description() string {
+	return warningDescription(w.Reason, w.ChatRulesCited)
+}
+
+// ChatRules are the chat rules of the channel cited in a warning, as written in the
+// channel's rules.
+type ChatRules []string
+
+// Contains reports whether the rule was cited, ignoring case and surrounding spaces.
+func (r ChatRules) Contains(rule string) bool {
+	rule = strings.TrimSpace(rule)
+	for _, cited := range r {
+		if strings.EqualFold(strings.TrimSpace(cited), rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the rules separated by commas.
+func (r ChatRules) String() string {
+	return strings.Join(r, ", ")
+}
+
 type Warning struct {
 	User
-	Reason         string   `json:"reason"`
-	ChatRulesCited []string `json:"chat_rules_cited"`
+	Reason         string    `json:"reason"`
+	ChatRulesCited ChatRules `json:"chat_rules_cited"`
 }
 
 type Target struct {
@@ -95,14 +134,36 @@ type HostBroadcaster struct {
 	HostBroadcasterUserName  string `json:"host_broadcaster_user_name"`
 }
 
+type ContentClassificationLabel string
+
+const (
+	ContentClassificationDebatedSocialIssuesAndPolitics ContentClassificationLabel = "DebatedSocialIssuesAndPolitics"
+	ContentClassificationDrugsIntoxication              ContentClassificationLabel = "DrugsIntoxication"
+	ContentClassificationSexualThemes                   ContentClassificationLabel = "SexualThemes"
+	ContentClassificationViolentGraphic                 ContentClassificationLabel = "ViolentGraphic"
+	ContentClassificationGambling                       ContentClassificationLabel = "Gambling"
+	ContentClassificationProfanityVulgarity             ContentClassificationLabel = "ProfanityVulgarity"
+	ContentClassificationMatureGame                     ContentClassificationLabel = "MatureGame"
+)
+
 type EventChannelUpdate struct {
 	Broadcaster
 
-	Title                       string   `json:"title"`
-	Language                    string   `json:"language"`
-	CategoryID                  string   `json:"category_id"`
-	CategoryName                string   `json:"category_name"`
-	ContentClassificationLabels []string `json:"content_classification_labels"`
+	Title                       string                       `json:"title"`
+	Language                    string                       `json:"language"`
+	CategoryID                  string                       `json:"category_id"`
+	CategoryName                string                       `json:"category_name"`
+	ContentClassificationLabels []ContentClassificationLabel `json:"content_classification_labels"`
+}
+
+// HasContentClassificationLabel reports whether the stream is labeled with label.
+func (e EventChannelUpdate) HasContentClassificationLabel(label ContentClassificationLabel) bool {
+	for _, l := range e.ContentClassificationLabels {
+		if l == label {
+			return true
+		}
+	}
+	return false
 }
 
 type EventChannelFollow struct {
@@ -179,6 +240,31 @@ type EventChannelRaid struct {
 	Viewers                  int    `json:"viewers"`
 }
 
+type RaidDirection string
+
+const (
+	// RaidIncoming is a raid of the broadcaster's channel.
+	RaidIncoming RaidDirection = "incoming"
+	// RaidOutgoing is the broadcaster raiding another channel.
+	RaidOutgoing RaidDirection = "outgoing"
+	// RaidUnknown is a raid not involving the broadcaster, or whose direction can't
+	// be told.
+	RaidUnknown RaidDirection = ""
+)
+
+// Direction returns whether the broadcaster with the ID is being raided or raiding.
+func (e EventChannelRaid) Direction(selfBroadcasterID string) RaidDirection {
+	switch selfBroadcasterID {
+	case "":
+		return RaidUnknown
+	case e.ToBroadcasterUserId:
+		return RaidIncoming
+	case e.FromBroadcasterUserId:
+		return RaidOutgoing
+	}
+	return RaidUnknown
+}
+
 type EventChannelBan struct {
 	User
 	Broadcaster
@@ -221,17 +307,68 @@ type MaxChannelPointsPerStream struct {
 	Value     int  `json:"value"`
 }
 
+// Limit returns the maximum and false if there is none.
+func (m MaxChannelPointsPerStream) Limit() (int, bool) {
+	return m.Value, m.IsEnabled
+}
+
+// ImageScale is the scale of an image, 1x being 28 by 28 pixels.
+type ImageScale int
+
+const (
+	ImageScale1x ImageScale = 1
+	ImageScale2x ImageScale = 2
+	ImageScale4x ImageScale = 4
+)
+
 type Image struct {
 	Url1x string `json:"url_1x"`
 	Url2x string `json:"url_2x"`
 	Url4x string `json:"url_4x"`
 }
 
+func (i Image) IsZero() bool {
+	return i.Url1x == "" && i.Url2x == "" && i.Url4x == ""
+}
+
+// URL returns the URL of the image at the scale, or of the largest smaller scale the
+// image has, falling back to larger scales.
+func (i Image) URL(scale ImageScale) string {
+	urls := []string{i.Url1x, i.Url2x, i.Url4x}
+	index := 0
+	switch {
+	case scale >= ImageScale4x:
+		index = 2
+	case scale >= ImageScale2x:
+		index = 1
+	}
+
+	for j := index; j >= 0; j-- {
+		if urls[j] != "" {
+			return urls[j]
+		}
+	}
+	for j := index + 1; j < len(urls); j++ {
+		if urls[j] != "" {
+			return urls[j]
+		}
+	}
+	return ""
+}
+
 type GlobalCooldown struct {
 	IsEnabled bool `json:"is_enabled"`
 	Seconds   int  `json:"seconds"`
 }
 
+// Duration returns the cooldown and zero if it is disabled.
+func (g GlobalCooldown) Duration() time.Duration {
+	if !g.IsEnabled {
+		return 0
+	}
+	return time.Duration(g.Seconds) * time.Second
+}
+
 type EventChannelChannelPointsCustomRewardAdd struct {
 	Broadcaster
 
@@ -254,10 +391,53 @@ type EventChannelChannelPointsCustomRewardAdd struct {
 	RedemptionsRedeemedCurrentStream  int                       `json:"redemptions_redeemed_current_stream"`
 }
 
+// ImageURL returns the URL of the custom image at the scale, or of the default image if
+// the reward has no custom image.
+func (e EventChannelChannelPointsCustomRewardAdd) ImageURL(scale ImageScale) string {
+	if e.Image.IsZero() {
+		return e.DefaultImage.URL(scale)
+	}
+	return e.Image.URL(scale)
+}
+
+// CooldownRemaining returns how long the reward is on cooldown at now.
+func (e EventChannelChannelPointsCustomRewardAdd) CooldownRemaining(now time.Time) time.Duration {
+	return remainingUntil(e.CooldownExpiresAt, now)
+}
+
+// RemainingThisStream returns how many more times the reward can be redeemed in the
+// current stream and false if there is no maximum per stream.
+func (e EventChannelChannelPointsCustomRewardAdd) RemainingThisStream() (int, bool) {
+	limit, ok := e.MaxPerStream.Limit()
+	if !ok {
+		return 0, false
+	}
+	if remaining := limit - e.RedemptionsRedeemedCurrentStream; remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
 type EventChannelChannelPointsCustomRewardUpdate EventChannelChannelPointsCustomRewardAdd
 
+func (e EventChannelChannelPointsCustomRewardUpdate) ImageURL(scale ImageScale) string {
+	return EventChannelChannelPointsCustomRewardAdd(e).ImageURL(scale)
+}
+
+func (e EventChannelChannelPointsCustomRewardUpdate) CooldownRemaining(now time.Time) time.Duration {
+	return EventChannelChannelPointsCustomRewardAdd(e).CooldownRemaining(now)
+}
+
+func (e EventChannelChannelPointsCustomRewardUpdate) RemainingThisStream() (int, bool) {
+	return EventChannelChannelPointsCustomRewardAdd(e).RemainingThisStream()
+}
+
 type EventChannelChannelPointsCustomRewardRemove EventChannelChannelPointsCustomRewardAdd
 
+func (e EventChannelChannelPointsCustomRewardRemove) ImageURL(scale ImageScale) string {
+	return EventChannelChannelPointsCustomRewardAdd(e).ImageURL(scale)
+}
+
 type CustomChannelPointReward struct {
 	ID     string `json:"id"`
 	Title  string `json:"title"`
@@ -265,13 +445,22 @@ type CustomChannelPointReward struct {
 	Prompt string `json:"prompt"`
 }
 
+type RedemptionStatus string
+
+const (
+	RedemptionStatusUnknown     RedemptionStatus = "unknown"
+	RedemptionStatusUnfulfilled RedemptionStatus = "unfulfilled"
+	RedemptionStatusFulfilled   RedemptionStatus = "fulfilled"
+	RedemptionStatusCanceled    RedemptionStatus = "canceled"
+)
+
 type EventChannelChannelPointsCustomRewardRedemptionAdd struct {
 	Broadcaster
 	User
 
 	ID         string                   `json:"id"`
 	UserInput  string                   `json:"user_input"`
-	Status     string                   `json:"status"`
+	Status     RedemptionStatus         `json:"status"`
 	Reward     CustomChannelPointReward `json:"reward"`
 	RedeemedAt time.Time                `json:"redeemed_at"`
 }
@@ -308,6 +497,71 @@ type PollChoice struct {
 	Votes             int    `json:"votes"`
 }
 
+type PollChoices []PollChoice
+
+// Get returns the choice with the ID.
+func (c PollChoices) Get(id string) (PollChoice, bool) {
+	for _, choice := range c {
+		if choice.ID == id {
+			return choice, true
+		}
+	}
+	return PollChoice{}, false
+}
+
+// TotalVotes returns the votes on all choices, including the votes cast with bits and
+// channel points.
+func (c PollChoices) TotalVotes() int {
+	var total int
+	for _, choice := range c {
+		total += choice.Votes
+	}
+	return total
+}
+
+// TotalBitsVotes returns the votes cast with bits on all choices.
+func (c PollChoices) TotalBitsVotes() int {
+	var total int
+	for _, choice := range c {
+		total += choice.BitsVotes
+	}
+	return total
+}
+
+// TotalChannelPointVotes returns the votes cast with channel points on all choices.
+func (c PollChoices) TotalChannelPointVotes() int {
+	var total int
+	for _, choice := range c {
+		total += choice.ChannelPointVotes
+	}
+	return total
+}
+
+// Leaders returns the choices tied for the most votes, or nil if there are no votes.
+func (c PollChoices) Leaders() []PollChoice {
+	var leaders []PollChoice
+	for _, choice := range c {
+		switch {
+		case choice.Votes <= 0:
+		case len(leaders) == 0 || choice.Votes > leaders[0].Votes:
+			leaders = append(leaders[:0], choice)
+		case choice.Votes == leaders[0].Votes:
+			leaders = append(leaders, choice)
+		}
+	}
+	return leaders
+}
+
+// Leader returns the choice with the most votes. It returns false if there are no votes
+// or choices are tied, see Leaders.
+func (c PollChoices) Leader() (PollChoice, bool) {
+	leaders := c.Leaders()
+	if len(leaders) != 1 {
+		return PollChoice{}, false
+	}
+	return leaders[0], true
+}
+
 type PollVoting struct {
 	IsEnabled     bool `json:"is_enabled"`
 	AmountPerVote int  `json:"amount_per_vote"`
@@ -316,21 +570,46 @@ type PollVoting struct {
 type EventChannelPollBegin struct {
 	Broadcaster
 
-	ID                  string       `json:"id"`
-	Title               string       `json:"title"`
-	Choices             []PollChoice `json:"choices"`
-	BitsVoting          PollVoting   `json:"bits_voting"`
-	ChannelPointsVoting PollVoting   `json:"channel_points_voting"`
-	StartedAt           time.Time    `json:"started_at"`
-	EndsAt              time.Time    `json:"ends_at"`
+	ID                  string      `json:"id"`
+	Title               string      `json:"title"`
+	Choices             PollChoices `json:"choices"`
+	BitsVoting          PollVoting  `json:"bits_voting"`
+	ChannelPointsVoting PollVoting  `json:"channel_points_voting"`
+	StartedAt           time.Time   `json:"started_at"`
+	EndsAt              time.Time   `json:"ends_at"`
+}
+
+func (e EventChannelPollBegin) Leader() (PollChoice, bool) {
+	return e.Choices.Leader()
+}
+
+func (e EventChannelPollBegin) TotalVotes() int {
+	return e.Choices.TotalVotes()
 }
 
 type EventChannelPollProgress EventChannelPollBegin
 
+func (e EventChannelPollProgress) Leader() (PollChoice, bool) {
+	return e.Choices.Leader()
+}
+
+func (e EventChannelPollProgress) TotalVotes() int {
+	return e.Choices.TotalVotes()
+}
+
+type PollStatus string
+
+const (
+	PollStatusCompleted  PollStatus = "completed"
+	PollStatusArchived   PollStatus = "archived"
+	PollStatusTerminated PollStatus = "terminated"
+)
+
 type EventChannelPollEnd struct {
 	EventChannelPollBegin
 
-	Status string `json:"status"`
+	Status  PollStatus `json:"status"`
+	EndedAt time.Time  `json:"ended_at"`
 }
 
 type TopPredictor struct {
@@ -349,30 +628,78 @@ type PredictionOutcome struct {
 	TopPredictors []TopPredictor `json:"top_predictors"`
 }
 
+type PredictionOutcomes []PredictionOutcome
+
+// Get returns the outcome with the ID.
+func (o PredictionOutcomes) Get(id string) (PredictionOutcome, bool) {
+	for _, outcome := range o {
+		if outcome.ID == id {
+			return outcome, true
+		}
+	}
+	return PredictionOutcome{}, false
+}
+
+// TotalChannelPoints returns the channel points used on all outcomes.
+func (o PredictionOutcomes) TotalChannelPoints() int {
+	var total int
+	for _, outcome := range o {
+		total += outcome.ChannelPoints
+	}
+	return total
+}
+
+// TotalUsers returns the users who predicted any outcome.
+func (o PredictionOutcomes) TotalUsers() int {
+	var total int
+	for _, outcome := range o {
+		total += outcome.Users
+	}
+	return total
+}
+
 type EventChannelPredictionBegin struct {
 	Broadcaster
 
-	ID        string              `json:"id"`
-	Title     string              `json:"title"`
-	Outcomes  []PredictionOutcome `json:"outcomes"`
-	StartedAt time.Time           `json:"started_at"`
-	LocksAt   time.Time           `json:"locks_at"`
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Outcomes  PredictionOutcomes `json:"outcomes"`
+	StartedAt time.Time          `json:"started_at"`
+	LocksAt   time.Time          `json:"locks_at"`
+	// LockedAt is only sent with channel.prediction.lock.
+	LockedAt time.Time `json:"locked_at"`
 }
 
 type EventChannelPredictionProgress EventChannelPredictionBegin
 
 type EventChannelPredictionLock EventChannelPredictionBegin
 
+type PredictionStatus string
+
+const (
+	PredictionStatusResolved PredictionStatus = "resolved"
+	PredictionStatusCanceled PredictionStatus = "canceled"
+)
+
 type EventChannelPredictionEnd struct {
 	Broadcaster
 
-	ID               string              `json:"id"`
-	Title            string              `json:"title"`
-	WinningOutcomeID string              `json:"winning_outcome_id"`
-	Outcomes         []PredictionOutcome `json:"outcomes"`
-	Status           string              `json:"status"`
-	StartedAt        time.Time           `json:"started_at"`
-	EndedAt          time.Time           `json:"ended_at"`
+	ID               string             `json:"id"`
+	Title            string             `json:"title"`
+	WinningOutcomeID string             `json:"winning_outcome_id"`
+	Outcomes         PredictionOutcomes `json:"outcomes"`
+	Status           PredictionStatus   `json:"status"`
+	StartedAt        time.Time          `json:"started_at"`
+	EndedAt          time.Time          `json:"ended_at"`
+}
+
+// WinningOutcome returns the outcome which won, which there is none of for canceled
+// predictions.
+func (e EventChannelPredictionEnd) WinningOutcome() (PredictionOutcome, bool) {
+	if e.WinningOutcomeID == "" {
+		return PredictionOutcome{}, false
+	}
+	return e.Outcomes.Get(e.WinningOutcomeID)
 }
 
 type DropEntitlement struct {
@@ -392,6 +719,38 @@ type EventDropEntitlementGrant struct {
 	Data DropEntitlement `json:"data"`
 }
 
+// EventDropEntitlementGrantBatch is the batch of entitlements of a drop.entitlement.grant
+// notification. Each grant has its own ID to acknowledge or deduplicate it by.
+type EventDropEntitlementGrantBatch []EventDropEntitlementGrant
+
+// IDs returns the IDs of the grants in the batch.
+func (b EventDropEntitlementGrantBatch) IDs() []string {
+	ids := make([]string, len(b))
+	for i, grant := range b {
+		ids[i] = grant.ID
+	}
+	return ids
+}
+
+// Get returns the grant with the ID.
+func (b EventDropEntitlementGrantBatch) Get(id string) (EventDropEntitlementGrant, bool) {
+	for _, grant := range b {
+		if grant.ID == id {
+			return grant, true
+		}
+	}
+	return EventDropEntitlementGrant{}, false
+}
+
+// Entitlements returns the entitlement data of every grant in the batch.
+func (b EventDropEntitlementGrantBatch) Entitlements() []DropEntitlement {
+	entitlements := make([]DropEntitlement, len(b))
+	for i, grant := range b {
+		entitlements[i] = grant.Data
+	}
+	return entitlements
+}
+
 type ExtensionProduct struct {
 	Name          string `json:"name"`
 	Bits          int    `json:"bits"`
@@ -408,42 +767,167 @@ type EventExtensionBitsTransactionCreate struct {
 	Product           ExtensionProduct `json:"product"`
 }
 
+type GoalType string
+
+const (
+	GoalTypeFollow               GoalType = "follow"
+	GoalTypeSubscription         GoalType = "subscription"
+	GoalTypeSubscriptionCount    GoalType = "subscription_count"
+	GoalTypeNewSubscription      GoalType = "new_subscription"
+	GoalTypeNewSubscriptionCount GoalType = "new_subscription_count"
+	GoalTypeNewBit               GoalType = "new_bit"
+	GoalTypeNewCheerer           GoalType = "new_cheerer"
+)
+
 type EventChannelGoalBegin struct {
 	Broadcaster
 
-	ID                 string    `json:"id"`
-	CharityName        string    `json:"charity_name"`
-	CharityDescription string    `json:"charity_description"`
-	CharityLogo        string    `json:"charity_logo"`
-	CharityWebsite     string    `json:"charity_website"`
-	CurrentAmount      int       `json:"current_amount"`
-	TargetAmount       int       `json:"target_amount"`
-	StoppedAt          time.Time `json:"stopped_at"`
+	ID          string   `json:"id"`
+	Type        GoalType `json:"type"`
+	Description string   `json:"description"`
+	// Deprecated: Twitch does not send charity fields with goal events, charity
+	// campaigns have events of their own.
+	CharityName string `json:"charity_name,omitempty"`
+	// Deprecated: see CharityName.
+	CharityDescription string `json:"charity_description,omitempty"`
+	// Deprecated: see CharityName.
+	CharityLogo string `json:"charity_logo,omitempty"`
+	// Deprecated: see CharityName.
+	CharityWebsite string    `json:"charity_website,omitempty"`
+	CurrentAmount  int       `json:"current_amount"`
+	TargetAmount   int       `json:"target_amount"`
+	StartedAt      time.Time `json:"started_at"`
+	// IsAchieved and EndedAt are only sent with channel.goal.end.
+	IsAchieved bool      `json:"is_achieved"`
+	EndedAt    time.Time `json:"ended_at"`
+	// Deprecated: Twitch sends EndedAt.
+	StoppedAt time.Time `json:"stopped_at"`
+}
+
+// PercentComplete returns how far the goal is towards its target, which is over 100
+// once the target is exceeded.
+func (e EventChannelGoalBegin) PercentComplete() float64 {
+	return goalPercentComplete(e.CurrentAmount, e.TargetAmount)
+}
+
+// Remaining returns how much is left to reach the target, or 0 once it is reached.
+func (e EventChannelGoalBegin) Remaining() int {
+	return goalRemaining(e.CurrentAmount, e.TargetAmount)
 }
 
 type EventChannelGoalProgress EventChannelGoalBegin
 
+// PercentComplete is like EventChannelGoalBegin.PercentComplete.
+func (e EventChannelGoalProgress) PercentComplete() float64 {
+	return goalPercentComplete(e.CurrentAmount, e.TargetAmount)
+}
+
+// Remaining is like EventChannelGoalBegin.Remaining.
+func (e EventChannelGoalProgress) Remaining() int {
+	return goalRemaining(e.CurrentAmount, e.TargetAmount)
+}
+
+func goalPercentComplete(current, target int) float64 {
+	if target <= 0 {
+		return 0
+	}
+	return float64(current) / float64(target) * 100
+}
+
+func goalRemaining(current, target int) int {
+	if current >= target {
+		return 0
+	}
+	return target - current
+}
+
 type EventChannelGoalEnd EventChannelGoalBegin
 
+// PercentComplete is like EventChannelGoalBegin.PercentComplete.
+func (e EventChannelGoalEnd) PercentComplete() float64 {
+	return goalPercentComplete(e.CurrentAmount, e.TargetAmount)
+}
+
+// Remaining is like EventChannelGoalBegin.Remaining.
+func (e EventChannelGoalEnd) Remaining() int {
+	return goalRemaining(e.CurrentAmount, e.TargetAmount)
+}
+
+type HypeTrainContributionType string
+
+const (
+	HypeTrainContributionBits         HypeTrainContributionType = "bits"
+	HypeTrainContributionSubscription HypeTrainContributionType = "subscription"
+	HypeTrainContributionOther        HypeTrainContributionType = "other"
+)
+
+// Hype train points per subscription of each tier.
+const (
+	HypeUnitsTier1Subscription = 500
+	HypeUnitsTier2Subscription = 1000
+	HypeUnitsTier3Subscription = 2500
+)
+
 type HypeTrainContribution struct {
 	User
 
-	Type  string `json:"type"`
-	Total int    `json:"total"`
+	Type HypeTrainContributionType `json:"type"`
+	// Total is the bits used for bits, and the hype units of the subscriptions for
+	// subscriptions.
+	Total int `json:"total"`
+}
+
+// HypeUnits returns the points the contribution added to the train, the unit of its
+// progress and goal. A bit is worth one point and subscriptions are worth
+// HypeUnitsTier1Subscription, HypeUnitsTier2Subscription, or HypeUnitsTier3Subscription.
+func (c HypeTrainContribution) HypeUnits() int {
+	return c.Total
 }
 
+// Tier1Subscriptions returns the subscription contribution as the number of tier 1
+// subscriptions it is worth, as Twitch displays subscription contributions, or 0 for
+// other contributions.
+func (c HypeTrainContribution) Tier1Subscriptions() int {
+	if c.Type != HypeTrainContributionSubscription {
+		return 0
+	}
+	return c.Total / HypeUnitsTier1Subscription
+}
+
+type HypeTrainType string
+
+const (
+	HypeTrainTypeRegular     HypeTrainType = "regular"
+	HypeTrainTypeTreasure    HypeTrainType = "treasure"
+	HypeTrainTypeGoldenKappa HypeTrainType = "golden_kappa"
+)
+
 type EventChannelHypeTrainBegin struct {
 	Broadcaster
 
-	Id               string                  `json:"id"`
-	Total            int                     `json:"total"`
-	Progress         int                     `json:"progress"`
-	Goal             int                     `json:"goal"`
-	TopContributions []HypeTrainContribution `json:"top_contributions"`
-	LastContribution HypeTrainContribution   `json:"last_contribution"`
-	Level            int                     `json:"level"`
-	StartedAt        time.Time               `json:"started_at"`
-	ExpiresAt        time.Time               `json:"expires_at"`
+	Id                      string                  `json:"id"`
+	Total                   int                     `json:"total"`
+	Progress                int                     `json:"progress"`
+	Goal                    int                     `json:"goal"`
+	TopContributions        []HypeTrainContribution `json:"top_contributions"`
+	Level                   int                     `json:"level"`
+	AllTimeHighLevel        int                     `json:"all_time_high_level"`
+	AllTimeHighTotal        int                     `json:"all_time_high_total"`
+	Type                    HypeTrainType           `json:"type"`
+	IsSharedTrain           bool                    `json:"is_shared_train"`
+	SharedTrainParticipants []Broadcaster           `json:"shared_train_participants"`
+	StartedAt               time.Time               `json:"started_at"`
+	ExpiresAt               time.Time               `json:"expires_at"`
+
+	// LastContribution and IsGoldenKappaTrain are only sent by version 1, which
+	// Type replaces.
+	LastContribution   HypeTrainContribution `json:"last_contribution,omitempty"`
+	IsGoldenKappaTrain bool                  `json:"is_golden_kappa_train,omitempty"`
+}
+
+// IsGoldenKappa reports whether the train is a Golden Kappa Train for either version.
+func (e EventChannelHypeTrainBegin) IsGoldenKappa() bool {
+	return e.Type == HypeTrainTypeGoldenKappa || e.IsGoldenKappaTrain
 }
 
 type EventChannelHypeTrainProgress struct {
@@ -455,21 +939,54 @@ type EventChannelHypeTrainProgress struct {
 type EventChannelHypeTrainEnd struct {
 	Broadcaster
 
-	Id               string                  `json:"id"`
-	Level            int                     `json:"level"`
-	Total            int                     `json:"total"`
-	TopContributions []HypeTrainContribution `json:"top_contributions"`
-	StartedAt        time.Time               `json:"started_at"`
-	ExpiresAt        time.Time               `json:"expires_at"`
-	CooldownEndsAt   time.Time               `json:"cooldown_ends_at"`
+	Id                      string                  `json:"id"`
+	Level                   int                     `json:"level"`
+	Total                   int                     `json:"total"`
+	TopContributions        []HypeTrainContribution `json:"top_contributions"`
+	Type                    HypeTrainType           `json:"type"`
+	IsSharedTrain           bool                    `json:"is_shared_train"`
+	SharedTrainParticipants []Broadcaster           `json:"shared_train_participants"`
+	StartedAt               time.Time               `json:"started_at"`
+	ExpiresAt               time.Time               `json:"expires_at"`
+	EndedAt                 time.Time               `json:"ended_at"`
+	CooldownEndsAt          time.Time               `json:"cooldown_ends_at"`
+
+	// IsGoldenKappaTrain is only sent by version 1, which Type replaces.
+	IsGoldenKappaTrain bool `json:"is_golden_kappa_train,omitempty"`
+}
+
+// IsGoldenKappa reports whether the train was a Golden Kappa Train for either version.
+func (e EventChannelHypeTrainEnd) IsGoldenKappa() bool {
+	return e.Type == HypeTrainTypeGoldenKappa || e.IsGoldenKappaTrain
 }
 
+type StreamType string
+
+const (
+	StreamTypeLive       StreamType = "live"
+	StreamTypePlaylist   StreamType = "playlist"
+	StreamTypeWatchParty StreamType = "watch_party"
+	StreamTypeQuestion   StreamType = "question"
+	StreamTypePremiere   StreamType = "premiere"
+	StreamTypeRerun      StreamType = "rerun"
+)
+
 type EventStreamOnline struct {
 	Broadcaster
 
-	Id        string    `json:"id"`
-	Type      string    `json:"type"`
-	StartedAt time.Time `json:"started_at"`
+	Id        string     `json:"id"`
+	Type      StreamType `json:"type"`
+	StartedAt time.Time  `json:"started_at"`
+}
+
+// IsLive reports if the stream is live instead of a rerun, premiere, or playlist.
+func (e EventStreamOnline) IsLive() bool {
+	return e.Type == StreamTypeLive
+}
+
+// Uptime returns how long the stream has been online at now.
+func (e EventStreamOnline) Uptime(now time.Time) time.Duration {
+	return now.Sub(e.StartedAt)
 }
 
 type EventStreamOffline Broadcaster
@@ -513,14 +1030,32 @@ type BaseCharity struct {
 type EventChannelCharityCampaignDonate struct {
 	BaseCharity
 
-	Amount GoalAmount `json:"amount"`
+	ID         string     `json:"id"`
+	CampaignID string     `json:"campaign_id"`
+	Amount     GoalAmount `json:"amount"`
 }
 
 type EventChannelCharityCampaignProgress struct {
 	BaseCharity
 
-	CurrentAmount GoalAmount `json:"current_amount"`
-	TargetAmount  GoalAmount `json:"target_amount"`
+	ID string `json:"id"`
+	// The broadcaster of progress, start, and stop events is sent in these fields
+	// instead of the ones of Broadcaster.
+	BroadcasterId    string     `json:"broadcaster_id"`
+	BroadcasterLogin string     `json:"broadcaster_login"`
+	BroadcasterName  string     `json:"broadcaster_name"`
+	CurrentAmount    GoalAmount `json:"current_amount"`
+	TargetAmount     GoalAmount `json:"target_amount"`
+}
+
+// PercentComplete returns the current amount as a percentage of the target amount, above
+// 100 once the target is exceeded.
+func (e EventChannelCharityCampaignProgress) PercentComplete() float64 {
+	target := e.TargetAmount.Amount()
+	if target <= 0 {
+		return 0
+	}
+	return e.CurrentAmount.Amount() / target * 100
 }
 
 type EventChannelCharityCampaignStart struct {
@@ -540,7 +1075,10 @@ type EventChannelShieldModeBegin struct {
 	Moderator
 
 	StartedAt time.Time `json:"started_at"`
+	// Deprecated: Twitch sends EndedAt.
 	StoppedAt time.Time `json:"stopped_at"`
+	// EndedAt is only sent with channel.shield_mode.end.
+	EndedAt time.Time `json:"ended_at"`
 }
 
 type EventChannelShieldModeEnd EventChannelShieldModeBegin
@@ -558,6 +1096,26 @@ type EventChannelShoutoutCreate struct {
 	TargetCooldownEndsAt   time.Time `json:"target_cooldown_ends_at"`
 }
 
+// CooldownRemaining returns how long until the broadcaster can send another shoutout.
+func (e EventChannelShoutoutCreate) CooldownRemaining(now time.Time) time.Duration {
+	return remainingUntil(e.CooldownEndsAt, now)
+}
+
+// TargetCooldownRemaining returns how long until the broadcaster can shout out the same
+// target again.
+func (e EventChannelShoutoutCreate) TargetCooldownRemaining(now time.Time) time.Duration {
+	return remainingUntil(e.TargetCooldownEndsAt, now)
+}
+
+func remainingUntil(t time.Time, now time.Time) time.Duration {
+	if t.IsZero() || !t.After(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
+
+// EventChannelShoutoutReceive carries no cooldowns, they are only sent to the
+// broadcaster giving the shoutout.
 type EventChannelShoutoutReceive struct {
 	Broadcaster
 	Moderator
@@ -569,12 +1127,51 @@ type EventChannelShoutoutReceive struct {
 	StartedAt                time.Time `json:"started_at"`
 }
 
+type ModerateAction string
+
+const (
+	ModerateActionBan                 ModerateAction = "ban"
+	ModerateActionTimeout             ModerateAction = "timeout"
+	ModerateActionUnban               ModerateAction = "unban"
+	ModerateActionUntimeout           ModerateAction = "untimeout"
+	ModerateActionClear               ModerateAction = "clear"
+	ModerateActionEmoteOnly           ModerateAction = "emoteonly"
+	ModerateActionEmoteOnlyOff        ModerateAction = "emoteonlyoff"
+	ModerateActionFollowers           ModerateAction = "followers"
+	ModerateActionFollowersOff        ModerateAction = "followersoff"
+	ModerateActionUniqueChat          ModerateAction = "uniquechat"
+	ModerateActionUniqueChatOff       ModerateAction = "uniquechatoff"
+	ModerateActionSlow                ModerateAction = "slow"
+	ModerateActionSlowOff             ModerateAction = "slowoff"
+	ModerateActionSubscribers         ModerateAction = "subscribers"
+	ModerateActionSubscribersOff      ModerateAction = "subscribersoff"
+	ModerateActionRaid                ModerateAction = "raid"
+	ModerateActionUnraid              ModerateAction = "unraid"
+	ModerateActionDelete              ModerateAction = "delete"
+	ModerateActionVIP                 ModerateAction = "vip"
+	ModerateActionUnvip               ModerateAction = "unvip"
+	ModerateActionMod                 ModerateAction = "mod"
+	ModerateActionUnmod               ModerateAction = "unmod"
+	ModerateActionAddBlockedTerm      ModerateAction = "add_blocked_term"
+	ModerateActionAddPermittedTerm    ModerateAction = "add_permitted_term"
+	ModerateActionRemoveBlockedTerm   ModerateAction = "remove_blocked_term"
+	ModerateActionRemovePermittedTerm ModerateAction = "remove_permitted_term"
+	ModerateActionApproveUnbanRequest ModerateAction = "approve_unban_request"
+	ModerateActionDenyUnbanRequest    ModerateAction = "deny_unban_request"
+	ModerateActionWarn                ModerateAction = "warn"
+	ModerateActionSharedChatBan       ModerateAction = "shared_chat_ban"
+	ModerateActionSharedChatTimeout   ModerateAction = "shared_chat_timeout"
+	ModerateActionSharedChatUnban     ModerateAction = "shared_chat_unban"
+	ModerateActionSharedChatUntimeout ModerateAction = "shared_chat_untimeout"
+	ModerateActionSharedChatDelete    ModerateAction = "shared_chat_delete"
+)
+
 type EventChannelModerate struct {
 	Broadcaster
 	SourceBroadcaster
 	Moderator
 
-	Action              string          `json:"action"`
+	Action              ModerateAction  `json:"action"`
 	Followers           *Followers      `json:"followers,omitempty"`
 	Slow                *SlowMode       `json:"slow,omitempty"`
 	Vip                 *User           `json:"vip,omitempty"`
@@ -609,6 +1206,63 @@ type EventChannelAdBreakBegin struct {
 	RequesterUserName  string    `json:"requester_user_name"`
 }
 
+// UnmarshalJSON also accepts duration_seconds and is_automatic as strings, which Twitch
+// sends them as in its examples.
+func (e *EventChannelAdBreakBegin) UnmarshalJSON(data []byte) error {
+	type adBreak EventChannelAdBreakBegin
+	var raw struct {
+		*adBreak
+		DurationSeconds json.RawMessage `json:"duration_seconds"`
+		IsAutomatic     json.RawMessage `json:"is_automatic"`
+	}
+	raw.adBreak = (*adBreak)(e)
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if len(raw.DurationSeconds) > 0 && string(raw.DurationSeconds) != "null" {
+		e.DurationSeconds, err = strconv.Atoi(unquoteJSON(raw.DurationSeconds))
+		if err != nil {
+			return fmt.Errorf("could not parse duration_seconds: %w", err)
+		}
+	}
+	if len(raw.IsAutomatic) > 0 && string(raw.IsAutomatic) != "null" {
+		e.IsAutomatic, err = strconv.ParseBool(unquoteJSON(raw.IsAutomatic))
+		if err != nil {
+			return fmt.Errorf("could not parse is_automatic: %w", err)
+		}
+	}
+	return nil
+}
+
+func unquoteJSON(data json.RawMessage) string {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Duration returns the length of the ad break.
+func (e EventChannelAdBreakBegin) Duration() time.Duration {
+	return time.Duration(e.DurationSeconds) * time.Second
+}
+
+// EndsAt returns when the ad break ends.
+func (e EventChannelAdBreakBegin) EndsAt() time.Time {
+	return e.StartedAt.Add(e.Duration())
+}
+
+// Remaining returns how long is left of the ad break at now, or 0 once it ended.
+func (e EventChannelAdBreakBegin) Remaining(now time.Time) time.Duration {
+	remaining := e.EndsAt().Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 type EventChannelWarningAcknowledge struct {
 	Broadcaster
 	User
@@ -619,8 +1273,13 @@ type EventChannelWarningSend struct {
 	Moderator
 	User
 
-	Reason         string   `json:"reason"`
-	ChatRulesCited []string `json:"chat_rules_cited"`
+	Reason         string    `json:"reason"`
+	ChatRulesCited ChatRules `json:"chat_rules_cited"`
+}
+
+// Description describes the warning from its reason and cited rules for displaying.
+func (e EventChannelWarningSend) Description() string {
+	return warningDescription(e.Reason, e.ChatRulesCited)
 }
 
 type EventChannelUnbanRequestCreate struct {
@@ -632,14 +1291,22 @@ type EventChannelUnbanRequestCreate struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type UnbanRequestStatus string
+
+const (
+	UnbanRequestStatusApproved UnbanRequestStatus = "approved"
+	UnbanRequestStatusCanceled UnbanRequestStatus = "canceled"
+	UnbanRequestStatusDenied   UnbanRequestStatus = "denied"
+)
+
 type EventChannelUnbanRequestResolve struct {
 	Broadcaster
 	Moderator
 	User
 
-	Id             string `json:"id"`
-	ResolutionText string `json:"resolution_text"`
-	Status         string `json:"status"`
+	Id             string             `json:"id"`
+	ResolutionText string             `json:"resolution_text"`
+	Status         UnbanRequestStatus `json:"status"`
 }
 
 type ChatMessageFragmentCheermote struct {
@@ -675,6 +1342,23 @@ type TermBoundary struct {
 	EndPos   int `json:"end_pos"`
 }
 
+// Text returns the part of text within the boundary. Positions count runes from 0 and
+// both are inclusive. Positions outside text are clamped to it.
+func (b TermBoundary) Text(text string) string {
+	runes := []rune(text)
+	start, end := b.StartPos, b.EndPos+1
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+	if start >= end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
 type AutomodMessageAutomod struct {
 	Category   string         `json:"category"`
 	Level      int            `json:"level"`
@@ -693,6 +1377,19 @@ type AutomodMessageBlockedTerm struct {
 	TermsFound []AutomodMessageTermsFound `json:"terms_found"`
 }
 
+func flaggedBoundaries(automod *AutomodMessageAutomod, blockedTerm *AutomodMessageBlockedTerm) []TermBoundary {
+	var boundaries []TermBoundary
+	if automod != nil {
+		boundaries = append(boundaries, automod.Boundaries...)
+	}
+	if blockedTerm != nil {
+		for _, term := range blockedTerm.TermsFound {
+			boundaries = append(boundaries, term.Boundary)
+		}
+	}
+	return boundaries
+}
+
 type EventAutomodMessageHold struct {
 	Broadcaster
 	User
@@ -705,6 +1402,12 @@ type EventAutomodMessageHold struct {
 	BlockedTerm *AutomodMessageBlockedTerm `json:"blocked_term"`
 }
 
+// Boundaries returns the parts of the message which caused the hold, whether flagged by
+// automod or matching blocked terms.
+func (e EventAutomodMessageHold) Boundaries() []TermBoundary {
+	return flaggedBoundaries(e.Automod, e.BlockedTerm)
+}
+
 type EventAutomodMessageUpdate struct {
 	Broadcaster
 	User
@@ -719,19 +1422,108 @@ type EventAutomodMessageUpdate struct {
 	BlockedTerm *AutomodMessageBlockedTerm `json:"blocked_term"`
 }
 
+// Boundaries returns the parts of the message which caused the hold, whether flagged by
+// automod or matching blocked terms.
+func (e EventAutomodMessageUpdate) Boundaries() []TermBoundary {
+	return flaggedBoundaries(e.Automod, e.BlockedTerm)
+}
+
+// AutomodLevel is how strictly automod filters a category, from 0 to 4.
+type AutomodLevel int
+
+const (
+	AutomodLevelOff AutomodLevel = iota
+	AutomodLevelLeast
+	AutomodLevelSome
+	AutomodLevelMore
+	AutomodLevelMost
+)
+
+// AutomodCategory names a category of automod settings after its field in the event.
+type AutomodCategory string
+
+const (
+	AutomodCategoryOverall                 AutomodCategory = "overall_level"
+	AutomodCategoryDisability              AutomodCategory = "disability"
+	AutomodCategoryAggression              AutomodCategory = "aggression"
+	AutomodCategorySexualitySexOrGender    AutomodCategory = "sexuality_sex_or_gender"
+	AutomodCategoryMisogyny                AutomodCategory = "misogyny"
+	AutomodCategoryBullying                AutomodCategory = "bullying"
+	AutomodCategorySwearing                AutomodCategory = "swearing"
+	AutomodCategoryRaceEthnicityOrReligion AutomodCategory = "race_ethnicity_or_religion"
+	AutomodCategorySexBasedTerms           AutomodCategory = "sex_based_terms"
+)
+
 type EventAutomodSettingsUpdate struct {
 	Broadcaster
 	Moderator
 
-	OverallLevel            *int `json:"overall_level"`
-	Disability              int  `json:"disability"`
-	Aggression              int  `json:"aggression"`
-	SexualitySexOrGender    int  `json:"sexuality_sex_or_gender"`
-	Misogyny                int  `json:"misogyny"`
-	Bullying                int  `json:"bullying"`
-	Swearing                int  `json:"swearing"`
-	RaceEthnicityOrReligion int  `json:"race_ethnicity_or_religion"`
-	SexBasedTerms           int  `json:"sex_based_terms"`
+	// OverallLevel is nil when the categories were set individually.
+	OverallLevel            *AutomodLevel `json:"overall_level"`
+	Disability              AutomodLevel  `json:"disability"`
+	Aggression              AutomodLevel  `json:"aggression"`
+	SexualitySexOrGender    AutomodLevel  `json:"sexuality_sex_or_gender"`
+	Misogyny                AutomodLevel  `json:"misogyny"`
+	Bullying                AutomodLevel  `json:"bullying"`
+	Swearing                AutomodLevel  `json:"swearing"`
+	RaceEthnicityOrReligion AutomodLevel  `json:"race_ethnicity_or_religion"`
+	SexBasedTerms           AutomodLevel  `json:"sex_based_terms"`
+}
+
+// Levels returns the level of every category but the overall level.
+func (e EventAutomodSettingsUpdate) Levels() map[AutomodCategory]AutomodLevel {
+	return map[AutomodCategory]AutomodLevel{
+		AutomodCategoryDisability:              e.Disability,
+		AutomodCategoryAggression:              e.Aggression,
+		AutomodCategorySexualitySexOrGender:    e.SexualitySexOrGender,
+		AutomodCategoryMisogyny:                e.Misogyny,
+		AutomodCategoryBullying:                e.Bullying,
+		AutomodCategorySwearing:                e.Swearing,
+		AutomodCategoryRaceEthnicityOrReligion: e.RaceEthnicityOrReligion,
+		AutomodCategorySexBasedTerms:           e.SexBasedTerms,
+	}
+}
+
+// AutomodLevelChange is a category whose level changed between two settings. From and
+// To are nil for an unset overall level.
+type AutomodLevelChange struct {
+	Category AutomodCategory
+	From     *AutomodLevel
+	To       *AutomodLevel
+}
+
+// Diff returns the categories whose level changed since the previous settings, ordered
+// like the fields of the event.
+func (e EventAutomodSettingsUpdate) Diff(previous EventAutomodSettingsUpdate) []AutomodLevelChange {
+	var changes []AutomodLevelChange
+	if !automodLevelsEqual(previous.OverallLevel, e.OverallLevel) {
+		changes = append(changes, AutomodLevelChange{Category: AutomodCategoryOverall, From: previous.OverallLevel, To: e.OverallLevel})
+	}
+
+	from, to := previous.Levels(), e.Levels()
+	for _, category := range []AutomodCategory{
+		AutomodCategoryDisability,
+		AutomodCategoryAggression,
+		AutomodCategorySexualitySexOrGender,
+		AutomodCategoryMisogyny,
+		AutomodCategoryBullying,
+		AutomodCategorySwearing,
+		AutomodCategoryRaceEthnicityOrReligion,
+		AutomodCategorySexBasedTerms,
+	} {
+		if from[category] != to[category] {
+			fromLevel, toLevel := from[category], to[category]
+			changes = append(changes, AutomodLevelChange{Category: category, From: &fromLevel, To: &toLevel})
+		}
+	}
+	return changes
+}
+
+func automodLevelsEqual(a, b *AutomodLevel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
 type EventAutomodTermsUpdate struct {
@@ -794,17 +1586,28 @@ type EventChannelChatMessage struct {
 	SourceBroadcaster
 	Chatter
 
-	MessageId                   string                  `json:"message_id"`
-	SourceMessageId             string                  `json:"source_message_id"`
-	Message                     ChatMessage             `json:"message"`
-	Color                       string                  `json:"color"`
-	Badges                      []ChatMessageUserBadge  `json:"badges"`
-	SourceBadges                *[]ChatMessageUserBadge `json:"source_badges,omitempty"`
-	MessageType                 string                  `json:"message_type"`
-	Cheer                       *ChatMessageCheer       `json:"cheer,omitempty"`
-	Reply                       *ChatMessageReply       `json:"reply,omitempty"`
-	ChannelPointsCustomRewardId string                  `json:"channel_points_custom_reward_id"`
-	ChannelPointsAnimationId    string                  `json:"channel_points_animation_id"`
+	MessageId                   string            `json:"message_id"`
+	SourceMessageId             string            `json:"source_message_id"`
+	Message                     ChatMessage       `json:"message"`
+	Color                       string            `json:"color"`
+	Badges                      ChatBadges        `json:"badges"`
+	SourceBadges                *ChatBadges       `json:"source_badges,omitempty"`
+	MessageType                 string            `json:"message_type"`
+	Cheer                       *ChatMessageCheer `json:"cheer,omitempty"`
+	Reply                       *ChatMessageReply `json:"reply,omitempty"`
+	ChannelPointsCustomRewardId string            `json:"channel_points_custom_reward_id"`
+	ChannelPointsAnimationId    string            `json:"channel_points_animation_id"`
+}
+
+// IsFromSharedChat reports whether the message was sent in another channel of a shared
+// chat session, so it is also received by the subscriptions of that channel. The source
+// fields are also set for messages sent in the channel itself during a session.
+func (e EventChannelChatMessage) IsFromSharedChat() bool {
+	return isFromSharedChat(e.Broadcaster, e.SourceBroadcaster)
+}
+
+func isFromSharedChat(broadcaster Broadcaster, source SourceBroadcaster) bool {
+	return source.SourceBroadcasterUserId != "" && source.SourceBroadcasterUserId != broadcaster.BroadcasterUserId
 }
 
 type EventChannelChatMessageDelete struct {
@@ -825,6 +1628,7 @@ type ChatNotificationResub struct {
 	DurationMonths    int    `json:"duration_months"`
 	StreakMonths      int    `json:"streak_months"`
 	SubTier           string `json:"sub_tier"`
+	SubPlan           string `json:"sub_plan,omitempty"` // sent in place of sub_tier in the documented example
 	IsPrime           bool   `json:"is_prime"`
 	IsGift            bool   `json:"is_gift"`
 	GifterIsAnonymous bool   `json:"gifter_is_anonymous"`
@@ -900,14 +1704,14 @@ type EventChannelChatNotification struct {
 	SourceBroadcaster
 	Chatter
 
-	ChatterIsAnonymous bool                    `json:"chatter_is_anonymous"`
-	Color              string                  `json:"color"`
-	Badges             []ChatMessageUserBadge  `json:"badges"`
-	SourceBadges       *[]ChatMessageUserBadge `json:"source_badges"`
-	SystemMessage      string                  `json:"system_message"`
-	MessageId          string                  `json:"message_id"`
-	SourceMessageId    string                  `json:"source_message_id"`
-	Message            ChatMessage             `json:"message"`
+	ChatterIsAnonymous bool        `json:"chatter_is_anonymous"`
+	Color              string      `json:"color"`
+	Badges             ChatBadges  `json:"badges"`
+	SourceBadges       *ChatBadges `json:"source_badges"`
+	SystemMessage      string      `json:"system_message"`
+	MessageId          string      `json:"message_id"`
+	SourceMessageId    string      `json:"source_message_id"`
+	Message            ChatMessage `json:"message"`
 
 	NoticeType       string                            `json:"notice_type"`
 	Sub              *ChatNotificationSub              `json:"sub,omitempty"`
@@ -934,6 +1738,12 @@ type EventChannelChatNotification struct {
 	SharedChatAnnouncement     *ChatNotificationAnnouncement     `json:"shared_chat_announcement,omitempty"`
 }
 
+// IsFromSharedChat reports whether the notification was sent in another channel of a
+// shared chat session, like EventChannelChatMessage.IsFromSharedChat.
+func (e EventChannelChatNotification) IsFromSharedChat() bool {
+	return isFromSharedChat(e.Broadcaster, e.SourceBroadcaster)
+}
+
 type EventChannelChatSettingsUpdate struct {
 	Broadcaster
 
@@ -952,23 +1762,66 @@ type SuspiciousUserChatMessage struct {
 	MessageId string `json:"message_id"`
 }
 
+type LowTrustStatus string
+
+const (
+	LowTrustStatusNone             LowTrustStatus = "none"
+	LowTrustStatusActiveMonitoring LowTrustStatus = "active_monitoring"
+	LowTrustStatusRestricted       LowTrustStatus = "restricted"
+)
+
+type SuspiciousUserType string
+
+const (
+	SuspiciousUserTypeManual            SuspiciousUserType = "manual"
+	SuspiciousUserTypeBanEvaderDetector SuspiciousUserType = "ban_evader_detector"
+	SuspiciousUserTypeSharedChannelBan  SuspiciousUserType = "shared_channel_ban"
+	// SuspiciousUserTypeBanEvader is sent instead of ban_evader_detector by the
+	// examples of the Twitch docs and CLI.
+	SuspiciousUserTypeBanEvader SuspiciousUserType = "ban_evader"
+)
+
+type BanEvasionEvaluation string
+
+const (
+	BanEvasionEvaluationUnknown  BanEvasionEvaluation = "unknown"
+	BanEvasionEvaluationPossible BanEvasionEvaluation = "possible"
+	BanEvasionEvaluationLikely   BanEvasionEvaluation = "likely"
+)
+
 type EventChannelSuspiciousUserMessage struct {
 	Broadcaster
 	User
 
-	LowTrustStatus       string                    `json:"low_trust_status"`
+	LowTrustStatus       LowTrustStatus            `json:"low_trust_status"`
 	SharedBanChannelIds  []string                  `json:"shared_ban_channel_ids"`
-	Types                []string                  `json:"types"`
-	BanEvasionEvaluation string                    `json:"ban_evasion_evaluation"`
+	Types                []SuspiciousUserType      `json:"types"`
+	BanEvasionEvaluation BanEvasionEvaluation      `json:"ban_evasion_evaluation"`
 	Message              SuspiciousUserChatMessage `json:"message"`
 }
 
+// HasType reports whether the user was marked suspicious for the reason.
+func (e EventChannelSuspiciousUserMessage) HasType(suspiciousType SuspiciousUserType) bool {
+	for _, t := range e.Types {
+		if t == suspiciousType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBanEvader reports whether the user was detected as a likely or possible ban evader.
+func (e EventChannelSuspiciousUserMessage) IsBanEvader() bool {
+	return e.HasType(SuspiciousUserTypeBanEvaderDetector) || e.HasType(SuspiciousUserTypeBanEvader) ||
+		e.BanEvasionEvaluation == BanEvasionEvaluationLikely || e.BanEvasionEvaluation == BanEvasionEvaluationPossible
+}
+
 type EventChannelSuspiciousUserUpdate struct {
 	Broadcaster
 	Moderator
 	User
 
-	LowTrustStatus string `json:"low_trust_status"`
+	LowTrustStatus LowTrustStatus `json:"low_trust_status"`
 }
 
 type EventChannelSharedChatBegin struct {
@@ -989,6 +1842,9 @@ type EventChannelSharedChatEnd struct {
 }
 
 type UserWhisper struct {
+	// Id is only set by payloads nesting the ID in the whisper, Twitch sends it as
+	// whisper_id on the event.
+	Id   string `json:"id,omitempty"`
 	Text string `json:"text"`
 }
 
@@ -1003,6 +1859,28 @@ type EventUserWhisperMessage struct {
 	Whisper       UserWhisper `json:"whisper"`
 }
 
+// ID returns the ID of the whisper wherever the payload put it.
+func (e EventUserWhisperMessage) ID() string {
+	if e.WhisperId != "" {
+		return e.WhisperId
+	}
+	return e.Whisper.Id
+}
+
+func (e EventUserWhisperMessage) Text() string {
+	return e.Whisper.Text
+}
+
+// From returns the user who sent the whisper.
+func (e EventUserWhisperMessage) From() User {
+	return User{UserID: e.FromUserId, UserLogin: e.FromUserLogin, UserName: e.FromUserName}
+}
+
+// To returns the user who received the whisper.
+func (e EventUserWhisperMessage) To() User {
+	return User{UserID: e.ToUserId, UserLogin: e.ToUserLogin, UserName: e.ToUserName}
+}
+
 type ConduitTransport struct {
 	Method         string    `json:"method"`
 	SessionId      string    `json:"session_id"`
@@ -1,8 +1,10 @@
 package twitch
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestGoalAmount(t *testing.T) {
@@ -34,3 +36,449 @@ func TestGoalAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestTermBoundary(t *testing.T) {
+	testCases := []struct {
+		Boundary TermBoundary
+		Expected string
+	}{
+		{TermBoundary{0, 3}, "This"},
+		{TermBoundary{23, 30}, "pogchamp"},
+		{TermBoundary{21, 21}, "…"},
+		{TermBoundary{27, 100}, "hamp"},
+		{TermBoundary{5, 4}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%d", tc.Boundary.StartPos, tc.Boundary.EndPos), func(t *testing.T) {
+			actual := tc.Boundary.Text("This is a bad message… pogchamp")
+			if actual != tc.Expected {
+				t.Errorf("expected %q got %q", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestAutomodMessageBoundaries(t *testing.T) {
+	event := EventAutomodMessageUpdate{
+		Automod: &AutomodMessageAutomod{Boundaries: []TermBoundary{{0, 3}}},
+		BlockedTerm: &AutomodMessageBlockedTerm{TermsFound: []AutomodMessageTermsFound{
+			{TermId: "123", Boundary: TermBoundary{5, 6}},
+		}},
+	}
+
+	boundaries := event.Boundaries()
+	if len(boundaries) != 2 || boundaries[0] != (TermBoundary{0, 3}) || boundaries[1] != (TermBoundary{5, 6}) {
+		t.Errorf("unexpected boundaries %v", boundaries)
+	}
+	if len((EventAutomodMessageHold{}).Boundaries()) != 0 {
+		t.Error("expected no boundaries without automod or blocked terms")
+	}
+}
+
+func TestHypeTrainIsGoldenKappa(t *testing.T) {
+	if !(EventChannelHypeTrainBegin{Type: HypeTrainTypeGoldenKappa}).IsGoldenKappa() {
+		t.Error("expected a golden_kappa train to be a Golden Kappa Train")
+	}
+	if !(EventChannelHypeTrainEnd{IsGoldenKappaTrain: true}).IsGoldenKappa() {
+		t.Error("expected a version 1 golden kappa train to be a Golden Kappa Train")
+	}
+	if (EventChannelHypeTrainBegin{Type: HypeTrainTypeTreasure}).IsGoldenKappa() {
+		t.Error("expected a treasure train not to be a Golden Kappa Train")
+	}
+}
+
+func TestHasContentClassificationLabel(t *testing.T) {
+	event := EventChannelUpdate{ContentClassificationLabels: []ContentClassificationLabel{ContentClassificationMatureGame}}
+	if !event.HasContentClassificationLabel(ContentClassificationMatureGame) {
+		t.Error("expected the MatureGame label")
+	}
+	if event.HasContentClassificationLabel(ContentClassificationGambling) {
+		t.Error("did not expect the Gambling label")
+	}
+}
+
+func TestEnumFixtures(t *testing.T) {
+	payloads := loadFixtures(t)
+	decode := func(sub EventSubscription, event any) {
+		if err := json.Unmarshal(payloads[string(sub)], event); err != nil {
+			t.Fatalf("could not decode %s: %v", sub, err)
+		}
+	}
+
+	var pollEnd EventChannelPollEnd
+	decode(SubChannelPollEnd, &pollEnd)
+	var predictionEnd EventChannelPredictionEnd
+	decode(SubChannelPredictionEnd, &predictionEnd)
+	var goal EventChannelGoalBegin
+	decode(SubChannelGoalBegin, &goal)
+	var online EventStreamOnline
+	decode(SubStreamOnline, &online)
+	var moderate EventChannelModerate
+	decode(SubChannelModerate, &moderate)
+	var resolve EventChannelUnbanRequestResolve
+	decode(SubChannelUnbanRequestResolve, &resolve)
+	var redemption EventChannelChannelPointsCustomRewardRedemptionAdd
+	decode(SubChannelChannelPointsCustomRewardRedemptionAdd, &redemption)
+
+	testCases := []struct {
+		Actual, Expected any
+	}{
+		{pollEnd.Status, PollStatusCompleted},
+		{predictionEnd.Status, PredictionStatusResolved},
+		{goal.Type, GoalTypeSubscription},
+		{online.Type, StreamTypeLive},
+		{moderate.Action, ModerateActionWarn},
+		{resolve.Status, UnbanRequestStatusDenied},
+		{redemption.Status, RedemptionStatusUnfulfilled},
+	}
+	for _, tc := range testCases {
+		if tc.Actual != tc.Expected {
+			t.Errorf("expected %v got %v", tc.Expected, tc.Actual)
+		}
+	}
+}
+
+func TestIsFromSharedChat(t *testing.T) {
+	broadcaster := Broadcaster{BroadcasterUserId: "1337"}
+	testCases := []struct {
+		Source   string
+		Expected bool
+	}{
+		{"", false},
+		{"1337", false},
+		{"42", true},
+	}
+
+	for _, tc := range testCases {
+		source := SourceBroadcaster{SourceBroadcasterUserId: tc.Source}
+		message := EventChannelChatMessage{Broadcaster: broadcaster, SourceBroadcaster: source}
+		notification := EventChannelChatNotification{Broadcaster: broadcaster, SourceBroadcaster: source}
+		if message.IsFromSharedChat() != tc.Expected || notification.IsFromSharedChat() != tc.Expected {
+			t.Errorf("expected %t for source %q", tc.Expected, tc.Source)
+		}
+	}
+}
+
+func TestPredictionOutcomes(t *testing.T) {
+	var end EventChannelPredictionEnd
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelPredictionEnd)], &end); err != nil {
+		t.Fatal(err)
+	}
+
+	winner, ok := end.WinningOutcome()
+	if !ok || winner.Title != "Yeah!" {
+		t.Errorf("expected the winning outcome got %v", winner)
+	}
+	if len(winner.TopPredictors) != 2 || winner.TopPredictors[0].ChannelPointsWon != 10000 {
+		t.Errorf("unexpected top predictors %v", winner.TopPredictors)
+	}
+	if total := end.Outcomes.TotalChannelPoints(); total != 15200 {
+		t.Errorf("expected 15200 channel points got %d", total)
+	}
+	if total := end.Outcomes.TotalUsers(); total != 4 {
+		t.Errorf("expected 4 users got %d", total)
+	}
+
+	end.WinningOutcomeID = ""
+	if _, ok := end.WinningOutcome(); ok {
+		t.Error("expected no winning outcome")
+	}
+}
+
+func TestDropEntitlementGrantBatch(t *testing.T) {
+	batch := EventDropEntitlementGrantBatch{
+		{ID: "a", Data: DropEntitlement{EntitlementId: "ea"}},
+		{ID: "b", Data: DropEntitlement{EntitlementId: "eb"}},
+	}
+
+	if ids := batch.IDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("unexpected ids %v", ids)
+	}
+	if grant, ok := batch.Get("b"); !ok || grant.Data.EntitlementId != "eb" {
+		t.Errorf("expected grant b got %v", grant)
+	}
+	if _, ok := batch.Get("c"); ok {
+		t.Error("did not expect grant c")
+	}
+	if entitlements := batch.Entitlements(); len(entitlements) != 2 || entitlements[0].EntitlementId != "ea" {
+		t.Errorf("unexpected entitlements %v", entitlements)
+	}
+}
+
+func TestGoalProgress(t *testing.T) {
+	testCases := []struct {
+		Current, Target int
+		Percent         float64
+		Remaining       int
+	}{
+		{0, 100, 0, 100},
+		{25, 100, 25, 75},
+		{150, 100, 150, 0},
+		{5, 0, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%d", tc.Current, tc.Target), func(t *testing.T) {
+			goal := EventChannelGoalProgress{CurrentAmount: tc.Current, TargetAmount: tc.Target}
+			if percent := goal.PercentComplete(); percent != tc.Percent {
+				t.Errorf("expected %f%% got %f%%", tc.Percent, percent)
+			}
+			if remaining := goal.Remaining(); remaining != tc.Remaining {
+				t.Errorf("expected %d remaining got %d", tc.Remaining, remaining)
+			}
+			end := EventChannelGoalEnd(goal)
+			if end.PercentComplete() != tc.Percent || end.Remaining() != tc.Remaining {
+				t.Error("expected the end event to match the progress event")
+			}
+		})
+	}
+}
+
+func TestHypeTrainContribution(t *testing.T) {
+	bits := HypeTrainContribution{Type: HypeTrainContributionBits, Total: 350}
+	if bits.HypeUnits() != 350 || bits.Tier1Subscriptions() != 0 {
+		t.Errorf("unexpected units for bits %d %d", bits.HypeUnits(), bits.Tier1Subscriptions())
+	}
+
+	subs := HypeTrainContribution{Type: HypeTrainContributionSubscription, Total: HypeUnitsTier2Subscription + HypeUnitsTier1Subscription}
+	if subs.HypeUnits() != 1500 || subs.Tier1Subscriptions() != 3 {
+		t.Errorf("unexpected units for subscriptions %d %d", subs.HypeUnits(), subs.Tier1Subscriptions())
+	}
+}
+
+func TestRaidDirection(t *testing.T) {
+	raid := EventChannelRaid{FromBroadcasterUserId: "1", ToBroadcasterUserId: "2"}
+	testCases := []struct {
+		Self     string
+		Expected RaidDirection
+	}{
+		{"2", RaidIncoming},
+		{"1", RaidOutgoing},
+		{"3", RaidUnknown},
+		{"", RaidUnknown},
+	}
+	for _, tc := range testCases {
+		if direction := raid.Direction(tc.Self); direction != tc.Expected {
+			t.Errorf("expected %q for %q got %q", tc.Expected, tc.Self, direction)
+		}
+	}
+
+	var payloadContext PayloadContext
+	payloadContext.Subscription.Type = SubChannelRaid
+	payloadContext.Subscription.Condition = map[string]string{"to_broadcaster_user_id": "2"}
+	if direction := payloadContext.RaidDirection(); direction != RaidIncoming {
+		t.Errorf("expected an incoming raid got %q", direction)
+	}
+	payloadContext.Subscription.Condition = map[string]string{"from_broadcaster_user_id": "1"}
+	if direction := payloadContext.RaidDirection(); direction != RaidOutgoing {
+		t.Errorf("expected an outgoing raid got %q", direction)
+	}
+}
+
+func TestAdBreakBegin(t *testing.T) {
+	var numbers, strings EventChannelAdBreakBegin
+	err := json.Unmarshal([]byte(`{"duration_seconds":60,"started_at":"2019-11-16T10:11:12Z","is_automatic":true,"requester_user_id":"1337"}`), &numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal([]byte(`{"duration_seconds":"60","started_at":"2019-11-16T10:11:12Z","is_automatic":"true","requester_user_id":"1337"}`), &strings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numbers != strings {
+		t.Errorf("expected string fields to decode like numbers: %v %v", numbers, strings)
+	}
+
+	if numbers.Duration() != time.Minute || !numbers.IsAutomatic || numbers.RequesterUserId != "1337" {
+		t.Errorf("unexpected ad break %v", numbers)
+	}
+	if endsAt := numbers.EndsAt(); !endsAt.Equal(time.Date(2019, 11, 16, 10, 12, 12, 0, time.UTC)) {
+		t.Errorf("unexpected end %s", endsAt)
+	}
+	if remaining := numbers.Remaining(numbers.StartedAt.Add(45 * time.Second)); remaining != 15*time.Second {
+		t.Errorf("expected 15s remaining got %s", remaining)
+	}
+	if remaining := numbers.Remaining(numbers.EndsAt().Add(time.Second)); remaining != 0 {
+		t.Errorf("expected no time remaining got %s", remaining)
+	}
+	if err := json.Unmarshal([]byte(`{"duration_seconds":"soon"}`), &numbers); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+}
+
+func TestSuspiciousUserMessage(t *testing.T) {
+	var event EventChannelSuspiciousUserMessage
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelSuspiciousUserMessage)], &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if event.LowTrustStatus != LowTrustStatusActiveMonitoring || event.BanEvasionEvaluation != BanEvasionEvaluationLikely {
+		t.Errorf("unexpected evaluation %q %q", event.LowTrustStatus, event.BanEvasionEvaluation)
+	}
+	if !event.HasType(SuspiciousUserTypeBanEvader) || event.HasType(SuspiciousUserTypeManual) {
+		t.Errorf("unexpected types %v", event.Types)
+	}
+	if !event.IsBanEvader() {
+		t.Error("expected a ban evader")
+	}
+	if len(event.Message.Fragments) != 2 || event.Message.Fragments[1].Cheermote == nil || event.Message.MessageId != "101010" {
+		t.Errorf("unexpected message %v", event.Message)
+	}
+}
+
+func TestWarningChatRules(t *testing.T) {
+	warning := EventChannelWarningSend{Reason: "cut it out", ChatRulesCited: ChatRules{"No spam", "Be kind "}}
+	if !warning.ChatRulesCited.Contains("be kind") || warning.ChatRulesCited.Contains("No links") {
+		t.Errorf("unexpected rules %v", warning.ChatRulesCited)
+	}
+
+	testCases := []struct {
+		Warning  Warning
+		Expected string
+	}{
+		{Warning{Reason: "cut it out"}, "cut it out"},
+		{Warning{ChatRulesCited: ChatRules{"No spam"}}, "rules cited: No spam"},
+		{Warning{Reason: "cut it out", ChatRulesCited: ChatRules{"No spam", "Be kind"}}, "cut it out (rules cited: No spam, Be kind)"},
+	}
+	for _, tc := range testCases {
+		if description := tc.Warning.Description(); description != tc.Expected {
+			t.Errorf("expected %q got %q", tc.Expected, description)
+		}
+	}
+}
+
+func TestAutomodSettingsDiff(t *testing.T) {
+	overall := AutomodLevelSome
+	previous := EventAutomodSettingsUpdate{OverallLevel: &overall, Aggression: AutomodLevelSome, Swearing: AutomodLevelOff}
+	current := EventAutomodSettingsUpdate{Aggression: AutomodLevelMost, Swearing: AutomodLevelOff}
+
+	changes := current.Diff(previous)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes got %v", changes)
+	}
+	if changes[0].Category != AutomodCategoryOverall || *changes[0].From != AutomodLevelSome || changes[0].To != nil {
+		t.Errorf("unexpected overall change %v", changes[0])
+	}
+	if changes[1].Category != AutomodCategoryAggression || *changes[1].From != AutomodLevelSome || *changes[1].To != AutomodLevelMost {
+		t.Errorf("unexpected aggression change %v", changes[1])
+	}
+	if changes := current.Diff(current); len(changes) != 0 {
+		t.Errorf("expected no changes got %v", changes)
+	}
+	if level := current.Levels()[AutomodCategoryAggression]; level != AutomodLevelMost {
+		t.Errorf("expected the most aggression filtering got %d", level)
+	}
+}
+
+func TestUserWhisperMessage(t *testing.T) {
+	var event EventUserWhisperMessage
+	if err := json.Unmarshal(loadFixtures(t)[string(SubUserWhisperMessage)], &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if event.ID() != "some-whisper-id" {
+		t.Errorf("expected the whisper ID got %q", event.ID())
+	}
+	if event.Text() != "a secret" {
+		t.Errorf("expected the whisper text got %q", event.Text())
+	}
+	if from := event.From(); from.UserID != "423374343" || from.UserLogin != "glowillig" {
+		t.Errorf("unexpected sender %v", from)
+	}
+	if to := event.To(); to.UserID != "424596340" || to.UserName != "quotrok" {
+		t.Errorf("unexpected recipient %v", to)
+	}
+
+	var nested EventUserWhisperMessage
+	if err := json.Unmarshal([]byte(`{"whisper":{"id":"nested-id","text":"hi"}}`), &nested); err != nil {
+		t.Fatal(err)
+	}
+	if nested.ID() != "nested-id" {
+		t.Errorf("expected the nested whisper ID got %q", nested.ID())
+	}
+}
+
+func TestCustomRewardHelpers(t *testing.T) {
+	var event EventChannelChannelPointsCustomRewardAdd
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelChannelPointsCustomRewardAdd)], &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if url := event.ImageURL(ImageScale2x); url != "https://static-cdn.jtvnw.net/image-2.png" {
+		t.Errorf("expected the custom 2x image got %q", url)
+	}
+	if cooldown := event.GlobalCooldown.Duration(); cooldown != 1000*time.Second {
+		t.Errorf("expected a cooldown of 1000s got %s", cooldown)
+	}
+	if remaining, ok := event.RemainingThisStream(); !ok || remaining != 1000 {
+		t.Errorf("expected 1000 remaining redemptions got %d %v", remaining, ok)
+	}
+
+	event.Image = Image{}
+	if url := event.ImageURL(ImageScale4x); url != "https://static-cdn.jtvnw.net/default-4.png" {
+		t.Errorf("expected the default 4x image got %q", url)
+	}
+	if url := (Image{Url1x: "1x"}).URL(ImageScale4x); url != "1x" {
+		t.Errorf("expected a fallback to the 1x image got %q", url)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	update := EventChannelChannelPointsCustomRewardUpdate(event)
+	update.CooldownExpiresAt = now.Add(time.Minute)
+	if remaining := update.CooldownRemaining(now); remaining != time.Minute {
+		t.Errorf("expected 1m of cooldown got %s", remaining)
+	}
+	if remaining := update.CooldownRemaining(now.Add(time.Hour)); remaining != 0 {
+		t.Errorf("expected no cooldown got %s", remaining)
+	}
+}
+
+func TestPollChoices(t *testing.T) {
+	var end EventChannelPollEnd
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelPollEnd)], &end); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := end.TotalVotes(); total != 340 {
+		t.Errorf("expected 340 votes got %d", total)
+	}
+	if total := end.Choices.TotalBitsVotes(); total != 160 {
+		t.Errorf("expected 160 bits votes got %d", total)
+	}
+	if total := end.Choices.TotalChannelPointVotes(); total != 180 {
+		t.Errorf("expected 180 channel point votes got %d", total)
+	}
+	if leader, ok := end.Leader(); !ok || leader.ID != "124" {
+		t.Errorf("expected choice 124 to lead got %v %v", leader, ok)
+	}
+
+	tied := PollChoices{{ID: "a", Votes: 3}, {ID: "b", Votes: 3}, {ID: "c", Votes: 1}}
+	if _, ok := tied.Leader(); ok {
+		t.Error("expected no leader for a tie")
+	}
+	if leaders := tied.Leaders(); len(leaders) != 2 {
+		t.Errorf("expected 2 leaders got %v", leaders)
+	}
+	if _, ok := (PollChoices{{ID: "a"}}).Leader(); ok {
+		t.Error("expected no leader without votes")
+	}
+}
+
+func TestShoutoutCooldowns(t *testing.T) {
+	var event EventChannelShoutoutCreate
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelShoutoutCreate)], &event); err != nil {
+		t.Fatal(err)
+	}
+
+	now := event.CooldownEndsAt.Add(-time.Minute)
+	if remaining := event.CooldownRemaining(now); remaining != time.Minute {
+		t.Errorf("expected 1m of cooldown got %s", remaining)
+	}
+	if remaining := event.TargetCooldownRemaining(now); remaining != 59*time.Minute {
+		t.Errorf("expected 59m of target cooldown got %s", remaining)
+	}
+	if remaining := event.CooldownRemaining(event.TargetCooldownEndsAt); remaining != 0 {
+		t.Errorf("expected no cooldown got %s", remaining)
+	}
+}
@@ -0,0 +1,99 @@
+package twitch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestFixturePayloads asserts every field of the fixtures survives a decode and
+// re-encode of the event struct.
+func TestFixturePayloads(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("twitchtest", "payloads.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var payloads map[string]json.RawMessage
+	err = json.Unmarshal(data, &payloads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, payload := range payloads {
+		event, _, _ := strings.Cut(key, "-")
+		metadata, ok := subMetadata[EventSubscription(event)]
+		if !ok {
+			continue
+		}
+
+		payload := payload
+		t.Run(key, func(t *testing.T) {
+			assertRoundTrip(t, metadata, payload)
+		})
+	}
+}
+
+func assertRoundTrip(t *testing.T, metadata subscriptionMetadata, payload json.RawMessage) {
+	t.Helper()
+
+	event := metadata.EventGen()
+	err := json.Unmarshal(payload, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := fieldPaths(t, payload), fieldPaths(t, encoded)
+	var missing []string
+	for path := range want {
+		if !got[path] {
+			missing = append(missing, path)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("fields lost decoding into %T: %s", event, strings.Join(missing, ", "))
+	}
+}
+
+func fieldPaths(t *testing.T, data []byte) map[string]bool {
+	t.Helper()
+
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make(map[string]bool)
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if child == nil {
+					continue
+				}
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				paths[path] = true
+				walk(path, child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(prefix+"[]", child)
+			}
+		}
+	}
+	walk("", value)
+	return paths
+}
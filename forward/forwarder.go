@@ -0,0 +1,402 @@
+// Package forward POSTs EventSub notifications to HTTP endpoints, like Discord webhooks
+// or internal APIs, retrying failed deliveries and signing them so the endpoints can
+// trust them. Templates shape the body each endpoint expects.
+//
+//	tmpl, err := forward.ParseTemplate(`{"content": {{json (printf "%s followed!" .Event.user_name)}}}`)
+//	forwarder := forward.NewForwarder(forward.Endpoint{
+//		URL:      discordWebhookURL,
+//		Types:    []twitch.EventSubscription{twitch.SubChannelFollow},
+//		Template: tmpl,
+//	})
+//	client.SetPublishBridge(forwarder.Bridge())
+package forward
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// Headers of forwarded notifications. The signature is computed like Twitch signs
+// webhook callbacks, over the message ID, timestamp, and body.
+const (
+	MessageIDHeader        = "X-Eventsub-Forward-Message-Id"
+	MessageTimestampHeader = "X-Eventsub-Forward-Message-Timestamp"
+	MessageSignatureHeader = "X-Eventsub-Forward-Message-Signature"
+	MessageAttemptHeader   = "X-Eventsub-Forward-Message-Attempt"
+	SubscriptionTypeHeader = "X-Eventsub-Forward-Subscription-Type"
+)
+
+var (
+	ErrClosed    = errors.New("forward: forwarder closed")
+	ErrSignature = errors.New("forward: invalid signature")
+)
+
+// Endpoint is where notifications are forwarded to.
+type Endpoint struct {
+	URL string
+	// Types are the subscription types forwarded, every type if empty.
+	Types []twitch.EventSubscription
+	// Secret signs the deliveries with HMAC-SHA256 if set.
+	Secret string
+	// Template renders the body from a TemplateData. The body is the notification in the
+	// twitch.PublishEnvelope format without it.
+	Template *template.Template
+	// ContentType defaults to application/json.
+	ContentType string
+	// Header is added to every delivery.
+	Header http.Header
+}
+
+func (e Endpoint) wants(subscription twitch.EventSubscription) bool {
+	if len(e.Types) == 0 {
+		return true
+	}
+	for _, t := range e.Types {
+		if t == subscription {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateData is what templates are executed with.
+type TemplateData struct {
+	Metadata     twitch.MessageMetadata
+	Subscription twitch.PayloadSubscription
+	// Event is the event decoded from JSON, so its fields are named like in the Twitch
+	// reference, like .Event.broadcaster_user_name.
+	Event any
+	// Raw is the event JSON as Twitch sent it.
+	Raw string
+}
+
+// TemplateFuncs are the functions ParseTemplate adds: json encodes a value as JSON, to
+// embed strings in JSON bodies safely.
+var TemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ParseTemplate parses a body template with TemplateFuncs.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("body").Funcs(TemplateFuncs).Parse(text)
+}
+
+// DeliveryError is passed to OnError when a delivery failed for good.
+type DeliveryError struct {
+	URL       string
+	MessageID string
+	Attempts  int
+	// StatusCode is the status of the last response, 0 if there was none.
+	StatusCode int
+	Err        error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("forward: could not deliver %s to %s after %d attempts: %v", e.MessageID, e.URL, e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+type delivery struct {
+	messageID    string
+	subscription twitch.EventSubscription
+	body         []byte
+}
+
+type endpointQueue struct {
+	endpoint   Endpoint
+	deliveries chan delivery
+}
+
+// Forwarder delivers notifications to its endpoints in the background. Each endpoint
+// has its own queue, delivered in order, so a slow endpoint does not hold up the
+// others. It implements twitch.Publisher for notifications in the
+// twitch.PublishEnvelope format.
+type Forwarder struct {
+	// Client defaults to an http.Client with a 10 second timeout.
+	Client *http.Client
+	// MaxAttempts is how many times a delivery is tried. Defaults to 5.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each retry up to
+	// MaxBackoff. Defaults to 1 second and 1 minute. Retry-After headers of rate limited
+	// responses are followed instead.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	onError func(err error)
+
+	queues  []*endpointQueue
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped int64
+}
+
+// NewForwarder starts delivering to the endpoints, queueing up to 256 notifications for
+// each.
+func NewForwarder(endpoints ...Endpoint) *Forwarder {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &Forwarder{ctx: ctx, cancel: cancel}
+	for _, endpoint := range endpoints {
+		queue := &endpointQueue{endpoint: endpoint, deliveries: make(chan delivery, 256)}
+		f.queues = append(f.queues, queue)
+		f.workers.Add(1)
+		go f.deliverAll(queue)
+	}
+	return f
+}
+
+// OnError is called with a *DeliveryError for every delivery that failed for good.
+func (f *Forwarder) OnError(callback func(err error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.onError = callback
+}
+
+// Bridge returns a publish bridge sending every notification to the forwarder.
+func (f *Forwarder) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{Publisher: f, Format: twitch.PublishEnvelope}
+}
+
+// Publish renders the notification for every endpoint forwarding its type and queues
+// it. It never blocks, notifications are dropped for endpoints whose queue is full.
+func (f *Forwarder) Publish(_ context.Context, _ string, data []byte) error {
+	var published twitch.PublishedNotification
+	err := json.Unmarshal(data, &published)
+	if err != nil {
+		return fmt.Errorf("could not decode published notification: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	var templateData *TemplateData
+	var errs []string
+	for _, queue := range f.queues {
+		if !queue.endpoint.wants(published.Subscription.Type) {
+			continue
+		}
+
+		body := append([]byte(nil), data...)
+		if queue.endpoint.Template != nil {
+			if templateData == nil {
+				templateData = &TemplateData{
+					Metadata:     published.Metadata,
+					Subscription: published.Subscription,
+					Raw:          string(published.Event),
+				}
+				json.Unmarshal(published.Event, &templateData.Event)
+			}
+			var b bytes.Buffer
+			err := queue.endpoint.Template.Execute(&b, templateData)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("could not render template for %s: %v", queue.endpoint.URL, err))
+				continue
+			}
+			body = b.Bytes()
+		}
+
+		select {
+		case queue.deliveries <- delivery{messageID: published.Metadata.MessageID, subscription: published.Subscription.Type, body: body}:
+		default:
+			f.dropped++
+			errs = append(errs, fmt.Sprintf("queue of %s is full", queue.endpoint.URL))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("forward: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Dropped returns how many notifications were dropped because an endpoint queue was full.
+func (f *Forwarder) Dropped() int64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.dropped
+}
+
+// Close stops accepting notifications and waits for the queued ones to be delivered.
+// Once ctx is done, the remaining deliveries are abandoned.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		for _, queue := range f.queues {
+			close(queue.deliveries)
+		}
+	}
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		f.cancel()
+		return nil
+	case <-ctx.Done():
+		f.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (f *Forwarder) deliverAll(queue *endpointQueue) {
+	defer f.workers.Done()
+	for d := range queue.deliveries {
+		err := f.deliver(queue.endpoint, d)
+		if err != nil {
+			f.mu.RLock()
+			onError := f.onError
+			f.mu.RUnlock()
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// deliver tries the delivery until it succeeds, fails for good, or runs out of attempts.
+func (f *Forwarder) deliver(endpoint Endpoint, d delivery) error {
+	maxAttempts := f.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	backoff := f.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	maxBackoff := f.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	deliveryErr := &DeliveryError{URL: endpoint.URL, MessageID: d.messageID}
+	for attempt := 1; ; attempt++ {
+		deliveryErr.Attempts = attempt
+		statusCode, retryAfter, retry, err := f.post(endpoint, d, attempt)
+		deliveryErr.StatusCode = statusCode
+		if err == nil {
+			return nil
+		}
+		deliveryErr.Err = err
+		if !retry || attempt >= maxAttempts {
+			return deliveryErr
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-f.ctx.Done():
+			timer.Stop()
+			deliveryErr.Err = fmt.Errorf("%w: %v", ErrClosed, err)
+			return deliveryErr
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// post tries the delivery once, returning the response status, how long the endpoint
+// asked to wait before retrying, and whether retrying is worth it.
+func (f *Forwarder) post(endpoint Endpoint, d delivery, attempt int) (int, time.Duration, bool, error) {
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodPost, endpoint.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("could not create request: %w", err)
+	}
+
+	for name, values := range endpoint.Header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	contentType := endpoint.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(MessageIDHeader, d.messageID)
+	req.Header.Set(MessageTimestampHeader, time.Now().UTC().Format(time.RFC3339Nano))
+	req.Header.Set(MessageAttemptHeader, strconv.Itoa(attempt))
+	req.Header.Set(SubscriptionTypeHeader, string(d.subscription))
+	if endpoint.Secret != "" {
+		req.Header.Set(MessageSignatureHeader, sign(req.Header, d.body, endpoint.Secret))
+	}
+
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, 0, true, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res.StatusCode, 0, false, nil
+	}
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	retry := res.StatusCode == http.StatusRequestTimeout || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return res.StatusCode, retryAfter, retry, fmt.Errorf("unexpected status %s", res.Status)
+}
+
+func sign(headers http.Header, body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(headers.Get(MessageIDHeader)))
+	mac.Write([]byte(headers.Get(MessageTimestampHeader)))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature of a forwarded notification against the secret
+// of its endpoint. body must be the raw request body.
+func VerifySignature(headers http.Header, body []byte, secret string) error {
+	if !hmac.Equal([]byte(sign(headers, body, secret)), []byte(headers.Get(MessageSignatureHeader))) {
+		return ErrSignature
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package forward_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/forward"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+func TestForwarder(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, request{header: r.Header, body: body})
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tmpl, err := forward.ParseTemplate(`{"content": {{json (printf "%v raided with %v viewers" .Event.from_broadcaster_user_name .Event.viewers)}}}`)
+	require.NoError(t, err)
+	forwarder := forward.NewForwarder(forward.Endpoint{
+		URL:      server.URL,
+		Types:    []twitch.EventSubscription{twitch.SubChannelRaid},
+		Secret:   "secret",
+		Template: tmpl,
+	})
+	forwarder.Backoff = time.Millisecond
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(forwarder.Bridge())
+	raid := twitch.EventChannelRaid{Viewers: 42}
+	raid.FromBroadcasterUserName = "Raider"
+	require.NoError(t, client.InjectNotification(twitch.SubChannelFollow, twitch.EventChannelFollow{}))
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, raid))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, client.FlushPublished(ctx))
+	require.NoError(t, forwarder.Close(ctx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 2)
+	for i, req := range requests {
+		assert.JSONEq(t, `{"content": "Raider raided with 42 viewers"}`, string(req.body))
+		assert.Equal(t, "channel.raid", req.header.Get(forward.SubscriptionTypeHeader))
+		assert.Equal(t, requests[0].header.Get(forward.MessageIDHeader), req.header.Get(forward.MessageIDHeader))
+		assert.Equal(t, []string{"1", "2"}[i], req.header.Get(forward.MessageAttemptHeader))
+		assert.NoError(t, forward.VerifySignature(req.header, req.body, "secret"))
+		assert.ErrorIs(t, forward.VerifySignature(req.header, req.body, "wrong"), forward.ErrSignature)
+	}
+
+	assert.ErrorIs(t, forwarder.Publish(ctx, "", []byte(`{}`)), forward.ErrClosed)
+}
+
+func TestForwarderPermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	forwarder := forward.NewForwarder(forward.Endpoint{URL: server.URL})
+	forwarder.Backoff = time.Millisecond
+	errs := make(chan error, 1)
+	forwarder.OnError(func(err error) {
+		errs <- err
+	})
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(forwarder.Bridge())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 1}))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, forwarder.Close(context.Background()))
+
+	var deliveryErr *forward.DeliveryError
+	require.True(t, errors.As(<-errs, &deliveryErr))
+	assert.Equal(t, 1, deliveryErr.Attempts)
+	assert.Equal(t, http.StatusBadRequest, deliveryErr.StatusCode)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestForwarderEnvelope(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	forwarder := forward.NewForwarder(forward.Endpoint{URL: server.URL})
+	client := twitch.NewClient()
+	client.SetPublishBridge(forwarder.Bridge())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, forwarder.Close(context.Background()))
+
+	body := <-bodies
+	assert.Contains(t, string(body), `"subscription":{`)
+	assert.Contains(t, string(body), `"viewers":7`)
+}
@@ -0,0 +1,691 @@
+// Package gateway runs one process holding EventSub subscriptions for many
+// broadcasters, called tenants, and fans their notifications out to publish bridges
+// tagged with the tenant they belong to.
+//
+// Tenants either get a websocket session of their own, subscribed with their user
+// access token, or share a conduit subscribed with an app access token, whose shard is
+// the websocket session of the gateway.
+//
+//	g := gateway.New(gateway.Options{
+//		ClientID: clientID,
+//		Sinks:    []*twitch.PublishBridge{natsBridge, sseServer.Bridge()},
+//	})
+//	g.AddTenant(gateway.Tenant{
+//		ID:            "streamer",
+//		AccessToken:   userToken,
+//		Subscriptions: []gateway.Subscription{{Event: twitch.SubChannelFollow, Condition: condition}},
+//	})
+//	err := g.Run(ctx)
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API the gateway subscribes with.
+const DefaultHelixURL = helix.DefaultURL
+
+var ErrUnknownTenant = errors.New("gateway: unknown tenant")
+
+type Subscription struct {
+	Event twitch.EventSubscription
+	// Version overrides the version of the subscription type.
+	Version   string
+	Condition map[string]string
+}
+
+type Tenant struct {
+	// ID tags the notifications of the tenant.
+	ID string
+	// AccessToken is a user access token of the tenant, which its websocket session is
+	// subscribed with. When empty, the access token of the user with the ID of the
+	// tenant is taken from the Tokens of the options on every new session, so refreshed
+	// tokens are used. It is not used with a conduit.
+	AccessToken   string
+	Subscriptions []Subscription
+}
+
+// TokenSource returns the access tokens of users, like a tokens.Refresher.
+type TokenSource interface {
+	AccessToken(ctx context.Context, userID string) (string, error)
+}
+
+type Conduit struct {
+	ID             string
+	AppAccessToken string
+	// Shard is the shard of the conduit assigned to the websocket session of the
+	// gateway. Defaults to 0.
+	Shard string
+}
+
+type Options struct {
+	ClientID string
+	// Conduit subscribes every tenant to the conduit and receives their notifications on
+	// a single websocket session. Without it, every tenant has a session of its own.
+	Conduit *Conduit
+	// Sinks get every notification. Their subject defaults to
+	// twitch.eventsub.<tenant>.<subscription type>, and the PublishEnvelope and
+	// PublishMsgpack formats publish a Notification, enriched by the Enrich function of
+	// the sink.
+	Sinks []*twitch.PublishBridge
+	// Tokens returns the access tokens of the tenants without one.
+	Tokens TokenSource
+
+	// HelixURL defaults to DefaultHelixURL.
+	HelixURL string
+	// WebsocketURL defaults to the EventSub websocket of Twitch.
+	WebsocketURL string
+	// MaxBackoff bounds the wait between reconnects of a session that failed. Defaults
+	// to 1 minute.
+	MaxBackoff time.Duration
+	// Configure is called with every client the gateway creates, before it connects, to
+	// set up metrics, logging, or callbacks of its own.
+	Configure func(tenant string, client *twitch.Client)
+}
+
+// Notification is a PublishedNotification tagged with its tenant. It encodes with the
+// tenant next to the fields of the PublishedNotification, so consumers of envelopes
+// decode it as either.
+type Notification struct {
+	Tenant string `json:"tenant"`
+	twitch.PublishedNotification
+}
+
+// Gateway runs the websocket sessions of the tenants. It is safe for concurrent use.
+type Gateway struct {
+	options Options
+
+	mu            sync.Mutex
+	tenants       map[string]*tenant
+	subscriptions map[string]string
+	session       string
+	ctx           context.Context
+	onError       func(tenant string, err error)
+}
+
+type tenant struct {
+	Tenant
+	cancel context.CancelFunc
+	done   chan struct{}
+	// subscribed holds the indexes of the Subscriptions added to the conduit.
+	subscribed map[int]bool
+	// subscribing is set while the subscriptions are added to the conduit, and retrying
+	// while the failed ones wait for a retry.
+	subscribing, retrying bool
+}
+
+func New(options Options) *Gateway {
+	if options.HelixURL == "" {
+		options.HelixURL = DefaultHelixURL
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = time.Minute
+	}
+	if options.Conduit != nil && options.Conduit.Shard == "" {
+		conduit := *options.Conduit
+		conduit.Shard = "0"
+		options.Conduit = &conduit
+	}
+	return &Gateway{
+		options:       options,
+		tenants:       make(map[string]*tenant),
+		subscriptions: make(map[string]string),
+		onError:       func(tenant string, err error) { fmt.Printf("ERROR: %s: %v\n", tenant, err) },
+	}
+}
+
+// OnError is called with the errors of the sessions, subscriptions, and sinks, and the
+// tenant they concern, empty for the conduit session.
+func (g *Gateway) OnError(callback func(tenant string, err error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.onError = callback
+}
+
+func (g *Gateway) reportError(tenant string, err error) {
+	g.mu.Lock()
+	onError := g.onError
+	g.mu.Unlock()
+
+	onError(tenant, err)
+}
+
+// AddTenant adds a tenant, replacing the one with the same ID. While the gateway runs,
+// the tenant is connected or subscribed right away.
+func (g *Gateway) AddTenant(t Tenant) {
+	g.RemoveTenant(context.Background(), t.ID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state := &tenant{Tenant: t}
+	g.tenants[t.ID] = state
+	if g.ctx != nil {
+		g.startLocked(state)
+	}
+}
+
+// RemoveTenant stops receiving the notifications of a tenant, closing its session or
+// deleting its subscriptions from the conduit.
+func (g *Gateway) RemoveTenant(ctx context.Context, id string) error {
+	g.mu.Lock()
+	state, ok := g.tenants[id]
+	if !ok {
+		g.mu.Unlock()
+		return ErrUnknownTenant
+	}
+	delete(g.tenants, id)
+	var subscriptions []string
+	for subscription, tenant := range g.subscriptions {
+		if tenant == id {
+			subscriptions = append(subscriptions, subscription)
+			delete(g.subscriptions, subscription)
+		}
+	}
+	g.mu.Unlock()
+
+	if state.cancel != nil {
+		state.cancel()
+		<-state.done
+	}
+
+	var errs []string
+	for _, subscription := range subscriptions {
+		if err := g.unsubscribe(ctx, subscription); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("could not remove tenant %s: %s", id, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Run connects the sessions and keeps them connected until the context is canceled.
+func (g *Gateway) Run(ctx context.Context) error {
+	g.mu.Lock()
+	if g.ctx != nil {
+		g.mu.Unlock()
+		return errors.New("gateway: already running")
+	}
+	g.ctx = ctx
+	if g.options.Conduit == nil {
+		for _, state := range g.tenants {
+			g.startLocked(state)
+		}
+	}
+	g.mu.Unlock()
+
+	if g.options.Conduit != nil {
+		g.keepConnected(ctx, "", g.welcomeConduit)
+	} else {
+		<-ctx.Done()
+	}
+
+	g.mu.Lock()
+	g.ctx = nil
+	tenants := make([]*tenant, 0, len(g.tenants))
+	for _, state := range g.tenants {
+		tenants = append(tenants, state)
+	}
+	g.mu.Unlock()
+	for _, state := range tenants {
+		if state.done != nil {
+			<-state.done
+		}
+	}
+	return ctx.Err()
+}
+
+// startLocked connects the session of a tenant, or subscribes it to the conduit once
+// the gateway session is assigned to it.
+func (g *Gateway) startLocked(state *tenant) {
+	if g.options.Conduit != nil {
+		if g.session != "" {
+			go g.subscribeConduit(g.ctx, state)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(g.ctx)
+	state.cancel = cancel
+	state.done = make(chan struct{})
+	go func() {
+		defer close(state.done)
+		g.keepConnected(ctx, state.ID, func(ctx context.Context, client *twitch.Client, session string) error {
+			// Subscriptions of a session end with it, so the failed ones are retried
+			// while the session lasts.
+			subscribed := make(map[int]bool)
+			subscribe := func() bool {
+				if client.Session().ID != session {
+					return true
+				}
+				return g.subscribeSession(ctx, client, state, subscribed)
+			}
+			if !subscribe() {
+				go g.retry(ctx, subscribe)
+			}
+			return nil
+		})
+	}()
+}
+
+// subscribeSession subscribes the session of a tenant to the subscriptions not in
+// subscribed yet, adding the ones which succeed, and reports whether all did.
+func (g *Gateway) subscribeSession(ctx context.Context, client *twitch.Client, state *tenant, subscribed map[int]bool) bool {
+	accessToken := state.AccessToken
+	if accessToken == "" && g.options.Tokens != nil {
+		var err error
+		accessToken, err = g.options.Tokens.AccessToken(ctx, state.ID)
+		if err != nil {
+			g.reportError(state.ID, fmt.Errorf("could not get access token: %w", err))
+			return false
+		}
+	}
+	ok := true
+	for i, subscription := range state.Subscriptions {
+		if subscribed[i] {
+			continue
+		}
+		_, err := client.Subscribe(ctx, g.subscribeRequest(subscription, accessToken))
+		if err != nil {
+			g.reportError(state.ID, fmt.Errorf("could not subscribe to %s: %w", subscription.Event, err))
+			ok = false
+			continue
+		}
+		subscribed[i] = true
+	}
+	return ok
+}
+
+// retry calls attempt with backoff until it reports done or the context is canceled.
+func (g *Gateway) retry(ctx context.Context, attempt func() bool) {
+	backoff := time.Second
+	for {
+		if backoff > g.options.MaxBackoff {
+			backoff = g.options.MaxBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if attempt() {
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// keepConnected connects a client for the tenant, or the conduit when it is empty,
+// calling welcome on every new session with a context canceled when the connection
+// ends, and connects again with backoff when the connection fails until the context is
+// canceled.
+func (g *Gateway) keepConnected(ctx context.Context, tenantID string, welcome func(ctx context.Context, client *twitch.Client, session string) error) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		client := twitch.NewClient()
+		if g.options.WebsocketURL != "" {
+			client = twitch.NewClientWithUrl(g.options.WebsocketURL)
+		}
+		client.SubscriptionAddress = g.options.HelixURL + "/eventsub/subscriptions"
+		client.OnError(func(err error) { g.reportError(tenantID, err) })
+		client.SetPublishBridge(&twitch.PublishBridge{
+			Publisher: &tenantPublisher{gateway: g, tenant: tenantID},
+			Format:    twitch.PublishEnvelope,
+		})
+		// sessionCtx stops what welcome started with the connection.
+		sessionCtx, cancel := context.WithCancel(ctx)
+		var welcomed atomic.Bool
+		client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+			err := welcome(sessionCtx, client, message.Payload.Session.ID)
+			if err != nil {
+				g.reportError(tenantID, err)
+				return
+			}
+			welcomed.Store(true)
+		})
+		if g.options.Configure != nil {
+			g.options.Configure(tenantID, client)
+		}
+
+		err := client.ConnectWithContext(ctx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("gateway: session closed")
+		}
+		g.reportError(tenantID, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if welcomed.Load() {
+			backoff = time.Second
+		}
+		if backoff > g.options.MaxBackoff {
+			backoff = g.options.MaxBackoff
+		}
+	}
+}
+
+func (g *Gateway) subscribeRequest(subscription Subscription, accessToken string) twitch.SubscribeRequest {
+	return twitch.SubscribeRequest{
+		ClientID:        g.options.ClientID,
+		AccessToken:     accessToken,
+		VersionOverride: subscription.Version,
+		Event:           subscription.Event,
+		Condition:       subscription.Condition,
+	}
+}
+
+// welcomeConduit assigns the new session to the shard of the conduit, then subscribes
+// the tenants not subscribed yet. Subscriptions of a conduit outlive its sessions.
+func (g *Gateway) welcomeConduit(ctx context.Context, _ *twitch.Client, session string) error {
+	err := g.assignShard(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.session = session
+	tenants := make([]*tenant, 0, len(g.tenants))
+	for _, state := range g.tenants {
+		tenants = append(tenants, state)
+	}
+	g.mu.Unlock()
+
+	for _, state := range tenants {
+		g.subscribeConduit(ctx, state)
+	}
+	return nil
+}
+
+// subscribeConduit adds the subscriptions of a tenant to the conduit, retrying the
+// failed ones with backoff until they succeed, the tenant is removed, or the context is
+// canceled. The next welcome retries them too.
+func (g *Gateway) subscribeConduit(ctx context.Context, state *tenant) {
+	if g.subscribeConduitOnce(ctx, state) {
+		return
+	}
+	g.mu.Lock()
+	retrying := state.retrying
+	state.retrying = true
+	g.mu.Unlock()
+	if retrying {
+		return
+	}
+	go func() {
+		g.retry(ctx, func() bool { return g.subscribeConduitOnce(ctx, state) })
+		g.mu.Lock()
+		state.retrying = false
+		g.mu.Unlock()
+	}()
+}
+
+// subscribeConduitOnce adds the subscriptions of a tenant not added yet to the conduit,
+// and reports whether none is left to add.
+func (g *Gateway) subscribeConduitOnce(ctx context.Context, state *tenant) bool {
+	g.mu.Lock()
+	if g.tenants[state.ID] != state {
+		g.mu.Unlock()
+		return true
+	}
+	if state.subscribing {
+		// Check again once the subscriptions being added are.
+		g.mu.Unlock()
+		return false
+	}
+	state.subscribing = true
+	if state.subscribed == nil {
+		state.subscribed = make(map[int]bool)
+	}
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		state.subscribing = false
+		g.mu.Unlock()
+	}()
+
+	conduit := g.options.Conduit
+	ok := true
+	for i, subscription := range state.Subscriptions {
+		g.mu.Lock()
+		subscribed := state.subscribed[i]
+		g.mu.Unlock()
+		if subscribed {
+			continue
+		}
+
+		request := g.subscribeRequest(subscription, conduit.AppAccessToken)
+		request.ConduitID = conduit.ID
+		response, err := twitch.SubscribeEventUrlWithContext(ctx, request, g.options.HelixURL+"/eventsub/subscriptions")
+		if err != nil {
+			g.reportError(state.ID, fmt.Errorf("could not subscribe to %s: %w", subscription.Event, err))
+			ok = false
+			continue
+		}
+
+		g.mu.Lock()
+		removed := g.tenants[state.ID] != state
+		if !removed {
+			state.subscribed[i] = true
+			for _, created := range response.Data {
+				g.subscriptions[created.ID] = state.ID
+			}
+		}
+		g.mu.Unlock()
+
+		// Delete the subscriptions of a tenant removed while subscribing.
+		if removed {
+			for _, created := range response.Data {
+				if err := g.unsubscribe(context.Background(), created.ID); err != nil {
+					g.reportError(state.ID, err)
+				}
+			}
+			return true
+		}
+	}
+	return ok
+}
+
+type shardUpdate struct {
+	ConduitID string  `json:"conduit_id"`
+	Shards    []shard `json:"shards"`
+}
+
+type shard struct {
+	ID        string                       `json:"id"`
+	Transport twitch.SubscriptionTransport `json:"transport"`
+}
+
+type shardResponse struct {
+	Errors []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"errors"`
+}
+
+func (g *Gateway) assignShard(ctx context.Context, session string) error {
+	conduit := g.options.Conduit
+	update := shardUpdate{
+		ConduitID: conduit.ID,
+		Shards: []shard{{
+			ID:        conduit.Shard,
+			Transport: twitch.SubscriptionTransport{Method: "websocket", SessionID: session},
+		}},
+	}
+	var response shardResponse
+	err := g.helix().Do(ctx, http.MethodPatch, "/eventsub/conduits/shards", update, &response)
+	if err != nil {
+		return fmt.Errorf("could not assign conduit shard: %w", err)
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("could not assign conduit shard %s: %s", response.Errors[0].ID, response.Errors[0].Message)
+	}
+	return nil
+}
+
+func (g *Gateway) unsubscribe(ctx context.Context, id string) error {
+	err := g.helix().Do(ctx, http.MethodDelete, "/eventsub/subscriptions?id="+id, nil, nil)
+	if err != nil {
+		return fmt.Errorf("could not delete subscription %s: %w", id, err)
+	}
+	return nil
+}
+
+// helix returns the client of the API with the app access token of the conduit.
+func (g *Gateway) helix() helix.Client {
+	return helix.Client{
+		URL:         g.options.HelixURL,
+		ClientID:    g.options.ClientID,
+		AccessToken: g.options.Conduit.AppAccessToken,
+	}
+}
+
+// tenantPublisher tags the notifications of a client with its tenant and publishes them
+// to the sinks.
+type tenantPublisher struct {
+	gateway *Gateway
+	tenant  string
+}
+
+func (p *tenantPublisher) Publish(ctx context.Context, _ string, data []byte) error {
+	notification := Notification{Tenant: p.tenant}
+	err := json.Unmarshal(data, &notification.PublishedNotification)
+	if err != nil {
+		return fmt.Errorf("could not decode published notification: %w", err)
+	}
+
+	g := p.gateway
+	if notification.Tenant == "" {
+		tenant, ok := g.tenantOf(notification.Subscription)
+		if !ok {
+			return fmt.Errorf("gateway: notification for unknown subscription %s", notification.Subscription.ID)
+		}
+		notification.Tenant = tenant
+	}
+
+	sinks := make(twitch.MultiPublisher, len(g.options.Sinks))
+	for i, sink := range g.options.Sinks {
+		sink := sink
+		sinks[i] = twitch.PublisherFunc(func(ctx context.Context, _ string, _ []byte) error {
+			return g.publish(ctx, sink, notification)
+		})
+	}
+	var errs twitch.PublishErrors
+	if errors.As(sinks.Publish(ctx, "", nil), &errs) {
+		for _, i := range errs.Indexes() {
+			g.reportError(notification.Tenant, errs[i])
+		}
+	}
+	return nil
+}
+
+// tenantOf returns the tenant of a conduit subscription by its ID, or by its type and
+// condition when the notification came before the subscription was created.
+func (g *Gateway) tenantOf(subscription twitch.PayloadSubscription) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if tenant, ok := g.subscriptions[subscription.ID]; ok {
+		return tenant, true
+	}
+	for id, state := range g.tenants {
+		for _, s := range state.Subscriptions {
+			if s.Event == subscription.Type && conditionMatches(s.Condition, subscription.Condition) {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// conditionMatches reports whether the condition of a notification has the values
+// subscribed to. Twitch adds the keys left out as empty strings.
+func conditionMatches(subscribed, condition map[string]string) bool {
+	if len(subscribed) == 0 {
+		return false
+	}
+	for key, value := range subscribed {
+		if condition[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Gateway) publish(ctx context.Context, sink *twitch.PublishBridge, notification Notification) error {
+	subject := "twitch.eventsub." + notification.Tenant + "." + string(notification.Subscription.Type)
+	if sink.Subject != nil {
+		subject = sink.Subject(notification.Subscription)
+	}
+
+	if sink.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sink.Timeout)
+		defer cancel()
+	}
+	if sink.Enrich != nil && sink.Format != twitch.PublishRaw {
+		notification.Enrichment = g.enrich(ctx, sink, notification)
+	}
+
+	var data []byte
+	var err error
+	switch sink.Format {
+	case twitch.PublishRaw:
+		data = notification.Event
+	case twitch.PublishMsgpack:
+		data, err = twitch.MarshalMsgpack(notification)
+	default:
+		data, err = json.Marshal(notification)
+	}
+	if err != nil {
+		return fmt.Errorf("could not encode notification: %w", err)
+	}
+
+	err = sink.Publisher.Publish(ctx, subject, data)
+	if err != nil {
+		return fmt.Errorf("could not publish notification: %w", err)
+	}
+	return nil
+}
+
+func (g *Gateway) enrich(ctx context.Context, sink *twitch.PublishBridge, notification Notification) map[string]json.RawMessage {
+	values, err := sink.Enrich(ctx, notification.PublishedNotification)
+	if err != nil {
+		g.reportError(notification.Tenant, fmt.Errorf("could not enrich notification: %w", err))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	enrichment := make(map[string]json.RawMessage, len(values))
+	for name, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			g.reportError(notification.Tenant, fmt.Errorf("could not encode enrichment %s: %w", name, err))
+			continue
+		}
+		enrichment[name] = data
+	}
+	return enrichment
+}
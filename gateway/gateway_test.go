@@ -0,0 +1,265 @@
+package gateway_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/gateway"
+	"github.com/isabelcoolaf/go-twitch-eventsub/tokens"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type published struct {
+	subject string
+	data    []byte
+}
+
+type fakeSink struct {
+	published chan published
+}
+
+func (s *fakeSink) Publish(_ context.Context, subject string, data []byte) error {
+	s.published <- published{subject, data}
+	return nil
+}
+
+func tenant(id string) gateway.Tenant {
+	return gateway.Tenant{
+		ID:          id,
+		AccessToken: id + "-token",
+		Subscriptions: []gateway.Subscription{{
+			Event:     twitch.SubChannelRaid,
+			Condition: map[string]string{"to_broadcaster_user_id": id},
+		}},
+	}
+}
+
+func receive(t *testing.T, ctx context.Context, sink *fakeSink) (string, gateway.Notification) {
+	select {
+	case p := <-sink.published:
+		var notification gateway.Notification
+		require.NoError(t, json.Unmarshal(p.data, &notification))
+		return p.subject, notification
+	case <-ctx.Done():
+		t.Fatal("no notification published")
+		return "", gateway.Notification{}
+	}
+}
+
+func TestGatewaySessions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := twitchtest.NewServer()
+	defer server.Close()
+	helix := twitchtest.NewHelix(t)
+	store := tokens.NewMemoryStore()
+	require.NoError(t, store.Put(ctx, tokens.Token{UserID: "bob", AccessToken: "bob-token"}))
+
+	sink := &fakeSink{published: make(chan published, 16)}
+	g := gateway.New(gateway.Options{
+		ClientID:     "client",
+		Sinks:        []*twitch.PublishBridge{{Publisher: sink, Format: twitch.PublishEnvelope}},
+		Tokens:       tokens.NewRefresher(store, "client", "secret"),
+		HelixURL:     helix.URL,
+		WebsocketURL: server.URL,
+	})
+	g.OnError(func(tenant string, err error) {
+		t.Errorf("%s: %v", tenant, err)
+	})
+	g.AddTenant(tenant("alice"))
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- g.Run(runCtx) }()
+
+	alice, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+	request, err := helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer alice-token", request.Authorization)
+	// The token of bob comes from the store.
+	bobTenant := tenant("bob")
+	bobTenant.AccessToken = ""
+	g.AddTenant(bobTenant)
+	bob, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+	request, err = helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer bob-token", request.Authorization)
+	assert.Len(t, helix.Subscriptions(), 2)
+
+	require.NoError(t, bob.SendNotification(ctx, twitch.SubChannelRaid))
+	subject, notification := receive(t, ctx, sink)
+	assert.Equal(t, "twitch.eventsub.bob.channel.raid", subject)
+	assert.Equal(t, "bob", notification.Tenant)
+	assert.Equal(t, twitch.SubChannelRaid, notification.Subscription.Type)
+
+	require.NoError(t, alice.SendNotification(ctx, twitch.SubChannelRaid))
+	_, notification = receive(t, ctx, sink)
+	assert.Equal(t, "alice", notification.Tenant)
+
+	require.NoError(t, g.RemoveTenant(ctx, "alice"))
+	select {
+	case <-alice.Done():
+	case <-ctx.Done():
+		t.Fatal("session of removed tenant was not closed")
+	}
+
+	stop()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.ErrorIs(t, g.RemoveTenant(ctx, "carol"), gateway.ErrUnknownTenant)
+}
+
+func TestGatewayConduit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := twitchtest.NewServer()
+	defer server.Close()
+	helix := twitchtest.NewHelix(t)
+
+	sink := &fakeSink{published: make(chan published, 16)}
+	g := gateway.New(gateway.Options{
+		ClientID:     "client",
+		Conduit:      &gateway.Conduit{ID: "conduit", AppAccessToken: "app-token"},
+		Sinks:        []*twitch.PublishBridge{{Publisher: sink, Format: twitch.PublishMsgpack}},
+		HelixURL:     helix.URL,
+		WebsocketURL: server.URL,
+	})
+	g.OnError(func(tenant string, err error) {
+		t.Errorf("%s: %v", tenant, err)
+	})
+	g.AddTenant(tenant("alice"))
+	g.AddTenant(tenant("bob"))
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- g.Run(runCtx) }()
+
+	conn, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+	request, err := helix.WaitForRequest(ctx, http.MethodPatch, "/eventsub/conduits/shards")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer app-token", request.Authorization)
+	for i := 0; i < 2; i++ {
+		request, err := helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer app-token", request.Authorization)
+	}
+	var bob string
+	for _, subscription := range helix.Subscriptions() {
+		assert.Equal(t, "conduit", subscription.Transport.Method)
+		assert.Equal(t, "conduit", subscription.Transport.ConduitID)
+		if subscription.Condition["to_broadcaster_user_id"] == "bob" {
+			bob = subscription.ID
+		}
+	}
+	require.NotEmpty(t, bob)
+
+	message := twitchtest.NewNotificationFor(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42})
+	message.Payload.Subscription.ID = bob
+	message.Payload.Subscription.Condition = map[string]string{"to_broadcaster_user_id": "bob", "from_broadcaster_user_id": ""}
+	require.NoError(t, conn.Send(ctx, message))
+	select {
+	case p := <-sink.published:
+		assert.Equal(t, "twitch.eventsub.bob.channel.raid", p.subject)
+		var notification gateway.Notification
+		require.NoError(t, twitch.UnmarshalMsgpack(p.data, &notification))
+		assert.Equal(t, "bob", notification.Tenant)
+		assert.Contains(t, string(notification.Event), `"viewers":42`)
+	case <-ctx.Done():
+		t.Fatal("no notification published")
+	}
+
+	require.NoError(t, g.RemoveTenant(ctx, "bob"))
+	request, err = helix.WaitForRequest(ctx, http.MethodDelete, "/eventsub/subscriptions")
+	require.NoError(t, err)
+	assert.Equal(t, "/eventsub/subscriptions?id="+bob, request.URI)
+	assert.Len(t, helix.Subscriptions(), 1)
+
+	stop()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestGatewayRetriesSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	for _, conduit := range []*gateway.Conduit{nil, {ID: "conduit", AppAccessToken: "app-token"}} {
+		name := "sessions"
+		if conduit != nil {
+			name = "conduit"
+		}
+		conduit := conduit
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server := twitchtest.NewServer()
+			defer server.Close()
+			helix := twitchtest.NewHelix(t)
+			helix.Respond(http.MethodPost, "/eventsub/subscriptions", http.StatusInternalServerError)
+
+			g := gateway.New(gateway.Options{
+				ClientID:     "client",
+				Conduit:      conduit,
+				HelixURL:     helix.URL,
+				WebsocketURL: server.URL,
+				MaxBackoff:   10 * time.Millisecond,
+			})
+			errs := make(chan error, 16)
+			g.OnError(func(_ string, err error) {
+				errs <- err
+			})
+			alice := tenant("alice")
+			alice.Subscriptions = append(alice.Subscriptions, gateway.Subscription{
+				Event:     twitch.SubChannelFollow,
+				Condition: map[string]string{"broadcaster_user_id": "alice", "moderator_user_id": "alice"},
+			})
+			g.AddTenant(alice)
+
+			runCtx, stop := context.WithCancel(ctx)
+			done := make(chan error, 1)
+			go func() { done <- g.Run(runCtx) }()
+
+			_, err := server.WaitForConnection(ctx)
+			require.NoError(t, err)
+			select {
+			case err := <-errs:
+				assert.Contains(t, err.Error(), "could not subscribe to channel.raid")
+			case <-ctx.Done():
+				t.Fatal("failed subscription not reported")
+			}
+			for i := 0; i < 3; i++ {
+				_, err := helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+				require.NoError(t, err)
+			}
+			// The raid subscription failed first, and only it is retried.
+			var events []twitch.EventSubscription
+			for _, subscription := range helix.Subscriptions() {
+				events = append(events, subscription.Type)
+			}
+			assert.Equal(t, []twitch.EventSubscription{twitch.SubChannelFollow, twitch.SubChannelRaid}, events)
+
+			stop()
+			assert.ErrorIs(t, <-done, context.Canceled)
+			posts := 0
+			for _, request := range helix.Requests() {
+				if strings.HasPrefix(request, "POST ") {
+					posts++
+				}
+			}
+			assert.Equal(t, 3, posts)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package grpcstream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The messages of eventsub.proto, encoded by hand since they only hold strings and bytes.
+
+type SubscribeRequest struct {
+	Types []string
+}
+
+type Notification struct {
+	MessageID           string
+	SubscriptionType    string
+	SubscriptionVersion string
+	SubscriptionID      string
+	MessageTimestamp    string
+	Event               []byte
+}
+
+const wireBytes = 2
+
+func (n Notification) Marshal() []byte {
+	var b []byte
+	b = appendBytes(b, 1, []byte(n.MessageID))
+	b = appendBytes(b, 2, []byte(n.SubscriptionType))
+	b = appendBytes(b, 3, []byte(n.SubscriptionVersion))
+	b = appendBytes(b, 4, []byte(n.SubscriptionID))
+	b = appendBytes(b, 5, []byte(n.MessageTimestamp))
+	b = appendBytes(b, 6, n.Event)
+	return b
+}
+
+func (n *Notification) Unmarshal(data []byte) error {
+	return unmarshal(data, func(field int, value []byte) {
+		switch field {
+		case 1:
+			n.MessageID = string(value)
+		case 2:
+			n.SubscriptionType = string(value)
+		case 3:
+			n.SubscriptionVersion = string(value)
+		case 4:
+			n.SubscriptionID = string(value)
+		case 5:
+			n.MessageTimestamp = string(value)
+		case 6:
+			n.Event = append([]byte(nil), value...)
+		}
+	})
+}
+
+func (r SubscribeRequest) Marshal() []byte {
+	var b []byte
+	for _, t := range r.Types {
+		b = appendBytes(b, 1, []byte(t))
+	}
+	return b
+}
+
+func (r *SubscribeRequest) Unmarshal(data []byte) error {
+	return unmarshal(data, func(field int, value []byte) {
+		if field == 1 {
+			r.Types = append(r.Types, string(value))
+		}
+	})
+}
+
+// appendBytes appends a length-delimited field, skipping empty values like proto3 does.
+func appendBytes(b []byte, field int, value []byte) []byte {
+	if len(value) == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// unmarshal calls set with the length-delimited fields of the message and skips the
+// fields of other wire types.
+func unmarshal(data []byte, set func(field int, value []byte)) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+
+		field, wireType := int(key>>3), key&7
+		switch wireType {
+		case 0:
+			_, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case 1, 5:
+			size := 8
+			if wireType == 5 {
+				size = 4
+			}
+			if len(data) < size {
+				return errTruncated
+			}
+			data = data[size:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errTruncated
+			}
+			set(field, data[n:n+int(size)])
+			data = data[n+int(size):]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wireType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package grpcstream streams EventSub notifications to gRPC clients, so consumers in
+// any language can listen to a central EventSub gateway. It implements the EventSub
+// service of eventsub.proto on net/http instead of depending on grpc-go, so it is
+// served over HTTP/2 like any handler, which net/http does with TLS.
+//
+//	server := grpcstream.NewServer()
+//	client.SetPublishBridge(server.Bridge())
+//	http.ListenAndServeTLS(":8443", "cert.pem", "key.pem", server)
+package grpcstream
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// SubscribePath is the path gRPC clients call Subscribe on.
+const SubscribePath = "/twitch.eventsub.v1.EventSub/Subscribe"
+
+// gRPC status codes, see https://grpc.github.io/grpc/core/md_doc_statuscodes.html.
+const (
+	statusOK              = 0
+	statusInvalidArgument = 3
+	statusUnimplemented   = 12
+	statusInternal        = 13
+)
+
+// maxRequestSize bounds the SubscribeRequest, which only lists subscription types.
+const maxRequestSize = 64 << 10
+
+type subscriber struct {
+	types         map[string]bool
+	notifications chan []byte
+}
+
+// Server is an http.Handler serving the EventSub gRPC service. It implements
+// twitch.Publisher for the notifications published by the bridge it returns.
+type Server struct {
+	// Buffer is how many notifications are queued for a slow stream before new ones
+	// are dropped for it. Defaults to 256.
+	Buffer int
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	dropped     int64
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewServer() *Server {
+	return &Server{
+		subscribers: make(map[*subscriber]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Bridge returns a publish bridge sending every notification to the server.
+func (s *Server) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{Publisher: s, Format: twitch.PublishEnvelope}
+}
+
+// Publish queues the notification, published in the twitch.PublishEnvelope format, for
+// every stream subscribed to its type. It never blocks.
+func (s *Server) Publish(_ context.Context, _ string, data []byte) error {
+	var published twitch.PublishedNotification
+	err := json.Unmarshal(data, &published)
+	if err != nil {
+		return fmt.Errorf("could not decode published notification: %w", err)
+	}
+
+	subscriptionType := string(published.Subscription.Type)
+	frame := encodeFrame(Notification{
+		MessageID:           published.Metadata.MessageID,
+		SubscriptionType:    subscriptionType,
+		SubscriptionVersion: published.Subscription.Version,
+		SubscriptionID:      published.Subscription.ID,
+		MessageTimestamp:    published.Metadata.MessageTimestamp.Format(time.RFC3339Nano),
+		Event:               published.Event,
+	}.Marshal())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if len(sub.types) > 0 && !sub.types[subscriptionType] {
+			continue
+		}
+		select {
+		case sub.notifications <- frame:
+		default:
+			s.dropped++
+		}
+	}
+	return nil
+}
+
+// Dropped returns how many notifications were dropped for slow streams.
+func (s *Server) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+// Streams returns how many Subscribe calls are streaming.
+func (s *Server) Streams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subscribers)
+}
+
+// Close ends every stream with an OK status.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.WriteHeader(http.StatusOK)
+	if r.URL.Path != SubscribePath {
+		writeStatus(w, statusUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+
+	var request SubscribeRequest
+	data, code, err := readFrame(r.Body)
+	if err == nil {
+		err = request.Unmarshal(data)
+	}
+	if err != nil {
+		writeStatus(w, code, err.Error())
+		return
+	}
+
+	buffer := s.Buffer
+	if buffer <= 0 {
+		buffer = 256
+	}
+	sub := &subscriber{notifications: make(chan []byte, buffer)}
+	for _, t := range request.Types {
+		if sub.types == nil {
+			sub.types = make(map[string]bool)
+		}
+		sub.types[t] = true
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case frame := <-sub.notifications:
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			writeStatus(w, statusOK, "")
+			return
+		}
+	}
+}
+
+// readFrame reads a gRPC length-prefixed message, returning the status code to fail
+// the call with on errors.
+func readFrame(r io.Reader) ([]byte, int, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, statusInvalidArgument, fmt.Errorf("could not read request: %w", err)
+	}
+	if header[0] != 0 {
+		return nil, statusUnimplemented, fmt.Errorf("compressed requests are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxRequestSize {
+		return nil, statusInvalidArgument, fmt.Errorf("request of %d bytes is too large", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, statusInternal, fmt.Errorf("could not read request: %w", err)
+	}
+	return data, statusOK, nil
+}
+
+func encodeFrame(message []byte) []byte {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		w.Header().Set("Grpc-Message", message)
+	}
+}
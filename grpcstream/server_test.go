@@ -0,0 +1,95 @@
+package grpcstream_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/grpcstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func frame(message []byte) []byte {
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header[1:], uint32(len(message)))
+	return append(header, message...)
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	server := grpcstream.NewServer()
+	httpServer := httptest.NewUnstartedServer(server)
+	httpServer.EnableHTTP2 = true
+	httpServer.StartTLS()
+	defer httpServer.Close()
+
+	body := frame(grpcstream.SubscribeRequest{Types: []string{"channel.raid"}}.Marshal())
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL+grpcstream.SubscribePath, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	res, err := httpServer.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 2, res.ProtoMajor)
+	assert.Equal(t, "application/grpc+proto", res.Header.Get("Content-Type"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for server.Streams() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(server.Bridge())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelFollow, twitch.EventChannelFollow{}))
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+
+	header := make([]byte, 5)
+	_, err = io.ReadFull(res.Body, header)
+	require.NoError(t, err)
+	message := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	_, err = io.ReadFull(res.Body, message)
+	require.NoError(t, err)
+
+	var notification grpcstream.Notification
+	require.NoError(t, notification.Unmarshal(message))
+	assert.Equal(t, "channel.raid", notification.SubscriptionType)
+	assert.Equal(t, "1", notification.SubscriptionVersion)
+	assert.NotEmpty(t, notification.MessageID)
+	assert.Contains(t, string(notification.Event), `"viewers":42`)
+
+	server.Close()
+	_, err = io.Copy(io.Discard, res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+	t.Parallel()
+
+	server := grpcstream.NewServer()
+	defer server.Close()
+	httpServer := httptest.NewUnstartedServer(server)
+	httpServer.EnableHTTP2 = true
+	httpServer.StartTLS()
+	defer httpServer.Close()
+
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/twitch.eventsub.v1.EventSub/Unknown", bytes.NewReader(frame(nil)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc")
+
+	res, err := httpServer.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	_, err = io.Copy(io.Discard, res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "12", res.Trailer.Get("Grpc-Status"))
+}
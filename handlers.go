@@ -0,0 +1,111 @@
+package twitch
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+type SlowHandlerWarning struct {
+	// Handler is the name of the registered callback function.
+	Handler string
+	// Type is the subscription type for events, otherwise the message type.
+	Type          string
+	MessageID     string
+	CorrelationID string
+	Duration      time.Duration
+	Threshold     time.Duration
+}
+
+func (w SlowHandlerWarning) String() string {
+	return fmt.Sprintf("slow handler %s for %s took %s (threshold %s)", w.Handler, w.Type, w.Duration, w.Threshold)
+}
+
+func (c *Client) observeHandler(f any, payload any, wait, duration time.Duration) {
+	c.stats.addHandlerDuration(duration)
+
+	slow := c.slowHandlerThreshold > 0 && duration >= c.slowHandlerThreshold
+	if !slow && c.debugLogger == nil {
+		return
+	}
+
+	handler := handlerName(f)
+	handlerType, messageID, correlationID := handlerPayloadInfo(payload)
+	if c.debugLogger != nil {
+		c.debugLogger.Printf("handled %s message=%s correlation=%s handler=%s wait=%s duration=%s", handlerType, messageID, correlationID, handler, wait, duration)
+	}
+	if !slow {
+		return
+	}
+
+	warning := SlowHandlerWarning{
+		Handler:       handler,
+		Type:          handlerType,
+		MessageID:     messageID,
+		CorrelationID: correlationID,
+		Duration:      duration,
+		Threshold:     c.slowHandlerThreshold,
+	}
+
+	if c.onSlowHandler != nil {
+		c.onSlowHandler(warning)
+	} else {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+}
+
+func handlerPayloadInfo(payload any) (string, string, string) {
+	switch payload := payload.(type) {
+	case PayloadContext:
+		return string(payload.Subscription.Type), payload.Metadata.MessageID, payload.CorrelationID
+	case MessageMetadata:
+		return payload.MessageType, payload.MessageID, ""
+	}
+	return "", "", ""
+}
+
+func (c *Client) logDispatch(payloadContext PayloadContext, dispatched bool) {
+	c.eventListenersMu.RLock()
+	handlers := len(c.eventListeners)
+	c.eventListenersMu.RUnlock()
+	for _, registered := range []bool{dispatched, c.onNotification != nil, c.onRawEvent != nil, c.onLatency != nil, c.onEvent != nil} {
+		if registered {
+			handlers++
+		}
+	}
+
+	c.debugLogger.Printf("dispatched %s message=%s correlation=%s handlers=%d", payloadContext.Subscription.Type, payloadContext.Metadata.MessageID, payloadContext.CorrelationID, handlers)
+}
+
+func handlerName(f any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return fn.Name()
+}
+
+// SetSlowHandlerThreshold enables slow handler warnings for callbacks which take at least
+// threshold to return. A threshold of 0 disables the warnings.
+func (c *Client) SetSlowHandlerThreshold(threshold time.Duration) {
+	c.slowHandlerThreshold = threshold
+}
+
+// SetDebugLogger logs every dispatched notification and every handler call with its
+// queue wait and duration. A nil logger disables debug logging.
+func (c *Client) SetDebugLogger(logger *log.Logger) {
+	c.debugLogger = logger
+}
+
+// SetSynchronousDispatch runs handlers on the goroutine handling the message instead
+// of a new goroutine each. Combined with InjectMessage, handlers have returned by the
+// time it does, which makes tests deterministic. A slow handler blocks the read loop.
+func (c *Client) SetSynchronousDispatch(synchronous bool) {
+	c.synchronousDispatch = synchronous
+}
+
+func (c *Client) OnSlowHandler(callback func(warning SlowHandlerWarning)) {
+	c.onSlowHandler = callback
+}
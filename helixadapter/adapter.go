@@ -0,0 +1,148 @@
+// Package helixadapter converts between the subscription types of the twitch package
+// and the EventSub types of github.com/nicklaw5/helix, so projects using that Helix
+// client can create subscriptions for this websocket client with it.
+//
+// Both describe the JSON of the Twitch API, so the conversions go through JSON and take
+// the helix types as type parameters instead of importing helix. The type parameters
+// are helix.EventSubCondition for conditions and helix.EventSubSubscription for
+// subscriptions, or any struct encoding the same JSON.
+//
+//	request, err := helixadapter.SubscriptionRequestToHelix[helix.EventSubSubscription](twitch.SubscribeRequest{
+//		SessionID: sessionID,
+//		Event:     twitch.SubChannelFollow,
+//		Condition: map[string]string{"broadcaster_user_id": userID, "moderator_user_id": userID},
+//	})
+//	resp, err := helixClient.CreateEventSubSubscription(&request)
+package helixadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// ConditionFromHelix returns the condition of a subscription request from a
+// helix.EventSubCondition, leaving out the fields it does not set.
+func ConditionFromHelix(condition any) (map[string]string, error) {
+	var fields map[string]json.RawMessage
+	err := convert(condition, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(fields))
+	for name, value := range fields {
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			// Twitch conditions are strings, keep anything else as its JSON.
+			s = strings.TrimSpace(string(value))
+			if s == "null" {
+				s = ""
+			}
+		}
+		if s != "" {
+			result[name] = s
+		}
+	}
+	return result, nil
+}
+
+// ConditionToHelix returns the condition as a helix.EventSubCondition.
+func ConditionToHelix[T any](condition map[string]string) (T, error) {
+	var result T
+	err := convert(condition, &result)
+	return result, err
+}
+
+// SubscriptionFromHelix returns a helix.EventSubSubscription, like the subscriptions
+// listed by the Helix client, as a subscription of the twitch package.
+func SubscriptionFromHelix(subscription any) (twitch.PayloadSubscription, error) {
+	var result twitch.PayloadSubscription
+	err := convert(subscription, &result)
+	if err != nil {
+		return twitch.PayloadSubscription{}, err
+	}
+
+	result.Condition, err = conditionOf(subscription)
+	return result, err
+}
+
+// SubscriptionToHelix returns the subscription as a helix.EventSubSubscription.
+func SubscriptionToHelix[T any](subscription twitch.PayloadSubscription) (T, error) {
+	var result T
+	err := convert(subscription, &result)
+	return result, err
+}
+
+// SubscriptionRequestToHelix returns the subscription the request creates as a
+// helix.EventSubSubscription, with the transport of its session or conduit, to create
+// it with the Helix client. The client ID and access token of the request are left to
+// the Helix client.
+func SubscriptionRequestToHelix[T any](request twitch.SubscribeRequest) (T, error) {
+	version := request.Event.Version()
+	if request.VersionOverride != "" {
+		version = request.VersionOverride
+	}
+
+	transport := twitch.SubscriptionTransport{Method: "websocket", SessionID: request.SessionID}
+	if request.ConduitID != "" {
+		transport = twitch.SubscriptionTransport{Method: "conduit", ConduitID: request.ConduitID}
+	}
+
+	var result T
+	err := convert(twitch.SubscriptionRequest{
+		Type:      request.Event,
+		Version:   version,
+		Condition: request.Condition,
+		Transport: transport,
+	}, &result)
+	return result, err
+}
+
+// SubscribeRequestFromHelix returns a subscription request from a
+// helix.EventSubSubscription holding its type, version, condition, and websocket or
+// conduit transport.
+func SubscribeRequestFromHelix(subscription any, clientID, accessToken string) (twitch.SubscribeRequest, error) {
+	payload, err := SubscriptionFromHelix(subscription)
+	if err != nil {
+		return twitch.SubscribeRequest{}, err
+	}
+
+	request := twitch.SubscribeRequest{
+		SessionID:   payload.Transport.SessionID,
+		ConduitID:   payload.Transport.ConduitID,
+		ClientID:    clientID,
+		AccessToken: accessToken,
+		Event:       payload.Type,
+		Condition:   payload.Condition,
+	}
+	if payload.Version != payload.Type.Version() {
+		request.VersionOverride = payload.Version
+	}
+	return request, nil
+}
+
+func conditionOf(subscription any) (map[string]string, error) {
+	var fields struct {
+		Condition json.RawMessage `json:"condition"`
+	}
+	err := convert(subscription, &fields)
+	if err != nil || len(fields.Condition) == 0 || string(fields.Condition) == "null" {
+		return nil, err
+	}
+	return ConditionFromHelix(fields.Condition)
+}
+
+func convert(from any, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("could not encode %T: %w", from, err)
+	}
+	err = json.Unmarshal(data, to)
+	if err != nil {
+		return fmt.Errorf("could not convert %T to %T: %w", from, to, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package helixadapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/helixadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The shape of the helix EventSub types, which encode every field.
+
+type eventSubCondition struct {
+	BroadcasterUserID     string `json:"broadcaster_user_id"`
+	FromBroadcasterUserID string `json:"from_broadcaster_user_id"`
+	ModeratorUserID       string `json:"moderator_user_id"`
+	ToBroadcasterUserID   string `json:"to_broadcaster_user_id"`
+	RewardID              string `json:"reward_id"`
+	ClientID              string `json:"client_id"`
+	ExtensionClientID     string `json:"extension_client_id"`
+	UserID                string `json:"user_id"`
+}
+
+type eventSubTransport struct {
+	Method    string `json:"method"`
+	Callback  string `json:"callback"`
+	Secret    string `json:"secret"`
+	SessionID string `json:"session_id"`
+	ConduitID string `json:"conduit_id"`
+}
+
+type eventSubSubscription struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Version   string            `json:"version"`
+	Status    string            `json:"status"`
+	Condition eventSubCondition `json:"condition"`
+	Transport eventSubTransport `json:"transport"`
+	CreatedAt time.Time         `json:"created_at"`
+	Cost      int               `json:"cost"`
+}
+
+func TestCondition(t *testing.T) {
+	t.Parallel()
+
+	condition, err := helixadapter.ConditionFromHelix(eventSubCondition{BroadcasterUserID: "1337", ModeratorUserID: "42"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"broadcaster_user_id": "1337", "moderator_user_id": "42"}, condition)
+
+	helixCondition, err := helixadapter.ConditionToHelix[eventSubCondition](condition)
+	require.NoError(t, err)
+	assert.Equal(t, eventSubCondition{BroadcasterUserID: "1337", ModeratorUserID: "42"}, helixCondition)
+}
+
+func TestSubscription(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	helixSubscription := eventSubSubscription{
+		ID:        "sub-id",
+		Type:      "channel.follow",
+		Version:   "2",
+		Status:    "enabled",
+		Condition: eventSubCondition{BroadcasterUserID: "1337", ModeratorUserID: "1337"},
+		Transport: eventSubTransport{Method: "websocket", SessionID: "session-id"},
+		CreatedAt: createdAt,
+		Cost:      1,
+	}
+
+	subscription, err := helixadapter.SubscriptionFromHelix(helixSubscription)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-id", subscription.ID)
+	assert.Equal(t, twitch.SubChannelFollow, subscription.Type)
+	assert.Equal(t, "enabled", subscription.Status)
+	assert.Equal(t, map[string]string{"broadcaster_user_id": "1337", "moderator_user_id": "1337"}, subscription.Condition)
+	assert.Equal(t, "session-id", subscription.Transport.SessionID)
+	assert.True(t, subscription.CreatedAt.Equal(createdAt))
+	assert.Equal(t, 1, subscription.Cost)
+
+	roundTrip, err := helixadapter.SubscriptionToHelix[eventSubSubscription](subscription)
+	require.NoError(t, err)
+	assert.Equal(t, helixSubscription, roundTrip)
+}
+
+func TestSubscribeRequest(t *testing.T) {
+	t.Parallel()
+
+	request := twitch.SubscribeRequest{
+		ConduitID: "conduit-id",
+		Event:     twitch.SubChannelRaid,
+		Condition: map[string]string{"to_broadcaster_user_id": "1337"},
+	}
+	helixSubscription, err := helixadapter.SubscriptionRequestToHelix[eventSubSubscription](request)
+	require.NoError(t, err)
+	assert.Equal(t, "channel.raid", helixSubscription.Type)
+	assert.Equal(t, twitch.SubChannelRaid.Version(), helixSubscription.Version)
+	assert.Equal(t, "1337", helixSubscription.Condition.ToBroadcasterUserID)
+	assert.Equal(t, eventSubTransport{Method: "conduit", ConduitID: "conduit-id"}, helixSubscription.Transport)
+
+	helixSubscription.Version = "beta"
+	roundTrip, err := helixadapter.SubscribeRequestFromHelix(helixSubscription, "client-id", "token")
+	require.NoError(t, err)
+	request.ClientID = "client-id"
+	request.AccessToken = "token"
+	request.VersionOverride = "beta"
+	assert.Equal(t, request, roundTrip)
+}
@@ -0,0 +1,265 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"sync"
+	"time"
+)
+
+const twitchHypeTrainStatusUrl = "https://api.twitch.tv/helix/hypetrain/status"
+
+// HypeTrain is the state of a hype train built from its events.
+type HypeTrain struct {
+	EventChannelHypeTrainBegin
+
+	// EndedAt and CooldownEndsAt are zero until the train ends.
+	EndedAt        time.Time
+	CooldownEndsAt time.Time
+}
+
+func (t HypeTrain) IsActive() bool {
+	return t.EndedAt.IsZero()
+}
+
+// Remaining returns how long the train has left to reach its goal, which resets with
+// every contribution.
+func (t HypeTrain) Remaining(now time.Time) time.Duration {
+	if !t.IsActive() || !now.Before(t.ExpiresAt) {
+		return 0
+	}
+	return t.ExpiresAt.Sub(now)
+}
+
+// TopContributor returns the user who contributed the most of a type.
+func (t HypeTrain) TopContributor(contributionType HypeTrainContributionType) (HypeTrainContribution, bool) {
+	for _, contribution := range t.TopContributions {
+		if contribution.Type == contributionType {
+			return contribution, true
+		}
+	}
+	return HypeTrainContribution{}, false
+}
+
+// HypeTrains follows the hype trains of broadcasters from the channel.hype_train events.
+// Events missed while disconnected are caught up with Sync, which callers run when a
+// new session is welcomed. It is safe to use from concurrent callbacks.
+type HypeTrains struct {
+	mu     sync.Mutex
+	trains map[string]*HypeTrain
+	clock  Clock
+}
+
+func NewHypeTrains() *HypeTrains {
+	return &HypeTrains{trains: make(map[string]*HypeTrain), clock: SystemClock{}}
+}
+
+// SetClock sets the clock giving the time statuses are fetched by Sync.
+func (h *HypeTrains) SetClock(clock Clock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clock = clock
+}
+
+func (h *HypeTrains) Begin(event EventChannelHypeTrainBegin) HypeTrain {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	train := &HypeTrain{EventChannelHypeTrainBegin: event}
+	h.trains[event.BroadcasterUserId] = train
+	return *train
+}
+
+// Progress updates the train, ignoring events older than what it knows, since
+// notifications may come out of order.
+func (h *HypeTrains) Progress(event EventChannelHypeTrainProgress) HypeTrain {
+	state := event.EventChannelHypeTrainBegin
+	// Level is decoded into the field of the progress event, not the one it embeds.
+	state.Level = event.Level
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return *h.update(state)
+}
+
+// End ends the train. It is kept until the next one begins, with its cooldown.
+func (h *HypeTrains) End(event EventChannelHypeTrainEnd) HypeTrain {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	train, ok := h.trains[event.BroadcasterUserId]
+	if !ok || train.Id != event.Id {
+		train = &HypeTrain{}
+		h.trains[event.BroadcasterUserId] = train
+	}
+	train.Broadcaster = event.Broadcaster
+	train.Id = event.Id
+	train.Level = event.Level
+	train.Total = event.Total
+	train.TopContributions = event.TopContributions
+	train.Type = event.Type
+	train.IsSharedTrain = event.IsSharedTrain
+	train.SharedTrainParticipants = event.SharedTrainParticipants
+	train.StartedAt = event.StartedAt
+	train.ExpiresAt = event.ExpiresAt
+	train.IsGoldenKappaTrain = event.IsGoldenKappaTrain
+	train.EndedAt = event.EndedAt
+	train.CooldownEndsAt = event.CooldownEndsAt
+	return *train
+}
+
+func (h *HypeTrains) update(state EventChannelHypeTrainBegin) *HypeTrain {
+	train, ok := h.trains[state.BroadcasterUserId]
+	if ok && train.Id == state.Id && (!train.IsActive() || train.Total > state.Total) {
+		return train
+	}
+	if !ok || train.Id != state.Id {
+		train = &HypeTrain{}
+		h.trains[state.BroadcasterUserId] = train
+	}
+	train.EventChannelHypeTrainBegin = state
+	return train
+}
+
+// Get returns the current or last train of the broadcaster.
+func (h *HypeTrains) Get(broadcasterID string) (HypeTrain, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	train, ok := h.trains[broadcasterID]
+	if !ok {
+		return HypeTrain{}, false
+	}
+	return *train, true
+}
+
+// Active returns the trains going on.
+func (h *HypeTrains) Active() []HypeTrain {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var trains []HypeTrain
+	for _, train := range h.trains {
+		if train.IsActive() {
+			trains = append(trains, *train)
+		}
+	}
+	return trains
+}
+
+// Resync replaces what is known of the train of a broadcaster with its status from the
+// Twitch API. A train that was going on but is not anymore ends when the status was
+// fetched, since its end event was missed.
+func (h *HypeTrains) Resync(broadcasterID string, status HypeTrainStatus) (HypeTrain, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if status.Current != nil {
+		state := *status.Current
+		state.AllTimeHighLevel = status.AllTimeHigh.Level
+		state.AllTimeHighTotal = status.AllTimeHigh.Total
+		return *h.update(state), true
+	}
+
+	train, ok := h.trains[broadcasterID]
+	if !ok {
+		return HypeTrain{}, false
+	}
+	if train.IsActive() {
+		train.EndedAt = status.FetchedAt
+		if train.ExpiresAt.Before(train.EndedAt) {
+			train.EndedAt = train.ExpiresAt
+		}
+	}
+	return *train, true
+}
+
+// Sync fetches the status of the train of the broadcaster of the request and resyncs
+// with it, returning the current or last train.
+func (h *HypeTrains) Sync(ctx context.Context, request HypeTrainStatusRequest) (HypeTrain, bool, error) {
+	return h.sync(ctx, request, twitchHypeTrainStatusUrl)
+}
+
+func (h *HypeTrains) sync(ctx context.Context, request HypeTrainStatusRequest, url string) (HypeTrain, bool, error) {
+	status, err := GetHypeTrainStatusUrlWithContext(ctx, request, url)
+	if err != nil {
+		return HypeTrain{}, false, err
+	}
+	h.mu.Lock()
+	status.FetchedAt = h.clock.Now()
+	h.mu.Unlock()
+
+	train, ok := h.Resync(request.BroadcasterID, status)
+	return train, ok, nil
+}
+
+type HypeTrainStatusRequest struct {
+	ClientID    string
+	AccessToken string
+
+	BroadcasterID string
+}
+
+type HypeTrainRecord struct {
+	Level      int       `json:"level"`
+	Total      int       `json:"total"`
+	AchievedAt time.Time `json:"achieved_at"`
+}
+
+// HypeTrainStatus is the response of Get Hype Train Status.
+type HypeTrainStatus struct {
+	// Current is nil when no train is going on.
+	Current           *EventChannelHypeTrainBegin `json:"current"`
+	AllTimeHigh       HypeTrainRecord             `json:"all_time_high"`
+	SharedAllTimeHigh *HypeTrainRecord            `json:"shared_all_time_high"`
+	FetchedAt         time.Time                   `json:"-"`
+}
+
+func GetHypeTrainStatusWithContext(ctx context.Context, request HypeTrainStatusRequest) (HypeTrainStatus, error) {
+	return GetHypeTrainStatusUrlWithContext(ctx, request, twitchHypeTrainStatusUrl)
+}
+
+// GetHypeTrainStatusUrlWithContext needs a user access token of the broadcaster with
+// the channel:read:hype_train scope.
+func GetHypeTrainStatusUrlWithContext(ctx context.Context, request HypeTrainStatusRequest, url string) (HypeTrainStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"?broadcaster_id="+neturl.QueryEscape(request.BroadcasterID), nil)
+	if err != nil {
+		return HypeTrainStatus{}, fmt.Errorf("could not create new request: %w", err)
+	}
+
+	req.Header.Set("Client-Id", request.ClientID)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", request.AccessToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return HypeTrainStatus{}, fmt.Errorf("could not get hype train status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return HypeTrainStatus{}, fmt.Errorf("could not get hype train status: %s: %s", resp.Status, string(body))
+	}
+
+	var response struct {
+		Data []HypeTrainStatus `json:"data"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return HypeTrainStatus{}, fmt.Errorf("could not unmarshal hype train status: %w", err)
+	}
+
+	var status HypeTrainStatus
+	if len(response.Data) > 0 {
+		status = response.Data[0]
+	}
+	status.FetchedAt = time.Now()
+	return status, nil
+}
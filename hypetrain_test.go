@@ -0,0 +1,133 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHypeTrains(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trains := NewHypeTrains()
+	begin := EventChannelHypeTrainBegin{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		Id:          "train",
+		Level:       1,
+		Total:       100,
+		Goal:        1000,
+		StartedAt:   startedAt,
+		ExpiresAt:   startedAt.Add(5 * time.Minute),
+	}
+	trains.Begin(begin)
+
+	progress := EventChannelHypeTrainProgress{EventChannelHypeTrainBegin: begin, Level: 2}
+	progress.Total = 1500
+	progress.Progress = 500
+	progress.ExpiresAt = startedAt.Add(6 * time.Minute)
+	progress.TopContributions = []HypeTrainContribution{
+		{User: User{UserLogin: "cheerer"}, Type: HypeTrainContributionBits, Total: 1000},
+	}
+	trains.Progress(progress)
+	// A late progress event does not go back in time.
+	stale := EventChannelHypeTrainProgress{EventChannelHypeTrainBegin: begin, Level: 1}
+	train := trains.Progress(stale)
+
+	if !train.IsActive() || train.Level != 2 || train.Total != 1500 {
+		t.Errorf("unexpected train %+v", train)
+	}
+	if remaining := train.Remaining(startedAt.Add(time.Minute)); remaining != 5*time.Minute {
+		t.Errorf("expected 5m remaining got %s", remaining)
+	}
+	if top, ok := train.TopContributor(HypeTrainContributionBits); !ok || top.UserLogin != "cheerer" {
+		t.Errorf("unexpected top contributor %+v", top)
+	}
+	if _, ok := train.TopContributor(HypeTrainContributionSubscription); ok {
+		t.Error("expected no top subscription contributor")
+	}
+
+	train = trains.End(EventChannelHypeTrainEnd{
+		Broadcaster:    begin.Broadcaster,
+		Id:             "train",
+		Level:          3,
+		Total:          2500,
+		EndedAt:        startedAt.Add(10 * time.Minute),
+		CooldownEndsAt: startedAt.Add(70 * time.Minute),
+	})
+	if train.IsActive() || train.Level != 3 || train.Goal != 1000 || train.Remaining(startedAt) != 0 {
+		t.Errorf("unexpected ended train %+v", train)
+	}
+	if len(trains.Active()) != 0 {
+		t.Error("expected no active trains")
+	}
+}
+
+func TestHypeTrainsSync(t *testing.T) {
+	current := `{"id":"train","broadcaster_user_id":"1","level":4,"total":4000,"progress":100,"goal":2000,` +
+		`"top_contributions":[{"user_id":"2","user_login":"subber","user_name":"Subber","type":"subscription","total":2500}],` +
+		`"started_at":"2024-01-01T00:00:00Z","expires_at":"2024-01-01T00:05:00Z","type":"treasure"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprintf(w, `{"data":[{"current":%s,"all_time_high":{"level":6,"total":9000}}]}`, current)
+	}))
+	defer server.Close()
+
+	trains := NewHypeTrains()
+	trains.Begin(EventChannelHypeTrainBegin{Broadcaster: Broadcaster{BroadcasterUserId: "1"}, Id: "train", Level: 1})
+
+	request := HypeTrainStatusRequest{ClientID: "client", AccessToken: "token", BroadcasterID: "1"}
+	status, err := GetHypeTrainStatusUrlWithContext(context.Background(), request, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	train, ok := trains.Resync("1", status)
+	if !ok || train.Level != 4 || train.AllTimeHighLevel != 6 || train.Type != HypeTrainTypeTreasure {
+		t.Errorf("unexpected train %+v", train)
+	}
+	if top, _ := train.TopContributor(HypeTrainContributionSubscription); top.Tier1Subscriptions() != 5 {
+		t.Errorf("unexpected top contributor %+v", top)
+	}
+
+	// The end of the train was missed.
+	current = "null"
+	status, err = GetHypeTrainStatusUrlWithContext(context.Background(), request, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	train, ok = trains.Resync("1", status)
+	if !ok || train.IsActive() || !train.EndedAt.Equal(train.ExpiresAt) {
+		t.Errorf("expected the train to end when it expired got %+v", train)
+	}
+	if _, ok := trains.Resync("2", status); ok {
+		t.Error("expected no train for broadcaster 2")
+	}
+}
+
+func TestHypeTrainsSyncClock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"current":null,"all_time_high":{"level":6,"total":9000}}]}`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 0, 2, 0, 0, time.UTC)
+	trains := NewHypeTrains()
+	trains.SetClock(fixedClock(now))
+	trains.Begin(EventChannelHypeTrainBegin{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		Id:          "train",
+		ExpiresAt:   now.Add(3 * time.Minute),
+	})
+
+	// The end of the train was missed before it expired.
+	train, ok, err := trains.sync(context.Background(), HypeTrainStatusRequest{BroadcasterID: "1"}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || !train.EndedAt.Equal(now) {
+		t.Errorf("expected the train to end when the status was fetched got %+v", train)
+	}
+}
@@ -0,0 +1,88 @@
+package twitch
+
+// Identity is a user as events identify them, whichever fields of the event they came
+// from. The embedded user types of events return it from their Identity accessors, so
+// code can handle who did something without knowing the event.
+type Identity struct {
+	ID    string
+	Login string
+	Name  string
+}
+
+func (i Identity) IsZero() bool {
+	return i.ID == "" && i.Login == "" && i.Name == ""
+}
+
+// DisplayOrLogin returns the display name, or the login if there is none.
+func (i Identity) DisplayOrLogin() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	return i.Login
+}
+
+func (u User) UserIdentity() Identity {
+	return Identity{ID: u.UserID, Login: u.UserLogin, Name: u.UserName}
+}
+
+func (b Broadcaster) BroadcasterIdentity() Identity {
+	return Identity{ID: b.BroadcasterUserId, Login: b.BroadcasterUserLogin, Name: b.BroadcasterUserName}
+}
+
+func (e EventStreamOffline) BroadcasterIdentity() Identity {
+	return Broadcaster(e).BroadcasterIdentity()
+}
+
+func (e EventChannelChatClear) BroadcasterIdentity() Identity {
+	return Broadcaster(e).BroadcasterIdentity()
+}
+
+func (m Moderator) ModeratorIdentity() Identity {
+	return Identity{ID: m.ModeratorUserId, Login: m.ModeratorUserLogin, Name: m.ModeratorUserName}
+}
+
+func (s SourceBroadcaster) SourceBroadcasterIdentity() Identity {
+	return Identity{ID: s.SourceBroadcasterUserId, Login: s.SourceBroadcasterUserLogin, Name: s.SourceBroadcasterUserName}
+}
+
+func (t Target) TargetIdentity() Identity {
+	return Identity{ID: t.TargetUserId, Login: t.TargetUserLogin, Name: t.TargetUserName}
+}
+
+func (c Chatter) ChatterIdentity() Identity {
+	return Identity{ID: c.ChatterUserId, Login: c.ChatterUserLogin, Name: c.ChatterUserName}
+}
+
+func (h HostBroadcaster) HostBroadcasterIdentity() Identity {
+	return Identity{ID: h.HostBroadcasterUserId, Login: h.HostBroadcasterUserLogin, Name: h.HostBroadcasterUserName}
+}
+
+func (h GuestStarHost) HostIdentity() Identity {
+	return Identity{ID: h.HostUserId, Login: h.HostUserLogin, Name: h.HostUserName}
+}
+
+// Actor returns who caused an event: the moderator for moderation events, otherwise the
+// chatter or user, otherwise the broadcaster. It returns false if the event identifies
+// none of them.
+func Actor(event any) (Identity, bool) {
+	var identities []Identity
+	if e, ok := event.(interface{ ModeratorIdentity() Identity }); ok {
+		identities = append(identities, e.ModeratorIdentity())
+	}
+	if e, ok := event.(interface{ ChatterIdentity() Identity }); ok {
+		identities = append(identities, e.ChatterIdentity())
+	}
+	if e, ok := event.(interface{ UserIdentity() Identity }); ok {
+		identities = append(identities, e.UserIdentity())
+	}
+	if e, ok := event.(interface{ BroadcasterIdentity() Identity }); ok {
+		identities = append(identities, e.BroadcasterIdentity())
+	}
+
+	for _, identity := range identities {
+		if !identity.IsZero() {
+			return identity, true
+		}
+	}
+	return Identity{}, false
+}
@@ -0,0 +1,31 @@
+package twitch
+
+import "testing"
+
+func TestActor(t *testing.T) {
+	broadcaster := Broadcaster{BroadcasterUserId: "1", BroadcasterUserLogin: "broadcaster", BroadcasterUserName: "Broadcaster"}
+	moderator := Moderator{ModeratorUserId: "2", ModeratorUserLogin: "moderator"}
+	chatter := Chatter{ChatterUserId: "3", ChatterUserLogin: "chatter", ChatterUserName: "Chatter"}
+
+	testCases := []struct {
+		Name     string
+		Event    any
+		Expected string
+	}{
+		{"moderator", EventChannelBan{Broadcaster: broadcaster, Moderator: moderator, User: User{UserID: "4"}}, "moderator"},
+		{"chatter", EventChannelChatMessage{Broadcaster: broadcaster, Chatter: chatter}, "Chatter"},
+		{"pointer", &EventChannelChatMessage{Broadcaster: broadcaster, Chatter: chatter}, "Chatter"},
+		{"empty moderator", EventAutomodMessageHold{Broadcaster: broadcaster, User: User{UserLogin: "user"}}, "user"},
+		{"broadcaster", EventChannelChatClear(broadcaster), "Broadcaster"},
+	}
+
+	for _, tc := range testCases {
+		actor, ok := Actor(tc.Event)
+		if !ok || actor.DisplayOrLogin() != tc.Expected {
+			t.Errorf("%s: expected %q got %q", tc.Name, tc.Expected, actor.DisplayOrLogin())
+		}
+	}
+	if _, ok := Actor(EventChannelFollow{}); ok {
+		t.Error("expected no actor for an empty event")
+	}
+}
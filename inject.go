@@ -0,0 +1,100 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// InjectMessage handles a raw websocket message as if it was received from Twitch,
+// without needing a connection. Errors are returned instead of passed to OnError.
+func (c *Client) InjectMessage(raw []byte) error {
+	return c.handleMessage(raw)
+}
+
+// InjectNotification wraps event in a notification message for the subscription type
+// and injects it. The event can be an event struct or its raw JSON.
+func (c *Client) InjectNotification(subscription EventSubscription, event any) error {
+	var data json.RawMessage
+	switch event := event.(type) {
+	case json.RawMessage:
+		data = event
+	case []byte:
+		data = event
+	default:
+		var err error
+		data, err = json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("could not marshal event: %w", err)
+		}
+	}
+
+	message := NotificationMessage{
+		Metadata: MessageMetadata{
+			MessageID:           uuid.NewString(),
+			MessageType:         "notification",
+			MessageTimestamp:    c.now(),
+			SubscriptionType:    subscription,
+			SubscriptionVersion: subscription.Version(),
+		},
+	}
+	message.Payload.Event = &data
+	message.Payload.Subscription = PayloadSubscription{
+		SubscriptionRequest: SubscriptionRequest{
+			Type:      subscription,
+			Version:   subscription.Version(),
+			Condition: map[string]string{},
+			Transport: SubscriptionTransport{
+				Method:    "websocket",
+				SessionID: c.sessionID(),
+			},
+		},
+		ID:        uuid.NewString(),
+		Status:    "enabled",
+		CreatedAt: c.now(),
+	}
+
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("could not marshal notification: %w", err)
+	}
+	return c.InjectMessage(raw)
+}
+
+// InjectPublished injects a notification published in the PublishEnvelope format, like
+// one read back from an archive, with its original metadata and subscription.
+func (c *Client) InjectPublished(published PublishedNotification) error {
+	raw, err := publishedMessage(published)
+	if err != nil {
+		return err
+	}
+	return c.InjectMessage(raw)
+}
+
+// publishedMessage returns the notification message of a published notification.
+func publishedMessage(published PublishedNotification) ([]byte, error) {
+	message := NotificationMessage{Metadata: published.Metadata}
+	message.Metadata.MessageType = "notification"
+	event := published.Event
+	if event == nil {
+		event = json.RawMessage("null")
+	}
+	message.Payload.Event = &event
+	message.Payload.Subscription = published.Subscription
+	subscription := &message.Payload.Subscription
+	if subscription.Type == "" {
+		subscription.Type = message.Metadata.SubscriptionType
+		subscription.Version = message.Metadata.SubscriptionVersion
+	}
+	if message.Metadata.SubscriptionType == "" {
+		message.Metadata.SubscriptionType = subscription.Type
+		message.Metadata.SubscriptionVersion = subscription.Version
+	}
+
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal notification: %w", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,268 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectMessage(t *testing.T) {
+	t.Parallel()
+
+	assertEventOccured(t, func(ch chan struct{}) {
+		client := twitch.NewClient()
+		client.OnEventChannelCheer(func(event twitch.EventChannelCheer, _ twitch.PayloadContext) {
+			assert.True(t, event.IsAnonymous)
+			close(ch)
+		})
+
+		err := client.InjectMessage(twitchtest.NotificationJSON(twitch.SubChannelCheer, "anon"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestInjectNotification(t *testing.T) {
+	t.Parallel()
+
+	assertEventOccured(t, func(ch chan struct{}) {
+		client := twitch.NewClient()
+		client.OnEventChannelRaid(func(event twitch.EventChannelRaid, payloadContext twitch.PayloadContext) {
+			assert.Equal(t, 42, event.Viewers)
+			assert.Equal(t, twitch.SubChannelRaid, payloadContext.Subscription.Type)
+			close(ch)
+		})
+
+		err := client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42})
+		assert.NoError(t, err)
+	})
+}
+
+func TestInjectMessageError(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	assert.Error(t, client.InjectMessage([]byte(`{`)))
+}
+
+func TestSynchronousDispatch(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+
+	var raids []int
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
+		raids = append(raids, event.Viewers)
+	})
+
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: i}))
+	}
+	assert.Equal(t, []int{1, 2, 3}, raids)
+}
+
+func TestOnEvent(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+
+	var events []any
+	client.OnEvent(func(event any, _ twitch.PayloadContext) {
+		events = append(events, event)
+	})
+
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+	assert.Equal(t, []any{twitch.EventChannelRaid{Viewers: 7}}, events)
+}
+
+func TestOnEventPointer(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	client.SetEventPooling(true)
+
+	var raids []*twitch.EventChannelRaid
+	twitch.OnEventPointer(client, func(event *twitch.EventChannelRaid, _ twitch.PayloadContext) {
+		raids = append(raids, event)
+	})
+
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 8}))
+	if assert.Len(t, raids, 2) {
+		assert.Equal(t, 7, raids[0].Viewers, "events delivered by pointer must not be reused")
+		assert.Equal(t, 8, raids[1].Viewers)
+	}
+
+	assert.Panics(t, func() {
+		twitch.OnEventPointer(client, func(event *twitch.PayloadContext, _ twitch.PayloadContext) {})
+	})
+}
+
+func TestSkipDecodeWithoutListener(t *testing.T) {
+	t.Parallel()
+
+	invalid := json.RawMessage(`{"viewers":"many"}`)
+
+	client := twitch.NewClient()
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, invalid), "events without a listener are not decoded")
+
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {})
+	assert.Error(t, client.InjectNotification(twitch.SubChannelRaid, invalid))
+}
+
+func TestEventPooling(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetEventPooling(true)
+
+	raids := twitchtest.ExpectEvent[twitch.EventChannelRaid](t, client)
+
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, json.RawMessage(`{"viewers":5,"from_broadcaster_user_id":"1337"}`)))
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, json.RawMessage(`{"viewers":6}`)))
+
+	first, second := <-raids, <-raids
+	if first.Viewers == 6 {
+		first, second = second, first
+	}
+	assert.Equal(t, "1337", first.FromBroadcasterUserId)
+	assert.Equal(t, 6, second.Viewers)
+	assert.Empty(t, second.FromBroadcasterUserId, "pooled events must not keep fields from earlier events")
+}
+
+func TestWithHighThroughput(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient().WithHighThroughput()
+	events := twitchtest.ExpectEvent[twitch.EventChannelChatMessage](t, client)
+
+	assert.NoError(t, client.InjectMessage(twitchtest.NotificationJSON(twitch.SubChannelChatMessage)))
+	message := <-events
+	assert.NotEmpty(t, message.Message.Text)
+}
+
+func TestEventsBatched(t *testing.T) {
+	t.Parallel()
+
+	clock := twitchtest.NewFakeClock(time.Now())
+	client := twitch.NewClient()
+	client.SetClock(clock)
+	batches := client.EventsBatched(2, time.Second)
+
+	for viewers := 1; viewers <= 3; viewers++ {
+		assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: viewers}))
+	}
+
+	batch := <-batches
+	if assert.Len(t, batch, 2, "a full batch is sent right away") {
+		assert.Equal(t, twitch.EventChannelRaid{Viewers: 1}, batch[0].Event)
+		assert.Equal(t, twitch.SubChannelRaid, batch[1].PayloadContext.Subscription.Type)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		// The window timer starts when the batcher receives the event, so keep advancing.
+		clock.Advance(time.Second)
+		select {
+		case batch = <-batches:
+			assert.Equal(t, []twitch.BatchedEvent{{Event: twitch.EventChannelRaid{Viewers: 3}, PayloadContext: batch[0].PayloadContext}}, batch)
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("partial batch was not sent after the window")
+		}
+	}
+}
+
+type publishedMessage struct {
+	subject string
+	data    []byte
+}
+
+type fakePublisher struct {
+	published []publishedMessage
+}
+
+func (p *fakePublisher) Publish(_ context.Context, subject string, data []byte) error {
+	p.published = append(p.published, publishedMessage{subject, data})
+	return nil
+}
+
+func TestPublishBridge(t *testing.T) {
+	t.Parallel()
+
+	publisher := &fakePublisher{}
+	client := twitch.NewClient()
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher, Format: twitch.PublishEnvelope})
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+
+	client.SetPublishBridge(&twitch.PublishBridge{
+		Publisher: publisher,
+		Subject:   func(subscription twitch.PayloadSubscription) string { return "raids" },
+	})
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 7}))
+	assert.NoError(t, client.FlushPublished(context.Background()))
+
+	if assert.Len(t, publisher.published, 2) {
+		var envelope twitch.PublishedNotification
+		assert.NoError(t, json.Unmarshal(publisher.published[0].data, &envelope))
+		assert.Equal(t, "twitch.eventsub.channel.raid", publisher.published[0].subject)
+		assert.Equal(t, twitch.SubChannelRaid, envelope.Subscription.Type)
+		assert.Contains(t, string(envelope.Event), `"viewers":42`)
+
+		assert.Equal(t, "raids", publisher.published[1].subject)
+		assert.Contains(t, string(publisher.published[1].data), `"viewers":7`)
+	}
+}
+
+func TestPublishBridgeMsgpack(t *testing.T) {
+	t.Parallel()
+
+	publisher := &fakePublisher{}
+	client := twitch.NewClient()
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher, Format: twitch.PublishMsgpack})
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+	assert.NoError(t, client.FlushPublished(context.Background()))
+
+	if assert.Len(t, publisher.published, 1) {
+		var published twitch.PublishedNotification
+		assert.NoError(t, twitch.UnmarshalMsgpack(publisher.published[0].data, &published))
+		assert.Equal(t, twitch.SubChannelRaid, published.Subscription.Type)
+		assert.Contains(t, string(published.Event), `"viewers":42`)
+	}
+}
+
+func TestPublishBridgeEnrich(t *testing.T) {
+	t.Parallel()
+
+	publisher := &fakePublisher{}
+	client := twitch.NewClient()
+	var errs []error
+	client.OnError(func(err error) { errs = append(errs, err) })
+	client.SetPublishBridge(&twitch.PublishBridge{
+		Publisher: publisher,
+		Format:    twitch.PublishEnvelope,
+		Enrich: func(ctx context.Context, notification twitch.PublishedNotification) (map[string]any, error) {
+			assert.Equal(t, twitch.SubChannelRaid, notification.Subscription.Type)
+			return map[string]any{"game": map[string]string{"name": "Just Chatting"}}, assert.AnError
+		},
+	})
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+	assert.NoError(t, client.FlushPublished(context.Background()))
+
+	if assert.Len(t, publisher.published, 1) {
+		var envelope twitch.PublishedNotification
+		assert.NoError(t, json.Unmarshal(publisher.published[0].data, &envelope))
+		assert.JSONEq(t, `{"name":"Just Chatting"}`, string(envelope.Enrichment["game"]))
+	}
+	if assert.Len(t, errs, 1) {
+		assert.ErrorIs(t, errs[0], assert.AnError)
+	}
+}
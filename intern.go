@@ -0,0 +1,166 @@
+package twitch
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const defaultInternCapacity = 4096
+
+// SetStringInterning deduplicates the user IDs, logins, and names of decoded events and
+// the subscription metadata, so long-running clients seeing the same channels and users
+// keep one copy of each string instead of one per event. It is disabled by default.
+func (c *Client) SetStringInterning(enabled bool) {
+	if !enabled {
+		c.interner = nil
+	} else if c.interner == nil {
+		c.interner = newInterner(defaultInternCapacity)
+	}
+}
+
+// interner holds at most capacity strings and starts over when full, so unbounded
+// input like unique chatters cannot grow it forever.
+type interner struct {
+	mu       sync.Mutex
+	strings  map[string]string
+	capacity int
+}
+
+func newInterner(capacity int) *interner {
+	return &interner{strings: make(map[string]string), capacity: capacity}
+}
+
+func (in *interner) intern(s string) string {
+	if s == "" {
+		return s
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if interned, ok := in.strings[s]; ok {
+		return interned
+	}
+	if len(in.strings) >= in.capacity {
+		in.strings = make(map[string]string, in.capacity)
+	}
+	in.strings[s] = s
+	return s
+}
+
+func (in *interner) internContext(payloadContext *PayloadContext) {
+	metadata := &payloadContext.Metadata
+	metadata.MessageType = in.intern(metadata.MessageType)
+	metadata.SubscriptionType = EventSubscription(in.intern(string(metadata.SubscriptionType)))
+	metadata.SubscriptionVersion = in.intern(metadata.SubscriptionVersion)
+
+	subscription := &payloadContext.Subscription
+	subscription.ID = in.intern(subscription.ID)
+	subscription.Status = in.intern(subscription.Status)
+	subscription.Type = EventSubscription(in.intern(string(subscription.Type)))
+	subscription.Version = in.intern(subscription.Version)
+}
+
+func (in *interner) internEvent(event any) {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return
+	}
+	value = value.Elem()
+
+	if value.Kind() == reflect.Slice {
+		if plan := internPlanFor(value.Type().Elem()); plan != nil {
+			for i := 0; i < value.Len(); i++ {
+				plan.apply(in, value.Index(i))
+			}
+		}
+		return
+	}
+	if plan := internPlanFor(value.Type()); plan != nil {
+		plan.apply(in, value)
+	}
+}
+
+// internPlan lists the fields of a struct to intern, found once per type.
+type internPlan struct {
+	strings []int
+	structs []nestedInternPlan
+	slices  []nestedInternPlan
+}
+
+type nestedInternPlan struct {
+	index int
+	plan  *internPlan
+}
+
+var internPlans sync.Map
+
+func internPlanFor(t reflect.Type) *internPlan {
+	if cached, ok := internPlans.Load(t); ok {
+		return cached.(*internPlan)
+	}
+	plan := buildInternPlan(t, map[reflect.Type]bool{})
+	internPlans.Store(t, plan)
+	return plan
+}
+
+func buildInternPlan(t reflect.Type, visiting map[reflect.Type]bool) *internPlan {
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	plan := &internPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String:
+			if isInternedField(field) {
+				plan.strings = append(plan.strings, i)
+			}
+		case reflect.Struct:
+			if nested := buildInternPlan(field.Type, visiting); nested != nil {
+				plan.structs = append(plan.structs, nestedInternPlan{index: i, plan: nested})
+			}
+		case reflect.Slice:
+			if nested := buildInternPlan(field.Type.Elem(), visiting); nested != nil {
+				plan.slices = append(plan.slices, nestedInternPlan{index: i, plan: nested})
+			}
+		}
+	}
+
+	if len(plan.strings) == 0 && len(plan.structs) == 0 && len(plan.slices) == 0 {
+		return nil
+	}
+	return plan
+}
+
+func isInternedField(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return strings.HasSuffix(name, "user_id") ||
+		strings.HasSuffix(name, "user_login") ||
+		strings.HasSuffix(name, "user_name") ||
+		name == "broadcaster_id"
+}
+
+func (p *internPlan) apply(in *interner, value reflect.Value) {
+	for _, i := range p.strings {
+		field := value.Field(i)
+		field.SetString(in.intern(field.String()))
+	}
+	for _, nested := range p.structs {
+		nested.plan.apply(in, value.Field(nested.index))
+	}
+	for _, nested := range p.slices {
+		slice := value.Field(nested.index)
+		for i := 0; i < slice.Len(); i++ {
+			nested.plan.apply(in, slice.Index(i))
+		}
+	}
+}
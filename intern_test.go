@@ -0,0 +1,66 @@
+package twitch
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestStringInterning(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient()
+	client.SetSynchronousDispatch(true)
+	client.SetStringInterning(true)
+
+	var events []EventChannelRaid
+	var contexts []PayloadContext
+	client.OnEventChannelRaid(func(event EventChannelRaid, payloadContext PayloadContext) {
+		events = append(events, event)
+		contexts = append(contexts, payloadContext)
+	})
+
+	for i := 0; i < 2; i++ {
+		err := client.InjectNotification(SubChannelRaid, EventChannelRaid{
+			FromBroadcasterUserId: "1337",
+			ToBroadcasterUserId:   "42",
+			Viewers:               i,
+		})
+		assert.NoError(t, err)
+	}
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, stringData(events[0].FromBroadcasterUserId), stringData(events[1].FromBroadcasterUserId))
+		assert.Equal(t, stringData(events[0].ToBroadcasterUserId), stringData(events[1].ToBroadcasterUserId))
+		assert.Equal(t, stringData(string(contexts[0].Subscription.Type)), stringData(string(contexts[1].Subscription.Type)))
+	}
+}
+
+func TestInternerCapacity(t *testing.T) {
+	t.Parallel()
+
+	in := newInterner(2)
+	in.intern("a")
+	in.intern("b")
+	in.intern("c")
+	assert.Len(t, in.strings, 1, "a full interner starts over")
+	assert.Equal(t, "", in.intern(""))
+}
+
+func TestInternEventSlice(t *testing.T) {
+	t.Parallel()
+
+	in := newInterner(defaultInternCapacity)
+	userID := string([]byte("1337"))
+	in.intern(userID)
+
+	grants := EventDropEntitlementGrantBatch{{Data: DropEntitlement{User: User{UserID: string([]byte("1337"))}}}}
+	in.internEvent(&grants)
+	assert.Equal(t, stringData(userID), stringData(grants[0].Data.UserID))
+}
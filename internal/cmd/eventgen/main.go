@@ -0,0 +1,472 @@
+// Command eventgen generates the event plumbing of the twitch package from its source,
+// including the files specgen generates: dispatch_gen.go holds the per-type dispatch,
+// listener and constructor switches, and decode_gen.go holds reflection-free decoders
+// for the event types on the hot path.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = "// Code generated by eventgen. DO NOT EDIT.\n\npackage twitch\n\n"
+
+var defaultDecoders = strings.Join([]string{
+	"EventChannelChatMessage",
+	"EventChannelChatNotification",
+	"EventChannelCheer",
+	"EventChannelChannelPointsCustomRewardRedemptionAdd",
+	"EventChannelChannelPointsAutomaticRewardRedemptionAdd",
+}, ",")
+
+type subscription struct {
+	name      string
+	value     string
+	eventType ast.Expr
+}
+
+type pkg struct {
+	types         map[string]*ast.TypeSpec
+	subscriptions []subscription
+	callbacks     map[string]string
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the twitch package")
+	decoders := flag.String("decoders", defaultDecoders, "comma separated event types to generate decoders for")
+	flag.Parse()
+
+	p, err := load(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = write(filepath.Join(*dir, "dispatch_gen.go"), p.dispatch())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	decode, err := p.decoders(strings.Split(*decoders, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = write(filepath.Join(*dir, "decode_gen.go"), decode)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func write(path string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("could not format %s: %w\n%s", path, err, src)
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+func load(dir string) (*pkg, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != "dispatch_gen.go" && info.Name() != "decode_gen.go"
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	twitch, ok := pkgs["twitch"]
+	if !ok {
+		return nil, fmt.Errorf("no twitch package in %s", dir)
+	}
+
+	p := &pkg{
+		types:     make(map[string]*ast.TypeSpec),
+		callbacks: make(map[string]string),
+	}
+	values := make(map[string]string)
+	var metadata *ast.CompositeLit
+
+	names := make([]string, 0, len(twitch.Files))
+	for name := range twitch.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, decl := range twitch.Files[name].Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					p.types[spec.Name.Name] = spec
+				case *ast.ValueSpec:
+					for i, ident := range spec.Names {
+						if i >= len(spec.Values) {
+							continue
+						}
+						switch value := spec.Values[i].(type) {
+						case *ast.BasicLit:
+							if value.Kind == token.STRING {
+								values[ident.Name], _ = strconv.Unquote(value.Value)
+							}
+						case *ast.CompositeLit:
+							if ident.Name == "subMetadata" {
+								metadata = value
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if metadata == nil {
+		return nil, fmt.Errorf("subMetadata not found")
+	}
+	for _, elt := range metadata.Elts {
+		kv := elt.(*ast.KeyValueExpr)
+		name := kv.Key.(*ast.Ident).Name
+		sub := subscription{name: name, value: values[name]}
+		for _, field := range kv.Value.(*ast.CompositeLit).Elts {
+			field := field.(*ast.KeyValueExpr)
+			if field.Key.(*ast.Ident).Name != "EventGen" {
+				continue
+			}
+			if call, ok := field.Value.(*ast.CallExpr); ok {
+				if index, ok := call.Fun.(*ast.IndexExpr); ok {
+					sub.eventType = index.Index
+				}
+			}
+		}
+		if sub.eventType != nil {
+			p.subscriptions = append(p.subscriptions, sub)
+		}
+	}
+
+	callbacks, ok := p.types["eventCallbacks"]
+	if !ok {
+		return nil, fmt.Errorf("eventCallbacks not found")
+	}
+	for _, field := range callbacks.Type.(*ast.StructType).Fields.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 || !strings.HasPrefix(field.Names[0].Name, "onEvent") || len(fn.Params.List) != 2 {
+			continue
+		}
+		p.callbacks[types.ExprString(fn.Params.List[0].Type)] = field.Names[0].Name
+	}
+	return p, nil
+}
+
+func (p *pkg) dispatch() []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+
+	b.WriteString("// dispatchEvent calls the typed callback for a decoded event. known is false if the\n")
+	b.WriteString("// event is not of a type the client has callbacks for.\n")
+	b.WriteString("func (c *Client) dispatchEvent(event any, payloadContext PayloadContext) (dispatched bool, known bool) {\n")
+	b.WriteString("switch event := event.(type) {\n")
+	for _, sub := range p.subscriptions {
+		eventType := types.ExprString(sub.eventType)
+		callback, ok := p.callbacks[eventType]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "case *%s:\nreturn callFunc(c, c.%s, *event, payloadContext), true\n", eventType, callback)
+	}
+	b.WriteString("}\nreturn false, false\n}\n\n")
+
+	b.WriteString("// hasTypedListener reports whether the typed callback for the subscription type is\n")
+	b.WriteString("// registered. known is false for subscription types without a typed callback.\n")
+	b.WriteString("func (c *Client) hasTypedListener(subscriptionType EventSubscription) (registered bool, known bool) {\n")
+	b.WriteString("switch subscriptionType {\n")
+	for _, sub := range p.subscriptions {
+		callback, ok := p.callbacks[types.ExprString(sub.eventType)]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "case %q:\nreturn c.%s != nil, true\n", sub.value, callback)
+	}
+	b.WriteString("}\nreturn false, false\n}\n\n")
+
+	b.WriteString("// newEventFor allocates the event struct for the subscription type.\n")
+	b.WriteString("func newEventFor(subscriptionType EventSubscription) any {\n")
+	b.WriteString("switch subscriptionType {\n")
+	for _, sub := range p.subscriptions {
+		fmt.Fprintf(&b, "case %q:\nreturn &%s{}\n", sub.value, types.ExprString(sub.eventType))
+	}
+	b.WriteString("}\nreturn nil\n}\n")
+	return b.Bytes()
+}
+
+type decoderGen struct {
+	p       *pkg
+	b       bytes.Buffer
+	pending []string
+	done    map[string]bool
+	vars    int
+}
+
+func (p *pkg) decoders(roots []string) ([]byte, error) {
+	g := &decoderGen{p: p, done: make(map[string]bool)}
+	g.b.WriteString(header)
+	g.b.WriteString("import (\n\"encoding/json\"\n\n\"github.com/isabelcoolaf/go-twitch-eventsub/internal/jsonscan\"\n)\n\n")
+	g.b.WriteString("var _ = json.Unmarshal\n\n")
+
+	g.b.WriteString("// decodeGenerated decodes data into event without reflection if a decoder was\n")
+	g.b.WriteString("// generated for its type.\n")
+	g.b.WriteString("func decodeGenerated(data []byte, event any) (bool, error) {\n")
+	g.b.WriteString("switch event := event.(type) {\n")
+	for _, root := range roots {
+		root = strings.TrimSpace(root)
+		if _, ok := p.types[root]; !ok {
+			return nil, fmt.Errorf("unknown type %s", root)
+		}
+		fmt.Fprintf(&g.b, "case *%s:\nd := jsonscan.New(data)\nif !d.Null() {\nif err := %s(&d, event); err != nil {\nreturn true, err\n}\n}\nreturn true, d.End()\n", root, g.decoderFor(root))
+	}
+	g.b.WriteString("}\nreturn false, nil\n}\n")
+
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		if err := g.structDecoder(name); err != nil {
+			return nil, err
+		}
+	}
+	return g.b.Bytes(), nil
+}
+
+// resolve follows type definitions like `type A B` to the type declaring the struct.
+func (g *decoderGen) resolve(name string) (string, *ast.StructType) {
+	for {
+		spec, ok := g.p.types[name]
+		if !ok {
+			return name, nil
+		}
+		switch t := spec.Type.(type) {
+		case *ast.StructType:
+			return name, t
+		case *ast.Ident:
+			name = t.Name
+		default:
+			return name, nil
+		}
+	}
+}
+
+func (g *decoderGen) decoderFor(name string) string {
+	resolved, _ := g.resolve(name)
+	if !g.done[resolved] {
+		g.done[resolved] = true
+		g.pending = append(g.pending, resolved)
+	}
+	return "decode" + resolved
+}
+
+type jsonField struct {
+	key   string
+	path  string
+	expr  ast.Expr
+	depth int
+}
+
+func (g *decoderGen) fields(st *ast.StructType, prefix string, depth int, into map[string][]jsonField) error {
+	for _, field := range st.Fields.List {
+		key, tagged, skip := jsonKey(field)
+		if skip {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("unsupported embedded field %s", types.ExprString(field.Type))
+			}
+			_, embedded := g.resolve(ident.Name)
+			if embedded != nil && !tagged {
+				err := g.fields(embedded, prefix+ident.Name+".", depth+1, into)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if !tagged {
+				key = ident.Name
+			}
+			into[key] = append(into[key], jsonField{key: key, path: prefix + ident.Name, expr: field.Type, depth: depth})
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			fieldKey := key
+			if !tagged {
+				fieldKey = name.Name
+			}
+			into[fieldKey] = append(into[fieldKey], jsonField{key: fieldKey, path: prefix + name.Name, expr: field.Type, depth: depth})
+		}
+	}
+	return nil
+}
+
+func jsonKey(field *ast.Field) (key string, tagged bool, skip bool) {
+	if field.Tag == nil {
+		return "", false, false
+	}
+	tag, _ := strconv.Unquote(field.Tag.Value)
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	if name == "-" {
+		return "", false, true
+	}
+	return name, name != "", false
+}
+
+func (g *decoderGen) structDecoder(name string) error {
+	_, st := g.resolve(name)
+	if st == nil {
+		return fmt.Errorf("%s is not a struct", name)
+	}
+
+	candidates := make(map[string][]jsonField)
+	err := g.fields(st, "", 0, candidates)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	// Like encoding/json, the shallowest field wins and ambiguous fields are ignored.
+	var fields []jsonField
+	for _, list := range candidates {
+		sort.Slice(list, func(i, j int) bool { return list[i].depth < list[j].depth })
+		if len(list) > 1 && list[0].depth == list[1].depth {
+			continue
+		}
+		fields = append(fields, list[0])
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].key < fields[j].key })
+
+	fmt.Fprintf(&g.b, "\nfunc decode%s(d *jsonscan.Decoder, v *%s) error {\n", name, name)
+	g.b.WriteString("if err := d.ObjectStart(); err != nil {\nreturn err\n}\n")
+	g.b.WriteString("for {\nkey, more, err := d.NextKey()\nif err != nil {\nreturn err\n}\nif !more {\nreturn nil\n}\n\n")
+	g.b.WriteString("switch string(key) {\n")
+	for _, field := range fields {
+		fmt.Fprintf(&g.b, "case %q:\n", field.key)
+		g.value("v."+field.path, field.expr)
+	}
+	g.b.WriteString("default:\nif err := d.Skip(); err != nil {\nreturn err\n}\n}\n}\n}\n")
+	return nil
+}
+
+func (g *decoderGen) newVar() string {
+	g.vars++
+	return fmt.Sprintf("x%d", g.vars)
+}
+
+// value writes statements decoding the next value into target, returning any error.
+func (g *decoderGen) value(target string, expr ast.Expr) {
+	underlying := expr
+	if ident, ok := expr.(*ast.Ident); ok {
+		if spec, ok := g.p.types[ident.Name]; ok {
+			underlying = spec.Type
+		}
+	}
+
+	switch underlying.(type) {
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.InterfaceType:
+		fmt.Fprintf(&g.b, "if d.Null() {\n%s = nil\n} else {\n", target)
+	default:
+		g.b.WriteString("if !d.Null() {\n")
+	}
+	g.decode(target, expr)
+	g.b.WriteString("}\n")
+}
+
+func (g *decoderGen) decode(target string, expr ast.Expr) {
+	typeName := types.ExprString(expr)
+	scanners := map[string]string{
+		"string":    "String",
+		"int":       "Int",
+		"int64":     "Int64",
+		"float64":   "Float64",
+		"bool":      "Bool",
+		"time.Time": "Time",
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		if method, ok := scanners[typeName]; ok {
+			x := g.newVar()
+			fmt.Fprintf(&g.b, "%s, err := d.%s()\nif err != nil {\nreturn err\n}\n%s = %s\n", x, method, target, x)
+			return
+		}
+
+		if ident, ok := t.(*ast.Ident); ok {
+			// Named scalars are looked up before resolving, which follows them down to
+			// the builtin type.
+			if spec, ok := g.p.types[ident.Name]; ok {
+				if underlying, ok := spec.Type.(*ast.Ident); ok {
+					if method, ok := scanners[underlying.Name]; ok {
+						x := g.newVar()
+						fmt.Fprintf(&g.b, "%s, err := d.%s()\nif err != nil {\nreturn err\n}\n%s = %s(%s)\n", x, method, target, ident.Name, x)
+						return
+					}
+				}
+			}
+
+			resolved, st := g.resolve(ident.Name)
+			if st != nil {
+				decoder := g.decoderFor(resolved)
+				if resolved == ident.Name {
+					fmt.Fprintf(&g.b, "if err := %s(d, &%s); err != nil {\nreturn err\n}\n", decoder, target)
+				} else {
+					fmt.Fprintf(&g.b, "if err := %s(d, (*%s)(&%s)); err != nil {\nreturn err\n}\n", decoder, resolved, target)
+				}
+				return
+			}
+			if spec, ok := g.p.types[resolved]; ok {
+				if slice, ok := spec.Type.(*ast.ArrayType); ok && slice.Len == nil {
+					g.decode(target, slice)
+					return
+				}
+			}
+		}
+	case *ast.StarExpr:
+		x := g.newVar()
+		fmt.Fprintf(&g.b, "%s := new(%s)\n", x, types.ExprString(t.X))
+		g.decode("(*"+x+")", t.X)
+		fmt.Fprintf(&g.b, "%s = %s\n", target, x)
+		return
+	case *ast.ArrayType:
+		if t.Len == nil {
+			slice, elem := g.newVar(), g.newVar()
+			elemType := types.ExprString(t.Elt)
+			fmt.Fprintf(&g.b, "%s := %s[:0]\n", slice, target)
+			g.b.WriteString("if err := d.ArrayStart(); err != nil {\nreturn err\n}\n")
+			g.b.WriteString("for {\nmore, err := d.NextElem()\nif err != nil {\nreturn err\n}\nif !more {\nbreak\n}\n")
+			fmt.Fprintf(&g.b, "var %s %s\n", elem, elemType)
+			g.value(elem, t.Elt)
+			fmt.Fprintf(&g.b, "%s = append(%s, %s)\n}\n", slice, slice, elem)
+			fmt.Fprintf(&g.b, "if %s == nil {\n%s = %s{}\n}\n%s = %s\n", slice, slice, typeName, target, slice)
+			return
+		}
+	}
+
+	// Anything else goes through encoding/json.
+	x := g.newVar()
+	fmt.Fprintf(&g.b, "%s, err := d.Raw()\nif err != nil {\nreturn err\n}\n", x)
+	fmt.Fprintf(&g.b, "if err := json.Unmarshal(%s, &%s); err != nil {\nreturn err\n}\n", x, target)
+}
@@ -0,0 +1,21 @@
+// Command protogen writes events.proto, the protobuf definitions of the event structs
+// of the twitch package. It runs after specgen since it reads the generated structs.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+func main() {
+	out := flag.String("out", "events.proto", "file to write the definitions to")
+	flag.Parse()
+
+	err := os.WriteFile(*out, twitch.EventProtoDefinitions(), 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,218 @@
+// Command specgen generates the subscription types, their versions, and the typed
+// callbacks of the twitch package from eventsub.json, a description of the Twitch
+// EventSub reference. Event structs described in its types are generated too, the rest
+// are written by hand in events.go.
+//
+// It runs before eventgen, which generates the dispatch and decoders from its output.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const header = "// Code generated by specgen from eventsub.json. DO NOT EDIT.\n\npackage twitch\n\n"
+
+type spec struct {
+	// Subscriptions are grouped like the declarations of their types.
+	Subscriptions [][]subscription `json:"subscriptions"`
+	Types         []structType     `json:"types"`
+}
+
+type subscription struct {
+	// Name is the name of the subscription type without the Sub prefix, which also
+	// names its callback.
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	Event   string `json:"event"`
+}
+
+type structType struct {
+	Name   string   `json:"name"`
+	Doc    string   `json:"doc"`
+	Embed  []string `json:"embed"`
+	Fields []field  `json:"fields"`
+}
+
+type field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	JSON string `json:"json"`
+	Doc  string `json:"doc"`
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the twitch package")
+	in := flag.String("spec", "eventsub.json", "description of the EventSub reference, relative to dir")
+	flag.Parse()
+
+	s, err := load(filepath.Join(*dir, *in))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"subscriptions_gen.go": s.subscriptions(),
+		"callbacks_gen.go":     s.callbacks(),
+		"events_gen.go":        s.events(),
+	}
+	for name, src := range files {
+		err = write(filepath.Join(*dir, name), src)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func load(path string) (*spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s spec
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	return &s, s.validate()
+}
+
+func (s *spec) validate() error {
+	names := make(map[string]bool)
+	types := make(map[string]bool)
+	for _, group := range s.Subscriptions {
+		for _, sub := range group {
+			if sub.Name == "" || sub.Type == "" || sub.Version == "" || sub.Event == "" {
+				return fmt.Errorf("subscription %q needs a name, type, version, and event", sub.Type)
+			}
+			if names[sub.Name] || types[sub.Type] {
+				return fmt.Errorf("duplicate subscription %s", sub.Type)
+			}
+			names[sub.Name] = true
+			types[sub.Type] = true
+		}
+	}
+
+	structs := make(map[string]bool)
+	for _, t := range s.Types {
+		if structs[t.Name] {
+			return fmt.Errorf("duplicate type %s", t.Name)
+		}
+		structs[t.Name] = true
+	}
+	return nil
+}
+
+func write(path string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("could not format %s: %w\n%s", path, err, src)
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+func (s *spec) subscriptions() []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+
+	b.WriteString("var (\n")
+	for i, group := range s.Subscriptions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, sub := range group {
+			fmt.Fprintf(&b, "Sub%s EventSubscription = %q\n", sub.Name, sub.Type)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("var subMetadata = map[EventSubscription]subscriptionMetadata{\n")
+	for _, group := range s.Subscriptions {
+		for _, sub := range group {
+			fmt.Fprintf(&b, "Sub%s: {\nVersion: %q,\nEventGen: zeroPtrGen[%s](),\n},\n", sub.Name, sub.Version, sub.Event)
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func (s *spec) callbacks() []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+
+	b.WriteString("// eventCallbacks holds the typed event callbacks, embedded in Client.\n")
+	b.WriteString("type eventCallbacks struct {\n")
+	for _, group := range s.Subscriptions {
+		for _, sub := range group {
+			fmt.Fprintf(&b, "onEvent%s func(event %s, payloadContext PayloadContext)\n", sub.Name, sub.Event)
+		}
+	}
+	b.WriteString("}\n")
+
+	for _, group := range s.Subscriptions {
+		for _, sub := range group {
+			fmt.Fprintf(&b, "\nfunc (c *Client) OnEvent%s(callback func(event %s, payloadContext PayloadContext)) {\n", sub.Name, sub.Event)
+			fmt.Fprintf(&b, "c.onEvent%s = callback\n}\n", sub.Name)
+		}
+	}
+	return b.Bytes()
+}
+
+func (s *spec) events() []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+
+	var imports bool
+	for _, t := range s.Types {
+		for _, f := range t.Fields {
+			imports = imports || strings.Contains(f.Type, "time.")
+		}
+	}
+	if imports {
+		b.WriteString("import \"time\"\n")
+	}
+
+	for _, t := range s.Types {
+		b.WriteString("\n")
+		comment(&b, "", t.Doc)
+		fmt.Fprintf(&b, "type %s struct {\n", t.Name)
+		for _, embed := range t.Embed {
+			fmt.Fprintf(&b, "%s\n", embed)
+		}
+		if len(t.Embed) > 0 && len(t.Fields) > 0 {
+			b.WriteString("\n")
+		}
+		for _, f := range t.Fields {
+			comment(&b, "\t", f.Doc)
+			fmt.Fprintf(&b, "%s %s `json:%q`\n", f.Name, f.Type, f.JSON)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+// comment writes doc as a comment wrapped like the comments written by hand.
+func comment(b *bytes.Buffer, indent string, doc string) {
+	const width = 88
+
+	line := indent + "//"
+	for _, word := range strings.Fields(doc) {
+		if len(line)+1+len(word) > width && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	if line != indent+"//" {
+		b.WriteString(line + "\n")
+	}
+}
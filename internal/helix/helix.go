@@ -0,0 +1,80 @@
+// Package helix sends the requests of the packages of this module to the Twitch API.
+package helix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultURL is the Twitch API.
+const DefaultURL = "https://api.twitch.tv/helix"
+
+// Client sends requests authorized with an access token of an application.
+type Client struct {
+	// URL defaults to DefaultURL.
+	URL         string
+	ClientID    string
+	AccessToken string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// StatusError is a response of the API with a status other than 2xx.
+type StatusError struct {
+	Response *http.Response
+	Body     []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Response.Status, e.Body)
+}
+
+// Do sends a request to the path of the API, which includes the query, with body
+// encoded as JSON unless nil, and decodes the response into v unless nil. Responses with
+// a status other than 2xx return a *StatusError.
+func (c Client) Do(ctx context.Context, method, path string, body any, v any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	url := c.URL
+	if url == "" {
+		url = DefaultURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Client-Id", c.ClientID)
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return &StatusError{Response: resp, Body: respBody}
+	}
+	if v == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, v)
+}
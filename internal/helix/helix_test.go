@@ -0,0 +1,48 @@
+package helix_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := twitchtest.NewHelix(t)
+	server.Handle(http.MethodPost, "/chat/messages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"message_id":"sent"}]}`)
+	})
+	client := helix.Client{URL: server.URL, ClientID: "client", AccessToken: "token"}
+
+	var response struct {
+		Data []struct {
+			MessageID string `json:"message_id"`
+		} `json:"data"`
+	}
+	require.NoError(t, client.Do(ctx, http.MethodPost, "/chat/messages", map[string]string{"message": "hi"}, &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "sent", response.Data[0].MessageID)
+	require.NoError(t, client.Do(ctx, http.MethodDelete, "/moderation/chat?message_id=1", nil, nil))
+	assert.Equal(t, []string{`POST /chat/messages {"message":"hi"}`, "DELETE /moderation/chat?message_id=1"}, server.Requests())
+
+	request, err := server.WaitForRequest(ctx, http.MethodPost, "/chat/messages")
+	require.NoError(t, err)
+	assert.Equal(t, "client", request.ClientID)
+	assert.Equal(t, "Bearer token", request.Authorization)
+
+	server.Respond(http.MethodGet, "/users", http.StatusTooManyRequests)
+	err = client.Do(ctx, http.MethodGet, "/users", nil, nil)
+	var statusError *helix.StatusError
+	require.True(t, errors.As(err, &statusError))
+	assert.Equal(t, http.StatusTooManyRequests, statusError.Response.StatusCode)
+	assert.ErrorContains(t, err, "429 Too Many Requests: ")
+}
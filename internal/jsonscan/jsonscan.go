@@ -0,0 +1,369 @@
+// Package jsonscan is a minimal JSON decoder used by the generated event decoders. It
+// reads values in place without reflection and follows encoding/json semantics for the
+// types events use, except that object keys are matched case-sensitively.
+package jsonscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type Decoder struct {
+	data  []byte
+	pos   int
+	first bool
+}
+
+func New(data []byte) Decoder {
+	return Decoder{data: data}
+}
+
+// SyntaxError reports malformed JSON at an offset of the input.
+type SyntaxError struct {
+	Offset int
+	msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jsonscan: %s at offset %d", e.msg, e.Offset)
+}
+
+func (d *Decoder) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: d.pos, msg: fmt.Sprintf(format, args...)}
+}
+
+func (d *Decoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\r', '\n':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *Decoder) peek() byte {
+	d.skipSpace()
+	if d.pos >= len(d.data) {
+		return 0
+	}
+	return d.data[d.pos]
+}
+
+func (d *Decoder) expect(c byte) error {
+	if d.peek() != c {
+		return d.errorf("expected %q", c)
+	}
+	d.pos++
+	return nil
+}
+
+func (d *Decoder) literal(word string) error {
+	if len(d.data)-d.pos < len(word) || string(d.data[d.pos:d.pos+len(word)]) != word {
+		return d.errorf("invalid literal")
+	}
+	d.pos += len(word)
+	return nil
+}
+
+// End checks only whitespace is left.
+func (d *Decoder) End() error {
+	if d.peek() != 0 || d.pos < len(d.data) {
+		return d.errorf("unexpected data after value")
+	}
+	return nil
+}
+
+// Null consumes a null literal and reports whether there was one.
+func (d *Decoder) Null() bool {
+	if d.peek() != 'n' {
+		return false
+	}
+	return d.literal("null") == nil
+}
+
+// ObjectStart consumes the start of an object. Call NextKey until it returns false.
+func (d *Decoder) ObjectStart() error {
+	if err := d.expect('{'); err != nil {
+		return err
+	}
+	d.first = true
+	return nil
+}
+
+// NextKey returns the next key of the current object, after which the caller must
+// consume its value. The key is only valid until the next call.
+func (d *Decoder) NextKey() ([]byte, bool, error) {
+	more, err := d.next('}')
+	if err != nil || !more {
+		return nil, false, err
+	}
+
+	key, _, err := d.stringBytes()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := d.expect(':'); err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// ArrayStart consumes the start of an array. Call NextElem until it returns false.
+func (d *Decoder) ArrayStart() error {
+	if err := d.expect('['); err != nil {
+		return err
+	}
+	d.first = true
+	return nil
+}
+
+// NextElem reports whether the current array has another element for the caller to
+// consume.
+func (d *Decoder) NextElem() (bool, error) {
+	return d.next(']')
+}
+
+func (d *Decoder) next(end byte) (bool, error) {
+	first := d.first
+	d.first = false
+
+	if d.peek() == end {
+		d.pos++
+		return false, nil
+	}
+	if !first {
+		if err := d.expect(','); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Object calls field for every key of an object, which must consume the value.
+func (d *Decoder) Object(field func(key []byte) error) error {
+	if err := d.ObjectStart(); err != nil {
+		return err
+	}
+	for {
+		key, more, err := d.NextKey()
+		if err != nil || !more {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+}
+
+// Array calls elem for every element of an array, which must consume the value.
+func (d *Decoder) Array(elem func() error) error {
+	if err := d.ArrayStart(); err != nil {
+		return err
+	}
+	for {
+		more, err := d.NextElem()
+		if err != nil || !more {
+			return err
+		}
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+}
+
+// stringBytes returns the contents of a string and whether it contains escapes. Without
+// escapes the bytes reference the input.
+func (d *Decoder) stringBytes() ([]byte, bool, error) {
+	if err := d.expect('"'); err != nil {
+		return nil, false, err
+	}
+
+	start := d.pos
+	escaped := false
+	for d.pos < len(d.data) {
+		switch c := d.data[d.pos]; {
+		case c == '"':
+			d.pos++
+			return d.data[start : d.pos-1], escaped, nil
+		case c == '\\':
+			escaped = true
+			d.pos += 2
+		case c < 0x20:
+			return nil, false, d.errorf("invalid character in string")
+		default:
+			d.pos++
+		}
+	}
+	return nil, false, d.errorf("unterminated string")
+}
+
+func (d *Decoder) String() (string, error) {
+	if d.peek() != '"' {
+		return "", d.typeError("string")
+	}
+
+	start := d.pos
+	s, escaped, err := d.stringBytes()
+	if err != nil {
+		return "", err
+	}
+	// Every string is allocated on its own, so a string kept by the application does
+	// not keep the whole payload alive.
+	if !escaped {
+		return string(s), nil
+	}
+
+	var unquoted string
+	err = json.Unmarshal(d.data[start:d.pos], &unquoted)
+	return unquoted, err
+}
+
+func (d *Decoder) Bool() (bool, error) {
+	switch d.peek() {
+	case 't':
+		return true, d.literal("true")
+	case 'f':
+		return false, d.literal("false")
+	}
+	return false, d.typeError("bool")
+}
+
+// number consumes a number of the JSON grammar: an optional minus, an integer without
+// leading zeros, an optional fraction, and an optional exponent.
+func (d *Decoder) number() ([]byte, error) {
+	d.skipSpace()
+	start := d.pos
+	if d.pos < len(d.data) && d.data[d.pos] == '-' {
+		d.pos++
+	}
+	switch {
+	case d.pos >= len(d.data) || !isDigit(d.data[d.pos]):
+		if d.pos == start {
+			return nil, d.typeError("number")
+		}
+		return nil, d.errorf("invalid number")
+	case d.data[d.pos] == '0':
+		d.pos++
+	default:
+		d.digits()
+	}
+	if d.pos < len(d.data) && d.data[d.pos] == '.' {
+		d.pos++
+		if d.digits() == 0 {
+			return nil, d.errorf("invalid number")
+		}
+	}
+	if d.pos < len(d.data) && (d.data[d.pos] == 'e' || d.data[d.pos] == 'E') {
+		d.pos++
+		if d.pos < len(d.data) && (d.data[d.pos] == '+' || d.data[d.pos] == '-') {
+			d.pos++
+		}
+		if d.digits() == 0 {
+			return nil, d.errorf("invalid number")
+		}
+	}
+	return d.data[start:d.pos], nil
+}
+
+// digits consumes digits and returns how many.
+func (d *Decoder) digits() int {
+	start := d.pos
+	for d.pos < len(d.data) && isDigit(d.data[d.pos]) {
+		d.pos++
+	}
+	return d.pos - start
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (d *Decoder) Int64() (int64, error) {
+	b, err := d.number()
+	if err != nil {
+		return 0, err
+	}
+
+	negative := b[0] == '-'
+	digits := b
+	if negative {
+		digits = b[1:]
+	}
+	if len(digits) == 0 || len(digits) > 18 {
+		return strconv.ParseInt(string(b), 10, 64)
+	}
+
+	var n int64
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, &json.UnmarshalTypeError{Value: "number " + string(b), Offset: int64(d.pos)}
+		}
+		n = n*10 + int64(c-'0')
+	}
+	if negative {
+		n = -n
+	}
+	return n, nil
+}
+
+func (d *Decoder) Int() (int, error) {
+	n, err := d.Int64()
+	return int(n), err
+}
+
+func (d *Decoder) Float64() (float64, error) {
+	b, err := d.number()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(b), 64)
+}
+
+func (d *Decoder) Time() (time.Time, error) {
+	s, _, err := d.stringBytes()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, string(s))
+}
+
+// Raw consumes the next value and returns it, referencing the input.
+func (d *Decoder) Raw() ([]byte, error) {
+	d.skipSpace()
+	start := d.pos
+	if err := d.Skip(); err != nil {
+		return nil, err
+	}
+	return d.data[start:d.pos], nil
+}
+
+// Skip consumes the next value.
+func (d *Decoder) Skip() error {
+	switch d.peek() {
+	case '{':
+		return d.Object(func([]byte) error { return d.Skip() })
+	case '[':
+		return d.Array(d.Skip)
+	case '"':
+		_, _, err := d.stringBytes()
+		return err
+	case 't':
+		return d.literal("true")
+	case 'f':
+		return d.literal("false")
+	case 'n':
+		return d.literal("null")
+	case 0:
+		return d.errorf("unexpected end of input")
+	}
+	_, err := d.number()
+	return err
+}
+
+func (d *Decoder) typeError(want string) error {
+	return fmt.Errorf("jsonscan: cannot decode value at offset %d into %s", d.pos, want)
+}
@@ -0,0 +1,104 @@
+package jsonscan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	dec := New([]byte(` {"s": "a\"bé", "n": -42, "f": 1.5, "b": true, "t": "2020-07-15T17:16:03.17106713Z",
+		"skip": {"x": [1, {"y": null}, {}, [], "z"]}, "arr": [1, 2, 3], "null": null} `))
+	d := &dec
+
+	var s string
+	var n, sum int
+	var f float64
+	var b, null bool
+	var ts time.Time
+	err := d.Object(func(key []byte) error {
+		var err error
+		switch string(key) {
+		case "s":
+			s, err = d.String()
+		case "n":
+			n, err = d.Int()
+		case "f":
+			f, err = d.Float64()
+		case "b":
+			b, err = d.Bool()
+		case "t":
+			ts, err = d.Time()
+		case "arr":
+			err = d.Array(func() error {
+				v, err := d.Int()
+				sum += v
+				return err
+			})
+		case "null":
+			null = d.Null()
+		default:
+			err = d.Skip()
+		}
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, d.End())
+
+	assert.Equal(t, "a\"bé", s)
+	assert.Equal(t, -42, n)
+	assert.Equal(t, 1.5, f)
+	assert.True(t, b)
+	assert.Equal(t, 2020, ts.Year())
+	assert.Equal(t, 6, sum)
+	assert.True(t, null)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	for name, input := range map[string]string{
+		"float into int":       `1.5`,
+		"string into int":      `"1"`,
+		"unterminated string":  `"abc`,
+		"missing colon":        `{"a" 1}`,
+		"trailing comma array": `[1,]`,
+		"missing comma":        `{"a": {} "b": 1}`,
+		"leading comma":        `[,1]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dec := New([]byte(input))
+			d := &dec
+			var err error
+			if input[0] == '{' || input[0] == '[' {
+				err = d.Skip()
+			} else if input[0] == '"' && name == "unterminated string" {
+				_, err = d.String()
+			} else {
+				_, err = d.Int()
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNumberGrammar(t *testing.T) {
+	for _, input := range []string{`0`, `-0`, `12`, `-3.25`, `1e9`, `1E+2`, `2.5e-3`} {
+		dec := New([]byte(input))
+		_, err := dec.Float64()
+		assert.NoError(t, err, input)
+		assert.NoError(t, dec.End(), input)
+	}
+
+	for _, input := range []string{`1-2e`, `-`, `+1`, `01`, `1.`, `.5`, `1e`, `1e+`, `--1`, `1.2.3`} {
+		dec := New([]byte(`[` + input + `]`))
+		assert.Error(t, dec.Skip(), input)
+
+		dec = New([]byte(input))
+		_, err := dec.Float64()
+		if err == nil {
+			err = dec.End()
+		}
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sqltest provides a database/sql driver recording the statements it gets, to
+// test the SQL sinks without a database driver as a dependency of the module.
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Recorder records the statements executed on its database. Queries return the rows
+// Rows returns for them.
+type Recorder struct {
+	// Rows returns the columns and rows of a query, none by default.
+	Rows func(query string, args []driver.Value) ([]string, [][]driver.Value)
+	// Err returns the error of a statement, which is recorded still. Statements
+	// succeed by default.
+	Err func(query string) error
+
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+}
+
+// Statements returns the statements executed so far, including BEGIN, COMMIT, and
+// ROLLBACK for transactions.
+func (r *Recorder) Statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.statements...)
+}
+
+// Args returns the arguments of the statements executed so far.
+func (r *Recorder) Args() [][]driver.Value {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([][]driver.Value(nil), r.args...)
+}
+
+func (r *Recorder) record(statement string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = append(r.statements, statement)
+	r.args = append(r.args, args)
+}
+
+var (
+	registerOnce sync.Once
+	recorders    sync.Map
+	nextName     int64
+)
+
+// Open returns a database recording its statements in the returned recorder.
+func Open(t testing.TB) (*sql.DB, *Recorder) {
+	registerOnce.Do(func() {
+		sql.Register("sqltest", recorderDriver{})
+	})
+
+	name := strconv.FormatInt(atomic.AddInt64(&nextName, 1), 10)
+	r := &Recorder{}
+	recorders.Store(name, r)
+	db, err := sql.Open("sqltest", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A single connection keeps the statements in order.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+		recorders.Delete(name)
+	})
+	return db, r
+}
+
+type recorderDriver struct{}
+
+func (recorderDriver) Open(name string) (driver.Conn, error) {
+	r, _ := recorders.Load(name)
+	return conn{r.(*Recorder)}, nil
+}
+
+type conn struct{ r *Recorder }
+
+func (c conn) Prepare(query string) (driver.Stmt, error) {
+	return stmt{c.r, query}, nil
+}
+
+func (conn) Close() error { return nil }
+
+func (c conn) Begin() (driver.Tx, error) {
+	c.r.record("BEGIN", nil)
+	return tx(c), nil
+}
+
+type tx struct{ r *Recorder }
+
+func (t tx) Commit() error {
+	t.r.record("COMMIT", nil)
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.r.record("ROLLBACK", nil)
+	return nil
+}
+
+type stmt struct {
+	r     *Recorder
+	query string
+}
+
+func (stmt) Close() error  { return nil }
+func (stmt) NumInput() int { return -1 }
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.record(s.query, args)
+	if s.r.Err != nil {
+		if err := s.r.Err(s.query); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.r.record(s.query, args)
+	if s.r.Rows == nil {
+		return &rows{}, nil
+	}
+	columns, values := s.r.Rows(s.query, args)
+	return &rows{columns: columns, values: values}, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
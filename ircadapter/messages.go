@@ -0,0 +1,433 @@
+// Package ircadapter maps EventSub chat messages and notifications to the messages of
+// github.com/gempir/go-twitch-irc, so bots written against its handlers can move to
+// EventSub chat without rewriting them.
+//
+// The messages have the fields of the go-twitch-irc v4 messages, including the IRC tags
+// they would have been received with, and convert to them with Convert:
+//
+//	ircadapter.OnPrivateMessage(client, func(message ircadapter.PrivateMessage) {
+//		ircMessage, err := ircadapter.Convert[twitchirc.PrivateMessage](message)
+//		if err == nil {
+//			handlePrivateMessage(ircMessage)
+//		}
+//	})
+package ircadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// MessageType is the type of an IRC message, with the values of go-twitch-irc.
+type MessageType int
+
+const (
+	PRIVMSG    MessageType = 1
+	USERNOTICE MessageType = 4
+)
+
+type User struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Color       string
+	// Badges are the versions of the badges of the user by set ID, like subscriber: 12.
+	Badges map[string]int
+}
+
+type EmotePosition struct {
+	Start int
+	// End is exclusive, both count runes of the message.
+	End int
+}
+
+type Emote struct {
+	Name      string
+	ID        string
+	Count     int
+	Positions []EmotePosition
+}
+
+type Reply struct {
+	ParentMsgID       string
+	ParentUserID      string
+	ParentUserLogin   string
+	ParentDisplayName string
+	ParentMsgBody     string
+}
+
+// PrivateMessage is a chat message, an IRC PRIVMSG. Raw is left empty since the message
+// was not received over IRC.
+type PrivateMessage struct {
+	User User
+
+	Raw     string
+	Type    MessageType
+	RawType string
+	Tags    map[string]string
+	Message string
+	Channel string
+	RoomID  string
+	ID      string
+	Time    time.Time
+	Emotes  []*Emote
+	Bits    int
+	Action  bool
+	// FirstMessage is set for the first message of a user in the channel, which EventSub
+	// sends as a user_intro message.
+	FirstMessage   bool
+	Reply          *Reply
+	CustomRewardID string
+}
+
+// UserNoticeMessage is a chat notification like a sub or raid, an IRC USERNOTICE. MsgID
+// and MsgParams are named like the msg-id and msg-param tags of IRC.
+type UserNoticeMessage struct {
+	User User
+
+	Raw       string
+	Type      MessageType
+	RawType   string
+	Tags      map[string]string
+	Message   string
+	Channel   string
+	RoomID    string
+	ID        string
+	Time      time.Time
+	Emotes    []*Emote
+	MsgID     string
+	MsgParams map[string]string
+	SystemMsg string
+}
+
+// Convert returns the message as the go-twitch-irc message T, like
+// twitchirc.PrivateMessage for a PrivateMessage. The fields are copied by name, through
+// JSON, so this package does not depend on go-twitch-irc.
+func Convert[T any](message any) (T, error) {
+	var result T
+	data, err := json.Marshal(message)
+	if err != nil {
+		return result, fmt.Errorf("could not encode %T: %w", message, err)
+	}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return result, fmt.Errorf("could not convert %T to %T: %w", message, result, err)
+	}
+	return result, nil
+}
+
+// OnPrivateMessage sets the channel.chat.message callback of the client to call
+// callback with the messages mapped to PrivateMessage.
+func OnPrivateMessage(client *twitch.Client, callback func(message PrivateMessage)) {
+	client.OnEventChannelChatMessage(func(event twitch.EventChannelChatMessage, payloadContext twitch.PayloadContext) {
+		callback(NewPrivateMessage(event, payloadContext))
+	})
+}
+
+// OnUserNoticeMessage sets the channel.chat.notification callback of the client to call
+// callback with the notifications mapped to UserNoticeMessage.
+func OnUserNoticeMessage(client *twitch.Client, callback func(message UserNoticeMessage)) {
+	client.OnEventChannelChatNotification(func(event twitch.EventChannelChatNotification, payloadContext twitch.PayloadContext) {
+		callback(NewUserNoticeMessage(event, payloadContext))
+	})
+}
+
+// NewPrivateMessage maps a chat message to a PrivateMessage, timed by its notification.
+func NewPrivateMessage(event twitch.EventChannelChatMessage, payloadContext twitch.PayloadContext) PrivateMessage {
+	sentAt := payloadContext.Metadata.MessageTimestamp
+	emotes, emotesTag := emotesOf(event.Message)
+	message := PrivateMessage{
+		User:           newUser(event.Chatter, event.Color, event.Badges),
+		Type:           PRIVMSG,
+		RawType:        "PRIVMSG",
+		Message:        event.Message.Text,
+		Channel:        event.BroadcasterUserLogin,
+		RoomID:         event.BroadcasterUserId,
+		ID:             event.MessageId,
+		Time:           sentAt,
+		Emotes:         emotes,
+		FirstMessage:   event.MessageType == "user_intro",
+		CustomRewardID: event.ChannelPointsCustomRewardId,
+	}
+	if event.Cheer != nil {
+		message.Bits = event.Cheer.Bits
+	}
+
+	tags := newTags(message.User, event.Badges, event.MessageId, event.BroadcasterUserId, sentAt, emotesTag)
+	tags["first-msg"] = boolTag(message.FirstMessage)
+	if message.Bits > 0 {
+		tags["bits"] = strconv.Itoa(message.Bits)
+	}
+	if message.CustomRewardID != "" {
+		tags["custom-reward-id"] = message.CustomRewardID
+	}
+	if event.Reply != nil {
+		message.Reply = &Reply{
+			ParentMsgID:       event.Reply.ParentMessageId,
+			ParentUserID:      event.Reply.ParentUserId,
+			ParentUserLogin:   event.Reply.ParentUserLogin,
+			ParentDisplayName: event.Reply.ParentUserName,
+			ParentMsgBody:     event.Reply.ParentMessageBody,
+		}
+		tags["reply-parent-msg-id"] = event.Reply.ParentMessageId
+		tags["reply-parent-user-id"] = event.Reply.ParentUserId
+		tags["reply-parent-user-login"] = event.Reply.ParentUserLogin
+		tags["reply-parent-display-name"] = event.Reply.ParentUserName
+		tags["reply-parent-msg-body"] = event.Reply.ParentMessageBody
+		tags["reply-thread-parent-msg-id"] = event.Reply.ThreadMessageId
+		tags["reply-thread-parent-user-login"] = event.Reply.ThreadUserLogin
+	}
+	if event.IsFromSharedChat() {
+		tags["source-room-id"] = event.SourceBroadcasterUserId
+		tags["source-id"] = event.SourceMessageId
+	}
+	message.Tags = tags
+	return message
+}
+
+// NewUserNoticeMessage maps a chat notification to a UserNoticeMessage, timed by its
+// notification. Notifications of shared chat sessions get the msg-id of the notice they
+// share, like sub for shared_chat_sub.
+func NewUserNoticeMessage(event twitch.EventChannelChatNotification, payloadContext twitch.PayloadContext) UserNoticeMessage {
+	sentAt := payloadContext.Metadata.MessageTimestamp
+	emotes, emotesTag := emotesOf(event.Message)
+	msgID, params := noticeParams(event)
+	message := UserNoticeMessage{
+		User:      newUser(event.Chatter, event.Color, event.Badges),
+		Type:      USERNOTICE,
+		RawType:   "USERNOTICE",
+		Message:   event.Message.Text,
+		Channel:   event.BroadcasterUserLogin,
+		RoomID:    event.BroadcasterUserId,
+		ID:        event.MessageId,
+		Time:      sentAt,
+		Emotes:    emotes,
+		MsgID:     msgID,
+		MsgParams: params,
+		SystemMsg: event.SystemMessage,
+	}
+
+	tags := newTags(message.User, event.Badges, event.MessageId, event.BroadcasterUserId, sentAt, emotesTag)
+	tags["login"] = event.ChatterUserLogin
+	tags["msg-id"] = msgID
+	tags["system-msg"] = event.SystemMessage
+	for name, value := range params {
+		tags[name] = value
+	}
+	if event.IsFromSharedChat() {
+		tags["source-room-id"] = event.SourceBroadcasterUserId
+		tags["source-id"] = event.SourceMessageId
+	}
+	message.Tags = tags
+	return message
+}
+
+func newUser(chatter twitch.Chatter, color string, badges twitch.ChatBadges) User {
+	user := User{
+		ID:          chatter.ChatterUserId,
+		Name:        chatter.ChatterUserLogin,
+		DisplayName: chatter.ChatterUserName,
+		Color:       color,
+		Badges:      make(map[string]int, len(badges)),
+	}
+	for _, badge := range badges {
+		version, _ := strconv.Atoi(badge.Id)
+		user.Badges[badge.SetId] = version
+	}
+	return user
+}
+
+func newTags(user User, badges twitch.ChatBadges, id, roomID string, sentAt time.Time, emotes string) map[string]string {
+	var badgeList, badgeInfo []string
+	for _, badge := range badges {
+		badgeList = append(badgeList, badge.SetId+"/"+badge.Id)
+		if badge.Info != "" {
+			badgeInfo = append(badgeInfo, badge.SetId+"/"+badge.Info)
+		}
+	}
+
+	return map[string]string{
+		"badge-info":   strings.Join(badgeInfo, ","),
+		"badges":       strings.Join(badgeList, ","),
+		"color":        user.Color,
+		"display-name": user.DisplayName,
+		"emotes":       emotes,
+		"id":           id,
+		"mod":          boolTag(badges.IsModerator()),
+		"vip":          boolTag(badges.IsVIP()),
+		"subscriber":   boolTag(badges.IsSubscriber()),
+		"room-id":      roomID,
+		"tmi-sent-ts":  strconv.FormatInt(sentAt.UnixMilli(), 10),
+		"user-id":      user.ID,
+	}
+}
+
+func boolTag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// emotesOf returns the emotes of the fragments of a message, and the emotes tag IRC
+// would have sent with it, whose positions are inclusive.
+func emotesOf(message twitch.ChatMessage) ([]*Emote, string) {
+	var emotes []*Emote
+	byID := make(map[string]*Emote)
+	var position int
+	for _, fragment := range message.Fragments {
+		length := len([]rune(fragment.Text))
+		if fragment.Emote != nil {
+			emote, ok := byID[fragment.Emote.Id]
+			if !ok {
+				emote = &Emote{Name: fragment.Text, ID: fragment.Emote.Id}
+				byID[fragment.Emote.Id] = emote
+				emotes = append(emotes, emote)
+			}
+			emote.Count++
+			emote.Positions = append(emote.Positions, EmotePosition{Start: position, End: position + length})
+		}
+		position += length
+	}
+
+	tag := make([]string, 0, len(emotes))
+	for _, emote := range emotes {
+		positions := make([]string, len(emote.Positions))
+		for i, p := range emote.Positions {
+			positions[i] = fmt.Sprintf("%d-%d", p.Start, p.End-1)
+		}
+		tag = append(tag, emote.ID+":"+strings.Join(positions, ","))
+	}
+	return emotes, strings.Join(tag, "/")
+}
+
+// noticeParams returns the msg-id of a notification and its msg-param tags.
+func noticeParams(event twitch.EventChannelChatNotification) (string, map[string]string) {
+	params := make(map[string]string)
+	noticeType := strings.TrimPrefix(event.NoticeType, "shared_chat_")
+
+	switch noticeType {
+	case "sub":
+		if sub := first(event.Sub, event.SharedChatSub); sub != nil {
+			params["msg-param-sub-plan"] = subPlan(sub.SubTier, sub.IsPrime)
+			params["msg-param-multimonth-duration"] = strconv.Itoa(sub.DurationMonths)
+		}
+		return "sub", params
+	case "resub":
+		if resub := first(event.Resub, event.SharedChatResub); resub != nil {
+			tier := resub.SubTier
+			if tier == "" {
+				tier = resub.SubPlan
+			}
+			params["msg-param-sub-plan"] = subPlan(tier, resub.IsPrime)
+			params["msg-param-cumulative-months"] = strconv.Itoa(resub.CumulativeMonths)
+			params["msg-param-streak-months"] = strconv.Itoa(resub.StreakMonths)
+			params["msg-param-multimonth-duration"] = strconv.Itoa(resub.DurationMonths)
+			params["msg-param-was-gifted"] = strconv.FormatBool(resub.IsGift)
+			if resub.IsGift && !resub.GifterIsAnonymous {
+				params["msg-param-gifter-id"] = resub.GifterUserId
+				params["msg-param-gifter-login"] = resub.GifterUserLogin
+				params["msg-param-gifter-name"] = resub.GifterUserName
+			}
+		}
+		return "resub", params
+	case "sub_gift":
+		if gift := first(event.SubGift, event.SharedChatSubGift); gift != nil {
+			params["msg-param-sub-plan"] = gift.SubTier
+			params["msg-param-gift-months"] = strconv.Itoa(gift.DurationMonths)
+			params["msg-param-sender-count"] = strconv.Itoa(gift.CumulativeTotal)
+			params["msg-param-recipient-id"] = gift.RecipientUserId
+			params["msg-param-recipient-user-name"] = gift.RecipientUserLogin
+			params["msg-param-recipient-display-name"] = gift.RecipientUserName
+			if gift.CommunityGiftId != "" {
+				params["msg-param-community-gift-id"] = gift.CommunityGiftId
+			}
+		}
+		return "subgift", params
+	case "community_sub_gift":
+		if gift := first(event.CommunitySubGift, event.SharedChatCommunitySubGift); gift != nil {
+			params["msg-param-sub-plan"] = gift.SubTier
+			params["msg-param-mass-gift-count"] = strconv.Itoa(gift.Total)
+			params["msg-param-sender-count"] = strconv.Itoa(gift.CumulativeTotal)
+			params["msg-param-community-gift-id"] = gift.Id
+		}
+		return "submysterygift", params
+	case "gift_paid_upgrade":
+		upgrade := first(event.GiftPaidUpgrade, event.SharedChatGiftPaidUpgrade)
+		if upgrade != nil && upgrade.GifterIsAnonymous {
+			return "anongiftpaidupgrade", params
+		}
+		if upgrade != nil {
+			params["msg-param-sender-name"] = upgrade.GifterUserName
+		}
+		return "giftpaidupgrade", params
+	case "prime_paid_upgrade":
+		if upgrade := first(event.PrimePaidUpgrade, event.SharedChatPrimePaidUpgrade); upgrade != nil {
+			params["msg-param-sub-plan"] = upgrade.SubTier
+		}
+		return "primepaidupgrade", params
+	case "pay_it_forward":
+		if payItForward := first(event.PayItForward, event.SharedChatPayItForward); payItForward != nil {
+			params["msg-param-prior-gifter-anonymous"] = strconv.FormatBool(payItForward.GifterIsAnonymous)
+			if !payItForward.GifterIsAnonymous {
+				params["msg-param-prior-gifter-id"] = payItForward.GifterUserId
+				params["msg-param-prior-gifter-user-name"] = payItForward.GifterUserLogin
+				params["msg-param-prior-gifter-display-name"] = payItForward.GifterUserName
+			}
+		}
+		// EventSub does not say who the sub was paid forward to, like IRC does for
+		// standardpayforward.
+		return "communitypayforward", params
+	case "raid":
+		if raid := first(event.Raid, event.SharedChatRaid); raid != nil {
+			params["msg-param-login"] = raid.UserLogin
+			params["msg-param-displayName"] = raid.UserName
+			params["msg-param-viewerCount"] = raid.ViewerCount
+			params["msg-param-profileImageURL"] = raid.ProfileImageUrl
+		}
+		return "raid", params
+	case "unraid":
+		return "unraid", params
+	case "announcement":
+		if announcement := first(event.Announcement, event.SharedChatAnnouncement); announcement != nil {
+			params["msg-param-color"] = strings.ToUpper(announcement.Color)
+		}
+		return "announcement", params
+	case "bits_badge_tier":
+		if event.BitsBadgeTier != nil {
+			params["msg-param-threshold"] = strconv.Itoa(event.BitsBadgeTier.Tier)
+		}
+		return "bitsbadgetier", params
+	case "charity_donation":
+		if donation := event.CharityDonation; donation != nil {
+			params["msg-param-charity-name"] = donation.CharityName
+			params["msg-param-donation-amount"] = strconv.Itoa(donation.Amount.Value)
+			params["msg-param-donation-currency"] = donation.Amount.Currency
+			params["msg-param-exponent"] = strconv.Itoa(donation.Amount.DecimalPlace)
+		}
+		return "charitydonation", params
+	}
+	return noticeType, params
+}
+
+// subPlan returns the sub plan as IRC sends it, where Prime subs have the Prime plan.
+func subPlan(tier string, isPrime bool) string {
+	if isPrime {
+		return "Prime"
+	}
+	return tier
+}
+
+func first[T any](values ...*T) *T {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package ircadapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/ircadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chatMessage() twitch.EventChannelChatMessage {
+	event := twitch.EventChannelChatMessage{
+		MessageId: "message-id",
+		Message: twitch.ChatMessage{
+			Text: "Hi Kappa chat Kappa",
+			Fragments: []twitch.ChatMessageFragment{
+				{Type: "text", Text: "Hi "},
+				{Type: "emote", Text: "Kappa", Emote: &twitch.ChatMessageFragmentEmote{Id: "25"}},
+				{Type: "text", Text: " chat "},
+				{Type: "emote", Text: "Kappa", Emote: &twitch.ChatMessageFragmentEmote{Id: "25"}},
+			},
+		},
+		Color:       "#00FF7F",
+		Badges:      twitch.ChatBadges{{SetId: "moderator", Id: "1"}, {SetId: "subscriber", Id: "12", Info: "16"}},
+		MessageType: "user_intro",
+		Cheer:       &twitch.ChatMessageCheer{Bits: 100},
+		Reply:       &twitch.ChatMessageReply{ParentMessageId: "parent-id", ParentUserLogin: "parent"},
+	}
+	event.BroadcasterUserId = "1971641"
+	event.BroadcasterUserLogin = "streamer"
+	event.ChatterUserId = "4145994"
+	event.ChatterUserLogin = "viewer32"
+	event.ChatterUserName = "Viewer32"
+	return event
+}
+
+func TestPrivateMessage(t *testing.T) {
+	t.Parallel()
+
+	sentAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	payloadContext := twitch.PayloadContext{Metadata: twitch.MessageMetadata{MessageTimestamp: sentAt}}
+	message := ircadapter.NewPrivateMessage(chatMessage(), payloadContext)
+
+	assert.Equal(t, ircadapter.PRIVMSG, message.Type)
+	assert.Equal(t, "Hi Kappa chat Kappa", message.Message)
+	assert.Equal(t, "streamer", message.Channel)
+	assert.Equal(t, "1971641", message.RoomID)
+	assert.Equal(t, "message-id", message.ID)
+	assert.Equal(t, sentAt, message.Time)
+	assert.Equal(t, ircadapter.User{
+		ID:          "4145994",
+		Name:        "viewer32",
+		DisplayName: "Viewer32",
+		Color:       "#00FF7F",
+		Badges:      map[string]int{"moderator": 1, "subscriber": 12},
+	}, message.User)
+	assert.Equal(t, 100, message.Bits)
+	assert.True(t, message.FirstMessage)
+	require.NotNil(t, message.Reply)
+	assert.Equal(t, "parent-id", message.Reply.ParentMsgID)
+
+	require.Len(t, message.Emotes, 1)
+	assert.Equal(t, ircadapter.Emote{
+		Name:      "Kappa",
+		ID:        "25",
+		Count:     2,
+		Positions: []ircadapter.EmotePosition{{Start: 3, End: 8}, {Start: 14, End: 19}},
+	}, *message.Emotes[0])
+
+	assert.Equal(t, "25:3-7,14-18", message.Tags["emotes"])
+	assert.Equal(t, "moderator/1,subscriber/12", message.Tags["badges"])
+	assert.Equal(t, "subscriber/16", message.Tags["badge-info"])
+	assert.Equal(t, "1", message.Tags["mod"])
+	assert.Equal(t, "1", message.Tags["first-msg"])
+	assert.Equal(t, "100", message.Tags["bits"])
+	assert.Equal(t, "1704164645000", message.Tags["tmi-sent-ts"])
+}
+
+func TestUserNoticeMessage(t *testing.T) {
+	t.Parallel()
+
+	event := twitch.EventChannelChatNotification{
+		SystemMessage: "viewer32 subscribed for 16 months!",
+		NoticeType:    "resub",
+		Resub:         &twitch.ChatNotificationResub{CumulativeMonths: 16, StreakMonths: 4, SubTier: "1000"},
+	}
+	event.ChatterUserLogin = "viewer32"
+
+	message := ircadapter.NewUserNoticeMessage(event, twitch.PayloadContext{})
+	assert.Equal(t, ircadapter.USERNOTICE, message.Type)
+	assert.Equal(t, "resub", message.MsgID)
+	assert.Equal(t, "viewer32 subscribed for 16 months!", message.SystemMsg)
+	assert.Equal(t, "16", message.MsgParams["msg-param-cumulative-months"])
+	assert.Equal(t, "1000", message.MsgParams["msg-param-sub-plan"])
+	assert.Equal(t, "resub", message.Tags["msg-id"])
+	assert.Equal(t, "4", message.Tags["msg-param-streak-months"])
+
+	event = twitch.EventChannelChatNotification{
+		NoticeType:     "shared_chat_raid",
+		SharedChatRaid: &twitch.ChatNotificationRaid{ViewerCount: "42"},
+	}
+	event.SharedChatRaid.UserLogin = "raider"
+	message = ircadapter.NewUserNoticeMessage(event, twitch.PayloadContext{})
+	assert.Equal(t, "raid", message.MsgID)
+	assert.Equal(t, "raider", message.MsgParams["msg-param-login"])
+	assert.Equal(t, "42", message.MsgParams["msg-param-viewerCount"])
+}
+
+func TestOnPrivateMessage(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	var messages []ircadapter.PrivateMessage
+	ircadapter.OnPrivateMessage(client, func(message ircadapter.PrivateMessage) {
+		messages = append(messages, message)
+	})
+
+	require.NoError(t, client.InjectNotification(twitch.SubChannelChatMessage, chatMessage()))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "message-id", messages[0].ID)
+	assert.False(t, messages[0].Time.IsZero())
+}
+
+// The go-twitch-irc messages have no JSON tags, so their fields convert by name.
+type ircUser struct {
+	ID          string
+	Name        string
+	DisplayName string
+	Color       string
+	Badges      map[string]int
+}
+
+type ircPrivateMessage struct {
+	User    ircUser
+	Type    int
+	Tags    map[string]string
+	Message string
+	Channel string
+	Time    time.Time
+	Bits    int
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	message := ircadapter.NewPrivateMessage(chatMessage(), twitch.PayloadContext{})
+	converted, err := ircadapter.Convert[ircPrivateMessage](message)
+	require.NoError(t, err)
+	assert.Equal(t, "viewer32", converted.User.Name)
+	assert.Equal(t, 12, converted.User.Badges["subscriber"])
+	assert.Equal(t, 1, converted.Type)
+	assert.Equal(t, "Hi Kappa chat Kappa", converted.Message)
+	assert.Equal(t, "streamer", converted.Channel)
+	assert.Equal(t, 100, converted.Bits)
+	assert.Equal(t, message.Tags, converted.Tags)
+}
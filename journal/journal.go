@@ -0,0 +1,451 @@
+// Package journal appends every EventSub notification to a SQLite database, a durable
+// record for audits and for replaying events. It uses database/sql, so the application
+// registers the SQLite driver it prefers, like modernc.org/sqlite or
+// github.com/mattn/go-sqlite3, and this module does not depend on one.
+//
+//	db, err := sql.Open("sqlite", "events.db")
+//	j, err := journal.Open(ctx, db, journal.Retention{MaxAge: 30 * 24 * time.Hour})
+//	client.SetPublishBridge(j.Bridge())
+//	defer j.Close(ctx)
+package journal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// Table is the table notifications are appended to.
+const Table = "eventsub_journal"
+
+const (
+	// batchSize is how many queued notifications are appended at once.
+	batchSize = 100
+	// flushInterval is the longest a queued notification waits for its batch.
+	flushInterval = time.Second
+	// bufferSize is how many notifications wait to be appended before new ones are
+	// dropped.
+	bufferSize = 10000
+)
+
+var ErrClosed = errors.New("journal: closed")
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS ` + Table + ` (
+	message_id TEXT PRIMARY KEY,
+	subscription_type TEXT NOT NULL,
+	subscription_version TEXT NOT NULL,
+	broadcaster_user_id TEXT NOT NULL,
+	message_timestamp INTEGER NOT NULL,
+	event TEXT NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS ` + Table + `_timestamp ON ` + Table + ` (message_timestamp)`,
+	`CREATE INDEX IF NOT EXISTS ` + Table + `_broadcaster ON ` + Table + ` (broadcaster_user_id, message_timestamp)`,
+}
+
+// Retention bounds how much the journal keeps. Zero values keep everything.
+type Retention struct {
+	MaxAge  time.Duration
+	MaxRows int64
+	// PruneInterval is how often the journal prunes in the background when it keeps
+	// a max age or max rows. Defaults to 1 minute.
+	PruneInterval time.Duration
+}
+
+// Entry is a journaled notification.
+type Entry struct {
+	MessageID           string
+	SubscriptionType    twitch.EventSubscription
+	SubscriptionVersion string
+	// BroadcasterUserID is the broadcaster of the subscription condition, or of the
+	// event for subscriptions without one.
+	BroadcasterUserID string
+	MessageTimestamp  time.Time
+	Event             json.RawMessage
+}
+
+// Journal appends notifications to the table. It is safe for concurrent use.
+type Journal struct {
+	db        *sql.DB
+	retention Retention
+
+	entries chan Entry
+	flushes chan chan error
+	done    chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	onError func(err error)
+}
+
+// Open creates the table and its indexes if needed, and starts appending published
+// notifications and pruning in the background.
+func Open(ctx context.Context, db *sql.DB, retention Retention) (*Journal, error) {
+	for _, statement := range schema {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, fmt.Errorf("could not create journal table: %w", err)
+		}
+	}
+
+	if retention.PruneInterval <= 0 {
+		retention.PruneInterval = time.Minute
+	}
+	j := &Journal{
+		db:        db,
+		retention: retention,
+		entries:   make(chan Entry, bufferSize),
+		flushes:   make(chan chan error),
+		done:      make(chan struct{}),
+	}
+	go j.run()
+	return j, nil
+}
+
+// OnError is called when published notifications could not be appended, which drops
+// them, or when the journal could not be pruned.
+func (j *Journal) OnError(callback func(err error)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.onError = callback
+}
+
+func (j *Journal) reportError(err error) {
+	j.mu.Lock()
+	onError := j.onError
+	j.mu.Unlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// Bridge returns a publish bridge appending every notification to the journal.
+// Notifications are queued and appended in batches of up to 100, at least every
+// second.
+func (j *Journal) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{Publisher: j, Format: twitch.PublishEnvelope}
+}
+
+// Publish queues a notification published in the twitch.PublishEnvelope format. It
+// never blocks.
+func (j *Journal) Publish(ctx context.Context, subject string, data []byte) error {
+	var published twitch.PublishedNotification
+	err := json.Unmarshal(data, &published)
+	if err != nil {
+		return fmt.Errorf("could not decode published notification: %w", err)
+	}
+	return j.PublishNotification(ctx, subject, published)
+}
+
+// PublishNotification queues a notification. It never blocks.
+func (j *Journal) PublishNotification(_ context.Context, _ string, published twitch.PublishedNotification) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrClosed
+	}
+	select {
+	case j.entries <- NewEntry(published):
+		return nil
+	default:
+		return fmt.Errorf("journal: buffer full, dropped %s", published.Metadata.MessageID)
+	}
+}
+
+// Flush appends the queued notifications.
+func (j *Journal) Flush(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case j.flushes <- result:
+	case <-j.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting notifications and waits for the queued ones to be appended.
+func (j *Journal) Close(ctx context.Context) error {
+	j.mu.Lock()
+	if !j.closed {
+		j.closed = true
+		close(j.entries)
+	}
+	j.mu.Unlock()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *Journal) run() {
+	defer close(j.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var prune <-chan time.Time
+	if j.retention.MaxAge > 0 || j.retention.MaxRows > 0 {
+		pruneTicker := time.NewTicker(j.retention.PruneInterval)
+		defer pruneTicker.Stop()
+		prune = pruneTicker.C
+	}
+
+	batch := make([]Entry, 0, batchSize)
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := j.appendBatch(batch)
+		batch = batch[:0]
+		if err != nil {
+			j.reportError(err)
+		}
+		return err
+	}
+
+	for {
+		select {
+		case entry, ok := <-j.entries:
+			if !ok {
+				write()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= batchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case now := <-prune:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_, err := j.Prune(ctx, now)
+			cancel()
+			if err != nil {
+				j.reportError(err)
+			}
+		case result := <-j.flushes:
+			// Take what was queued before the flush with the batch.
+			var err error
+			for queued := len(j.entries); queued > 0; queued-- {
+				entry, ok := <-j.entries
+				if !ok {
+					break
+				}
+				batch = append(batch, entry)
+				if len(batch) >= batchSize {
+					if batchErr := write(); err == nil {
+						err = batchErr
+					}
+				}
+			}
+			if batchErr := write(); err == nil {
+				err = batchErr
+			}
+			result <- err
+		}
+	}
+}
+
+// NewEntry returns the entry of a published notification.
+func NewEntry(published twitch.PublishedNotification) Entry {
+	return Entry{
+		MessageID:           published.Metadata.MessageID,
+		SubscriptionType:    published.Subscription.Type,
+		SubscriptionVersion: published.Subscription.Version,
+		BroadcasterUserID:   broadcasterOf(published),
+		MessageTimestamp:    published.Metadata.MessageTimestamp,
+		Event:               published.Event,
+	}
+}
+
+func broadcasterOf(published twitch.PublishedNotification) string {
+	for _, key := range []string{"broadcaster_user_id", "to_broadcaster_user_id"} {
+		if id := published.Subscription.Condition[key]; id != "" {
+			return id
+		}
+	}
+
+	var event struct {
+		BroadcasterUserID   string `json:"broadcaster_user_id"`
+		ToBroadcasterUserID string `json:"to_broadcaster_user_id"`
+	}
+	json.Unmarshal(published.Event, &event)
+	if event.BroadcasterUserID != "" {
+		return event.BroadcasterUserID
+	}
+	return event.ToBroadcasterUserID
+}
+
+// Append adds the entry. Entries with a message ID already journaled are ignored, since
+// Twitch can send notifications again.
+func (j *Journal) Append(ctx context.Context, entry Entry) error {
+	_, err := j.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO `+Table+` (message_id, subscription_type, subscription_version, broadcaster_user_id, message_timestamp, event) VALUES (?, ?, ?, ?, ?, ?)`,
+		entry.MessageID, string(entry.SubscriptionType), entry.SubscriptionVersion, entry.BroadcasterUserID, entry.MessageTimestamp.UnixNano(), string(entry.Event),
+	)
+	if err != nil {
+		return fmt.Errorf("could not append %s: %w", entry.MessageID, err)
+	}
+	return nil
+}
+
+// appendBatch adds the entries with a single statement.
+func (j *Journal) appendBatch(entries []Entry) error {
+	var b strings.Builder
+	b.WriteString(`INSERT OR IGNORE INTO ` + Table + ` (message_id, subscription_type, subscription_version, broadcaster_user_id, message_timestamp, event) VALUES `)
+	args := make([]any, 0, len(entries)*6)
+	for i, entry := range entries {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(?, ?, ?, ?, ?, ?)")
+		args = append(args, entry.MessageID, string(entry.SubscriptionType), entry.SubscriptionVersion, entry.BroadcasterUserID, entry.MessageTimestamp.UnixNano(), string(entry.Event))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := j.db.ExecContext(ctx, b.String(), args...)
+	if err != nil {
+		return fmt.Errorf("could not append %d notifications: %w", len(entries), err)
+	}
+	return nil
+}
+
+// Prune deletes the entries older than the max age at now and the oldest entries over
+// the max rows, returning how many were deleted.
+func (j *Journal) Prune(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	if j.retention.MaxAge > 0 {
+		result, err := j.db.ExecContext(ctx,
+			`DELETE FROM `+Table+` WHERE message_timestamp < ?`,
+			now.Add(-j.retention.MaxAge).UnixNano(),
+		)
+		if err != nil {
+			return deleted, fmt.Errorf("could not prune old entries: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+
+	if j.retention.MaxRows > 0 {
+		result, err := j.db.ExecContext(ctx,
+			`DELETE FROM `+Table+` WHERE message_id NOT IN (SELECT message_id FROM `+Table+` ORDER BY message_timestamp DESC LIMIT ?)`,
+			j.retention.MaxRows,
+		)
+		if err != nil {
+			return deleted, fmt.Errorf("could not prune extra entries: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// Query selects entries for Entries. Zero fields match every entry.
+type Query struct {
+	SubscriptionType  twitch.EventSubscription
+	BroadcasterUserID string
+	Since             time.Time
+	Until             time.Time
+	// Limit defaults to every matching entry.
+	Limit int
+}
+
+// Entries calls fn with the matching entries, oldest first, stopping at the first error
+// fn returns.
+func (j *Journal) Entries(ctx context.Context, query Query, fn func(entry Entry) error) error {
+	statement := `SELECT message_id, subscription_type, subscription_version, broadcaster_user_id, message_timestamp, event FROM ` + Table + ` WHERE 1 = 1`
+	var args []any
+	if query.SubscriptionType != "" {
+		statement += ` AND subscription_type = ?`
+		args = append(args, string(query.SubscriptionType))
+	}
+	if query.BroadcasterUserID != "" {
+		statement += ` AND broadcaster_user_id = ?`
+		args = append(args, query.BroadcasterUserID)
+	}
+	if !query.Since.IsZero() {
+		statement += ` AND message_timestamp >= ?`
+		args = append(args, query.Since.UnixNano())
+	}
+	if !query.Until.IsZero() {
+		statement += ` AND message_timestamp < ?`
+		args = append(args, query.Until.UnixNano())
+	}
+	statement += ` ORDER BY message_timestamp, message_id`
+	if query.Limit > 0 {
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := j.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return fmt.Errorf("could not query journal: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry Entry
+		var subscriptionType, event string
+		var timestamp int64
+		err = rows.Scan(&entry.MessageID, &subscriptionType, &entry.SubscriptionVersion, &entry.BroadcasterUserID, &timestamp, &event)
+		if err != nil {
+			return fmt.Errorf("could not read journal entry: %w", err)
+		}
+		entry.SubscriptionType = twitch.EventSubscription(subscriptionType)
+		entry.MessageTimestamp = time.Unix(0, timestamp).UTC()
+		entry.Event = json.RawMessage(event)
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Replay injects the matching entries into the client as notifications with their
+// original message ID and timestamp, oldest first, so its callbacks see them again. The
+// speed is the same as for twitch.Replay: 1 keeps the original pacing and 0 replays as
+// fast as possible. Errors from handling them are passed to OnError.
+func (j *Journal) Replay(ctx context.Context, client *twitch.Client, query Query, speed float64) error {
+	var previous time.Time
+	return j.Entries(ctx, query, func(entry Entry) error {
+		err := twitch.ReplayPublishedNotification(ctx, client, entry.published(), previous, speed)
+		previous = entry.MessageTimestamp
+		return err
+	})
+}
+
+func (e Entry) published() twitch.PublishedNotification {
+	var published twitch.PublishedNotification
+	published.Metadata = twitch.MessageMetadata{
+		MessageID:           e.MessageID,
+		MessageType:         "notification",
+		MessageTimestamp:    e.MessageTimestamp,
+		SubscriptionType:    e.SubscriptionType,
+		SubscriptionVersion: e.SubscriptionVersion,
+	}
+	published.Subscription.Type = e.SubscriptionType
+	published.Subscription.Version = e.SubscriptionVersion
+	published.Subscription.Status = "enabled"
+	published.Event = e.Event
+	return published
+}
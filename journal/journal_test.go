@@ -0,0 +1,140 @@
+package journal_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/sqltest"
+	"github.com/isabelcoolaf/go-twitch-eventsub/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openJournal(t *testing.T, retention journal.Retention) (*journal.Journal, *sqltest.Recorder) {
+	db, r := sqltest.Open(t)
+	j, err := journal.Open(context.Background(), db, retention)
+	require.NoError(t, err)
+	return j, r
+}
+
+func TestJournalAppend(t *testing.T) {
+	t.Parallel()
+
+	j, r := openJournal(t, journal.Retention{})
+	require.Len(t, r.Statements(), 3)
+	assert.Contains(t, r.Statements()[0], "CREATE TABLE IF NOT EXISTS eventsub_journal")
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(j.Bridge())
+	raid := twitch.EventChannelRaid{Viewers: 42}
+	raid.ToBroadcasterUserId = "1337"
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, raid))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, j.Flush(context.Background()))
+
+	statements := r.Statements()
+	require.Len(t, statements, 4)
+	assert.True(t, strings.HasPrefix(statements[3], "INSERT OR IGNORE INTO eventsub_journal"))
+	args := r.Args()[3]
+	require.Len(t, args, 6)
+	assert.NotEmpty(t, args[0])
+	assert.Equal(t, "channel.raid", args[1])
+	assert.Equal(t, "1", args[2])
+	assert.Equal(t, "1337", args[3])
+	assert.Contains(t, args[5], `"viewers":42`)
+}
+
+func TestJournalBatch(t *testing.T) {
+	t.Parallel()
+
+	j, r := openJournal(t, journal.Retention{})
+	client := twitch.NewClient()
+	client.SetPublishBridge(j.Bridge())
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: i}))
+	}
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, j.Close(context.Background()))
+
+	statements, args := r.Statements(), r.Args()
+	require.Len(t, statements, 4)
+	assert.Contains(t, statements[3], "(?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?)")
+	require.Len(t, args[3], 18)
+	assert.Contains(t, args[3][17], `"viewers":3`)
+
+	assert.ErrorIs(t, j.PublishNotification(context.Background(), "", twitch.PublishedNotification{}), journal.ErrClosed)
+}
+
+func TestJournalPrunesInBackground(t *testing.T) {
+	t.Parallel()
+
+	j, r := openJournal(t, journal.Retention{MaxAge: time.Hour, PruneInterval: time.Millisecond})
+	defer j.Close(context.Background())
+
+	assert.Eventually(t, func() bool {
+		statements := r.Statements()
+		return len(statements) > 3 && strings.HasPrefix(statements[3], "DELETE FROM eventsub_journal")
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestJournalPrune(t *testing.T) {
+	t.Parallel()
+
+	j, r := openJournal(t, journal.Retention{MaxAge: time.Hour, MaxRows: 1000})
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	deleted, err := j.Prune(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	statements, args := r.Statements(), r.Args()
+	require.Len(t, statements, 5)
+	assert.Equal(t, []driver.Value{now.Add(-time.Hour).UnixNano()}, args[3])
+	assert.Contains(t, statements[4], "LIMIT ?")
+	assert.Equal(t, []driver.Value{int64(1000)}, args[4])
+}
+
+func TestJournalReplay(t *testing.T) {
+	t.Parallel()
+
+	j, r := openJournal(t, journal.Retention{})
+	timestamp := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	r.Rows = func(string, []driver.Value) ([]string, [][]driver.Value) {
+		columns := []string{"message_id", "subscription_type", "subscription_version", "broadcaster_user_id", "message_timestamp", "event"}
+		return columns, [][]driver.Value{
+			{"message-id", "channel.raid", "1", "1337", timestamp.UnixNano(), `{"viewers":42}`},
+		}
+	}
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	var raids []twitch.EventChannelRaid
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, payloadContext twitch.PayloadContext) {
+		assert.Equal(t, "message-id", payloadContext.Metadata.MessageID)
+		assert.True(t, timestamp.Equal(payloadContext.Metadata.MessageTimestamp))
+		raids = append(raids, event)
+	})
+
+	since := timestamp.Add(-time.Minute)
+	require.NoError(t, j.Replay(context.Background(), client, journal.Query{SubscriptionType: twitch.SubChannelRaid, Since: since, Limit: 10}, 0))
+	require.Len(t, raids, 1)
+	assert.Equal(t, 42, raids[0].Viewers)
+
+	statements, args := r.Statements(), r.Args()
+	query := statements[len(statements)-1]
+	assert.Contains(t, query, "subscription_type = ?")
+	assert.Contains(t, query, "message_timestamp >= ?")
+	assert.Equal(t, []driver.Value{"channel.raid", since.UnixNano(), int64(10)}, args[len(args)-1])
+
+	var entries []journal.Entry
+	require.NoError(t, j.Entries(context.Background(), journal.Query{}, func(entry journal.Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	require.Len(t, entries, 1)
+	assert.Equal(t, twitch.SubChannelRaid, entries[0].SubscriptionType)
+	assert.Equal(t, timestamp, entries[0].MessageTimestamp)
+}
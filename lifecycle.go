@@ -0,0 +1,45 @@
+package twitch
+
+import "time"
+
+type LifecycleEventType string
+
+const (
+	LifecycleConnected          LifecycleEventType = "connected"
+	LifecycleWelcomeReceived    LifecycleEventType = "welcome_received"
+	LifecycleKeepAlive          LifecycleEventType = "keepalive"
+	LifecycleReconnectRequested LifecycleEventType = "reconnect_requested"
+	LifecycleReconnectCompleted LifecycleEventType = "reconnect_completed"
+	LifecycleRevoked            LifecycleEventType = "revoked"
+	LifecycleDisconnected       LifecycleEventType = "disconnected"
+)
+
+type LifecycleEvent struct {
+	Type      LifecycleEventType
+	Time      time.Time
+	Address   string
+	SessionID string
+
+	// Subscription is only set for LifecycleRevoked.
+	Subscription *PayloadSubscription
+	// Err is only set for LifecycleDisconnected when the connection did not close cleanly.
+	Err error
+}
+
+// Lifecycle callbacks are called synchronously so they are received in order.
+func (c *Client) emitLifecycle(event LifecycleEvent) {
+	if c.onLifecycle == nil {
+		return
+	}
+
+	event.Time = c.now()
+	event.Address = c.address()
+	if event.SessionID == "" {
+		event.SessionID = c.sessionID()
+	}
+	c.onLifecycle(event)
+}
+
+func (c *Client) OnLifecycle(callback func(event LifecycleEvent)) {
+	c.onLifecycle = callback
+}
@@ -0,0 +1,61 @@
+package twitch
+
+// hasListener reports whether any callback needs the decoded event for the
+// subscription type, so events nobody handles are never decoded.
+func (c *Client) hasListener(subscriptionType EventSubscription) bool {
+	if c.onEvent != nil || c.pointerHandlers[subscriptionType] != nil || c.hasBatchers() || c.hasEventListeners() {
+		return true
+	}
+
+	registered, known := c.hasTypedListener(subscriptionType)
+	return registered || !known
+}
+
+type eventListener struct {
+	id       int
+	callback func(event any, payloadContext PayloadContext)
+}
+
+// AddEventListener calls listener with every decoded event, like OnEvent, until the
+// returned function is called. Unlike the On callbacks, listeners can be added and
+// removed while the client is connected, e.g. to wait for an event from another
+// goroutine.
+func (c *Client) AddEventListener(listener func(event any, payloadContext PayloadContext)) (remove func()) {
+	c.eventListenersMu.Lock()
+	defer c.eventListenersMu.Unlock()
+	c.nextEventListener++
+	id := c.nextEventListener
+	c.eventListeners = append(c.eventListeners, eventListener{id: id, callback: listener})
+
+	return func() {
+		c.eventListenersMu.Lock()
+		defer c.eventListenersMu.Unlock()
+		for i, registered := range c.eventListeners {
+			if registered.id == id {
+				// Copy, as the read loop may be iterating a snapshot of the slice.
+				listeners := make([]eventListener, 0, len(c.eventListeners)-1)
+				listeners = append(listeners, c.eventListeners[:i]...)
+				c.eventListeners = append(listeners, c.eventListeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) hasEventListeners() bool {
+	c.eventListenersMu.RLock()
+	defer c.eventListenersMu.RUnlock()
+	return len(c.eventListeners) > 0
+}
+
+// callEventListeners calls the listeners outside of the lock, so a listener can remove
+// itself.
+func (c *Client) callEventListeners(event any, payloadContext PayloadContext) {
+	c.eventListenersMu.RLock()
+	listeners := c.eventListeners
+	c.eventListenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		callFunc(c, listener.callback, event, payloadContext)
+	}
+}
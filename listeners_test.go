@@ -0,0 +1,53 @@
+package twitch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedDispatchCoversSubscriptions(t *testing.T) {
+	client := NewClient()
+	for sub, metadata := range subMetadata {
+		if metadata.EventGen == nil {
+			continue
+		}
+		_, known := client.hasTypedListener(sub)
+		assert.True(t, known, "no typed callback for %s, run go generate", sub)
+		assert.IsType(t, metadata.EventGen(), newEventFor(sub), "wrong event type for %s, run go generate", sub)
+	}
+}
+
+func TestHasListener(t *testing.T) {
+	client := NewClient()
+	assert.False(t, client.hasListener(SubChannelRaid))
+
+	client.OnEventChannelRaid(func(event EventChannelRaid, payloadContext PayloadContext) {})
+	assert.True(t, client.hasListener(SubChannelRaid))
+	assert.False(t, client.hasListener(SubChannelFollow))
+
+	client.OnEvent(func(event any, payloadContext PayloadContext) {})
+	assert.True(t, client.hasListener(SubChannelFollow))
+}
+
+func TestAddEventListener(t *testing.T) {
+	client := NewClient()
+	client.SetSynchronousDispatch(true)
+
+	var first, second []any
+	removeFirst := client.AddEventListener(func(event any, payloadContext PayloadContext) {
+		first = append(first, event)
+	})
+	client.AddEventListener(func(event any, payloadContext PayloadContext) {
+		second = append(second, event)
+	})
+	assert.True(t, client.hasListener(SubChannelFollow))
+
+	assert.NoError(t, client.InjectNotification(SubChannelRaid, EventChannelRaid{Viewers: 1}))
+	removeFirst()
+	removeFirst()
+	assert.NoError(t, client.InjectNotification(SubChannelRaid, EventChannelRaid{Viewers: 2}))
+
+	assert.Equal(t, []any{EventChannelRaid{Viewers: 1}}, first)
+	assert.Equal(t, []any{EventChannelRaid{Viewers: 1}, EventChannelRaid{Viewers: 2}}, second)
+}
@@ -0,0 +1,13 @@
+package twitch
+
+import "time"
+
+type Metrics interface {
+	Notification(subscriptionType EventSubscription, latency time.Duration)
+	Reconnect()
+	Error()
+}
+
+func (c *Client) SetMetrics(metrics Metrics) {
+	c.metrics = metrics
+}
@@ -0,0 +1,192 @@
+package twitch
+
+// ModerateDetails is the payload of a channel.moderate action, returned by
+// EventChannelModerate.Details. It is one of the Moderate types below, picked by the
+// action of the event, so a type switch replaces probing the fields of the event.
+type ModerateDetails interface {
+	moderateDetails()
+}
+
+// ModerateBan is the payload of ban and shared_chat_ban.
+type ModerateBan struct {
+	Ban
+	SharedChat bool
+}
+
+// ModerateTimeout is the payload of timeout and shared_chat_timeout.
+type ModerateTimeout struct {
+	Timeout
+	SharedChat bool
+}
+
+// ModerateUnban is the payload of unban and shared_chat_unban.
+type ModerateUnban struct {
+	User
+	SharedChat bool
+}
+
+// ModerateUntimeout is the payload of untimeout and shared_chat_untimeout.
+type ModerateUntimeout struct {
+	User
+	SharedChat bool
+}
+
+// ModerateDelete is the payload of delete and shared_chat_delete.
+type ModerateDelete struct {
+	DeletedMessage
+	SharedChat bool
+}
+
+// ModerateRole is the payload of vip, unvip, mod, and unmod.
+type ModerateRole struct {
+	User
+	Granted bool
+}
+
+// ModerateRaid is the payload of raid.
+type ModerateRaid struct {
+	Raid
+}
+
+// ModerateUnraid is the payload of unraid.
+type ModerateUnraid struct {
+	User
+}
+
+// ModerateFollowers is the payload of followers.
+type ModerateFollowers struct {
+	Followers
+}
+
+// ModerateSlow is the payload of slow.
+type ModerateSlow struct {
+	SlowMode
+}
+
+// ModerateAutomodTerms is the payload of add_blocked_term, add_permitted_term,
+// remove_blocked_term, and remove_permitted_term.
+type ModerateAutomodTerms struct {
+	AutomodTerms
+}
+
+// ModerateUnbanRequest is the payload of approve_unban_request and deny_unban_request.
+type ModerateUnbanRequest struct {
+	UnbanRequest
+}
+
+// ModerateWarn is the payload of warn.
+type ModerateWarn struct {
+	Warning
+}
+
+// ModerateChatMode is the payload of the actions without one: clear, emoteonly,
+// emoteonlyoff, followersoff, uniquechat, uniquechatoff, slowoff, subscribers, and
+// subscribersoff.
+type ModerateChatMode struct {
+	Action ModerateAction
+}
+
+func (ModerateBan) moderateDetails()          {}
+func (ModerateTimeout) moderateDetails()      {}
+func (ModerateUnban) moderateDetails()        {}
+func (ModerateUntimeout) moderateDetails()    {}
+func (ModerateDelete) moderateDetails()       {}
+func (ModerateRole) moderateDetails()         {}
+func (ModerateRaid) moderateDetails()         {}
+func (ModerateUnraid) moderateDetails()       {}
+func (ModerateFollowers) moderateDetails()    {}
+func (ModerateSlow) moderateDetails()         {}
+func (ModerateAutomodTerms) moderateDetails() {}
+func (ModerateUnbanRequest) moderateDetails() {}
+func (ModerateWarn) moderateDetails()         {}
+func (ModerateChatMode) moderateDetails()     {}
+
+// Details returns the payload of the action as one of the Moderate types. It returns nil
+// for unknown actions and actions missing their payload.
+func (e EventChannelModerate) Details() ModerateDetails {
+	switch e.Action {
+	case ModerateActionBan:
+		if e.Ban != nil {
+			return ModerateBan{Ban: *e.Ban}
+		}
+	case ModerateActionSharedChatBan:
+		if e.SharedChatBan != nil {
+			return ModerateBan{Ban: *e.SharedChatBan, SharedChat: true}
+		}
+	case ModerateActionTimeout:
+		if e.Timeout != nil {
+			return ModerateTimeout{Timeout: *e.Timeout}
+		}
+	case ModerateActionSharedChatTimeout:
+		if e.SharedChatTimeout != nil {
+			return ModerateTimeout{Timeout: *e.SharedChatTimeout, SharedChat: true}
+		}
+	case ModerateActionUnban:
+		if e.Unban != nil {
+			return ModerateUnban{User: *e.Unban}
+		}
+	case ModerateActionSharedChatUnban:
+		if e.SharedChatUnban != nil {
+			return ModerateUnban{User: *e.SharedChatUnban, SharedChat: true}
+		}
+	case ModerateActionUntimeout:
+		if e.Untimeout != nil {
+			return ModerateUntimeout{User: *e.Untimeout}
+		}
+	case ModerateActionSharedChatUntimeout:
+		if e.SharedChatUntimeout != nil {
+			return ModerateUntimeout{User: *e.SharedChatUntimeout, SharedChat: true}
+		}
+	case ModerateActionDelete:
+		if e.Delete != nil {
+			return ModerateDelete{DeletedMessage: *e.Delete}
+		}
+	case ModerateActionSharedChatDelete:
+		if e.SharedChatDelete != nil {
+			return ModerateDelete{DeletedMessage: *e.SharedChatDelete, SharedChat: true}
+		}
+	case ModerateActionVIP, ModerateActionUnvip, ModerateActionMod, ModerateActionUnmod:
+		user := map[ModerateAction]*User{
+			ModerateActionVIP:   e.Vip,
+			ModerateActionUnvip: e.Unvip,
+			ModerateActionMod:   e.Mod,
+			ModerateActionUnmod: e.Unmod,
+		}[e.Action]
+		if user != nil {
+			return ModerateRole{User: *user, Granted: e.Action == ModerateActionVIP || e.Action == ModerateActionMod}
+		}
+	case ModerateActionRaid:
+		if e.Raid != nil {
+			return ModerateRaid{Raid: *e.Raid}
+		}
+	case ModerateActionUnraid:
+		if e.Unraid != nil {
+			return ModerateUnraid{User: *e.Unraid}
+		}
+	case ModerateActionFollowers:
+		if e.Followers != nil {
+			return ModerateFollowers{Followers: *e.Followers}
+		}
+	case ModerateActionSlow:
+		if e.Slow != nil {
+			return ModerateSlow{SlowMode: *e.Slow}
+		}
+	case ModerateActionAddBlockedTerm, ModerateActionAddPermittedTerm, ModerateActionRemoveBlockedTerm, ModerateActionRemovePermittedTerm:
+		if e.AutomodTerms != nil {
+			return ModerateAutomodTerms{AutomodTerms: *e.AutomodTerms}
+		}
+	case ModerateActionApproveUnbanRequest, ModerateActionDenyUnbanRequest:
+		if e.UnbanRequest != nil {
+			return ModerateUnbanRequest{UnbanRequest: *e.UnbanRequest}
+		}
+	case ModerateActionWarn:
+		if e.Warn != nil {
+			return ModerateWarn{Warning: *e.Warn}
+		}
+	case ModerateActionClear, ModerateActionEmoteOnly, ModerateActionEmoteOnlyOff, ModerateActionFollowersOff,
+		ModerateActionUniqueChat, ModerateActionUniqueChatOff, ModerateActionSlowOff, ModerateActionSubscribers,
+		ModerateActionSubscribersOff:
+		return ModerateChatMode{Action: e.Action}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package twitch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestModerateDetails(t *testing.T) {
+	var event EventChannelModerate
+	if err := json.Unmarshal(loadFixtures(t)[string(SubChannelModerate)], &event); err != nil {
+		t.Fatal(err)
+	}
+	warn, ok := event.Details().(ModerateWarn)
+	if !ok {
+		t.Fatalf("expected a warning got %T", event.Details())
+	}
+	if warn.UserID == "" {
+		t.Error("expected the warned user")
+	}
+
+	user := User{UserID: "1", UserLogin: "user", UserName: "User"}
+	reason := "spam"
+	testCases := []struct {
+		Name     string
+		Event    EventChannelModerate
+		Expected ModerateDetails
+	}{
+		{"ban", EventChannelModerate{Action: ModerateActionBan, Ban: &Ban{User: user}}, ModerateBan{Ban: Ban{User: user}}},
+		{"shared chat ban", EventChannelModerate{Action: ModerateActionSharedChatBan, SharedChatBan: &Ban{User: user, Reason: &reason}}, ModerateBan{Ban: Ban{User: user, Reason: &reason}, SharedChat: true}},
+		{"untimeout", EventChannelModerate{Action: ModerateActionUntimeout, Untimeout: &user}, ModerateUntimeout{User: user}},
+		{"vip", EventChannelModerate{Action: ModerateActionVIP, Vip: &user}, ModerateRole{User: user, Granted: true}},
+		{"unmod", EventChannelModerate{Action: ModerateActionUnmod, Unmod: &user}, ModerateRole{User: user}},
+		{"slow", EventChannelModerate{Action: ModerateActionSlow, Slow: &SlowMode{WaitTimeSeconds: 10}}, ModerateSlow{SlowMode: SlowMode{WaitTimeSeconds: 10}}},
+		{"emote only", EventChannelModerate{Action: ModerateActionEmoteOnly}, ModerateChatMode{Action: ModerateActionEmoteOnly}},
+		{"missing payload", EventChannelModerate{Action: ModerateActionRaid}, nil},
+		{"unknown", EventChannelModerate{Action: "unknown"}, nil},
+	}
+
+	for _, tc := range testCases {
+		details := tc.Event.Details()
+		if details != tc.Expected {
+			t.Errorf("%s: expected %v got %v", tc.Name, tc.Expected, details)
+		}
+	}
+}
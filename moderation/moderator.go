@@ -0,0 +1,248 @@
+// Package moderation bans, times out, warns, and deletes the messages of chatters with
+// the Twitch API from event handlers, like those of automod.message.hold or
+// channel.suspicious_user.message. Actions are rate limited so a raid of spam bots
+// does not exhaust the rate limit of the moderator, and every action is audited with
+// the event which triggered it.
+//
+//	moderator := moderation.New(moderation.Options{
+//		ClientID:    clientID,
+//		AccessToken: moderatorToken,
+//		ModeratorID: moderatorID,
+//		AuditLog:    log.Default(),
+//	})
+//	client.OnEventChannelSuspiciousUserMessage(func(event twitch.EventChannelSuspiciousUserMessage, payloadContext twitch.PayloadContext) {
+//		if event.IsBanEvader() {
+//			moderator.DeleteMessage(context.Background(), event.BroadcasterUserId, event.Message.MessageId, moderation.TriggeredBy(event, payloadContext))
+//		}
+//	})
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API actions are taken with.
+const DefaultHelixURL = helix.DefaultURL
+
+type Action string
+
+const (
+	ActionBan           Action = "ban"
+	ActionTimeout       Action = "timeout"
+	ActionDeleteMessage Action = "delete_message"
+	ActionWarn          Action = "warn"
+)
+
+// Trigger is the event an action was taken for.
+type Trigger struct {
+	Event   any
+	Context twitch.PayloadContext
+}
+
+func TriggeredBy(event any, payloadContext twitch.PayloadContext) Trigger {
+	return Trigger{Event: event, Context: payloadContext}
+}
+
+// AuditEntry records an action and the event which triggered it.
+type AuditEntry struct {
+	Action        Action
+	BroadcasterID string
+	// UserID is empty when deleting a message, and MessageID is only set then.
+	UserID    string
+	MessageID string
+	// Duration is only set for timeouts.
+	Duration time.Duration
+	Reason   string
+	Trigger  Trigger
+	At       time.Time
+	// Err is set when the action failed.
+	Err error
+}
+
+func (e AuditEntry) String() string {
+	target := "user=" + e.UserID
+	if e.Action == ActionDeleteMessage {
+		target = "message=" + e.MessageID
+	}
+	s := fmt.Sprintf("moderation: %s broadcaster=%s %s", e.Action, e.BroadcasterID, target)
+	if e.Duration > 0 {
+		s += fmt.Sprintf(" duration=%s", e.Duration)
+	}
+	if e.Reason != "" {
+		s += fmt.Sprintf(" reason=%q", e.Reason)
+	}
+	s += fmt.Sprintf(" trigger=%s trigger_message=%s", e.Trigger.Context.Subscription.Type, e.Trigger.Context.Metadata.MessageID)
+	if e.Err != nil {
+		s += fmt.Sprintf(" error=%q", e.Err)
+	}
+	return s
+}
+
+type Options struct {
+	ClientID string
+	// AccessToken is a user access token of the moderator with the
+	// moderator:manage:banned_users, moderator:manage:chat_messages, and
+	// moderator:manage:warnings scopes.
+	AccessToken string
+	ModeratorID string
+
+	// Interval is the time between actions, which can burst up to Burst actions at once.
+	// Defaults to 300 milliseconds and 10, 100 actions per 30 seconds.
+	Interval time.Duration
+	Burst    int
+
+	// AuditLog logs every action when set.
+	AuditLog *log.Logger
+	// OnAction is called with every action, after it was taken or failed.
+	OnAction func(entry AuditEntry)
+
+	// HelixURL defaults to DefaultHelixURL.
+	HelixURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock times the rate limit. Defaults to the system clock.
+	Clock twitch.Clock
+}
+
+// Moderator takes moderation actions as a moderator. It is safe for concurrent use.
+type Moderator struct {
+	options Options
+
+	mu       sync.Mutex
+	tokens   float64
+	filledAt time.Time
+
+	helix helix.Client
+}
+
+func New(options Options) *Moderator {
+	if options.Interval <= 0 {
+		options.Interval = 300 * time.Millisecond
+	}
+	if options.Burst <= 0 {
+		options.Burst = 10
+	}
+	if options.HelixURL == "" {
+		options.HelixURL = DefaultHelixURL
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	if options.Clock == nil {
+		options.Clock = twitch.SystemClock{}
+	}
+	return &Moderator{
+		options:  options,
+		tokens:   float64(options.Burst),
+		filledAt: options.Clock.Now(),
+		helix: helix.Client{
+			URL:         options.HelixURL,
+			ClientID:    options.ClientID,
+			AccessToken: options.AccessToken,
+			HTTPClient:  options.HTTPClient,
+		},
+	}
+}
+
+// Ban bans the user from the chat of the broadcaster.
+func (m *Moderator) Ban(ctx context.Context, broadcasterID, userID, reason string, trigger Trigger) error {
+	return m.ban(ctx, AuditEntry{Action: ActionBan, BroadcasterID: broadcasterID, UserID: userID, Reason: reason, Trigger: trigger})
+}
+
+// Timeout times the user out of the chat of the broadcaster, for 1 second to 2 weeks.
+func (m *Moderator) Timeout(ctx context.Context, broadcasterID, userID string, duration time.Duration, reason string, trigger Trigger) error {
+	return m.ban(ctx, AuditEntry{Action: ActionTimeout, BroadcasterID: broadcasterID, UserID: userID, Duration: duration, Reason: reason, Trigger: trigger})
+}
+
+func (m *Moderator) ban(ctx context.Context, entry AuditEntry) error {
+	data := map[string]any{"user_id": entry.UserID}
+	if entry.Duration > 0 {
+		seconds := int(entry.Duration / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		data["duration"] = seconds
+	}
+	if entry.Reason != "" {
+		data["reason"] = entry.Reason
+	}
+	return m.act(ctx, entry, http.MethodPost, "/moderation/bans", nil, map[string]any{"data": data})
+}
+
+// DeleteMessage deletes the message from the chat of the broadcaster.
+func (m *Moderator) DeleteMessage(ctx context.Context, broadcasterID, messageID string, trigger Trigger) error {
+	entry := AuditEntry{Action: ActionDeleteMessage, BroadcasterID: broadcasterID, MessageID: messageID, Trigger: trigger}
+	return m.act(ctx, entry, http.MethodDelete, "/moderation/chat", url.Values{"message_id": {messageID}}, nil)
+}
+
+// Warn warns the user in the chat of the broadcaster, who has to acknowledge the
+// warning before chatting again. The reason is required.
+func (m *Moderator) Warn(ctx context.Context, broadcasterID, userID, reason string, trigger Trigger) error {
+	entry := AuditEntry{Action: ActionWarn, BroadcasterID: broadcasterID, UserID: userID, Reason: reason, Trigger: trigger}
+	body := map[string]any{"data": map[string]any{"user_id": userID, "reason": reason}}
+	return m.act(ctx, entry, http.MethodPost, "/moderation/warnings", nil, body)
+}
+
+// act waits for the rate limit, takes the action, and audits it.
+func (m *Moderator) act(ctx context.Context, entry AuditEntry, method, path string, query url.Values, body any) error {
+	err := m.wait(ctx)
+	if err == nil {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("broadcaster_id", entry.BroadcasterID)
+		query.Set("moderator_id", m.options.ModeratorID)
+		err = m.helix.Do(ctx, method, path+"?"+query.Encode(), body, nil)
+	}
+	if err != nil {
+		err = fmt.Errorf("could not %s: %w", strings.ReplaceAll(string(entry.Action), "_", " "), err)
+	}
+
+	entry.At = m.options.Clock.Now()
+	entry.Err = err
+	if m.options.AuditLog != nil {
+		m.options.AuditLog.Print(entry)
+	}
+	if m.options.OnAction != nil {
+		m.options.OnAction(entry)
+	}
+	return err
+}
+
+// wait takes a token of the rate limit, waiting for one if there is none.
+func (m *Moderator) wait(ctx context.Context) error {
+	m.mu.Lock()
+	now := m.options.Clock.Now()
+	m.tokens += float64(now.Sub(m.filledAt)) / float64(m.options.Interval)
+	if m.tokens > float64(m.options.Burst) {
+		m.tokens = float64(m.options.Burst)
+	}
+	m.filledAt = now
+	// Taking the token before it is there queues concurrent actions behind each other.
+	m.tokens--
+	wait := time.Duration(-m.tokens * float64(m.options.Interval))
+	m.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-m.options.Clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		m.tokens++
+		m.mu.Unlock()
+		return ctx.Err()
+	}
+}
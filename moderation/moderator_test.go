@@ -0,0 +1,108 @@
+package moderation_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/moderation"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	helix := twitchtest.NewHelix(t)
+	var audit bytes.Buffer
+	var entries []moderation.AuditEntry
+	moderator := moderation.New(moderation.Options{
+		ClientID:    "client",
+		AccessToken: "token",
+		ModeratorID: "mod",
+		AuditLog:    log.New(&audit, "", 0),
+		OnAction: func(entry moderation.AuditEntry) {
+			entries = append(entries, entry)
+		},
+		HelixURL: helix.URL,
+	})
+
+	hold := twitch.EventAutomodMessageHold{MessageId: "held"}
+	trigger := moderation.TriggeredBy(hold, twitch.PayloadContext{
+		Metadata:     twitch.MessageMetadata{MessageID: "notification"},
+		Subscription: twitch.PayloadSubscription{SubscriptionRequest: twitch.SubscriptionRequest{Type: twitch.SubAutomodMessageHold}},
+	})
+	require.NoError(t, moderator.Timeout(ctx, "1", "2", 10*time.Minute, "spam", trigger))
+	require.NoError(t, moderator.Ban(ctx, "1", "2", "", trigger))
+	require.NoError(t, moderator.DeleteMessage(ctx, "1", "held", trigger))
+	require.NoError(t, moderator.Warn(ctx, "1", "2", "be nice", trigger))
+	assert.Equal(t, []string{
+		`POST /moderation/bans?broadcaster_id=1&moderator_id=mod {"data":{"duration":600,"reason":"spam","user_id":"2"}}`,
+		`POST /moderation/bans?broadcaster_id=1&moderator_id=mod {"data":{"user_id":"2"}}`,
+		`DELETE /moderation/chat?broadcaster_id=1&message_id=held&moderator_id=mod`,
+		`POST /moderation/warnings?broadcaster_id=1&moderator_id=mod {"data":{"reason":"be nice","user_id":"2"}}`,
+	}, helix.Requests())
+	request, err := helix.WaitForRequest(ctx, http.MethodDelete, "/moderation/chat")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", request.Authorization)
+
+	helix.Respond(http.MethodPost, "/moderation/bans", http.StatusForbidden)
+	err = moderator.Ban(ctx, "1", "2", "", trigger)
+	assert.ErrorContains(t, err, "could not ban: 403 Forbidden")
+
+	if assert.Len(t, entries, 5) {
+		assert.Equal(t, hold, entries[0].Trigger.Event)
+		assert.Equal(t, moderation.ActionDeleteMessage, entries[2].Action)
+		assert.Equal(t, err, entries[4].Err)
+	}
+	assert.Contains(t, audit.String(), `moderation: timeout broadcaster=1 user=2 duration=10m0s reason="spam" trigger=automod.message.hold trigger_message=notification`)
+	assert.Contains(t, audit.String(), `moderation: delete_message broadcaster=1 message=held trigger=automod.message.hold`)
+}
+
+func TestModeratorRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	helix := twitchtest.NewHelix(t)
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	moderator := moderation.New(moderation.Options{
+		ClientID:    "client",
+		AccessToken: "token",
+		ModeratorID: "mod",
+		Interval:    time.Second,
+		Burst:       2,
+		HelixURL:    helix.URL,
+		Clock:       clock,
+	})
+
+	require.NoError(t, moderator.Ban(ctx, "1", "2", "", moderation.Trigger{}))
+	require.NoError(t, moderator.Ban(ctx, "1", "3", "", moderation.Trigger{}))
+	done := make(chan error, 1)
+	go func() {
+		done <- moderator.Ban(ctx, "1", "4", "", moderation.Trigger{})
+	}()
+	select {
+	case <-done:
+		t.Fatal("third ban did not wait for the rate limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("third ban never taken")
+	}
+	assert.Len(t, helix.Requests(), 3)
+
+	canceled, cancelBan := context.WithCancel(ctx)
+	cancelBan()
+	assert.ErrorIs(t, moderator.Ban(canceled, "1", "5", "", moderation.Trigger{}), context.Canceled)
+}
@@ -0,0 +1,602 @@
+// Package mqtt publishes EventSub notifications to an MQTT broker, so devices like
+// alert lights and stream decks can subscribe to channel events directly. It speaks
+// MQTT 3.1.1 itself instead of depending on an MQTT client, and only publishes.
+//
+//	publisher, err := mqtt.Dial(ctx, "localhost:1883", mqtt.Options{QoS: 1, Topic: "twitch/{broadcaster_user_id}/{type}"})
+//	client.SetPublishBridge(publisher.Bridge())
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// DefaultTopic is the topic pattern of Bridge when Options has none.
+const DefaultTopic = "twitch/eventsub/{type}"
+
+var (
+	ErrClosed = errors.New("mqtt: publisher closed")
+	// ErrDisconnected is returned by Publish while the publisher connects again after
+	// losing its connection, and for messages which were waiting for an
+	// acknowledgement when it was lost.
+	ErrDisconnected = errors.New("mqtt: disconnected")
+)
+
+// Packet types of MQTT 3.1.1.
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPubrec     = 5
+	packetPubrel     = 6
+	packetPubcomp    = 7
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// ConnectError is the refusal of a connection by the broker.
+type ConnectError byte
+
+func (e ConnectError) Error() string {
+	switch e {
+	case 1:
+		return "mqtt: unacceptable protocol version"
+	case 2:
+		return "mqtt: client identifier rejected"
+	case 3:
+		return "mqtt: server unavailable"
+	case 4:
+		return "mqtt: bad user name or password"
+	case 5:
+		return "mqtt: not authorized"
+	}
+	return fmt.Sprintf("mqtt: connection refused with code %d", byte(e))
+}
+
+// temporary reports whether connecting again may succeed.
+func (e ConnectError) temporary() bool {
+	return e == 3
+}
+
+type Options struct {
+	// ClientID identifies the connection to the broker. The broker assigns one when it
+	// is empty.
+	ClientID string
+
+	Username string
+	Password string
+
+	// QoS is the quality of service of published messages: 0 to send them at most once,
+	// 1 to wait for the broker to acknowledge them, or 2 to have them delivered exactly
+	// once.
+	QoS byte
+	// Retain asks the broker to keep the last message of every topic for devices
+	// subscribing later, so a widget shows the latest follow as soon as it starts.
+	Retain bool
+
+	// Format is the format of the notifications Bridge publishes.
+	Format twitch.PublishFormat
+	// Topic is the topic pattern of the notifications Bridge publishes, see Bridge.
+	// Defaults to DefaultTopic.
+	Topic string
+
+	// KeepAlive is how often the connection is checked. Defaults to 60 seconds.
+	KeepAlive time.Duration
+	// DialTimeout defaults to 5 seconds.
+	DialTimeout time.Duration
+	// ReconnectWait is how long the publisher waits before connecting again after
+	// losing its connection, doubling after every failed attempt up to a minute.
+	// Defaults to 1 second.
+	ReconnectWait time.Duration
+}
+
+// Publisher publishes messages over a single connection. It implements
+// twitch.Publisher and is safe for concurrent use.
+//
+// When the connection is lost, the publisher connects again with backoff, failing
+// publishes with ErrDisconnected meanwhile. Only Close and the broker refusing the
+// credentials on a new connection stop it for good.
+type Publisher struct {
+	address string
+	options Options
+
+	mu sync.Mutex
+	// conn is nil while disconnected.
+	conn net.Conn
+	w    *bufio.Writer
+	// connDone is closed when the read loop of conn ends.
+	connDone chan struct{}
+	// err is the error which stopped the publisher for good.
+	err      error
+	onError  func(err error)
+	packetID uint16
+	pending  map[uint16]chan struct{}
+	pinging  bool
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Dial connects to the MQTT broker at address, like localhost:1883, and waits for the
+// broker to accept the connection.
+func Dial(ctx context.Context, address string, options Options) (*Publisher, error) {
+	if options.QoS > 2 {
+		return nil, fmt.Errorf("mqtt: invalid QoS %d", options.QoS)
+	}
+	if options.KeepAlive <= 0 {
+		options.KeepAlive = time.Minute
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+	if options.ReconnectWait <= 0 {
+		options.ReconnectWait = time.Second
+	}
+
+	p := &Publisher{
+		address: address,
+		options: options,
+		pending: make(map[uint16]chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	conn, r, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.connected(conn, r)
+	p.wg.Add(1)
+	go p.keepAlive()
+	return p, nil
+}
+
+// OnError is called with the errors of the publisher which no Publish returns, like
+// lost connections.
+func (p *Publisher) OnError(callback func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = callback
+}
+
+func (p *Publisher) reportError(err error) {
+	p.mu.Lock()
+	onError := p.onError
+	p.mu.Unlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+func (p *Publisher) dial(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	dialer := net.Dialer{Timeout: p.options.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not dial %s: %w", p.address, err)
+	}
+
+	r := bufio.NewReader(conn)
+	err = p.connect(ctx, conn, r)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, r, nil
+}
+
+func (p *Publisher) connect(ctx context.Context, conn net.Conn, r *bufio.Reader) error {
+	deadline := time.Now().Add(p.options.DialTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+	defer conn.SetDeadline(time.Time{})
+
+	flags := byte(0x02) // clean session
+	if p.options.Username != "" {
+		flags |= 0x80
+	}
+	if p.options.Password != "" {
+		flags |= 0x40
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(p.options.KeepAlive/time.Second))
+	body = appendString(body, p.options.ClientID)
+	if p.options.Username != "" {
+		body = appendString(body, p.options.Username)
+	}
+	if p.options.Password != "" {
+		body = appendString(body, p.options.Password)
+	}
+	w := bufio.NewWriter(conn)
+	writePacket(w, packetConnect<<4, body)
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("could not connect: %w", err)
+	}
+
+	header, body, err := readPacket(r)
+	if err != nil {
+		return fmt.Errorf("could not connect: %w", err)
+	}
+	if header>>4 != packetConnack || len(body) != 2 {
+		return fmt.Errorf("mqtt: expected CONNACK, got packet type %d", header>>4)
+	}
+	if body[1] != 0 {
+		return ConnectError(body[1])
+	}
+	return nil
+}
+
+// connected starts using a new connection.
+func (p *Publisher) connected(conn net.Conn, r *bufio.Reader) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		conn.Close()
+		return
+	}
+	done := make(chan struct{})
+	p.conn, p.w, p.connDone = conn, bufio.NewWriter(conn), done
+	p.pinging = false
+	p.wg.Add(1)
+	go p.readLoop(conn, r, done)
+}
+
+// fail drops the connection after an I/O error, reports it, and connects again unless
+// the publisher stopped.
+func (p *Publisher) fail(conn net.Conn, err error) {
+	p.mu.Lock()
+	stopped := p.err != nil
+	p.disconnectedLocked(conn)
+	p.mu.Unlock()
+	if !stopped {
+		p.reportError(fmt.Errorf("mqtt: connection lost: %w", err))
+	}
+}
+
+// disconnectedLocked drops the connection and connects again, unless the publisher
+// stopped. Messages waiting for an acknowledgement fail, since the broker forgets them
+// with the session. The caller holds p.mu.
+func (p *Publisher) disconnectedLocked(conn net.Conn) {
+	if p.conn != conn {
+		return
+	}
+	conn.Close()
+	p.conn, p.w = nil, nil
+	p.pending = make(map[uint16]chan struct{})
+	if p.err == nil {
+		p.wg.Add(1)
+		go p.reconnect()
+	}
+}
+
+// reconnect connects again with backoff, until it succeeds or the publisher stops.
+func (p *Publisher) reconnect() {
+	defer p.wg.Done()
+
+	wait := p.options.ReconnectWait
+	for {
+		select {
+		case <-p.closed:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.options.DialTimeout)
+		conn, r, err := p.dial(ctx)
+		cancel()
+		if err == nil {
+			p.connected(conn, r)
+			return
+		}
+
+		var refused ConnectError
+		if errors.As(err, &refused) && !refused.temporary() {
+			p.mu.Lock()
+			if p.err == nil {
+				p.err = err
+			}
+			p.mu.Unlock()
+			p.reportError(err)
+			return
+		}
+		p.reportError(fmt.Errorf("mqtt: could not reconnect: %w", err))
+		wait *= 2
+		if wait > time.Minute {
+			wait = time.Minute
+		}
+	}
+}
+
+// Bridge returns a publish bridge sending notifications in the format of the options to
+// the topics of their Topic pattern. The pattern refers to the subscription with {type},
+// {version}, and the keys of its condition, like twitch/{broadcaster_user_id}/{type}.
+// Conditions without the key leave it empty. Characters MQTT reserves for topic levels
+// and wildcards are replaced with underscores in the values.
+func (p *Publisher) Bridge() *twitch.PublishBridge {
+	pattern := p.options.Topic
+	if pattern == "" {
+		pattern = DefaultTopic
+	}
+	return &twitch.PublishBridge{
+		Publisher: p,
+		Format:    p.options.Format,
+		Subject: func(subscription twitch.PayloadSubscription) string {
+			return Topic(pattern, subscription)
+		},
+	}
+}
+
+var topicEscaper = strings.NewReplacer("/", "_", "+", "_", "#", "_")
+
+// Topic returns the topic of the pattern for a subscription, as described by Bridge.
+func Topic(pattern string, subscription twitch.PayloadSubscription) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(pattern[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(pattern[:start])
+
+		var value string
+		switch key := pattern[start+1 : start+end]; key {
+		case "type":
+			value = string(subscription.Type)
+		case "version":
+			value = subscription.Version
+		default:
+			value = subscription.Condition[key]
+		}
+		b.WriteString(topicEscaper.Replace(value))
+		pattern = pattern[start+end+1:]
+	}
+	b.WriteString(pattern)
+	return b.String()
+}
+
+// Publish publishes the data to the topic with the QoS of the options, waiting for the
+// broker to acknowledge it unless the QoS is 0.
+func (p *Publisher) Publish(ctx context.Context, topic string, data []byte) error {
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return fmt.Errorf("mqtt: invalid topic %q", topic)
+	}
+
+	qos := p.options.QoS
+	header := byte(packetPublish<<4) | qos<<1
+	if p.options.Retain {
+		header |= 0x01
+	}
+	body := appendString(make([]byte, 0, len(topic)+len(data)+4), topic)
+
+	p.mu.Lock()
+	if p.err != nil {
+		p.mu.Unlock()
+		return p.err
+	}
+	if p.conn == nil {
+		p.mu.Unlock()
+		return ErrDisconnected
+	}
+	done := p.connDone
+	var id uint16
+	var acked chan struct{}
+	if qos > 0 {
+		id = p.nextPacketID()
+		body = binary.BigEndian.AppendUint16(body, id)
+		acked = make(chan struct{})
+		p.pending[id] = acked
+	}
+	body = append(body, data...)
+	err := p.writeLocked(ctx, header, body)
+	p.mu.Unlock()
+	if err != nil || qos == 0 {
+		return err
+	}
+
+	select {
+	case <-acked:
+		return nil
+	case <-done:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.err != nil {
+			return p.err
+		}
+		return ErrDisconnected
+	case <-ctx.Done():
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (p *Publisher) nextPacketID() uint16 {
+	for {
+		p.packetID++
+		if _, ok := p.pending[p.packetID]; p.packetID != 0 && !ok {
+			return p.packetID
+		}
+	}
+}
+
+// writeLocked writes a packet, dropping the connection when it fails. The caller holds
+// p.mu and checked the connection.
+func (p *Publisher) writeLocked(ctx context.Context, header byte, body []byte) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		p.conn.SetWriteDeadline(deadline)
+		defer func(conn net.Conn) { conn.SetWriteDeadline(time.Time{}) }(p.conn)
+	}
+
+	writePacket(p.w, header, body)
+	if err := p.w.Flush(); err != nil {
+		p.disconnectedLocked(p.conn)
+		return err
+	}
+	return nil
+}
+
+// readLoop completes the acknowledgements of published messages, until the connection
+// fails.
+func (p *Publisher) readLoop(conn net.Conn, r *bufio.Reader, done chan struct{}) {
+	defer p.wg.Done()
+	defer close(done)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			p.fail(conn, err)
+			return
+		}
+
+		switch header >> 4 {
+		case packetPuback, packetPubcomp:
+			if len(body) >= 2 {
+				p.ack(binary.BigEndian.Uint16(body))
+			}
+		case packetPingresp:
+			p.mu.Lock()
+			p.pinging = false
+			conn.SetReadDeadline(time.Time{})
+			p.mu.Unlock()
+		case packetPubrec:
+			if len(body) < 2 {
+				continue
+			}
+			// Release the message so the broker delivers it, then wait for PUBCOMP.
+			p.mu.Lock()
+			if p.conn == conn {
+				err = p.writeLocked(context.Background(), packetPubrel<<4|0x02, body[:2])
+			}
+			p.mu.Unlock()
+			if err != nil {
+				p.fail(conn, err)
+				return
+			}
+		}
+	}
+}
+
+func (p *Publisher) ack(id uint16) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if acked, ok := p.pending[id]; ok {
+		delete(p.pending, id)
+		close(acked)
+	}
+}
+
+// keepAlive pings the broker so it does not close an idle connection. A broker which
+// stopped answering is noticed when the keep alive passes without a response.
+func (p *Publisher) keepAlive() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.options.KeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			if p.conn != nil && !p.pinging {
+				p.pinging = true
+				p.conn.SetReadDeadline(time.Now().Add(p.options.KeepAlive))
+				p.writeLocked(context.Background(), packetPingreq<<4, nil)
+			}
+			p.mu.Unlock()
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+// Close disconnects from the broker. Messages waiting for an acknowledgement fail.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if errors.Is(p.err, ErrClosed) {
+		p.mu.Unlock()
+		return nil
+	}
+	p.err = ErrClosed
+	close(p.closed)
+	var err error
+	if conn := p.conn; conn != nil {
+		p.writeLocked(context.Background(), packetDisconnect<<4, nil)
+		if p.conn == conn {
+			p.conn, p.w = nil, nil
+			err = conn.Close()
+		}
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func writePacket(w *bufio.Writer, header byte, body []byte) {
+	w.WriteByte(header)
+	// The remaining length is encoded 7 bits at a time, least significant first.
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		w.WriteByte(digit)
+		if length == 0 {
+			break
+		}
+	}
+	w.Write(body)
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("mqtt: malformed remaining length")
+		}
+		multiplier *= 128
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
@@ -0,0 +1,203 @@
+package mqtt_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/mqtt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type packet struct {
+	header byte
+	body   []byte
+}
+
+type message struct {
+	topic string
+	qos   byte
+	data  string
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	length, multiplier := 0, 1
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return packet{}, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	return packet{header: header, body: body}, err
+}
+
+// fakeBroker accepts connections, answers them with the CONNACK return code, and sends
+// the messages published on them. With drop, the first connection is closed after its
+// first message, without acknowledging it.
+func fakeBroker(t *testing.T, returnCode byte, drop bool) (string, <-chan message) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan message, 16)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn, returnCode, drop && i == 0, messages)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func serve(conn net.Conn, returnCode byte, drop bool, messages chan<- message) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		p, err := readPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch p.header >> 4 {
+		case 1:
+			// Skip the protocol name, level, flags, keep alive, and client ID.
+			offset := 12 + int(binary.BigEndian.Uint16(p.body[10:]))
+			username := string(p.body[offset+2 : offset+2+int(binary.BigEndian.Uint16(p.body[offset:]))])
+			if username != "overlay" {
+				returnCode = 4
+			}
+			conn.Write([]byte{0x20, 2, 0, returnCode})
+		case 3:
+			qos := p.header >> 1 & 0x03
+			size := int(binary.BigEndian.Uint16(p.body))
+			topic, rest := string(p.body[2:2+size]), p.body[2+size:]
+			var id []byte
+			if qos > 0 {
+				id, rest = rest[:2], rest[2:]
+			}
+			messages <- message{topic: topic, qos: qos, data: string(rest)}
+			if drop {
+				return
+			}
+			switch qos {
+			case 1:
+				conn.Write(append([]byte{0x40, 2}, id...))
+			case 2:
+				conn.Write(append([]byte{0x50, 2}, id...))
+			}
+		case 6:
+			conn.Write(append([]byte{0x70, 2}, p.body...))
+		case 12:
+			conn.Write([]byte{0xd0, 0})
+		case 14:
+			return
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	for _, qos := range []byte{0, 1, 2} {
+		address, messages := fakeBroker(t, 0, false)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		publisher, err := mqtt.Dial(ctx, address, mqtt.Options{ClientID: "test", Username: "overlay", Password: "secret", QoS: qos, Topic: "twitch/{to_broadcaster_user_id}/{type}"})
+		require.NoError(t, err)
+
+		client := twitch.NewClient()
+		client.OnError(func(err error) {
+			t.Errorf("client registered an error: %v", err)
+		})
+		client.SetPublishBridge(publisher.Bridge())
+		raid := twitch.EventChannelRaid{Viewers: 42}
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, raid))
+
+		select {
+		case msg := <-messages:
+			assert.Equal(t, "twitch//channel.raid", msg.topic)
+			assert.Equal(t, qos, msg.qos)
+			assert.Contains(t, msg.data, `"viewers":42`)
+		case <-ctx.Done():
+			t.Fatal("message was not published")
+		}
+
+		require.NoError(t, client.FlushPublished(ctx))
+		require.NoError(t, publisher.Close())
+		assert.ErrorIs(t, publisher.Publish(ctx, "twitch", nil), mqtt.ErrClosed)
+	}
+}
+
+func TestPublisherRefused(t *testing.T) {
+	t.Parallel()
+
+	address, _ := fakeBroker(t, 5, false)
+	_, err := mqtt.Dial(context.Background(), address, mqtt.Options{Username: "overlay"})
+	assert.Equal(t, mqtt.ConnectError(5), err)
+}
+
+func TestPublisherReconnects(t *testing.T) {
+	t.Parallel()
+
+	address, messages := fakeBroker(t, 0, true)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	publisher, err := mqtt.Dial(ctx, address, mqtt.Options{Username: "overlay", QoS: 1, ReconnectWait: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer publisher.Close()
+	errs := make(chan error, 16)
+	publisher.OnError(func(err error) { errs <- err })
+
+	// The broker drops the connection instead of acknowledging the first message.
+	assert.ErrorIs(t, publisher.Publish(ctx, "first", nil), mqtt.ErrDisconnected)
+	assert.Equal(t, "first", (<-messages).topic)
+	assert.ErrorContains(t, <-errs, "connection lost")
+
+	for {
+		err := publisher.Publish(ctx, "second", nil)
+		if err == nil {
+			break
+		}
+		require.ErrorIs(t, err, mqtt.ErrDisconnected)
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("did not reconnect")
+		}
+	}
+	assert.Equal(t, "second", (<-messages).topic)
+}
+
+func TestTopic(t *testing.T) {
+	t.Parallel()
+
+	subscription := twitch.PayloadSubscription{}
+	subscription.Type = twitch.SubChannelFollow
+	subscription.Version = "2"
+	subscription.Condition = map[string]string{"broadcaster_user_id": "12/+#"}
+
+	assert.Equal(t, "twitch/12___/channel.follow/2", mqtt.Topic("twitch/{broadcaster_user_id}/{type}/{version}", subscription))
+	assert.Equal(t, "twitch/eventsub/channel.follow", mqtt.Topic(mqtt.DefaultTopic, subscription))
+	assert.Equal(t, "twitch/{unclosed", mqtt.Topic("twitch/{unclosed", subscription))
+}
@@ -0,0 +1,388 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Envelopes and events are encoded as MessagePack with the same structure as their JSON,
+// so they decode into the same types and consumers outside of Go read them with the
+// names of the Twitch documentation. Fields keep their order, integers and floats are
+// binary, and times stay RFC 3339 strings. Decoding also accepts the MessagePack
+// timestamp extension, which other encoders use for times.
+
+// msgpackTimestamp is the extension type of MessagePack timestamps.
+const msgpackTimestamp = -1
+
+var errMsgpackTruncated = errors.New("truncated msgpack value")
+
+// MarshalMsgpack returns the MessagePack encoding of v, like a PublishedNotification or
+// an event struct, structured like its JSON encoding.
+func MarshalMsgpack(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToMsgpack(data)
+}
+
+// UnmarshalMsgpack decodes MessagePack data into v like json.Unmarshal decodes the JSON
+// encoding of the same value.
+func UnmarshalMsgpack(data []byte, v any) error {
+	b, err := msgpackToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	b, err := appendMsgpackJSON(nil, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode msgpack: %w", err)
+	}
+	return b, nil
+}
+
+// appendMsgpackJSON appends the next JSON value of the decoder.
+func appendMsgpackJSON(b []byte, decoder *json.Decoder) ([]byte, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if token {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case string:
+		return appendMsgpackString(b, token), nil
+	case json.Number:
+		return appendMsgpackNumber(b, token)
+	case json.Delim:
+		// Encode the elements first since the header holds their count.
+		var elements []byte
+		n := 0
+		for decoder.More() {
+			if token == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				elements = appendMsgpackString(elements, key.(string))
+			}
+			elements, err = appendMsgpackJSON(elements, decoder)
+			if err != nil {
+				return nil, err
+			}
+			n++
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		if token == '{' {
+			b = appendMsgpackHeader(b, n, 0x80, 16, 0xde)
+		} else {
+			b = appendMsgpackHeader(b, n, 0x90, 16, 0xdc)
+		}
+		return append(b, elements...), nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", token)
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch {
+	case len(s) < 32:
+		b = append(b, 0xa0|byte(len(s)))
+	case len(s) <= math.MaxUint8:
+		b = append(b, 0xd9, byte(len(s)))
+	case len(s) <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(len(s)))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(len(s)))
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackHeader appends the header of a map or array, fixed up to the limit.
+func appendMsgpackHeader(b []byte, n int, fixed byte, limit int, code byte) []byte {
+	switch {
+	case n < limit:
+		return append(b, fixed|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code+1), uint32(n))
+	}
+}
+
+func appendMsgpackNumber(b []byte, number json.Number) ([]byte, error) {
+	if i, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+		switch {
+		case i >= 0 && i <= math.MaxInt8:
+			return append(b, byte(i)), nil
+		case i < 0 && i >= -32:
+			return append(b, byte(int8(i))), nil
+		case i >= math.MinInt8 && i <= math.MaxInt8:
+			return append(b, 0xd0, byte(int8(i))), nil
+		case i >= math.MinInt16 && i <= math.MaxInt16:
+			return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(int16(i))), nil
+		case i >= math.MinInt32 && i <= math.MaxInt32:
+			return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(int32(i))), nil
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i)), nil
+	}
+	if u, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u), nil
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+}
+
+func msgpackToJSON(data []byte) ([]byte, error) {
+	d := msgpackDecoder{data: data}
+	b, err := d.appendJSON(nil)
+	if err == nil && d.offset != len(data) {
+		err = fmt.Errorf("%d bytes after the value", len(data)-d.offset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not decode msgpack: %w", err)
+	}
+	return b, nil
+}
+
+type msgpackDecoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.offset < n {
+		return nil, errMsgpackTruncated
+	}
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+// uint reads a big endian unsigned integer of n bytes.
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// appendJSON appends the JSON encoding of the next value.
+func (d *msgpackDecoder) appendJSON(b []byte) ([]byte, error) {
+	code, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	c := code[0]
+	switch {
+	case c <= 0x7f:
+		return strconv.AppendInt(b, int64(c), 10), nil
+	case c >= 0xe0:
+		return strconv.AppendInt(b, int64(int8(c)), 10), nil
+	case c&0xf0 == 0x80:
+		return d.appendMap(b, int(c&0x0f))
+	case c&0xf0 == 0x90:
+		return d.appendArray(b, int(c&0x0f))
+	case c&0xe0 == 0xa0:
+		return d.appendString(b, int(c&0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return append(b, "null"...), nil
+	case 0xc2:
+		return append(b, "false"...), nil
+	case 0xc3:
+		return append(b, "true"...), nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		// Binary data is a base64 string, like encoding/json encodes []byte.
+		b = append(b, '"')
+		b = append(b, base64.StdEncoding.EncodeToString(data)...)
+		return append(b, '"'), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.appendExtension(b, int(n))
+	case 0xca:
+		v, err := d.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return appendJSONFloat(b, float64(math.Float32frombits(uint32(v))))
+	case 0xcb:
+		v, err := d.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return appendJSONFloat(b, math.Float64frombits(v))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return strconv.AppendUint(b, v, 10), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		v, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign extend the value from its size.
+		shift := 64 - 8*size
+		return strconv.AppendInt(b, int64(v<<shift)>>shift, 10), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.appendExtension(b, 1<<(c-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.appendString(b, int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.appendArray(b, int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.appendMap(b, int(n))
+	}
+	return nil, fmt.Errorf("unknown msgpack type 0x%x", c)
+}
+
+func (d *msgpackDecoder) appendString(b []byte, n int) ([]byte, error) {
+	s, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(string(s))
+	if err != nil {
+		return nil, err
+	}
+	return append(b, encoded...), nil
+}
+
+func (d *msgpackDecoder) appendArray(b []byte, n int) ([]byte, error) {
+	b = append(b, '[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		b, err = d.appendJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(b, ']'), nil
+}
+
+func (d *msgpackDecoder) appendMap(b []byte, n int) ([]byte, error) {
+	b = append(b, '{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		start := len(b)
+		var err error
+		b, err = d.appendJSON(b)
+		if err != nil {
+			return nil, err
+		}
+		// JSON object keys are strings, so quote integer keys.
+		if b[start] != '"' {
+			key := string(b[start:])
+			if _, err := strconv.ParseFloat(key, 64); err != nil {
+				return nil, fmt.Errorf("unsupported map key %s", key)
+			}
+			b = append(append(append(b[:start], '"'), key...), '"')
+		}
+		b = append(b, ':')
+		b, err = d.appendJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendExtension appends a timestamp extension of n bytes as an RFC 3339 string.
+func (d *msgpackDecoder) appendExtension(b []byte, n int) ([]byte, error) {
+	typ, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ[0]) != msgpackTimestamp {
+		return nil, fmt.Errorf("unsupported msgpack extension %d", int8(typ[0]))
+	}
+
+	var t time.Time
+	switch n {
+	case 4:
+		t = time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		t = time.Unix(int64(v&0x3ffffffff), int64(v>>34))
+	case 12:
+		t = time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data)))
+	default:
+		return nil, fmt.Errorf("invalid msgpack timestamp of %d bytes", n)
+	}
+	b = append(b, '"')
+	b = t.UTC().AppendFormat(b, time.RFC3339Nano)
+	return append(b, '"'), nil
+}
+
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("unsupported float %v", f)
+	}
+	return strconv.AppendFloat(b, f, 'g', -1, 64), nil
+}
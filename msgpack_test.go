@@ -0,0 +1,122 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	var tested int
+	for key, payload := range loadFixtures(t) {
+		event, _, _ := strings.Cut(key, "-")
+		metadata, ok := subMetadata[EventSubscription(event)]
+		if !ok || metadata.EventGen == nil {
+			continue
+		}
+		tested++
+
+		want := metadata.EventGen()
+		if err := json.Unmarshal(payload, want); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		data, err := MarshalMsgpack(want)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if len(data) >= len(payload) {
+			t.Errorf("%s: expected msgpack smaller than %d bytes of JSON, got %d", key, len(payload), len(data))
+		}
+
+		got := metadata.EventGen()
+		if err := UnmarshalMsgpack(data, got); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: event changed after decoding", key)
+		}
+	}
+	if tested == 0 {
+		t.Fatal("no fixtures tested")
+	}
+}
+
+func TestMsgpackValues(t *testing.T) {
+	type values struct {
+		Small    int               `json:"small"`
+		Negative int64             `json:"negative"`
+		Large    uint64            `json:"large"`
+		Float    float64           `json:"float"`
+		Text     string            `json:"text"`
+		Long     string            `json:"long"`
+		Bytes    []byte            `json:"bytes"`
+		Nil      *string           `json:"nil"`
+		Map      map[string]bool   `json:"map"`
+		Array    []int16           `json:"array"`
+		Time     time.Time         `json:"time"`
+		Raw      json.RawMessage   `json:"raw"`
+		Keys     map[int]string    `json:"keys"`
+		Nested   map[string][]bool `json:"nested"`
+	}
+	want := values{
+		Small:    7,
+		Negative: -40000,
+		Large:    1 << 63,
+		Float:    0.25,
+		Text:     "Kappa",
+		Long:     strings.Repeat("a", 300),
+		Bytes:    []byte{1, 2, 3},
+		Map:      map[string]bool{"yes": true},
+		Array:    []int16{-1, -100, 300},
+		Time:     time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		Raw:      json.RawMessage(`{"b":1,"a":[null]}`),
+		Keys:     map[int]string{4: "four"},
+		Nested:   map[string][]bool{"flags": {false}},
+	}
+
+	data, err := MarshalMsgpack(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got values
+	if err := UnmarshalMsgpack(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expected %+v got %+v", want, got)
+	}
+
+	// Fields keep the order of the JSON.
+	if !bytes.HasPrefix(data, []byte{0x8e, 0xa5, 's', 'm', 'a', 'l', 'l', 7}) {
+		t.Errorf("unexpected encoding % x", data[:8])
+	}
+	if err := UnmarshalMsgpack(data[:len(data)-1], &got); err == nil {
+		t.Error("expected truncated data to fail")
+	}
+	if err := UnmarshalMsgpack(append(data, 0xc0), &got); err == nil {
+		t.Error("expected trailing data to fail")
+	}
+}
+
+func TestMsgpackTimestampExtension(t *testing.T) {
+	var got struct {
+		At time.Time `json:"at"`
+	}
+	// {"at": timestamp 64 of 2024-01-02T03:04:05.000000006Z}
+	seconds := uint64(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).Unix())
+	v := 6<<34 | seconds
+	data := []byte{0x81, 0xa2, 'a', 't', 0xd7, 0xff}
+	for shift := 56; shift >= 0; shift -= 8 {
+		data = append(data, byte(v>>shift))
+	}
+
+	if err := UnmarshalMsgpack(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC); !got.At.Equal(want) {
+		t.Errorf("expected %s got %s", want, got.At)
+	}
+}
@@ -0,0 +1,56 @@
+package twitch
+
+import "sort"
+
+// Mute stops publishing and dispatching the notifications of the subscription types,
+// without unsubscribing, like to quiet a noisy type while debugging. Muted
+// notifications still count in Stats.
+func (c *Client) Mute(types ...EventSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	muted := c.mutedCopy()
+	for _, t := range types {
+		muted[t] = true
+	}
+	c.muted.Store(muted)
+}
+
+// Unmute publishes and dispatches the notifications of the subscription types again.
+func (c *Client) Unmute(types ...EventSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	muted := c.mutedCopy()
+	for _, t := range types {
+		delete(muted, t)
+	}
+	c.muted.Store(muted)
+}
+
+// Muted returns the muted subscription types, sorted.
+func (c *Client) Muted() []EventSubscription {
+	snapshot, _ := c.muted.Load().(map[EventSubscription]bool)
+	muted := make([]EventSubscription, 0, len(snapshot))
+	for t := range snapshot {
+		muted = append(muted, t)
+	}
+	sort.Slice(muted, func(i, j int) bool { return muted[i] < muted[j] })
+	return muted
+}
+
+// mutedCopy returns a copy of the muted types to change and store. The caller holds
+// c.mu.
+func (c *Client) mutedCopy() map[EventSubscription]bool {
+	snapshot, _ := c.muted.Load().(map[EventSubscription]bool)
+	muted := make(map[EventSubscription]bool, len(snapshot)+1)
+	for t := range snapshot {
+		muted[t] = true
+	}
+	return muted
+}
+
+func (c *Client) isMuted(t EventSubscription) bool {
+	muted, _ := c.muted.Load().(map[EventSubscription]bool)
+	return muted[t]
+}
@@ -0,0 +1,39 @@
+package twitch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMute(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	var raids, follows int
+	client.OnEventChannelRaid(func(twitch.EventChannelRaid, twitch.PayloadContext) { raids++ })
+	client.OnEventChannelFollow(func(twitch.EventChannelFollow, twitch.PayloadContext) { follows++ })
+	publisher := &fakePublisher{}
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher})
+
+	client.Mute(twitch.SubChannelRaid, twitch.SubChannelCheer)
+	assert.Equal(t, []twitch.EventSubscription{twitch.SubChannelCheer, twitch.SubChannelRaid}, client.Muted())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{}))
+	require.NoError(t, client.InjectNotification(twitch.SubChannelFollow, twitch.EventChannelFollow{}))
+	require.NoError(t, client.FlushPublished(context.Background()))
+	assert.Equal(t, 0, raids)
+	assert.Equal(t, 1, follows)
+	if assert.Len(t, publisher.published, 1) {
+		assert.Equal(t, "twitch.eventsub.channel.follow", publisher.published[0].subject)
+	}
+	assert.EqualValues(t, 2, client.Stats().Notifications)
+
+	client.Unmute(twitch.SubChannelRaid)
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{}))
+	assert.Equal(t, 1, raids)
+	assert.Equal(t, []twitch.EventSubscription{twitch.SubChannelCheer}, client.Muted())
+}
@@ -0,0 +1,440 @@
+// Package nats publishes EventSub notifications to a NATS server. It speaks the core
+// NATS protocol itself instead of depending on a NATS client, and only publishes.
+//
+//	publisher, err := nats.Dial(ctx, "localhost:4222", nats.Options{})
+//	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher})
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrClosed = errors.New("nats: publisher closed")
+	// ErrDisconnected is returned by Publish and Flush while the publisher connects
+	// again after losing its connection.
+	ErrDisconnected = errors.New("nats: disconnected")
+)
+
+type Options struct {
+	// Name identifies the connection in the server monitoring.
+	Name string
+
+	User     string
+	Password string
+	Token    string
+
+	// DialTimeout defaults to 5 seconds. It also bounds writes to the server.
+	DialTimeout time.Duration
+	// ReconnectWait is how long the publisher waits before connecting again after
+	// losing its connection, doubling after every failed attempt up to a minute.
+	// Defaults to 1 second.
+	ReconnectWait time.Duration
+}
+
+// Publisher publishes messages over a single connection. It implements
+// twitch.Publisher and is safe for concurrent use.
+//
+// Published messages are buffered and written by a background goroutine, so Publish
+// does not wait for the network; use Flush to wait for the server. When the connection
+// is lost, the publisher connects again with backoff, failing publishes with
+// ErrDisconnected meanwhile. Only Close and the server refusing the credentials on a
+// new connection stop it for good.
+type Publisher struct {
+	address string
+	options Options
+
+	mu sync.Mutex
+	// conn is nil while disconnected.
+	conn net.Conn
+	w    *bufio.Writer
+	// connDone is closed when the read loop of conn ends.
+	connDone chan struct{}
+	// err is the error which stopped the publisher for good.
+	err     error
+	onError func(err error)
+
+	pong   chan struct{}
+	flush  chan struct{}
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+type connectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name,omitempty"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+}
+
+// refusedError is the refusal of a connection by the server, like for wrong
+// credentials, which connecting again does not fix.
+type refusedError struct {
+	message string
+}
+
+func (e refusedError) Error() string {
+	return "server refused connection: " + e.message
+}
+
+// Dial connects to the NATS server at address, like localhost:4222, and waits for the
+// server to accept the connection.
+func Dial(ctx context.Context, address string, options Options) (*Publisher, error) {
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+	if options.ReconnectWait <= 0 {
+		options.ReconnectWait = time.Second
+	}
+	p := &Publisher{
+		address: address,
+		options: options,
+		pong:    make(chan struct{}, 1),
+		flush:   make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+
+	conn, r, w, err := p.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.connected(conn, r, w)
+	p.wg.Add(1)
+	go p.flushLoop()
+	return p, nil
+}
+
+// OnError is called with the errors of the publisher which no Publish returns, like
+// errors the server reports asynchronously and lost connections.
+func (p *Publisher) OnError(callback func(err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onError = callback
+}
+
+func (p *Publisher) reportError(err error) {
+	p.mu.Lock()
+	onError := p.onError
+	p.mu.Unlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+func (p *Publisher) dial(ctx context.Context) (net.Conn, *bufio.Reader, *bufio.Writer, error) {
+	dialer := net.Dialer{Timeout: p.options.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not dial %s: %w", p.address, err)
+	}
+
+	r, w, err := handshake(ctx, conn, p.options)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	return conn, r, w, nil
+}
+
+func handshake(ctx context.Context, conn net.Conn, options Options) (*bufio.Reader, *bufio.Writer, error) {
+	deadline := time.Now().Add(options.DialTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+	defer conn.SetDeadline(time.Time{})
+
+	r := bufio.NewReader(conn)
+	line, err := readLine(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return nil, nil, fmt.Errorf("expected server info, got %q", line)
+	}
+
+	connect, err := json.Marshal(connectOptions{
+		Name:     options.Name,
+		User:     options.User,
+		Password: options.Password,
+		Token:    options.Token,
+		Lang:     "go",
+		Version:  "twitch-eventsub",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "CONNECT %s\r\nPING\r\n", connect)
+	if err := w.Flush(); err != nil {
+		return nil, nil, fmt.Errorf("could not connect: %w", err)
+	}
+
+	// The server answers the PING once it processed CONNECT, or errors if it refused it.
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not connect: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return r, w, nil
+		case strings.HasPrefix(line, "-ERR"):
+			return nil, nil, refusedError{serverError(line)}
+		}
+	}
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func serverError(line string) string {
+	return strings.TrimSpace(strings.TrimPrefix(line, "-ERR"))
+}
+
+// connected starts using a new connection.
+func (p *Publisher) connected(conn net.Conn, r *bufio.Reader, w *bufio.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		conn.Close()
+		return
+	}
+	done := make(chan struct{})
+	p.conn, p.w, p.connDone = conn, w, done
+	p.wg.Add(1)
+	go p.readLoop(conn, r, done)
+}
+
+// disconnectedLocked drops the connection after an I/O error and connects again, unless
+// the publisher stopped. The caller holds p.mu.
+func (p *Publisher) disconnectedLocked(conn net.Conn) {
+	if p.conn != conn {
+		return
+	}
+	conn.Close()
+	p.conn, p.w = nil, nil
+	if p.err == nil {
+		p.wg.Add(1)
+		go p.reconnect()
+	}
+}
+
+// reconnect connects again with backoff, until it succeeds or the publisher stops.
+func (p *Publisher) reconnect() {
+	defer p.wg.Done()
+
+	wait := p.options.ReconnectWait
+	for {
+		select {
+		case <-p.closed:
+			return
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.options.DialTimeout)
+		conn, r, w, err := p.dial(ctx)
+		cancel()
+		if err == nil {
+			p.connected(conn, r, w)
+			return
+		}
+
+		var refused refusedError
+		if errors.As(err, &refused) {
+			p.mu.Lock()
+			if p.err == nil {
+				p.err = fmt.Errorf("nats: %w", err)
+			}
+			p.mu.Unlock()
+			p.reportError(err)
+			return
+		}
+		p.reportError(fmt.Errorf("nats: could not reconnect: %w", err))
+		wait *= 2
+		if wait > time.Minute {
+			wait = time.Minute
+		}
+	}
+}
+
+// readLoop answers the server PINGs, which it sends to check the connection, and
+// reports the errors it sends. The server closes the connection after fatal errors,
+// like a revoked authorization, while errors like a publish permission violation leave
+// it open.
+func (p *Publisher) readLoop(conn net.Conn, r *bufio.Reader, done chan struct{}) {
+	defer p.wg.Done()
+	defer close(done)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			p.mu.Lock()
+			stopped := p.err != nil
+			p.disconnectedLocked(conn)
+			p.mu.Unlock()
+			if !stopped {
+				p.reportError(fmt.Errorf("nats: connection lost: %w", err))
+			}
+			return
+		}
+
+		switch {
+		case line == "PING":
+			p.mu.Lock()
+			if p.conn == conn {
+				p.w.WriteString("PONG\r\n")
+				if p.flushLocked() != nil {
+					p.disconnectedLocked(conn)
+				}
+			}
+			p.mu.Unlock()
+		case line == "PONG":
+			select {
+			case p.pong <- struct{}{}:
+			default:
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			p.reportError(fmt.Errorf("nats: %s", serverError(line)))
+		}
+	}
+}
+
+// flushLocked writes the buffered messages. The caller holds p.mu and checked the
+// connection.
+func (p *Publisher) flushLocked() error {
+	p.conn.SetWriteDeadline(time.Now().Add(p.options.DialTimeout))
+	return p.w.Flush()
+}
+
+// flushLoop writes the buffered messages in the background, so concurrent publishes
+// share writes.
+func (p *Publisher) flushLoop() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.closed:
+			return
+		case <-p.flush:
+		}
+
+		p.mu.Lock()
+		if p.conn != nil && p.w.Buffered() > 0 {
+			if p.flushLocked() != nil {
+				p.disconnectedLocked(p.conn)
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+// Publish buffers the message to be written to the connection. It does not wait for
+// the server, use Flush to know the server received every message published before it.
+func (p *Publisher) Publish(ctx context.Context, subject string, data []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("nats: invalid subject %q", subject)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	if p.conn == nil {
+		return ErrDisconnected
+	}
+	// Writes go to the network when the buffer is full.
+	if deadline, ok := ctx.Deadline(); ok {
+		p.conn.SetWriteDeadline(deadline)
+	}
+
+	fmt.Fprintf(p.w, "PUB %s %d\r\n", subject, len(data))
+	p.w.Write(data)
+	_, err := p.w.WriteString("\r\n")
+	if err != nil {
+		p.disconnectedLocked(p.conn)
+		return err
+	}
+	select {
+	case p.flush <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush waits for the server to process every message published so far.
+func (p *Publisher) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	if p.err != nil {
+		p.mu.Unlock()
+		return p.err
+	}
+	if p.conn == nil {
+		p.mu.Unlock()
+		return ErrDisconnected
+	}
+	done := p.connDone
+	p.w.WriteString("PING\r\n")
+	err := p.flushLocked()
+	if err != nil {
+		p.disconnectedLocked(p.conn)
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-p.pong:
+		return nil
+	case <-done:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.err != nil {
+			return p.err
+		}
+		return ErrDisconnected
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes the buffered messages and closes the connection.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	if errors.Is(p.err, ErrClosed) {
+		p.mu.Unlock()
+		return nil
+	}
+	p.err = ErrClosed
+	close(p.closed)
+	var err error
+	conn := p.conn
+	if conn != nil {
+		p.flushLocked()
+		p.conn, p.w = nil, nil
+		err = conn.Close()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
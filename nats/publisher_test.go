@@ -0,0 +1,166 @@
+package nats_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/nats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	subject string
+	data    string
+}
+
+type fakeOptions struct {
+	// refuse refuses the credentials of every connection.
+	refuse bool
+	// drop closes the first connection after its first message.
+	drop bool
+	// deny answers messages to the subject with a permissions violation.
+	deny string
+}
+
+// fakeServer accepts connections and sends the messages published on them.
+func fakeServer(t *testing.T, options fakeOptions) (string, <-chan message) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan message, 16)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn, options, options.drop && i == 0, messages)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func serve(conn net.Conn, options fakeOptions, drop bool, messages chan<- message) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT ") && options.refuse:
+			fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+			return
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case strings.HasPrefix(line, "PUB "):
+			var subject string
+			var size int
+			fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			if subject == options.deny {
+				fmt.Fprintf(conn, "-ERR 'Permissions Violation for Publish to \"%s\"'\r\n", subject)
+				continue
+			}
+			messages <- message{subject: subject, data: string(data[:size])}
+			if drop {
+				return
+			}
+		}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	address, messages := fakeServer(t, fakeOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := nats.Dial(ctx, address, nats.Options{Name: "test"})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher})
+	client.OnError(func(err error) { t.Error(err) })
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+	require.NoError(t, client.FlushPublished(ctx))
+	require.NoError(t, publisher.Flush(ctx))
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, "twitch.eventsub.channel.raid", msg.subject)
+		assert.Contains(t, msg.data, `"viewers":42`)
+	case <-ctx.Done():
+		t.Fatal("no message published")
+	}
+
+	assert.Error(t, publisher.Publish(ctx, "bad subject", nil))
+}
+
+func TestDialRefused(t *testing.T) {
+	t.Parallel()
+
+	address, _ := fakeServer(t, fakeOptions{refuse: true})
+	_, err := nats.Dial(context.Background(), address, nats.Options{Token: "wrong"})
+	assert.ErrorContains(t, err, "Authorization Violation")
+}
+
+func TestPublisherReconnects(t *testing.T) {
+	t.Parallel()
+
+	address, messages := fakeServer(t, fakeOptions{drop: true, deny: "secret"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := nats.Dial(ctx, address, nats.Options{ReconnectWait: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer publisher.Close()
+	errs := make(chan error, 16)
+	publisher.OnError(func(err error) { errs <- err })
+
+	require.NoError(t, publisher.Publish(ctx, "first", []byte("1")))
+	assert.Equal(t, "first", (<-messages).subject)
+	assert.ErrorContains(t, <-errs, "connection lost")
+
+	// Publishing fails until the publisher connected again.
+	for {
+		err := publisher.Publish(ctx, "second", []byte("2"))
+		if err == nil {
+			break
+		}
+		require.ErrorIs(t, err, nats.ErrDisconnected)
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("did not reconnect")
+		}
+	}
+	require.NoError(t, publisher.Flush(ctx))
+	assert.Equal(t, "second", (<-messages).subject)
+
+	// A permissions violation is reported without closing the connection.
+	require.NoError(t, publisher.Publish(ctx, "secret", []byte("3")))
+	require.NoError(t, publisher.Flush(ctx))
+	assert.ErrorContains(t, <-errs, "Permissions Violation")
+	require.NoError(t, publisher.Publish(ctx, "third", []byte("4")))
+	require.NoError(t, publisher.Flush(ctx))
+	assert.Equal(t, "third", (<-messages).subject)
+}
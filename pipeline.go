@@ -0,0 +1,85 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// SetDecodeWorkers decodes frames on the given number of goroutines instead of the
+// read loop. Frames are still delivered in the order they were read, so handlers of a
+// subscription are dispatched in order; with synchronous dispatch they also run in
+// order. A count of 0 or less decodes on the read loop. Defaults to 0.
+func (c *Client) SetDecodeWorkers(workers int) {
+	c.decodeWorkers = workers
+}
+
+// pipelineFrame is a frame stamped with its place in the read order by the queue
+// position of its done channel.
+type pipelineFrame struct {
+	data       []byte
+	receivedAt time.Time
+	decoded    decodedMessage
+	done       chan struct{}
+}
+
+// decodePipeline decodes frames on a set of workers and delivers them in read order
+// from a single goroutine. At most twice the worker count frames are in flight, after
+// which submit blocks the read loop.
+type decodePipeline struct {
+	client  *Client
+	frames  chan *pipelineFrame
+	ordered chan *pipelineFrame
+	wg      sync.WaitGroup
+}
+
+func newDecodePipeline(client *Client, workers int) *decodePipeline {
+	p := &decodePipeline{
+		client:  client,
+		frames:  make(chan *pipelineFrame, workers),
+		ordered: make(chan *pipelineFrame, 2*workers),
+	}
+
+	p.wg.Add(workers + 1)
+	for i := 0; i < workers; i++ {
+		go p.decode()
+	}
+	go p.deliver()
+	return p
+}
+
+// submit queues a copy of data, since the read loop reuses its buffer for the next frame.
+func (p *decodePipeline) submit(data []byte, receivedAt time.Time) {
+	frame := &pipelineFrame{
+		data:       append([]byte(nil), data...),
+		receivedAt: receivedAt,
+		done:       make(chan struct{}),
+	}
+	p.ordered <- frame
+	p.frames <- frame
+}
+
+func (p *decodePipeline) decode() {
+	defer p.wg.Done()
+	for frame := range p.frames {
+		frame.decoded = p.client.decodeMessage(frame.data, frame.receivedAt)
+		close(frame.done)
+	}
+}
+
+func (p *decodePipeline) deliver() {
+	defer p.wg.Done()
+	for frame := range p.ordered {
+		<-frame.done
+		err := p.client.deliverSerialized(frame.decoded)
+		if err != nil {
+			p.client.reportError(err)
+		}
+	}
+}
+
+// close delivers the frames already submitted and stops the pipeline.
+func (p *decodePipeline) close() {
+	close(p.frames)
+	close(p.ordered)
+	p.wg.Wait()
+}
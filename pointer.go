@@ -0,0 +1,41 @@
+package twitch
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OnEventPointer registers a callback receiving a pointer to the decoded event instead
+// of a copy, avoiding copying large events like chat messages. T is the event type, e.g.
+// OnEventPointer(client, func(event *EventChannelChatMessage, payloadContext PayloadContext) {}).
+//
+// The event is shared with the other pointer callbacks and the catch-all OnEvent
+// copy is taken from it, so it must not be modified. The client never reuses it, even
+// with event pooling, so it can be retained. It runs alongside the typed callback.
+// A nil callback removes the registration. It panics if T is not an event type.
+func OnEventPointer[T any](c *Client, callback func(event *T, payloadContext PayloadContext)) {
+	eventType := reflect.TypeOf((*T)(nil))
+
+	var found bool
+	for sub, metadata := range subMetadata {
+		if metadata.EventGen == nil || reflect.TypeOf(metadata.EventGen()) != eventType {
+			continue
+		}
+		found = true
+
+		if callback == nil {
+			delete(c.pointerHandlers, sub)
+			continue
+		}
+		if c.pointerHandlers == nil {
+			c.pointerHandlers = make(map[EventSubscription]func(event any, payloadContext PayloadContext))
+		}
+		c.pointerHandlers[sub] = func(event any, payloadContext PayloadContext) {
+			callback(event.(*T), payloadContext)
+		}
+	}
+
+	if !found {
+		panic(fmt.Sprintf("twitch: %s is not an event type", eventType.Elem()))
+	}
+}
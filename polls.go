@@ -0,0 +1,128 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// Poll is the state of a poll built from its events.
+type Poll struct {
+	EventChannelPollBegin
+
+	// Status and EndedAt are zero until the poll ends.
+	Status  PollStatus
+	EndedAt time.Time
+}
+
+func (p Poll) IsActive() bool {
+	return p.EndedAt.IsZero()
+}
+
+// Remaining returns how long the poll has left.
+func (p Poll) Remaining(now time.Time) time.Duration {
+	if !p.IsActive() || !now.Before(p.EndsAt) {
+		return 0
+	}
+	return p.EndsAt.Sub(now)
+}
+
+// Polls follows the polls of broadcasters from the channel.poll events, keeping the
+// last poll of each broadcaster once it ends. It is safe to use from concurrent
+// callbacks.
+type Polls struct {
+	mu    sync.Mutex
+	polls map[string]*Poll
+	onEnd func(poll Poll)
+}
+
+func NewPolls() *Polls {
+	return &Polls{polls: make(map[string]*Poll)}
+}
+
+// OnEnd is called with the final state of polls when they end.
+func (p *Polls) OnEnd(callback func(poll Poll)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onEnd = callback
+}
+
+func (p *Polls) Begin(event EventChannelPollBegin) Poll {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	poll := p.poll(event.BroadcasterUserId, event.ID)
+	// Progress may have come first.
+	if poll.StartedAt.IsZero() {
+		poll.EventChannelPollBegin = event
+	}
+	return *poll
+}
+
+// Progress updates the votes of the poll, ignoring events following its end, since
+// notifications may come out of order.
+func (p *Polls) Progress(event EventChannelPollProgress) Poll {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	poll := p.poll(event.BroadcasterUserId, event.ID)
+	if poll.IsActive() {
+		poll.EventChannelPollBegin = EventChannelPollBegin(event)
+	}
+	return *poll
+}
+
+// End ends the poll, calling OnEnd the first time.
+func (p *Polls) End(event EventChannelPollEnd) Poll {
+	p.mu.Lock()
+	poll := p.poll(event.BroadcasterUserId, event.ID)
+	ended := !poll.IsActive()
+	poll.EventChannelPollBegin = event.EventChannelPollBegin
+	poll.Status = event.Status
+	poll.EndedAt = event.EndedAt
+	final := *poll
+	onEnd := p.onEnd
+	p.mu.Unlock()
+
+	if !ended && onEnd != nil {
+		onEnd(final)
+	}
+	return final
+}
+
+// poll returns the poll of the broadcaster with the ID, replacing the last one if it is
+// another.
+func (p *Polls) poll(broadcasterID, id string) *Poll {
+	poll, ok := p.polls[broadcasterID]
+	if !ok || poll.ID != id {
+		poll = &Poll{EventChannelPollBegin: EventChannelPollBegin{ID: id}}
+		p.polls[broadcasterID] = poll
+	}
+	return poll
+}
+
+// Get returns the current or last poll of the broadcaster.
+func (p *Polls) Get(broadcasterID string) (Poll, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	poll, ok := p.polls[broadcasterID]
+	if !ok {
+		return Poll{}, false
+	}
+	return *poll, true
+}
+
+// Active returns the polls going on.
+func (p *Polls) Active() []Poll {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var polls []Poll
+	for _, poll := range p.polls {
+		if poll.IsActive() {
+			polls = append(polls, *poll)
+		}
+	}
+	return polls
+}
@@ -0,0 +1,54 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolls(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	polls := NewPolls()
+	var ended []Poll
+	polls.OnEnd(func(poll Poll) {
+		ended = append(ended, poll)
+	})
+
+	begin := EventChannelPollBegin{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		ID:          "poll",
+		Choices:     PollChoices{{ID: "a"}, {ID: "b"}},
+		StartedAt:   startedAt,
+		EndsAt:      startedAt.Add(time.Minute),
+	}
+	progress := EventChannelPollProgress(begin)
+	progress.Choices = PollChoices{{ID: "a", Votes: 3}, {ID: "b", Votes: 1}}
+	// The progress event comes before the begin event.
+	polls.Progress(progress)
+	poll := polls.Begin(begin)
+	if !poll.IsActive() || poll.TotalVotes() != 4 {
+		t.Errorf("unexpected poll %+v", poll)
+	}
+	if remaining := poll.Remaining(startedAt.Add(15 * time.Second)); remaining != 45*time.Second {
+		t.Errorf("expected 45s remaining got %s", remaining)
+	}
+
+	end := EventChannelPollEnd{EventChannelPollBegin: begin, Status: PollStatusCompleted, EndedAt: startedAt.Add(time.Minute)}
+	end.Choices = PollChoices{{ID: "a", Votes: 3}, {ID: "b", Votes: 5}}
+	polls.End(end)
+	polls.End(end)
+	poll = polls.Progress(progress)
+	if leader, _ := poll.Leader(); poll.IsActive() || leader.ID != "b" || poll.Status != PollStatusCompleted {
+		t.Errorf("unexpected ended poll %+v", poll)
+	}
+	if len(ended) != 1 || ended[0].ID != "poll" {
+		t.Errorf("expected OnEnd to be called once got %+v", ended)
+	}
+	if len(polls.Active()) != 0 {
+		t.Error("expected no active polls")
+	}
+
+	poll = polls.Begin(EventChannelPollBegin{Broadcaster: Broadcaster{BroadcasterUserId: "1"}, ID: "next", StartedAt: startedAt})
+	if got, _ := polls.Get("1"); !poll.IsActive() || got.ID != "next" {
+		t.Errorf("expected the next poll to replace the last got %+v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package twitch
+
+import (
+	"reflect"
+	"sync"
+)
+
+var eventPools = func() map[EventSubscription]*sync.Pool {
+	pools := make(map[EventSubscription]*sync.Pool)
+	for sub, metadata := range subMetadata {
+		if metadata.EventGen != nil {
+			sub := sub
+			pools[sub] = &sync.Pool{New: func() any { return newEventFor(sub) }}
+		}
+	}
+	return pools
+}()
+
+// SetEventPooling decodes events into structs reused from a pool instead of allocating
+// one per notification, reducing GC pressure at high event rates. Handlers receive
+// copies of the event, so structs go back to the pool as soon as they are dispatched.
+func (c *Client) SetEventPooling(enabled bool) {
+	c.eventPooling = enabled
+}
+
+func (c *Client) newEvent(subscriptionType EventSubscription) any {
+	if c.eventPooling {
+		if pool, ok := eventPools[subscriptionType]; ok {
+			return pool.Get()
+		}
+	}
+	return newEventFor(subscriptionType)
+}
+
+func (c *Client) releaseEvent(subscriptionType EventSubscription, event any) {
+	if !c.eventPooling || event == nil {
+		return
+	}
+	pool, ok := eventPools[subscriptionType]
+	if !ok {
+		return
+	}
+
+	value := reflect.ValueOf(event).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	pool.Put(event)
+}
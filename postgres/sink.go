@@ -0,0 +1,404 @@
+// Package postgres stores EventSub notifications in PostgreSQL for analytics over long
+// periods of channel activity, with the event as JSONB. It manages its schema with
+// migrations and inserts in batches. It uses database/sql, so the application registers
+// the driver it prefers, like github.com/jackc/pgx/v5/stdlib or github.com/lib/pq.
+//
+//	db, err := sql.Open("pgx", "postgres://localhost/twitch")
+//	sink, err := postgres.Open(ctx, db, postgres.Options{})
+//	client.SetPublishBridge(sink.Bridge())
+//	defer sink.Close(ctx)
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/journal"
+)
+
+// Table is the table notifications are stored in.
+const Table = "eventsub_events"
+
+// MigrationsTable records the migrations applied to the schema.
+const MigrationsTable = "eventsub_schema_migrations"
+
+// migrationLock is the key of the advisory lock serializing migrations of processes
+// starting together.
+const migrationLock = 7_466_923_011
+
+// SchemaVersion is the version of the schema once every migration is applied.
+const SchemaVersion = 1
+
+var ErrClosed = errors.New("postgres: sink closed")
+
+// migrations are applied in order, each once. Applied migrations must not change.
+var migrations = [SchemaVersion][]string{
+	{
+		`CREATE TABLE ` + Table + ` (
+	message_id TEXT PRIMARY KEY,
+	subscription_id TEXT NOT NULL,
+	subscription_type TEXT NOT NULL,
+	subscription_version TEXT NOT NULL,
+	broadcaster_user_id TEXT NOT NULL,
+	message_timestamp TIMESTAMPTZ NOT NULL,
+	received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	event JSONB NOT NULL
+)`,
+		`CREATE INDEX ` + Table + `_type_timestamp ON ` + Table + ` (subscription_type, message_timestamp)`,
+		`CREATE INDEX ` + Table + `_broadcaster_timestamp ON ` + Table + ` (broadcaster_user_id, message_timestamp)`,
+		`CREATE INDEX ` + Table + `_event ON ` + Table + ` USING GIN (event jsonb_path_ops)`,
+	},
+}
+
+// Migrate applies the migrations the database is missing. Concurrent migrations wait
+// for each other with an advisory lock, taken before the migrations table is created
+// since concurrent creations of a table fail even if it does not exist yet.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, int64(migrationLock))
+	if err != nil {
+		return fmt.Errorf("could not lock migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, int64(migrationLock))
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+MigrationsTable+` (
+	version INTEGER PRIMARY KEY,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("could not create migrations table: %w", err)
+	}
+
+	for version := 1; version <= len(migrations); version++ {
+		err = migrate(ctx, conn, version)
+		if err != nil {
+			return fmt.Errorf("could not apply migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// migrate applies a migration unless it was. The caller holds the advisory lock on the
+// connection.
+func migrate(ctx context.Context, conn *sql.Conn, version int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+MigrationsTable+` WHERE version = $1)`, version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	for _, statement := range migrations[version-1] {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO `+MigrationsTable+` (version) VALUES ($1)`, version)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type Options struct {
+	// BatchSize is how many notifications are inserted at once. Defaults to 100.
+	BatchSize int
+	// FlushInterval is the longest a notification waits for its batch. Defaults to 1
+	// second.
+	FlushInterval time.Duration
+	// Buffer is how many notifications wait to be inserted before new ones are dropped.
+	// Defaults to 10000.
+	Buffer int
+	// RetryInterval is how long a batch which could not be inserted waits before it is
+	// retried, doubling after every failure up to a minute. Notifications queue in the
+	// buffer meanwhile. Defaults to 1 second.
+	RetryInterval time.Duration
+}
+
+type row struct {
+	entry          journal.Entry
+	subscriptionID string
+}
+
+// Sink inserts notifications in the background. It implements twitch.Publisher for
+// notifications in the twitch.PublishEnvelope format.
+type Sink struct {
+	db      *sql.DB
+	options Options
+
+	rows    chan row
+	flushes chan chan error
+	done    chan struct{}
+	// abort stops retrying the last batch when Close gives up.
+	abort     chan struct{}
+	abortOnce sync.Once
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64
+	onError func(err error)
+}
+
+// Open migrates the schema and starts inserting.
+func Open(ctx context.Context, db *sql.DB, options Options) (*Sink, error) {
+	err := Migrate(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.Buffer <= 0 {
+		options.Buffer = 10000
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = time.Second
+	}
+	s := &Sink{
+		db:      db,
+		options: options,
+		rows:    make(chan row, options.Buffer),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		abort:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// OnError is called when a batch could not be inserted, every time it is retried.
+func (s *Sink) OnError(callback func(err error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = callback
+}
+
+func (s *Sink) reportError(err error) {
+	s.mu.Lock()
+	onError := s.onError
+	s.mu.Unlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// Bridge returns a publish bridge sending every notification to the sink.
+func (s *Sink) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{Publisher: s, Format: twitch.PublishEnvelope}
+}
+
+// Publish queues a notification published in the twitch.PublishEnvelope format. It
+// never blocks.
+func (s *Sink) Publish(_ context.Context, _ string, data []byte) error {
+	var published twitch.PublishedNotification
+	err := json.Unmarshal(data, &published)
+	if err != nil {
+		return fmt.Errorf("could not decode published notification: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case s.rows <- row{entry: journal.NewEntry(published), subscriptionID: published.Subscription.ID}:
+		return nil
+	default:
+		s.dropped++
+		return fmt.Errorf("postgres: buffer full, dropped %s", published.Metadata.MessageID)
+	}
+}
+
+// Dropped returns how many notifications were dropped because the buffer was full.
+func (s *Sink) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+// Flush inserts the queued notifications.
+func (s *Sink) Flush(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case s.flushes <- result:
+	case <-s.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting notifications and waits for the queued ones to be inserted,
+// retrying the batches which fail until ctx is done.
+func (s *Sink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.rows)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.abortOnce.Do(func() { close(s.abort) })
+		return ctx.Err()
+	}
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]row, 0, s.options.BatchSize)
+	// retry is set while the batch waits to be inserted again, which stops taking
+	// notifications from the buffer.
+	var retry <-chan time.Time
+	backoff := s.options.RetryInterval
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := s.insert(batch)
+		if err != nil {
+			s.reportError(err)
+			retry = time.After(backoff)
+			backoff *= 2
+			if backoff > time.Minute {
+				backoff = time.Minute
+			}
+			return err
+		}
+		batch = batch[:0]
+		retry = nil
+		backoff = s.options.RetryInterval
+		return nil
+	}
+
+	closing := false
+	for {
+		if closing && len(batch) == 0 {
+			return
+		}
+		rows := s.rows
+		if closing || retry != nil {
+			rows = nil
+		}
+
+		select {
+		case r, ok := <-rows:
+			if !ok {
+				closing = true
+				write()
+				continue
+			}
+			batch = append(batch, r)
+			if len(batch) >= s.options.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			if retry == nil {
+				write()
+			}
+		case <-retry:
+			write()
+		case result := <-s.flushes:
+			// Take what was queued before the flush with the batch.
+			var err error
+			for err == nil && !closing && len(s.rows) > 0 {
+				if len(batch) >= s.options.BatchSize {
+					err = write()
+					continue
+				}
+				r, ok := <-s.rows
+				if !ok {
+					break
+				}
+				batch = append(batch, r)
+			}
+			if err == nil {
+				err = write()
+			}
+			result <- err
+		case <-s.abort:
+			s.reportError(fmt.Errorf("postgres: closed before %d notifications could be inserted", len(batch)+len(s.rows)))
+			return
+		}
+	}
+}
+
+// insert inserts the batch with a single statement, ignoring notifications already
+// stored since Twitch can send them again.
+func (s *Sink) insert(batch []row) error {
+	var b strings.Builder
+	b.WriteString(`INSERT INTO ` + Table + ` (message_id, subscription_id, subscription_type, subscription_version, broadcaster_user_id, message_timestamp, event) VALUES `)
+	args := make([]any, 0, len(batch)*7)
+	for i, r := range batch {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for column := 0; column < 7; column++ {
+			if column > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("$" + strconv.Itoa(i*7+column+1))
+		}
+		b.WriteString(")")
+
+		event := string(r.entry.Event)
+		if event == "" {
+			event = "null"
+		}
+		args = append(args, r.entry.MessageID, r.subscriptionID, string(r.entry.SubscriptionType),
+			r.entry.SubscriptionVersion, r.entry.BroadcasterUserID, r.entry.MessageTimestamp.UTC(), event)
+	}
+	b.WriteString(` ON CONFLICT (message_id) DO NOTHING`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := s.db.ExecContext(ctx, b.String(), args...)
+	if err != nil {
+		return fmt.Errorf("could not insert %d notifications: %w", len(batch), err)
+	}
+	return nil
+}
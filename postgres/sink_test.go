@@ -0,0 +1,138 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/sqltest"
+	"github.com/isabelcoolaf/go-twitch-eventsub/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func filter(statements []string, prefix string) []int {
+	var indexes []int
+	for i, statement := range statements {
+		if strings.HasPrefix(statement, prefix) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	db, r := sqltest.Open(t)
+	applied := false
+	r.Rows = func(string, []driver.Value) ([]string, [][]driver.Value) {
+		return []string{"exists"}, [][]driver.Value{{applied}}
+	}
+	require.NoError(t, postgres.Migrate(context.Background(), db))
+
+	// The lock is taken before the migrations table is created.
+	statements := r.Statements()
+	assert.Contains(t, statements[0], "pg_advisory_lock")
+	assert.Contains(t, statements[1], "CREATE TABLE IF NOT EXISTS eventsub_schema_migrations")
+	assert.Equal(t, "BEGIN", statements[2])
+	assert.Contains(t, statements[4], "CREATE TABLE eventsub_events")
+	assert.Contains(t, strings.Join(statements, "\n"), "USING GIN (event jsonb_path_ops)")
+	require.Len(t, filter(statements, "INSERT INTO eventsub_schema_migrations"), postgres.SchemaVersion)
+	assert.Equal(t, "COMMIT", statements[len(statements)-2])
+	assert.Contains(t, statements[len(statements)-1], "pg_advisory_unlock")
+
+	// Applied migrations are skipped.
+	db, r = sqltest.Open(t)
+	applied = true
+	r.Rows = func(string, []driver.Value) ([]string, [][]driver.Value) {
+		return []string{"exists"}, [][]driver.Value{{applied}}
+	}
+	require.NoError(t, postgres.Migrate(context.Background(), db))
+	assert.Empty(t, filter(r.Statements(), "CREATE TABLE eventsub_events"))
+	assert.Empty(t, filter(r.Statements(), "COMMIT"))
+}
+
+func TestSinkBatches(t *testing.T) {
+	t.Parallel()
+
+	db, r := sqltest.Open(t)
+	r.Rows = func(string, []driver.Value) ([]string, [][]driver.Value) {
+		return []string{"exists"}, [][]driver.Value{{true}}
+	}
+	sink, err := postgres.Open(context.Background(), db, postgres.Options{BatchSize: 2, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(sink.Bridge())
+	for i := 1; i <= 3; i++ {
+		raid := twitch.EventChannelRaid{Viewers: i}
+		raid.ToBroadcasterUserId = "1337"
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, raid))
+	}
+	require.NoError(t, client.FlushPublished(context.Background()))
+	require.NoError(t, sink.Flush(context.Background()))
+
+	statements, args := r.Statements(), r.Args()
+	inserts := filter(statements, "INSERT INTO eventsub_events")
+	require.Len(t, inserts, 2)
+	assert.Contains(t, statements[inserts[0]], "($8, $9, $10, $11, $12, $13, $14) ON CONFLICT (message_id) DO NOTHING")
+	require.Len(t, args[inserts[0]], 14)
+	require.Len(t, args[inserts[1]], 7)
+
+	row := args[inserts[1]]
+	assert.Equal(t, "channel.raid", row[2])
+	assert.Equal(t, "1337", row[4])
+	assert.IsType(t, time.Time{}, row[5])
+	assert.Contains(t, row[6], `"viewers":3`)
+
+	require.NoError(t, sink.Close(context.Background()))
+	assert.ErrorIs(t, sink.Publish(context.Background(), "", []byte(`{}`)), postgres.ErrClosed)
+}
+
+func TestSinkRetries(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db, r := sqltest.Open(t)
+	r.Rows = func(string, []driver.Value) ([]string, [][]driver.Value) {
+		return []string{"exists"}, [][]driver.Value{{true}}
+	}
+	var failures atomic.Int32
+	failures.Store(2)
+	r.Err = func(query string) error {
+		if strings.HasPrefix(query, "INSERT INTO eventsub_events") && failures.Add(-1) >= 0 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	sink, err := postgres.Open(ctx, db, postgres.Options{BatchSize: 2, FlushInterval: time.Hour, RetryInterval: time.Millisecond})
+	require.NoError(t, err)
+	errs := make(chan error, 4)
+	sink.OnError(func(err error) {
+		errs <- err
+	})
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(sink.Bridge())
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: i}))
+	}
+	require.NoError(t, client.FlushPublished(ctx))
+	require.NoError(t, sink.Close(ctx))
+	assert.Len(t, errs, 2)
+	assert.ErrorContains(t, <-errs, "could not insert 2 notifications: connection refused")
+
+	// The failed batch is inserted again before the notifications after it.
+	statements, args := r.Statements(), r.Args()
+	inserts := filter(statements, "INSERT INTO eventsub_events")
+	require.Len(t, inserts, 4)
+	assert.Equal(t, args[inserts[0]], args[inserts[2]])
+	assert.Contains(t, args[inserts[3]][6], `"viewers":3`)
+}
@@ -0,0 +1,153 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// Prediction is the state of a prediction built from its events.
+type Prediction struct {
+	// EventChannelPredictionBegin holds the last outcomes. Its LockedAt is zero until
+	// the prediction locks, which it may not before ending.
+	EventChannelPredictionBegin
+
+	// Status, WinningOutcomeID, and EndedAt are zero until the prediction ends.
+	Status           PredictionStatus
+	WinningOutcomeID string
+	EndedAt          time.Time
+}
+
+func (p Prediction) IsActive() bool {
+	return p.EndedAt.IsZero()
+}
+
+// IsLocked reports whether predictions can no longer be made.
+func (p Prediction) IsLocked() bool {
+	return !p.LockedAt.IsZero() || !p.IsActive()
+}
+
+// WinningOutcome returns the outcome which won, which there is none of until the
+// prediction is resolved.
+func (p Prediction) WinningOutcome() (PredictionOutcome, bool) {
+	if p.WinningOutcomeID == "" {
+		return PredictionOutcome{}, false
+	}
+	return p.Outcomes.Get(p.WinningOutcomeID)
+}
+
+// Predictions follows the predictions of broadcasters from the channel.prediction
+// events, keeping the last prediction of each broadcaster once it ends. It is safe to
+// use from concurrent callbacks.
+type Predictions struct {
+	mu          sync.Mutex
+	predictions map[string]*Prediction
+	onEnd       func(prediction Prediction)
+}
+
+func NewPredictions() *Predictions {
+	return &Predictions{predictions: make(map[string]*Prediction)}
+}
+
+// OnEnd is called with the final state of predictions when they are resolved or
+// canceled.
+func (p *Predictions) OnEnd(callback func(prediction Prediction)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onEnd = callback
+}
+
+func (p *Predictions) Begin(event EventChannelPredictionBegin) Prediction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prediction := p.prediction(event.BroadcasterUserId, event.ID)
+	// Progress may have come first.
+	if prediction.StartedAt.IsZero() {
+		prediction.EventChannelPredictionBegin = event
+	}
+	return *prediction
+}
+
+// Progress updates the outcomes of the prediction, ignoring events following its lock
+// or end, since notifications may come out of order.
+func (p *Predictions) Progress(event EventChannelPredictionProgress) Prediction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prediction := p.prediction(event.BroadcasterUserId, event.ID)
+	if !prediction.IsLocked() {
+		prediction.EventChannelPredictionBegin = EventChannelPredictionBegin(event)
+	}
+	return *prediction
+}
+
+func (p *Predictions) Lock(event EventChannelPredictionLock) Prediction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prediction := p.prediction(event.BroadcasterUserId, event.ID)
+	if prediction.IsActive() {
+		prediction.EventChannelPredictionBegin = EventChannelPredictionBegin(event)
+	}
+	return *prediction
+}
+
+// End ends the prediction, calling OnEnd the first time.
+func (p *Predictions) End(event EventChannelPredictionEnd) Prediction {
+	p.mu.Lock()
+	prediction := p.prediction(event.BroadcasterUserId, event.ID)
+	ended := !prediction.IsActive()
+	prediction.Broadcaster = event.Broadcaster
+	prediction.Title = event.Title
+	prediction.Outcomes = event.Outcomes
+	prediction.StartedAt = event.StartedAt
+	prediction.Status = event.Status
+	prediction.WinningOutcomeID = event.WinningOutcomeID
+	prediction.EndedAt = event.EndedAt
+	final := *prediction
+	onEnd := p.onEnd
+	p.mu.Unlock()
+
+	if !ended && onEnd != nil {
+		onEnd(final)
+	}
+	return final
+}
+
+// prediction returns the prediction of the broadcaster with the ID, replacing the last
+// one if it is another.
+func (p *Predictions) prediction(broadcasterID, id string) *Prediction {
+	prediction, ok := p.predictions[broadcasterID]
+	if !ok || prediction.ID != id {
+		prediction = &Prediction{EventChannelPredictionBegin: EventChannelPredictionBegin{ID: id}}
+		p.predictions[broadcasterID] = prediction
+	}
+	return prediction
+}
+
+// Get returns the current or last prediction of the broadcaster.
+func (p *Predictions) Get(broadcasterID string) (Prediction, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prediction, ok := p.predictions[broadcasterID]
+	if !ok {
+		return Prediction{}, false
+	}
+	return *prediction, true
+}
+
+// Active returns the predictions going on, locked or not.
+func (p *Predictions) Active() []Prediction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var predictions []Prediction
+	for _, prediction := range p.predictions {
+		if prediction.IsActive() {
+			predictions = append(predictions, *prediction)
+		}
+	}
+	return predictions
+}
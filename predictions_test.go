@@ -0,0 +1,60 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictions(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	predictions := NewPredictions()
+	var ended []Prediction
+	predictions.OnEnd(func(prediction Prediction) {
+		ended = append(ended, prediction)
+	})
+
+	begin := EventChannelPredictionBegin{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		ID:          "prediction",
+		Outcomes:    PredictionOutcomes{{ID: "win"}, {ID: "lose"}},
+		StartedAt:   startedAt,
+		LocksAt:     startedAt.Add(time.Minute),
+	}
+	predictions.Begin(begin)
+	progress := EventChannelPredictionProgress(begin)
+	progress.Outcomes = PredictionOutcomes{{ID: "win", Users: 2, ChannelPoints: 200}, {ID: "lose", Users: 1, ChannelPoints: 50}}
+	predictions.Progress(progress)
+
+	lock := EventChannelPredictionLock(progress)
+	lock.LockedAt = startedAt.Add(time.Minute)
+	predictions.Lock(lock)
+	// A late progress event does not change a locked prediction.
+	prediction := predictions.Progress(EventChannelPredictionProgress(begin))
+	if !prediction.IsActive() || !prediction.IsLocked() || prediction.Outcomes.TotalChannelPoints() != 250 {
+		t.Errorf("unexpected locked prediction %+v", prediction)
+	}
+	if _, ok := prediction.WinningOutcome(); ok {
+		t.Error("expected no winning outcome before the end")
+	}
+
+	end := EventChannelPredictionEnd{
+		Broadcaster:      begin.Broadcaster,
+		ID:               "prediction",
+		WinningOutcomeID: "lose",
+		Outcomes:         progress.Outcomes,
+		Status:           PredictionStatusResolved,
+		StartedAt:        startedAt,
+		EndedAt:          startedAt.Add(time.Hour),
+	}
+	predictions.End(end)
+	prediction = predictions.End(end)
+	if winner, ok := prediction.WinningOutcome(); !ok || winner.ID != "lose" || prediction.IsActive() || !prediction.LocksAt.Equal(begin.LocksAt) {
+		t.Errorf("unexpected ended prediction %+v", prediction)
+	}
+	if len(ended) != 1 || ended[0].Status != PredictionStatusResolved {
+		t.Errorf("expected OnEnd to be called once got %+v", ended)
+	}
+	if len(predictions.Active()) != 0 {
+		t.Error("expected no active predictions")
+	}
+}
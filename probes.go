@@ -0,0 +1,88 @@
+package twitch
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const defaultKeepaliveTimeout = 10 * time.Second
+
+// Subscribe subscribes to an event on the client's current session, or on the conduit
+// of the request, and tracks the created subscriptions so they count towards Ready.
+func (c *Client) Subscribe(ctx context.Context, request SubscribeRequest) (SubscribeResponse, error) {
+	if request.SessionID == "" && request.ConduitID == "" {
+		request.SessionID = c.sessionID()
+	}
+
+	response, err := SubscribeEventUrlWithContext(ctx, request, c.SubscriptionAddress)
+	if err != nil {
+		return response, err
+	}
+
+	for _, subscription := range response.Data {
+		c.trackSubscription(subscription)
+	}
+	return response, nil
+}
+
+// Unsubscribe deletes a subscription and stops tracking it.
+func (c *Client) Unsubscribe(ctx context.Context, request UnsubscribeRequest) error {
+	err := UnsubscribeEventUrlWithContext(ctx, request, c.SubscriptionAddress)
+	if err != nil {
+		return err
+	}
+
+	c.removeSubscription(request.ID)
+	return nil
+}
+
+// Ready reports whether the welcome message was received and at least one
+// subscription of the session is enabled, whether it was created with Subscribe or
+// SubscribeEvent and its variants, or seen in a notification.
+func (c *Client) Ready() bool {
+	if !c.connected.Load() || c.sessionID() == "" {
+		return false
+	}
+
+	for _, subscription := range c.Subscriptions() {
+		if subscription.Status == "enabled" {
+			return true
+		}
+	}
+	return false
+}
+
+// Live reports whether a message was received within the session keepalive window.
+func (c *Client) Live() bool {
+	if !c.connected.Load() {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timeout := defaultKeepaliveTimeout
+	if c.session.KeepaliveTimeoutSeconds > 0 {
+		timeout = time.Duration(c.session.KeepaliveTimeoutSeconds) * time.Second
+	}
+	return c.now().Sub(c.lastMessageAt) <= timeout
+}
+
+func (c *Client) setLastMessage(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastMessageAt = at
+}
+
+// ProbeHandler responds 200 when check returns true and 503 otherwise, e.g.
+// ProbeHandler(client.Ready) for a readiness probe.
+func ProbeHandler(check func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !check() {
+			http.Error(w, "not ok", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,550 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Events are encoded as the messages of events.proto, generated from the event structs
+// by EventProtoDefinitions. Each struct is a message of the same name whose fields are
+// numbered in declaration order, with the fields of embedded structs inlined, so fields
+// must only be appended to keep the numbers of stored events. Times are
+// google.protobuf.Timestamp messages, raw JSON is bytes, pointers to scalars are
+// optional fields, and batched events like EventDropEntitlementGrantBatch are a message
+// holding the repeated items field.
+
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+type protoField struct {
+	number int
+	name   string
+	index  []int
+	typ    reflect.Type
+}
+
+var protoFieldCache sync.Map
+
+// protoFields returns the fields of a struct encoded like encoding/json encodes them,
+// numbered from 1.
+func protoFields(t reflect.Type) []protoField {
+	if fields, ok := protoFieldCache.Load(t); ok {
+		return fields.([]protoField)
+	}
+
+	fields := appendProtoFields(nil, t, nil, nil)
+	for i := range fields {
+		fields[i].number = i + 1
+	}
+	protoFieldCache.Store(t, fields)
+	return fields
+}
+
+// appendProtoFields appends the fields of t, leaving out those named in shadowed since
+// a field of an outer struct has the name.
+func appendProtoFields(fields []protoField, t reflect.Type, index []int, shadowed map[string]bool) []protoField {
+	own := make(map[string]bool, len(shadowed)+t.NumField())
+	for name := range shadowed {
+		own[name] = true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := protoFieldName(t.Field(i)); ok && !isEmbeddedStruct(t.Field(i), name) {
+			own[name] = true
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := protoFieldName(field)
+		if !ok {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if isEmbeddedStruct(field, name) {
+			fields = appendProtoFields(fields, field.Type, fieldIndex, own)
+			continue
+		}
+		if shadowed[name] {
+			continue
+		}
+		fields = append(fields, protoField{name: name, index: fieldIndex, typ: field.Type})
+	}
+	return fields
+}
+
+func protoFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+		return "", true
+	}
+	if !field.IsExported() {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func isEmbeddedStruct(field reflect.StructField, name string) bool {
+	return field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct
+}
+
+var (
+	protoEventTypesOnce sync.Once
+	protoEventTypes     map[reflect.Type]bool
+)
+
+func isProtoEventType(t reflect.Type) bool {
+	protoEventTypesOnce.Do(func() {
+		protoEventTypes = make(map[reflect.Type]bool)
+		for _, metadata := range subMetadata {
+			if metadata.EventGen != nil {
+				protoEventTypes[reflect.TypeOf(metadata.EventGen()).Elem()] = true
+			}
+		}
+	})
+	return protoEventTypes[t]
+}
+
+// MarshalEventProto encodes an event, like EventChannelFollow, as the message of the
+// same name in events.proto, for compact storage or to hand it to other languages.
+func MarshalEventProto(event any) ([]byte, error) {
+	v := reflect.ValueOf(event)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || !isProtoEventType(v.Type()) {
+		return nil, fmt.Errorf("%T is not an event type", event)
+	}
+
+	if v.Kind() == reflect.Slice {
+		return appendProtoValue(nil, 1, v, false), nil
+	}
+	return appendProtoMessage(nil, v), nil
+}
+
+// UnmarshalEventProto decodes the event of a subscription type encoded by
+// MarshalEventProto, or by any implementation of events.proto. It returns the event
+// like OnEvent passes it, so a channel.follow event is an EventChannelFollow.
+func UnmarshalEventProto(event EventSubscription, data []byte) (any, error) {
+	metadata, ok := subMetadata[event]
+	if !ok || metadata.EventGen == nil {
+		return nil, fmt.Errorf("unknown subscription type %s", event)
+	}
+
+	v := reflect.ValueOf(metadata.EventGen()).Elem()
+	var err error
+	if v.Kind() == reflect.Slice {
+		err = decodeProtoFields(data, func(number int) (reflect.Value, bool) {
+			return v, number == 1
+		})
+	} else {
+		err = decodeProtoMessage(data, v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", v.Type().Name(), err)
+	}
+	return v.Interface(), nil
+}
+
+func appendProtoMessage(b []byte, v reflect.Value) []byte {
+	for _, field := range protoFields(v.Type()) {
+		b = appendProtoValue(b, field.number, v.FieldByIndex(field.index), false)
+	}
+	return b
+}
+
+func appendProtoKey(b []byte, number int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+func appendProtoBytes(b []byte, number int, value []byte) []byte {
+	b = appendProtoKey(b, number, protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+func appendProtoVarint(b []byte, number int, value uint64) []byte {
+	b = appendProtoKey(b, number, protoVarint)
+	return binary.AppendUvarint(b, value)
+}
+
+// appendProtoValue appends v as field number. Zero values are left out like proto3
+// does, unless explicit is set for the values of pointers and repeated fields.
+func appendProtoValue(b []byte, number int, v reflect.Value, explicit bool) []byte {
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() && !explicit {
+			return b
+		}
+		var timestamp []byte
+		if seconds := t.Unix(); seconds != 0 {
+			timestamp = appendProtoVarint(timestamp, 1, uint64(seconds))
+		}
+		if nanos := t.Nanosecond(); nanos != 0 {
+			timestamp = appendProtoVarint(timestamp, 2, uint64(nanos))
+		}
+		return appendProtoBytes(b, number, timestamp)
+	case rawMessageType:
+		if v.Len() == 0 && !explicit {
+			return b
+		}
+		return appendProtoBytes(b, number, v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return b
+		}
+		return appendProtoValue(b, number, v.Elem(), v.Elem().Kind() != reflect.Slice)
+	case reflect.String:
+		if v.Len() == 0 && !explicit {
+			return b
+		}
+		return appendProtoBytes(b, number, []byte(v.String()))
+	case reflect.Bool:
+		if !v.Bool() && !explicit {
+			return b
+		}
+		var value uint64
+		if v.Bool() {
+			value = 1
+		}
+		return appendProtoVarint(b, number, value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 && !explicit {
+			return b
+		}
+		return appendProtoVarint(b, number, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 && !explicit {
+			return b
+		}
+		return appendProtoVarint(b, number, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		if v.Float() == 0 && !explicit {
+			return b
+		}
+		b = appendProtoKey(b, number, protoFixed64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Float()))
+	case reflect.Struct:
+		message := appendProtoMessage(nil, v)
+		if len(message) == 0 && !explicit {
+			return b
+		}
+		return appendProtoBytes(b, number, message)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			b = appendProtoValue(b, number, v.Index(i), true)
+		}
+	}
+	return b
+}
+
+func decodeProtoMessage(data []byte, v reflect.Value) error {
+	fields := protoFields(v.Type())
+	return decodeProtoFields(data, func(number int) (reflect.Value, bool) {
+		if number < 1 || number > len(fields) {
+			return reflect.Value{}, false
+		}
+		return v.FieldByIndex(fields[number-1].index), true
+	})
+}
+
+// decodeProtoFields decodes the fields of a message into the values returned by field,
+// skipping unknown fields.
+func decodeProtoFields(data []byte, field func(number int) (reflect.Value, bool)) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+		number, wireType := int(key>>3), int(key&7)
+
+		var varint uint64
+		var payload []byte
+		switch wireType {
+		case protoVarint:
+			varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+			data = data[n:]
+		case protoFixed64, protoFixed32:
+			size := 8
+			if wireType == protoFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errProtoTruncated
+			}
+			payload, data = data[:size], data[size:]
+		case protoBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errProtoTruncated
+			}
+			payload, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wireType)
+		}
+
+		v, ok := field(number)
+		if !ok {
+			continue
+		}
+		err := decodeProtoValue(v, wireType, varint, payload)
+		if err != nil {
+			return fmt.Errorf("field %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+func decodeProtoValue(v reflect.Value, wireType int, varint uint64, payload []byte) error {
+	switch v.Type() {
+	case timeType:
+		if wireType != protoBytes {
+			return fmt.Errorf("unexpected wire type %d for a timestamp", wireType)
+		}
+		var seconds, nanos int64
+		err := decodeProtoFields(payload, func(number int) (reflect.Value, bool) {
+			switch number {
+			case 1:
+				return reflect.ValueOf(&seconds).Elem(), true
+			case 2:
+				return reflect.ValueOf(&nanos).Elem(), true
+			}
+			return reflect.Value{}, false
+		})
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(time.Unix(seconds, nanos).UTC()))
+		return nil
+	case rawMessageType:
+		if wireType != protoBytes {
+			return fmt.Errorf("unexpected wire type %d for bytes", wireType)
+		}
+		v.SetBytes(append([]byte(nil), payload...))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeProtoValue(v.Elem(), wireType, varint, payload)
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		if wireType == protoBytes && isPackableProtoKind(elem) {
+			return decodeProtoPacked(v, payload)
+		}
+		item := reflect.New(elem).Elem()
+		err := decodeProtoValue(item, wireType, varint, payload)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, item))
+		return nil
+	case reflect.String:
+		if wireType != protoBytes {
+			return fmt.Errorf("unexpected wire type %d for a string", wireType)
+		}
+		v.SetString(string(payload))
+		return nil
+	case reflect.Struct:
+		if wireType != protoBytes {
+			return fmt.Errorf("unexpected wire type %d for a message", wireType)
+		}
+		return decodeProtoMessage(payload, v)
+	case reflect.Float32, reflect.Float64:
+		switch wireType {
+		case protoFixed64:
+			v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(payload)))
+		case protoFixed32:
+			v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(payload))))
+		default:
+			return fmt.Errorf("unexpected wire type %d for a number", wireType)
+		}
+		return nil
+	}
+
+	if wireType != protoVarint {
+		return fmt.Errorf("unexpected wire type %d for %s", wireType, v.Kind())
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(varint != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(varint))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(varint)
+	}
+	return nil
+}
+
+func isPackableProtoKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// decodeProtoPacked decodes repeated scalars, which other implementations pack into a
+// single field.
+func decodeProtoPacked(v reflect.Value, payload []byte) error {
+	elem := v.Type().Elem()
+	for len(payload) > 0 {
+		item := reflect.New(elem).Elem()
+		if elem.Kind() == reflect.Float32 || elem.Kind() == reflect.Float64 {
+			if len(payload) < 8 {
+				return errProtoTruncated
+			}
+			item.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(payload)))
+			payload = payload[8:]
+		} else {
+			varint, n := binary.Uvarint(payload)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+			payload = payload[n:]
+			err := decodeProtoValue(item, protoVarint, varint, nil)
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(reflect.Append(v, item))
+	}
+	return nil
+}
+
+// EventProtoDefinitions returns events.proto, the proto3 definitions of the messages
+// MarshalEventProto encodes events as.
+func EventProtoDefinitions() []byte {
+	messages := make(map[string]reflect.Type)
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice:
+			if t != rawMessageType {
+				collect(t.Elem())
+			}
+		case reflect.Struct:
+			if t == timeType || messages[t.Name()] != nil {
+				return
+			}
+			messages[t.Name()] = t
+			for _, field := range protoFields(t) {
+				collect(field.typ)
+			}
+		}
+	}
+	for _, metadata := range subMetadata {
+		if metadata.EventGen == nil {
+			continue
+		}
+		t := reflect.TypeOf(metadata.EventGen()).Elem()
+		if t.Kind() == reflect.Slice {
+			messages[t.Name()] = t
+			collect(t.Elem())
+			continue
+		}
+		collect(t)
+	}
+
+	names := make([]string, 0, len(messages))
+	for name := range messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by protogen from the event structs. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\npackage twitch.eventsub.v1;\n\n")
+	b.WriteString("import \"google/protobuf/timestamp.proto\";\n")
+	for _, name := range names {
+		t := messages[name]
+		fmt.Fprintf(&b, "\nmessage %s {\n", name)
+		if t.Kind() == reflect.Slice {
+			fmt.Fprintf(&b, "  repeated %s items = 1;\n", protoTypeName(t.Elem()))
+		} else {
+			for _, field := range protoFields(t) {
+				fmt.Fprintf(&b, "  %s%s %s = %d;\n", protoLabel(field.typ), protoTypeName(field.typ), field.name, field.number)
+			}
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+func protoLabel(t reflect.Type) string {
+	if t == rawMessageType {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "repeated "
+	case reflect.Pointer:
+		switch {
+		case t.Elem().Kind() == reflect.Slice:
+			return protoLabel(t.Elem())
+		case t.Elem().Kind() != reflect.Struct:
+			return "optional "
+		}
+	}
+	return ""
+}
+
+func protoTypeName(t reflect.Type) string {
+	switch t {
+	case timeType:
+		return "google.protobuf.Timestamp"
+	case rawMessageType:
+		return "bytes"
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		return protoTypeName(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int64"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint64"
+	case reflect.Float32, reflect.Float64:
+		return "double"
+	case reflect.Struct:
+		return t.Name()
+	}
+	return "bytes"
+}
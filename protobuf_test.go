@@ -0,0 +1,117 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventProtoIsGenerated(t *testing.T) {
+	data, err := os.ReadFile("events.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, EventProtoDefinitions()) {
+		t.Error("events.proto is out of date, run go generate")
+	}
+}
+
+func TestEventProtoRoundTrip(t *testing.T) {
+	var tested int
+	for key, payload := range loadFixtures(t) {
+		event, _, _ := strings.Cut(key, "-")
+		metadata, ok := subMetadata[EventSubscription(event)]
+		if !ok || metadata.EventGen == nil {
+			continue
+		}
+		tested++
+
+		want := metadata.EventGen()
+		if err := json.Unmarshal(payload, want); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		data, err := MarshalEventProto(want)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+
+		got, err := UnmarshalEventProto(EventSubscription(event), data)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(want).Elem() {
+			t.Fatalf("%s: expected %T got %T", key, want, got)
+		}
+		again, err := MarshalEventProto(got)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("%s: event changed after decoding", key)
+		}
+	}
+	if tested == 0 {
+		t.Fatal("no fixtures tested")
+	}
+}
+
+func TestEventProtoFields(t *testing.T) {
+	reason := ""
+	want := EventChannelChatMessage{
+		MessageId: "id",
+		Message: ChatMessage{
+			Text:      "Hi chat",
+			Fragments: []ChatMessageFragment{{Type: "text", Text: "Hi chat"}},
+		},
+		Badges: ChatBadges{{SetId: "moderator", Id: "1"}},
+	}
+	want.BroadcasterUserId = "1971641"
+	want.ChatterUserLogin = "viewer32"
+
+	data, err := MarshalEventProto(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalEventProto(SubChannelChatMessage, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v got %+v", want, got)
+	}
+
+	ban := EventChannelModerate{Action: "ban", Ban: &Ban{Reason: &reason}}
+	data, err = MarshalEventProto(ban)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalEventProto(SubChannelModerate, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moderate := decoded.(EventChannelModerate)
+	if moderate.Ban == nil || moderate.Ban.Reason == nil || *moderate.Ban.Reason != "" {
+		t.Errorf("expected an empty reason to be kept, got %+v", moderate.Ban)
+	}
+
+	startedAt := time.Date(2024, 2, 3, 4, 5, 6, 7, time.UTC)
+	data, err = MarshalEventProto(EventStreamOnline{StartedAt: startedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = UnmarshalEventProto(SubStreamOnline, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.(EventStreamOnline).StartedAt.Equal(startedAt) {
+		t.Errorf("expected %s got %s", startedAt, decoded.(EventStreamOnline).StartedAt)
+	}
+
+	if _, err := MarshalEventProto(Ban{}); err == nil {
+		t.Error("expected an error for a struct that is not an event")
+	}
+}
@@ -0,0 +1,320 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Publisher publishes data to a subject of a message bus, like NATS.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// NotificationPublisher is implemented by publishers which store the notification
+// itself, like the journal. With the PublishEnvelope format, the bridge passes them the
+// notification instead of encoding it for Publish to decode again.
+type NotificationPublisher interface {
+	PublishNotification(ctx context.Context, subject string, notification PublishedNotification) error
+}
+
+// PublisherFunc adapts a function to a Publisher.
+type PublisherFunc func(ctx context.Context, subject string, data []byte) error
+
+func (f PublisherFunc) Publish(ctx context.Context, subject string, data []byte) error {
+	return f(ctx, subject, data)
+}
+
+// MultiPublisher publishes to every publisher at once, so a slow publisher does not
+// delay the others, and waits for all of them. When some fail, the error is a
+// PublishErrors.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	errs := make([]error, len(m))
+	if len(m) == 1 {
+		errs[0] = m[0].Publish(ctx, subject, data)
+	} else {
+		var wg sync.WaitGroup
+		for i, publisher := range m {
+			wg.Add(1)
+			go func(i int, publisher Publisher) {
+				defer wg.Done()
+				errs[i] = publisher.Publish(ctx, subject, data)
+			}(i, publisher)
+		}
+		wg.Wait()
+	}
+
+	var failed PublishErrors
+	for i, err := range errs {
+		if err != nil {
+			if failed == nil {
+				failed = make(PublishErrors)
+			}
+			failed[i] = err
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+	return nil
+}
+
+// PublishErrors are the errors of the publishers of a MultiPublisher which failed, by
+// their index.
+type PublishErrors map[int]error
+
+// Indexes returns the indexes of the publishers which failed, in order.
+func (e PublishErrors) Indexes() []int {
+	indexes := make([]int, 0, len(e))
+	for i := range e {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func (e PublishErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, i := range e.Indexes() {
+		messages = append(messages, fmt.Sprintf("publisher %d: %v", i, e[i]))
+	}
+	return strings.Join(messages, "; ")
+}
+
+type PublishFormat int
+
+const (
+	// PublishRaw publishes the event of the notification as Twitch sent it.
+	PublishRaw PublishFormat = iota
+	// PublishEnvelope publishes a PublishedNotification, keeping the metadata and
+	// subscription of the notification with its event.
+	PublishEnvelope
+	// PublishMsgpack publishes a PublishedNotification encoded as MessagePack, which is
+	// smaller than the JSON of PublishEnvelope. See MarshalMsgpack.
+	PublishMsgpack
+)
+
+// PublishedNotification is the data published in the PublishEnvelope format.
+type PublishedNotification struct {
+	Metadata     MessageMetadata     `json:"metadata"`
+	Subscription PayloadSubscription `json:"subscription"`
+	Event        json.RawMessage     `json:"event"`
+	// Enrichment holds the data added by the Enrich function of the bridge, by name.
+	Enrichment map[string]json.RawMessage `json:"enrichment,omitempty"`
+}
+
+// PublishBridge forwards every notification to a Publisher.
+type PublishBridge struct {
+	Publisher Publisher
+	Format    PublishFormat
+	// Subject returns the subject of a notification. Defaults to DefaultPublishSubject.
+	Subject func(subscription PayloadSubscription) string
+	// Timeout bounds each publish, enrichment included. Defaults to 5 seconds.
+	Timeout time.Duration
+	// QueueSize is how many notifications wait to be published before new ones are
+	// dropped. Defaults to 1000.
+	QueueSize int
+	// Enrich returns data to add to the Enrichment of the notification by name, like
+	// the profiles of the users of the event, so consumers get denormalized events. It
+	// runs on the publish queue before publishing, so it should cache what it looks up
+	// to keep the queue from filling up.
+	// Only the envelope formats carry the enrichment. When it fails the error is passed
+	// to OnError and the notification is published with what it returned.
+	Enrich func(ctx context.Context, notification PublishedNotification) (map[string]any, error)
+}
+
+// DefaultPublishSubject returns twitch.eventsub.<subscription type>, like
+// twitch.eventsub.channel.follow.
+func DefaultPublishSubject(subscription PayloadSubscription) string {
+	return "twitch.eventsub." + string(subscription.Type)
+}
+
+// ErrPublishQueueFull is passed to OnError when a notification is dropped because
+// QueueSize notifications are already waiting to be published.
+var ErrPublishQueueFull = errors.New("publish queue is full")
+
+// SetPublishBridge publishes every notification received, before it is dispatched, so
+// events can fan out to other services. Notifications are queued and published in order
+// by one goroutine, so a slow publisher or Enrich doesn't hold up the read loop. Publish
+// errors are passed to OnError. Call FlushPublished before exiting to wait for the
+// notifications still queued.
+func (c *Client) SetPublishBridge(bridge *PublishBridge) {
+	c.publishBridge = bridge
+}
+
+// FlushPublished waits until the notifications queued by the publish bridge are
+// published, or until the context is done.
+func (c *Client) FlushPublished(ctx context.Context) error {
+	return c.publishQueue.wait(ctx)
+}
+
+type publishJob struct {
+	ctx          context.Context
+	bridge       *PublishBridge
+	data         json.RawMessage
+	metadata     MessageMetadata
+	subscription PayloadSubscription
+}
+
+func (c *Client) publish(data json.RawMessage, metadata MessageMetadata, subscription PayloadSubscription) {
+	bridge := c.publishBridge
+	if bridge == nil {
+		return
+	}
+
+	size := bridge.QueueSize
+	if size <= 0 {
+		size = 1000
+	}
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	job := publishJob{ctx: parent, bridge: bridge, data: data, metadata: metadata, subscription: subscription}
+	if !c.publishQueue.push(job, size, c.publishJob) {
+		c.reportError(c.newMessageError(metadata, &subscription, ErrPublishQueueFull))
+	}
+}
+
+func (c *Client) publishJob(job publishJob) {
+	bridge := job.bridge
+	metadata := job.metadata
+	subscription := job.subscription
+	data := job.data
+
+	timeout := bridge.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(job.ctx, timeout)
+	defer cancel()
+
+	subject := DefaultPublishSubject
+	if bridge.Subject != nil {
+		subject = bridge.Subject
+	}
+
+	if bridge.Format == PublishEnvelope || bridge.Format == PublishMsgpack {
+		published := PublishedNotification{
+			Metadata:     metadata,
+			Subscription: subscription,
+			Event:        data,
+		}
+		if bridge.Enrich != nil {
+			published.Enrichment = c.enrich(ctx, bridge, published)
+		}
+
+		if publisher, ok := bridge.Publisher.(NotificationPublisher); ok && bridge.Format == PublishEnvelope {
+			err := publisher.PublishNotification(ctx, subject(subscription), published)
+			if err != nil {
+				c.reportError(c.newMessageError(metadata, &subscription, fmt.Errorf("could not publish notification: %w", err)))
+			}
+			return
+		}
+
+		var err error
+		data, err = json.Marshal(published)
+		if err == nil && bridge.Format == PublishMsgpack {
+			data, err = jsonToMsgpack(data)
+		}
+		if err != nil {
+			c.reportError(c.newMessageError(metadata, &subscription, fmt.Errorf("could not encode published notification: %w", err)))
+			return
+		}
+	}
+
+	err := bridge.Publisher.Publish(ctx, subject(subscription), data)
+	if err != nil {
+		c.reportError(c.newMessageError(metadata, &subscription, fmt.Errorf("could not publish notification: %w", err)))
+	}
+}
+
+// publishQueue runs jobs in order on a goroutine started when the first job is queued,
+// which exits once the queue is empty.
+type publishQueue struct {
+	mu      sync.Mutex
+	jobs    []publishJob
+	running bool
+	// idle is closed when the goroutine exits.
+	idle chan struct{}
+}
+
+// push queues the job unless size jobs are already waiting.
+func (q *publishQueue) push(job publishJob, size int, run func(publishJob)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) >= size {
+		return false
+	}
+	q.jobs = append(q.jobs, job)
+	if !q.running {
+		q.running = true
+		q.idle = make(chan struct{})
+		go q.work(run)
+	}
+	return true
+}
+
+func (q *publishQueue) work(run func(publishJob)) {
+	for {
+		q.mu.Lock()
+		if len(q.jobs) == 0 {
+			q.jobs = nil
+			q.running = false
+			close(q.idle)
+			q.mu.Unlock()
+			return
+		}
+		job := q.jobs[0]
+		q.jobs[0] = publishJob{}
+		q.jobs = q.jobs[1:]
+		q.mu.Unlock()
+
+		run(job)
+	}
+}
+
+func (q *publishQueue) wait(ctx context.Context) error {
+	q.mu.Lock()
+	running, idle := q.running, q.idle
+	q.mu.Unlock()
+	if !running {
+		return nil
+	}
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) enrich(ctx context.Context, bridge *PublishBridge, published PublishedNotification) map[string]json.RawMessage {
+	values, err := bridge.Enrich(ctx, published)
+	if err != nil {
+		c.reportError(c.newMessageError(published.Metadata, &published.Subscription, fmt.Errorf("could not enrich notification: %w", err)))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	enrichment := make(map[string]json.RawMessage, len(values))
+	for name, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			c.reportError(c.newMessageError(published.Metadata, &published.Subscription, fmt.Errorf("could not encode enrichment %s: %w", name, err)))
+			continue
+		}
+		enrichment[name] = data
+	}
+	return enrichment
+}
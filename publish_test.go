@@ -0,0 +1,105 @@
+package twitch_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiPublisher(t *testing.T) {
+	t.Parallel()
+
+	var published int32
+	ok := twitch.PublisherFunc(func(ctx context.Context, subject string, data []byte) error {
+		assert.Equal(t, "subject", subject)
+		atomic.AddInt32(&published, 1)
+		return nil
+	})
+	failing := twitch.PublisherFunc(func(ctx context.Context, subject string, data []byte) error {
+		return errors.New("unavailable")
+	})
+	slow := twitch.PublisherFunc(func(ctx context.Context, subject string, data []byte) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.NoError(t, twitch.MultiPublisher{ok, ok}.Publish(context.Background(), "subject", nil))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&published))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := twitch.MultiPublisher{slow, ok, failing}.Publish(ctx, "subject", nil)
+	var errs twitch.PublishErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, []int{0, 2}, errs.Indexes())
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&published), "a failing publisher must not keep the others from publishing")
+	assert.Equal(t, "publisher 0: context deadline exceeded; publisher 2: unavailable", err.Error())
+}
+
+func TestPublishBridgeQueue(t *testing.T) {
+	t.Parallel()
+
+	publishing := make(chan string, 2)
+	release := make(chan struct{})
+	publisher := twitch.PublisherFunc(func(ctx context.Context, subject string, data []byte) error {
+		publishing <- string(data)
+		<-release
+		return nil
+	})
+	client := twitch.NewClient()
+	errs := make(chan error, 1)
+	client.OnError(func(err error) { errs <- err })
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher, QueueSize: 1})
+
+	// The publisher blocking doesn't hold up the notifications.
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 1}))
+	assert.Contains(t, <-publishing, `"viewers":1`)
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 2}))
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 3}))
+	assert.ErrorIs(t, <-errs, twitch.ErrPublishQueueFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.FlushPublished(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, client.FlushPublished(context.Background()))
+	assert.Contains(t, <-publishing, `"viewers":2`)
+	assert.Empty(t, publishing)
+}
+
+type notificationPublisher struct {
+	t         *testing.T
+	published chan twitch.PublishedNotification
+}
+
+func (p notificationPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	p.t.Error("the notification was encoded for a NotificationPublisher")
+	return nil
+}
+
+func (p notificationPublisher) PublishNotification(ctx context.Context, subject string, notification twitch.PublishedNotification) error {
+	assert.Equal(p.t, "twitch.eventsub.channel.raid", subject)
+	p.published <- notification
+	return nil
+}
+
+func TestPublishBridgeNotificationPublisher(t *testing.T) {
+	t.Parallel()
+
+	publisher := notificationPublisher{t: t, published: make(chan twitch.PublishedNotification, 1)}
+	client := twitch.NewClient()
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher, Format: twitch.PublishEnvelope})
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+
+	published := <-publisher.published
+	assert.Equal(t, twitch.SubChannelRaid, published.Subscription.Type)
+	assert.Contains(t, string(published.Event), `"viewers":42`)
+}
@@ -0,0 +1,38 @@
+package twitch
+
+import (
+	"bytes"
+	"context"
+)
+
+const defaultMaxReadBuffer = 64 * 1024
+
+// SetMaxReadBuffer sets the largest read buffer kept for reuse between frames. A frame
+// larger than max is still read, but its buffer is dropped afterwards so one burst of
+// large frames doesn't pin memory. A max of 0 or less allocates a new buffer per frame.
+// Defaults to 64KiB.
+func (c *Client) SetMaxReadBuffer(max int) {
+	c.maxReadBuffer = max
+}
+
+// readFrame reads the next frame into buf. The returned data is only valid until the
+// next call.
+func (c *Client) readFrame(ctx context.Context, buf *bytes.Buffer) ([]byte, error) {
+	if c.maxReadBuffer <= 0 || buf.Cap() > c.maxReadBuffer {
+		*buf = bytes.Buffer{}
+	} else {
+		buf.Reset()
+	}
+
+	_, reader, err := c.ws.Reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := c.stageTimer.start()
+	_, err = buf.ReadFrom(reader)
+	if err != nil {
+		return nil, err
+	}
+	c.stageTimer.observe(stageRead, start)
+	return buf.Bytes(), nil
+}
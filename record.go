@@ -0,0 +1,157 @@
+package twitch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type RecordedFrame struct {
+	ReceivedAt time.Time `json:"received_at"`
+	Frame      string    `json:"frame"`
+}
+
+// Recorder writes every frame received by a client as a line of JSON.
+type Recorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+func (r *Recorder) Record(receivedAt time.Time, frame []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.encoder.Encode(RecordedFrame{
+		ReceivedAt: receivedAt,
+		Frame:      string(frame),
+	})
+	if err != nil {
+		return fmt.Errorf("could not record frame: %w", err)
+	}
+	return nil
+}
+
+// SetRecorder records every frame read from the websocket.
+func (c *Client) SetRecorder(recorder *Recorder) {
+	c.recorder = recorder
+}
+
+func (c *Client) record(frame []byte) {
+	if c.recorder == nil {
+		return
+	}
+
+	err := c.recorder.Record(c.now(), frame)
+	if err != nil {
+		c.reportError(err)
+	}
+}
+
+// Replay feeds recorded frames through the client. A speed of 1 keeps the original
+// pacing, 2 replays twice as fast, and 0 replays as fast as possible. Errors from
+// handling frames are passed to OnError.
+func Replay(ctx context.Context, client *Client, r io.Reader, speed float64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var previous time.Time
+	for scanner.Scan() {
+		var frame RecordedFrame
+		err := json.Unmarshal(scanner.Bytes(), &frame)
+		if err != nil {
+			return fmt.Errorf("could not parse recorded frame: %w", err)
+		}
+
+		err = client.replayWait(ctx, previous, frame.ReceivedAt, speed)
+		if err != nil {
+			return err
+		}
+		previous = frame.ReceivedAt
+
+		err = client.InjectMessage([]byte(frame.Frame))
+		if err != nil {
+			client.reportError(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("could not read recorded frames: %w", err)
+	}
+	return nil
+}
+
+// ReplayPublished feeds notifications published in the PublishEnvelope format, one per
+// line like the archive package writes them, through the client with their original
+// metadata, so new handlers can be checked against past traffic. The speed is the same
+// as for Replay, paced by the timestamps of the notifications. Since those timestamps
+// are old, replaying skews the latency statistics of the client, so replays are best
+// run on a client of their own.
+func ReplayPublished(ctx context.Context, client *Client, r io.Reader, speed float64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var previous time.Time
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var published PublishedNotification
+		err := json.Unmarshal(scanner.Bytes(), &published)
+		if err != nil {
+			return fmt.Errorf("could not parse published notification: %w", err)
+		}
+
+		err = ReplayPublishedNotification(ctx, client, published, previous, speed)
+		if err != nil {
+			return err
+		}
+		previous = published.Metadata.MessageTimestamp
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("could not read published notifications: %w", err)
+	}
+	return nil
+}
+
+// ReplayPublishedNotification waits for the time between the previous notification
+// replayed and this one, divided by the speed, then injects it. Errors from handling it
+// are passed to OnError. It lets other stores of notifications pace replays like
+// ReplayPublished.
+func ReplayPublishedNotification(ctx context.Context, client *Client, published PublishedNotification, previous time.Time, speed float64) error {
+	err := client.replayWait(ctx, previous, published.Metadata.MessageTimestamp, speed)
+	if err != nil {
+		return err
+	}
+
+	err = client.InjectPublished(published)
+	if err != nil {
+		client.reportError(err)
+	}
+	return nil
+}
+
+func (c *Client) replayWait(ctx context.Context, previous, next time.Time, speed float64) error {
+	if speed <= 0 || previous.IsZero() {
+		return ctx.Err()
+	}
+
+	wait := time.Duration(float64(next.Sub(previous)) / speed)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.after(wait):
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package twitch_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var recording bytes.Buffer
+	client := newClient(t, keepAliveGen)
+	client.SetRecorder(twitch.NewRecorder(&recording))
+	client.OnKeepAlive(func(message twitch.KeepAliveMessage, _ twitch.MessageMetadata) {
+		client.Close()
+	})
+	connect(t, client)
+
+	assertEventOccured(t, func(ch chan struct{}) {
+		replayClient := twitch.NewClient()
+		replayClient.OnError(func(err error) {
+			t.Errorf("replay registered an error: %v", err)
+		})
+		replayClient.OnKeepAlive(func(message twitch.KeepAliveMessage, _ twitch.MessageMetadata) {
+			assert.NotEmpty(t, replayClient.Session().ID)
+			close(ch)
+		})
+
+		err := twitch.Replay(context.Background(), replayClient, &recording, 0)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReplayPublished(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	var archive bytes.Buffer
+	for i, viewers := range []int{42, 7} {
+		published := twitch.PublishedNotification{
+			Metadata: twitch.MessageMetadata{
+				MessageID:        fmt.Sprintf("message-%d", i),
+				MessageType:      "notification",
+				MessageTimestamp: first.Add(time.Duration(i) * time.Minute),
+			},
+			Event: json.RawMessage(fmt.Sprintf(`{"viewers":%d}`, viewers)),
+		}
+		published.Subscription.Type = twitch.SubChannelRaid
+		published.Subscription.Version = "1"
+		require.NoError(t, json.NewEncoder(&archive).Encode(published))
+	}
+
+	clock := &replayClock{}
+	client := twitch.NewClient()
+	client.SetClock(clock)
+	client.SetSynchronousDispatch(true)
+	client.OnError(func(err error) {
+		t.Errorf("replay registered an error: %v", err)
+	})
+	var ids []string
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, payloadContext twitch.PayloadContext) {
+		assert.Equal(t, twitch.SubChannelRaid, payloadContext.Metadata.SubscriptionType)
+		ids = append(ids, payloadContext.Metadata.MessageID)
+	})
+
+	require.NoError(t, twitch.ReplayPublished(context.Background(), client, &archive, 2))
+	assert.Equal(t, []string{"message-0", "message-1"}, ids)
+	assert.Equal(t, []time.Duration{30 * time.Second}, clock.waits)
+}
+
+type replayClock struct {
+	waits []time.Duration
+}
+
+func (c *replayClock) Now() time.Time { return time.Now() }
+
+func (c *replayClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
@@ -0,0 +1,323 @@
+// Package redemptions queues the redemptions of custom channel point rewards which
+// wait in the request queue of the broadcaster, and fulfills or cancels them with the
+// Twitch API.
+//
+//	manager := redemptions.New(redemptions.Options{ClientID: clientID, AccessToken: broadcasterToken})
+//	client.OnEventChannelChannelPointsCustomRewardRedemptionAdd(manager.Add)
+//	client.OnEventChannelChannelPointsCustomRewardRedemptionUpdate(manager.Update)
+//	...
+//	for _, redemption := range manager.Pending() {
+//		if play(redemption.UserInput) {
+//			err = manager.Fulfill(ctx, redemption.ID)
+//		} else {
+//			err = manager.Cancel(ctx, redemption.ID) // refunds the channel points
+//		}
+//	}
+package redemptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API redemptions are updated with.
+const DefaultHelixURL = helix.DefaultURL
+
+var (
+	// ErrUnknown is returned for redemptions which were never queued.
+	ErrUnknown = errors.New("redemptions: unknown redemption")
+	// ErrSettled is returned when fulfilling a canceled redemption or canceling a
+	// fulfilled one.
+	ErrSettled = errors.New("redemptions: redemption already settled")
+)
+
+type Options struct {
+	ClientID string
+	// AccessToken is a user access token of the broadcaster with the
+	// channel:manage:redemptions scope, from the client ID which created the rewards.
+	AccessToken string
+
+	// MaxAttempts is how many times an update is tried. Defaults to 3.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each retry. Defaults to
+	// 1 second. Rate limited updates wait for the limit to reset instead.
+	Backoff time.Duration
+
+	// HelixURL defaults to DefaultHelixURL.
+	HelixURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock times the retries and when redemptions were settled. Defaults to the system
+	// clock.
+	Clock twitch.Clock
+}
+
+type settled struct {
+	status twitch.RedemptionStatus
+	at     time.Time
+}
+
+// update is an update of the status of a redemption in flight, which updates to the same
+// status wait for.
+type update struct {
+	status twitch.RedemptionStatus
+	done   chan struct{}
+	err    error
+}
+
+// Manager queues redemptions in the order they were redeemed until they are fulfilled or
+// canceled. Fulfilling or canceling a redemption twice is not an error, so handlers may
+// retry. It is safe for concurrent use.
+type Manager struct {
+	options Options
+
+	mu      sync.Mutex
+	pending []twitch.EventChannelChannelPointsCustomRewardRedemptionAdd
+	updates map[string]*update
+	settled map[string]settled
+
+	helix helix.Client
+}
+
+func New(options Options) *Manager {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 3
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = time.Second
+	}
+	if options.HelixURL == "" {
+		options.HelixURL = DefaultHelixURL
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	if options.Clock == nil {
+		options.Clock = twitch.SystemClock{}
+	}
+	return &Manager{
+		options: options,
+		updates: make(map[string]*update),
+		settled: make(map[string]settled),
+		helix: helix.Client{
+			URL:         options.HelixURL,
+			ClientID:    options.ClientID,
+			AccessToken: options.AccessToken,
+			HTTPClient:  options.HTTPClient,
+		},
+	}
+}
+
+// Add queues the redemption if it waits in the request queue. Redemptions of rewards
+// skipping the queue are fulfilled already, and redemptions delivered twice are queued
+// once. It is an OnEventChannelChannelPointsCustomRewardRedemptionAdd callback.
+func (m *Manager) Add(event twitch.EventChannelChannelPointsCustomRewardRedemptionAdd, _ twitch.PayloadContext) {
+	if event.Status != twitch.RedemptionStatusUnfulfilled {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.settled[event.ID]; ok || m.index(event.ID) >= 0 {
+		return
+	}
+	m.pending = append(m.pending, event)
+}
+
+// Update forgets redemptions settled elsewhere, like on the dashboard of the broadcaster.
+// It is an OnEventChannelChannelPointsCustomRewardRedemptionUpdate callback.
+func (m *Manager) Update(event twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate, _ twitch.PayloadContext) {
+	if event.Status != twitch.RedemptionStatusFulfilled && event.Status != twitch.RedemptionStatusCanceled {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.settle(event.ID, event.Status)
+}
+
+// Pending returns the queued redemptions, oldest first.
+func (m *Manager) Pending() []twitch.EventChannelChannelPointsCustomRewardRedemptionAdd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]twitch.EventChannelChannelPointsCustomRewardRedemptionAdd(nil), m.pending...)
+}
+
+// Get returns the queued redemption with the ID.
+func (m *Manager) Get(id string) (twitch.EventChannelChannelPointsCustomRewardRedemptionAdd, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.index(id)
+	if i < 0 {
+		return twitch.EventChannelChannelPointsCustomRewardRedemptionAdd{}, false
+	}
+	return m.pending[i], true
+}
+
+// Fulfill marks the redemption as fulfilled, keeping the channel points spent on it.
+func (m *Manager) Fulfill(ctx context.Context, id string) error {
+	return m.setStatus(ctx, id, twitch.RedemptionStatusFulfilled)
+}
+
+// Cancel marks the redemption as canceled, refunding the channel points spent on it.
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	return m.setStatus(ctx, id, twitch.RedemptionStatusCanceled)
+}
+
+func (m *Manager) setStatus(ctx context.Context, id string, status twitch.RedemptionStatus) error {
+	m.mu.Lock()
+	if s, ok := m.settled[id]; ok {
+		m.mu.Unlock()
+		if s.status != status {
+			return fmt.Errorf("%w: %s", ErrSettled, s.status)
+		}
+		return nil
+	}
+	if u, ok := m.updates[id]; ok {
+		m.mu.Unlock()
+		if u.status != status {
+			return fmt.Errorf("%w: %s", ErrSettled, u.status)
+		}
+		select {
+		case <-u.done:
+			return u.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	i := m.index(id)
+	if i < 0 {
+		m.mu.Unlock()
+		return ErrUnknown
+	}
+	redemption := m.pending[i]
+	u := &update{status: status, done: make(chan struct{})}
+	m.updates[id] = u
+	m.mu.Unlock()
+
+	u.err = m.update(ctx, redemption, status)
+
+	m.mu.Lock()
+	delete(m.updates, id)
+	if u.err == nil {
+		m.settle(id, status)
+	}
+	m.mu.Unlock()
+	close(u.done)
+	return u.err
+}
+
+// settle removes the redemption from the queue and remembers its status.
+func (m *Manager) settle(id string, status twitch.RedemptionStatus) {
+	if i := m.index(id); i >= 0 {
+		m.pending = append(m.pending[:i], m.pending[i+1:]...)
+	}
+	now := m.options.Clock.Now()
+	m.settled[id] = settled{status: status, at: now}
+
+	// Forget old redemptions once many were settled.
+	if len(m.settled) > 10000 {
+		for id, s := range m.settled {
+			if now.Sub(s.at) > time.Hour {
+				delete(m.settled, id)
+			}
+		}
+	}
+}
+
+func (m *Manager) index(id string) int {
+	for i, redemption := range m.pending {
+		if redemption.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// update tries to update the status of the redemption until it succeeds, fails for good,
+// or runs out of attempts.
+func (m *Manager) update(ctx context.Context, redemption twitch.EventChannelChannelPointsCustomRewardRedemptionAdd, status twitch.RedemptionStatus) error {
+	backoff := m.options.Backoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, retry, err := m.patch(ctx, redemption, status)
+		// Twitch answers Not Found for redemptions which are not unfulfilled anymore, so
+		// a retry of an update which went through without its response succeeded.
+		if errors.Is(err, errNotFound) && attempt > 1 {
+			return nil
+		}
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= m.options.MaxAttempts {
+			return fmt.Errorf("could not update redemption %s: %w", redemption.ID, err)
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-m.options.Clock.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("could not update redemption %s: %w", redemption.ID, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+var errNotFound = errors.New("redemption not found or not unfulfilled")
+
+// patch tries the update once, returning how long the rate limit asks to wait before
+// retrying, and whether retrying is worth it.
+func (m *Manager) patch(ctx context.Context, redemption twitch.EventChannelChannelPointsCustomRewardRedemptionAdd, status twitch.RedemptionStatus) (time.Duration, bool, error) {
+	query := url.Values{
+		"id":             {redemption.ID},
+		"broadcaster_id": {redemption.BroadcasterUserId},
+		"reward_id":      {redemption.Reward.ID},
+	}
+	body := struct {
+		Status string `json:"status"`
+	}{helixStatus(status)}
+	err := m.helix.Do(ctx, http.MethodPatch, "/channel_points/custom_rewards/redemptions?"+query.Encode(), body, nil)
+	var statusError *helix.StatusError
+	switch {
+	case err == nil:
+		return 0, false, nil
+	case !errors.As(err, &statusError):
+		return 0, ctx.Err() == nil, err
+	case statusError.Response.StatusCode == http.StatusNotFound:
+		return 0, false, errNotFound
+	case statusError.Response.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if reset, err := strconv.ParseInt(statusError.Response.Header.Get("Ratelimit-Reset"), 10, 64); err == nil {
+			retryAfter = time.Unix(reset, 0).Sub(m.options.Clock.Now())
+		}
+		return retryAfter, true, err
+	default:
+		return 0, statusError.Response.StatusCode >= 500, err
+	}
+}
+
+// helixStatus returns the status as the API spells it.
+func helixStatus(status twitch.RedemptionStatus) string {
+	switch status {
+	case twitch.RedemptionStatusFulfilled:
+		return "FULFILLED"
+	case twitch.RedemptionStatusCanceled:
+		return "CANCELED"
+	}
+	return string(status)
+}
@@ -0,0 +1,125 @@
+package redemptions_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/redemptions"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redemptionsPath = "/channel_points/custom_rewards/redemptions"
+
+func redemption(id string, status twitch.RedemptionStatus) twitch.EventChannelChannelPointsCustomRewardRedemptionAdd {
+	return twitch.EventChannelChannelPointsCustomRewardRedemptionAdd{
+		Broadcaster: twitch.Broadcaster{BroadcasterUserId: "1"},
+		ID:          id,
+		Status:      status,
+		Reward:      twitch.CustomChannelPointReward{ID: "reward"},
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	helix := twitchtest.NewHelix(t)
+	manager := redemptions.New(redemptions.Options{ClientID: "client", AccessToken: "token", HelixURL: helix.URL})
+
+	manager.Add(redemption("a", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("b", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("a", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("skipped", twitch.RedemptionStatusFulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("c", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	if pending := manager.Pending(); assert.Len(t, pending, 3) {
+		assert.Equal(t, "a", pending[0].ID)
+		assert.Equal(t, "c", pending[2].ID)
+	}
+
+	require.NoError(t, manager.Fulfill(ctx, "a"))
+	require.NoError(t, manager.Fulfill(ctx, "a"))
+	require.NoError(t, manager.Cancel(ctx, "b"))
+	assert.Equal(t, []string{
+		`PATCH /channel_points/custom_rewards/redemptions?broadcaster_id=1&id=a&reward_id=reward {"status":"FULFILLED"}`,
+		`PATCH /channel_points/custom_rewards/redemptions?broadcaster_id=1&id=b&reward_id=reward {"status":"CANCELED"}`,
+	}, helix.Requests())
+	request, err := helix.WaitForRequest(ctx, http.MethodPatch, redemptionsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", request.Authorization)
+	assert.ErrorIs(t, manager.Cancel(ctx, "a"), redemptions.ErrSettled)
+	assert.ErrorIs(t, manager.Fulfill(ctx, "unknown"), redemptions.ErrUnknown)
+
+	// Settled on the dashboard.
+	manager.Update(twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate(redemption("c", twitch.RedemptionStatusCanceled)), twitch.PayloadContext{})
+	assert.Empty(t, manager.Pending())
+	assert.NoError(t, manager.Cancel(ctx, "c"))
+	assert.Empty(t, helix.Requests())
+}
+
+func TestManagerRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	helix := twitchtest.NewHelix(t)
+	manager := redemptions.New(redemptions.Options{ClientID: "client", AccessToken: "token", HelixURL: helix.URL, Backoff: time.Millisecond})
+	manager.Add(redemption("a", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("b", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+	manager.Add(redemption("c", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+
+	// The first update went through but its response was lost.
+	helix.Respond(http.MethodPatch, redemptionsPath, http.StatusBadGateway, http.StatusNotFound)
+	require.NoError(t, manager.Fulfill(ctx, "a"))
+	assert.Len(t, helix.Requests(), 2)
+
+	helix.Respond(http.MethodPatch, redemptionsPath, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	err := manager.Fulfill(ctx, "b")
+	assert.ErrorContains(t, err, "could not update redemption b: 500 Internal Server Error")
+	assert.Len(t, helix.Requests(), 3)
+	_, ok := manager.Get("b")
+	assert.True(t, ok)
+
+	// Settled elsewhere already, which is not retried.
+	helix.Respond(http.MethodPatch, redemptionsPath, http.StatusNotFound)
+	assert.Error(t, manager.Cancel(ctx, "c"))
+	assert.Len(t, helix.Requests(), 1)
+}
+
+func TestManagerRetryClock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	helix := twitchtest.NewHelix(t)
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	manager := redemptions.New(redemptions.Options{ClientID: "client", AccessToken: "token", HelixURL: helix.URL, Backoff: time.Hour, Clock: clock})
+	manager.Add(redemption("a", twitch.RedemptionStatusUnfulfilled), twitch.PayloadContext{})
+
+	helix.Respond(http.MethodPatch, redemptionsPath, http.StatusInternalServerError)
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.Fulfill(ctx, "a")
+	}()
+	_, err := helix.WaitForRequest(ctx, http.MethodPatch, redemptionsPath)
+	require.NoError(t, err)
+
+	// The retry waits for the backoff on the clock.
+	select {
+	case err := <-done:
+		t.Fatalf("the update was not retried after the backoff: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	for {
+		clock.Advance(time.Hour)
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+			assert.Len(t, helix.Requests(), 2)
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package redis publishes EventSub notifications to Redis, either with Pub/Sub for fan
+// out or appended to Streams for consumer groups to read durably. It speaks the Redis
+// protocol itself instead of depending on a Redis client.
+//
+//	publisher, err := redis.Dial(ctx, "localhost:6379", redis.Options{Mode: redis.Stream})
+//	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher})
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("redis: publisher closed")
+
+type Mode int
+
+const (
+	// PubSub publishes to the channel named by the subject with PUBLISH.
+	PubSub Mode = iota
+	// Stream appends to the stream named by the subject with XADD. Entries get IDs
+	// generated by Redis, which consumer groups read in order, and hold the subject and
+	// data fields.
+	Stream
+)
+
+type Options struct {
+	Mode Mode
+
+	Username string
+	Password string
+	DB       int
+
+	// StreamMaxLen approximately trims streams to the length if positive.
+	StreamMaxLen int64
+
+	// DialTimeout defaults to 5 seconds.
+	DialTimeout time.Duration
+}
+
+// Publisher publishes messages over a single connection, waiting for Redis to reply to
+// each. It implements twitch.Publisher and is safe for concurrent use. After an I/O
+// error, like a reply not arriving before the deadline of the context, the connection
+// is closed, and the next command dials again.
+type Publisher struct {
+	address string
+	options Options
+
+	mu sync.Mutex
+	// conn is nil while broken.
+	conn   net.Conn
+	r      *bufio.Reader
+	w      *bufio.Writer
+	closed bool
+}
+
+// Error is an error reply from Redis.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Dial connects to Redis at address, like localhost:6379, authenticating and selecting
+// the database of the options.
+func Dial(ctx context.Context, address string, options Options) (*Publisher, error) {
+	p := &Publisher{address: address, options: options}
+	if err := p.connect(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// connect dials Redis, authenticating and selecting the database of the options. The
+// caller holds p.mu.
+func (p *Publisher) connect(ctx context.Context) error {
+	timeout := p.options.DialTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return fmt.Errorf("could not dial %s: %w", p.address, err)
+	}
+
+	p.conn = conn
+	p.r = bufio.NewReader(conn)
+	p.w = bufio.NewWriter(conn)
+	if p.options.Password != "" {
+		args := []string{"AUTH", p.options.Password}
+		if p.options.Username != "" {
+			args = []string{"AUTH", p.options.Username, p.options.Password}
+		}
+		if _, err := p.do(ctx, args...); err != nil {
+			p.broken()
+			return fmt.Errorf("could not authenticate: %w", err)
+		}
+	}
+	if p.options.DB != 0 {
+		if _, err := p.do(ctx, "SELECT", strconv.Itoa(p.options.DB)); err != nil {
+			p.broken()
+			return fmt.Errorf("could not select database %d: %w", p.options.DB, err)
+		}
+	}
+	return nil
+}
+
+// broken closes the connection, so the next command dials again. The caller holds p.mu.
+func (p *Publisher) broken() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.r, p.w = nil, nil, nil
+	}
+}
+
+// Publish publishes the data to the channel or stream named by the subject.
+func (p *Publisher) Publish(ctx context.Context, subject string, data []byte) error {
+	if p.options.Mode == Stream {
+		args := []string{"XADD", subject}
+		if p.options.StreamMaxLen > 0 {
+			args = append(args, "MAXLEN", "~", strconv.FormatInt(p.options.StreamMaxLen, 10))
+		}
+		args = append(args, "*", "subject", subject, "data", string(data))
+		_, err := p.Do(ctx, args...)
+		return err
+	}
+
+	_, err := p.Do(ctx, "PUBLISH", subject, string(data))
+	return err
+}
+
+// Do sends a command and returns its reply: a string, an int64, nil, or a slice of them.
+func (p *Publisher) Do(ctx context.Context, args ...string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return p.do(ctx, args...)
+}
+
+// do sends a command over the connection and reads its reply. Errors other than
+// error replies leave the stream in an unknown state, like a late reply still to
+// arrive, so they break the connection. The caller holds p.mu.
+func (p *Publisher) do(ctx context.Context, args ...string) (any, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		p.conn.SetDeadline(deadline)
+	} else {
+		p.conn.SetDeadline(time.Time{})
+	}
+
+	fmt.Fprintf(p.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(p.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	err := p.w.Flush()
+	if err != nil {
+		p.broken()
+		return nil, err
+	}
+	reply, err := readReply(p.r)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		p.broken()
+	}
+	return reply, err
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, value := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return value, nil
+	case '-':
+		return nil, Error(value)
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		replies := make([]any, count)
+		for i := range replies {
+			replies[i], err = readReply(r)
+			if err != nil {
+				var redisErr Error
+				if !errors.As(err, &redisErr) {
+					return nil, err
+				}
+				replies[i] = redisErr
+			}
+		}
+		return replies, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply %q", line)
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.Close()
+}
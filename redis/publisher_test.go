@@ -0,0 +1,178 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer accepts one connection and sends the commands received on it.
+func fakeServer(t *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, 16)
+	go func() {
+		defer close(commands)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			args, err := readCommand(r)
+			if err != nil {
+				return
+			}
+			commands <- args
+
+			switch args[0] {
+			case "AUTH":
+				if args[len(args)-1] != "secret" {
+					fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+					continue
+				}
+				fmt.Fprint(conn, "+OK\r\n")
+			case "PUBLISH":
+				fmt.Fprint(conn, ":1\r\n")
+			case "XADD":
+				fmt.Fprint(conn, "$3\r\n1-0\r\n")
+			default:
+				fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+			}
+		}
+	}()
+	return listener.Addr().String(), commands
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestPublisherPubSub(t *testing.T) {
+	t.Parallel()
+
+	address, commands := fakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := redis.Dial(ctx, address, redis.Options{Password: "secret"})
+	require.NoError(t, err)
+	defer publisher.Close()
+	assert.Equal(t, []string{"AUTH", "secret"}, <-commands)
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(&twitch.PublishBridge{Publisher: publisher})
+	client.OnError(func(err error) { t.Error(err) })
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+
+	args := <-commands
+	require.Len(t, args, 3)
+	assert.Equal(t, "PUBLISH", args[0])
+	assert.Equal(t, "twitch.eventsub.channel.raid", args[1])
+	assert.Contains(t, args[2], `"viewers":42`)
+
+	_, err = publisher.Do(ctx, "NOPE")
+	assert.ErrorContains(t, err, "unknown command")
+}
+
+func TestPublisherStream(t *testing.T) {
+	t.Parallel()
+
+	address, commands := fakeServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	publisher, err := redis.Dial(ctx, address, redis.Options{Mode: redis.Stream, StreamMaxLen: 1000})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(ctx, "events", []byte(`{}`)))
+	assert.Equal(t, []string{"XADD", "events", "MAXLEN", "~", "1000", "*", "subject", "events", "data", "{}"}, <-commands)
+}
+
+func TestDialWrongPassword(t *testing.T) {
+	t.Parallel()
+
+	address, _ := fakeServer(t)
+	_, err := redis.Dial(context.Background(), address, redis.Options{Password: "wrong"})
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestPublisherRedialsAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// The first connection replies late, the ones after right away, with the number of
+	// the connection.
+	go func() {
+		for i := 1; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn, i int) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					if _, err := readCommand(r); err != nil {
+						return
+					}
+					if i == 1 {
+						time.Sleep(200 * time.Millisecond)
+					}
+					fmt.Fprintf(conn, ":%d\r\n", i)
+				}
+			}(conn, i)
+		}
+	}()
+
+	publisher, err := redis.Dial(context.Background(), listener.Addr().String(), redis.Options{})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, err = publisher.Do(ctx, "PUBLISH", "events", "{}")
+	cancel()
+	require.Error(t, err)
+
+	time.Sleep(300 * time.Millisecond)
+	reply, err := publisher.Do(context.Background(), "PUBLISH", "events", "{}")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), reply, "the late reply must not be read as the reply of the next command")
+}
@@ -0,0 +1,137 @@
+package twitch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookCallbackVerification is the message type of the callback Twitch sends to
+// verify the callback URL of a new subscription.
+const WebhookCallbackVerification = "webhook_callback_verification"
+
+// webhookMaxBodySize bounds the body of webhook callbacks read by a WebhookRelay.
+const webhookMaxBodySize = 4 << 20
+
+// WebhookRelay is an http.Handler receiving EventSub webhook callbacks and handling them
+// with a client as if they came over its websocket, so the same callbacks, publish
+// bridge, and metrics serve both transports. The client does not need to be connected.
+// Callbacks are handled one at a time with the messages of the websocket, and counted
+// in WebhookStats instead of Stats.
+//
+//	relay := twitch.NewWebhookRelay(client, secret)
+//	http.Handle("/eventsub", relay)
+//
+// Callbacks with an invalid signature are rejected, stale and duplicate ones are
+// acknowledged without being handled, and the challenge of callback verifications is
+// answered. Errors from handling a callback are passed to OnError and the callback is
+// still acknowledged, since Twitch sending it again would not help.
+type WebhookRelay struct {
+	client *Client
+	secret string
+	guard  *WebhookReplayGuard
+}
+
+// NewWebhookRelay returns a relay handling callbacks signed with the secret given when
+// subscribing.
+func NewWebhookRelay(client *Client, secret string) *WebhookRelay {
+	return &WebhookRelay{
+		client: client,
+		secret: secret,
+		guard:  NewWebhookReplayGuard(WebhookMaxMessageAge),
+	}
+}
+
+type webhookCallback struct {
+	Challenge    string              `json:"challenge"`
+	Subscription PayloadSubscription `json:"subscription"`
+	Event        json.RawMessage     `json:"event"`
+}
+
+func (r *WebhookRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, webhookMaxBodySize))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+	err = VerifyEventSubSignature(req.Header, body, r.secret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	err = r.guard.Check(req.Header, r.client.now())
+	if errors.Is(err, ErrWebhookDuplicate) || errors.Is(err, ErrWebhookStale) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var callback webhookCallback
+	err = json.Unmarshal(body, &callback)
+	if err != nil {
+		http.Error(w, "could not decode callback", http.StatusBadRequest)
+		return
+	}
+	timestamp, _ := WebhookTimestamp(req.Header)
+	metadata := MessageMetadata{
+		MessageID:           req.Header.Get(WebhookMessageIDHeader),
+		MessageType:         req.Header.Get(WebhookMessageTypeHeader),
+		MessageTimestamp:    timestamp,
+		SubscriptionType:    EventSubscription(req.Header.Get(WebhookSubscriptionTypeHeader)),
+		SubscriptionVersion: req.Header.Get(WebhookSubscriptionVersionHeader),
+	}
+
+	switch metadata.MessageType {
+	case WebhookCallbackVerification:
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, callback.Challenge)
+		return
+	case "notification":
+		var raw []byte
+		raw, err = publishedMessage(PublishedNotification{
+			Metadata:     metadata,
+			Subscription: callback.Subscription,
+			Event:        callback.Event,
+		})
+		if err == nil {
+			err = r.client.relayMessage(raw)
+		}
+	case "revocation":
+		message := RevokeMessage{Metadata: metadata}
+		message.Payload.Subscription = callback.Subscription
+		var raw []byte
+		raw, err = json.Marshal(message)
+		if err == nil {
+			err = r.client.relayMessage(raw)
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unknown message type %s", metadata.MessageType), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		r.client.reportErrorTo(&r.client.webhookStats, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// relayMessage handles a message of a webhook callback like one read by the read loop,
+// serialized with it, counting it in the webhook stats. The time of the last message
+// is left alone, since it tracks the health of the websocket.
+func (c *Client) relayMessage(raw []byte) error {
+	c.webhookStats.incr(statMessages)
+	decoded := c.decodeMessage(raw, c.now())
+	decoded.webhook = true
+	return c.deliverSerialized(decoded)
+}
@@ -0,0 +1,130 @@
+package twitch_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func webhookRequest(id, messageType, body, secret string) *http.Request {
+	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + timestamp + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/eventsub", strings.NewReader(body))
+	req.Header.Set(twitch.WebhookMessageIDHeader, id)
+	req.Header.Set(twitch.WebhookMessageTypeHeader, messageType)
+	req.Header.Set(twitch.WebhookMessageTimestampHeader, timestamp)
+	req.Header.Set(twitch.WebhookMessageSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set(twitch.WebhookSubscriptionTypeHeader, "channel.raid")
+	req.Header.Set(twitch.WebhookSubscriptionVersionHeader, "1")
+	return req
+}
+
+func TestWebhookRelay(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	client.OnError(func(err error) {
+		t.Errorf("relay registered an error: %v", err)
+	})
+	var raids []twitch.EventChannelRaid
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, payloadContext twitch.PayloadContext) {
+		assert.Equal(t, "message-1", payloadContext.Metadata.MessageID)
+		assert.Equal(t, "subscription-1", payloadContext.Subscription.ID)
+		raids = append(raids, event)
+	})
+	var revoked []twitch.RevokeMessage
+	client.OnRevoke(func(message twitch.RevokeMessage, _ twitch.MessageMetadata) {
+		revoked = append(revoked, message)
+	})
+	relay := twitch.NewWebhookRelay(client, "secret")
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		relay.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(webhookRequest("message-0", twitch.WebhookCallbackVerification, `{"challenge":"pogchamp","subscription":{"id":"subscription-1"}}`, "secret"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "pogchamp", w.Body.String())
+
+	notification := `{"subscription":{"id":"subscription-1","type":"channel.raid","version":"1"},"event":{"viewers":42}}`
+	w = serve(webhookRequest("message-1", "notification", notification, "secret"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(webhookRequest("message-1", "notification", notification, "secret"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, raids, 1)
+	assert.Equal(t, 42, raids[0].Viewers)
+
+	w = serve(webhookRequest("message-2", "notification", notification, "wrong"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Len(t, raids, 1)
+
+	w = serve(webhookRequest("message-3", "revocation", `{"subscription":{"id":"subscription-1","type":"channel.raid","status":"authorization_revoked"}}`, "secret"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, revoked, 1)
+	assert.Equal(t, "authorization_revoked", revoked[0].Payload.Subscription.Status)
+}
+
+func TestWebhookRelayConcurrent(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	client.OnError(func(err error) {
+		t.Errorf("relay registered an error: %v", err)
+	})
+	var inFlight int32
+	viewers := 0
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
+		if atomic.AddInt32(&inFlight, 1) > 1 {
+			t.Error("callbacks of concurrent webhook callbacks ran concurrently")
+		}
+		viewers += event.Viewers
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	})
+	server := httptest.NewServer(twitch.NewWebhookRelay(client, "secret"))
+	defer server.Close()
+
+	const count = 50
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			notification := `{"subscription":{"id":"subscription-1","type":"channel.raid","version":"1"},"event":{"viewers":1}}`
+			req := webhookRequest(fmt.Sprintf("message-%d", i), "notification", notification, "secret")
+			req.RequestURI = ""
+			req.URL, _ = url.Parse(server.URL + "/eventsub")
+			res, err := http.DefaultClient.Do(req)
+			if assert.NoError(t, err) {
+				res.Body.Close()
+				assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, count, viewers)
+	assert.Equal(t, int64(count), client.WebhookStats().Messages)
+	assert.Equal(t, int64(count), client.WebhookStats().Notifications)
+	assert.Zero(t, client.Stats().Messages, "webhook callbacks must not count as websocket messages")
+	assert.False(t, client.Live(), "webhook callbacks must not keep the websocket live")
+}
@@ -0,0 +1,151 @@
+package twitch
+
+import (
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CheermoteColor returns the color of the cheermotes cheering the bits, as in Twitch
+// chat.
+func CheermoteColor(bits int) string {
+	switch CheermoteTier(bits) {
+	case 10000:
+		return "#f43021"
+	case 5000:
+		return "#0099fe"
+	case 1000:
+		return "#1db2a5"
+	case 100:
+		return "#9c3ee8"
+	default:
+		return "#979797"
+	}
+}
+
+// URL returns the URL of the image of the emote from the Twitch CDN, animated if the
+// emote is. The theme is light or dark, and the scale 1.0, 2.0, or 3.0.
+func (e ChatMessageFragmentEmote) URL(theme, scale string) string {
+	format := "static"
+	for _, f := range e.Format {
+		if f == "animated" {
+			format = f
+		}
+	}
+	return "https://static-cdn.jtvnw.net/emoticons/v2/" + url.PathEscape(e.Id) + "/" + format + "/" + theme + "/" + scale
+}
+
+// ChatRenderer renders the fragments of chat messages as HTML or Markdown for
+// overlays and logs, escaping the text of chatters. The zero value renders emotes from
+// the Twitch CDN and cheermotes as text.
+//
+// HTML fragments get classes to style: emote, mention, mention-highlight,
+// cheermote, and cheermote-tier-<tier>. Cheermotes are colored by tier inline.
+type ChatRenderer struct {
+	// EmoteURL returns the URL of the image of an emote. Defaults to the dark 1.0
+	// image of the Twitch CDN.
+	EmoteURL func(emote ChatMessageFragmentEmote) string
+	// CheermoteURL returns the URL of the image of a cheermote, or an empty string to
+	// render it as text. The images of the cheermotes of a channel are listed by the
+	// Get Cheermotes API.
+	CheermoteURL func(cheermote ChatMessageFragmentCheermote) string
+	// HighlightUserID highlights the mentions of the user, like the broadcaster.
+	HighlightUserID string
+}
+
+func (r ChatRenderer) emoteURL(emote ChatMessageFragmentEmote) string {
+	if r.EmoteURL != nil {
+		return r.EmoteURL(emote)
+	}
+	return emote.URL("dark", "1.0")
+}
+
+func (r ChatRenderer) cheermoteURL(cheermote ChatMessageFragmentCheermote) string {
+	if r.CheermoteURL != nil {
+		return r.CheermoteURL(cheermote)
+	}
+	return ""
+}
+
+// fragments returns the fragments of the message, or its text for messages without.
+func fragments(message ChatMessage) []ChatMessageFragment {
+	if len(message.Fragments) == 0 && message.Text != "" {
+		return []ChatMessageFragment{{Type: "text", Text: message.Text}}
+	}
+	return message.Fragments
+}
+
+// HTML renders the message as HTML, safe to insert in a page.
+func (r ChatRenderer) HTML(message ChatMessage) string {
+	var b strings.Builder
+	for _, fragment := range fragments(message) {
+		text := html.EscapeString(fragment.Text)
+		switch {
+		case fragment.Emote != nil:
+			b.WriteString(`<img class="emote" src="` + html.EscapeString(r.emoteURL(*fragment.Emote)) + `" alt="` + text + `" title="` + text + `">`)
+		case fragment.Mention != nil:
+			class := "mention"
+			if r.HighlightUserID != "" && fragment.Mention.UserID == r.HighlightUserID {
+				class += " mention-highlight"
+			}
+			b.WriteString(`<span class="` + class + `" data-user-id="` + html.EscapeString(fragment.Mention.UserID) + `">` + text + `</span>`)
+		case fragment.Cheermote != nil:
+			cheermote := *fragment.Cheermote
+			tier := strconv.Itoa(CheermoteTier(cheermote.Bits))
+			b.WriteString(`<span class="cheermote cheermote-tier-` + tier + `" style="color: ` + CheermoteColor(cheermote.Bits) + `">`)
+			if src := r.cheermoteURL(cheermote); src != "" {
+				b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + text + `">` + strconv.Itoa(cheermote.Bits))
+			} else {
+				b.WriteString(text)
+			}
+			b.WriteString(`</span>`)
+		default:
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the message as Markdown, escaping what chatters type so it renders
+// as typed. Mentions and cheered bits are bold.
+func (r ChatRenderer) Markdown(message ChatMessage) string {
+	var b strings.Builder
+	for _, fragment := range fragments(message) {
+		text := escapeMarkdown(fragment.Text)
+		switch {
+		case fragment.Emote != nil:
+			b.WriteString("![" + text + "](" + markdownURL(r.emoteURL(*fragment.Emote)) + ")")
+		case fragment.Mention != nil:
+			b.WriteString("**" + text + "**")
+		case fragment.Cheermote != nil:
+			cheermote := *fragment.Cheermote
+			if src := r.cheermoteURL(cheermote); src != "" {
+				b.WriteString("![" + text + "](" + markdownURL(src) + ")**" + strconv.Itoa(cheermote.Bits) + "**")
+			} else {
+				b.WriteString("**" + text + "**")
+			}
+		default:
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+var markdownEscaper = func() *strings.Replacer {
+	var pairs []string
+	for _, c := range "\\`*_{}[]()<>#+-.!|~&" {
+		pairs = append(pairs, string(c), "\\"+string(c))
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+var markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+func markdownURL(u string) string {
+	return markdownURLEscaper.Replace(u)
+}
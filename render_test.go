@@ -0,0 +1,42 @@
+package twitch
+
+import "testing"
+
+func TestChatRenderer(t *testing.T) {
+	message := ChatMessage{Fragments: []ChatMessageFragment{
+		{Type: "text", Text: `<script>alert("hi")</script> *bold* `},
+		{Type: "emote", Text: "Kappa", Emote: &ChatMessageFragmentEmote{Id: "25", Format: []string{"static"}}},
+		{Type: "text", Text: " "},
+		{Type: "mention", Text: "@streamer", Mention: &ChatMessageFragmentMention{UserID: "1337", UserLogin: "streamer"}},
+		{Type: "text", Text: " "},
+		{Type: "cheermote", Text: "Cheer100", Cheermote: &ChatMessageFragmentCheermote{Prefix: "Cheer", Bits: 100, Tier: 100}},
+	}}
+	renderer := ChatRenderer{HighlightUserID: "1337"}
+
+	expected := `&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt; *bold* ` +
+		`<img class="emote" src="https://static-cdn.jtvnw.net/emoticons/v2/25/static/dark/1.0" alt="Kappa" title="Kappa"> ` +
+		`<span class="mention mention-highlight" data-user-id="1337">@streamer</span> ` +
+		`<span class="cheermote cheermote-tier-100" style="color: #9c3ee8">Cheer100</span>`
+	if actual := renderer.HTML(message); actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+
+	expected = `\<script\>alert\("hi"\)\</script\> \*bold\* ` +
+		`![Kappa](https://static-cdn.jtvnw.net/emoticons/v2/25/static/dark/1.0) **@streamer** **Cheer100**`
+	if actual := renderer.Markdown(message); actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+
+	renderer.CheermoteURL = func(cheermote ChatMessageFragmentCheermote) string {
+		return "https://example.com/" + cheermote.Prefix + `" onerror="x`
+	}
+	expected = `<span class="cheermote cheermote-tier-100" style="color: #9c3ee8"><img src="https://example.com/Cheer&#34; onerror=&#34;x" alt="Cheer100">100</span>`
+	if actual := renderer.HTML(ChatMessage{Fragments: message.Fragments[5:]}); actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+
+	// Messages without fragments render their text.
+	if actual := renderer.HTML(ChatMessage{Text: "a & b"}); actual != "a &amp; b" {
+		t.Errorf("expected escaped text got %s", actual)
+	}
+}
@@ -0,0 +1,183 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema draft the generated schemas declare.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// EventJSONSchema returns the JSON Schema of the event of a subscription type, so
+// consumers of published events outside of Go can validate them and generate their own
+// types. Structs shared between events are described under $defs. Properties are not
+// required since Twitch leaves some out, and other properties are allowed since Twitch
+// adds fields over time.
+func EventJSONSchema(event EventSubscription) ([]byte, error) {
+	metadata, ok := subMetadata[event]
+	if !ok || metadata.EventGen == nil {
+		return nil, fmt.Errorf("unknown subscription type %s", event)
+	}
+
+	return marshalJSONSchema(reflect.TypeOf(metadata.EventGen()).Elem(), string(event))
+}
+
+// MessageJSONSchema returns the JSON Schema of a websocket message, like
+// session_welcome or notification. The event of notifications is described by
+// EventJSONSchema.
+func MessageJSONSchema(messageType string) ([]byte, error) {
+	gen, ok := messageTypeMap[messageType]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", messageType)
+	}
+
+	return marshalJSONSchema(reflect.TypeOf(gen()).Elem(), messageType)
+}
+
+// JSONSchemas returns the schema of every event and websocket message, keyed by
+// subscription or message type.
+func JSONSchemas() (map[string][]byte, error) {
+	schemas := make(map[string][]byte, len(subMetadata)+len(messageTypeMap))
+	for event, metadata := range subMetadata {
+		if metadata.EventGen == nil {
+			continue
+		}
+		schema, err := EventJSONSchema(event)
+		if err != nil {
+			return nil, err
+		}
+		schemas[string(event)] = schema
+	}
+	for messageType := range messageTypeMap {
+		schema, err := MessageJSONSchema(messageType)
+		if err != nil {
+			return nil, err
+		}
+		schemas[messageType] = schema
+	}
+	return schemas, nil
+}
+
+func marshalJSONSchema(t reflect.Type, title string) ([]byte, error) {
+	g := schemaGenerator{defs: make(map[string]map[string]any)}
+	// Events are structs, except for batched ones like drop entitlement grants.
+	schema := g.schema(t)
+	if t.Kind() == reflect.Struct {
+		schema = g.object(t)
+	}
+	schema["$schema"] = JSONSchemaDialect
+	schema["title"] = title
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type schemaGenerator struct {
+	defs map[string]map[string]any
+}
+
+// schema returns the schema of a type, referring to named structs by their definition.
+func (g schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return map[string]any{"anyOf": []any{g.schema(t.Elem()), map[string]any{"type": "null"}}}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []any{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []any{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the definition before describing it in case the struct refers to itself.
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	return map[string]any{}
+}
+
+// object returns the schema of a struct, with the fields of embedded structs inlined
+// like encoding/json does.
+func (g schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	g.addProperties(properties, t)
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+func (g schemaGenerator) addProperties(properties map[string]any, t reflect.Type) {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, t.Field(i))
+	}
+	// Fields of the struct shadow the fields of the structs it embeds.
+	sort.SliceStable(fields, func(i, j int) bool {
+		return !fields[i].Anonymous && fields[j].Anonymous
+	})
+
+	for _, field := range fields {
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addEmbedded(properties, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := properties[name]; !ok {
+			properties[name] = g.schema(field.Type)
+		}
+	}
+}
+
+func (g schemaGenerator) addEmbedded(properties map[string]any, t reflect.Type) {
+	embedded := make(map[string]any)
+	g.addProperties(embedded, t)
+	for name, schema := range embedded {
+		if _, ok := properties[name]; !ok {
+			properties[name] = schema
+		}
+	}
+}
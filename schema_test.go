@@ -0,0 +1,114 @@
+package twitch
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testSchema struct {
+	Schema     string                     `json:"$schema"`
+	Title      string                     `json:"title"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Defs       map[string]json.RawMessage `json:"$defs"`
+}
+
+func TestEventJSONSchemaCoversFixtures(t *testing.T) {
+	for key, payload := range loadFixtures(t) {
+		event, _, _ := strings.Cut(key, "-")
+		if _, ok := subMetadata[EventSubscription(event)]; !ok {
+			continue
+		}
+
+		data, err := EventJSONSchema(EventSubscription(event))
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		var schema testSchema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if schema.Schema != JSONSchemaDialect || schema.Title != event {
+			t.Errorf("%s: unexpected header %q %q", key, schema.Schema, schema.Title)
+		}
+
+		if strings.HasPrefix(string(payload), "[") {
+			if _, ok := schema.Defs["EventDropEntitlementGrant"]; !ok {
+				t.Errorf("%s: schema does not define the batched event", key)
+			}
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		for field := range fields {
+			if _, ok := schema.Properties[field]; !ok {
+				t.Errorf("%s: schema has no property %s", key, field)
+			}
+		}
+	}
+}
+
+func TestEventJSONSchemaRefs(t *testing.T) {
+	data, err := EventJSONSchema(SubChannelPollBegin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema testSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(schema.Properties["choices"]) == "" {
+		t.Fatal("schema has no choices")
+	}
+	if !strings.Contains(string(schema.Properties["choices"]), `"$ref": "#/$defs/PollChoice"`) {
+		t.Errorf("choices do not refer to PollChoice: %s", schema.Properties["choices"])
+	}
+	if _, ok := schema.Defs["PollChoice"]; !ok {
+		t.Error("schema does not define PollChoice")
+	}
+	if !strings.Contains(string(schema.Properties["started_at"]), `"date-time"`) {
+		t.Errorf("started_at is not a date-time: %s", schema.Properties["started_at"])
+	}
+}
+
+func TestMessageJSONSchema(t *testing.T) {
+	for messageType := range messageTypeMap {
+		data, err := MessageJSONSchema(messageType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var schema testSchema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := schema.Properties["metadata"]; !ok {
+			t.Errorf("%s: schema has no metadata", messageType)
+		}
+		if _, ok := schema.Properties["payload"]; !ok {
+			t.Errorf("%s: schema has no payload", messageType)
+		}
+	}
+
+	if _, err := MessageJSONSchema("unknown"); err == nil {
+		t.Error("expected an error for an unknown message type")
+	}
+	if _, err := EventJSONSchema("unknown"); err == nil {
+		t.Error("expected an error for an unknown subscription type")
+	}
+}
+
+func TestJSONSchemas(t *testing.T) {
+	schemas, err := JSONSchemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schemas[string(SubChannelFollow)]; !ok {
+		t.Error("missing channel.follow")
+	}
+	if _, ok := schemas["notification"]; !ok {
+		t.Error("missing notification")
+	}
+}
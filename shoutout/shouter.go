@@ -0,0 +1,263 @@
+// Package shoutout shouts out the broadcasters raiding a channel with the Twitch API,
+// waiting out the cooldowns of shoutouts: a channel can give one shoutout every 2
+// minutes, and shout out the same broadcaster once every 60 minutes.
+//
+//	shouter := shoutout.New(shoutout.Options{
+//		ClientID:    clientID,
+//		AccessToken: modToken,
+//		ModeratorID: modID,
+//		Message: func(raid twitch.EventChannelRaid) string {
+//			return fmt.Sprintf("Thanks for the raid %s!", raid.FromBroadcasterUserName)
+//		},
+//	})
+//	defer shouter.Close()
+//	client.OnEventChannelRaid(shouter.HandleRaid)
+//	client.OnEventChannelShoutoutCreate(shouter.HandleShoutout)
+package shoutout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/helix"
+)
+
+// DefaultHelixURL is the Twitch API shoutouts are given with.
+const DefaultHelixURL = helix.DefaultURL
+
+const (
+	// Cooldown is how long a channel waits between shoutouts.
+	Cooldown = 2 * time.Minute
+	// TargetCooldown is how long a channel waits to shout out the same broadcaster again.
+	TargetCooldown = 60 * time.Minute
+)
+
+type Options struct {
+	ClientID string
+	// AccessToken is a user access token of the moderator with the
+	// moderator:manage:shoutouts scope, and user:write:chat to send Message.
+	AccessToken string
+	// ModeratorID is the user giving the shoutouts, the broadcaster or one of their
+	// moderators.
+	ModeratorID string
+
+	// Delay is how long after a raid its shoutout is given at the earliest, letting the
+	// raiders settle in.
+	Delay time.Duration
+	// MinViewers ignores the raids bringing fewer viewers.
+	MinViewers int
+	// Message returns a chat message sent by the moderator along with the shoutout, or
+	// an empty string to send none.
+	Message func(raid twitch.EventChannelRaid) string
+
+	// HelixURL defaults to DefaultHelixURL.
+	HelixURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock times the cooldowns. Defaults to the system clock.
+	Clock twitch.Clock
+}
+
+type queuedRaid struct {
+	raid     twitch.EventChannelRaid
+	queuedAt time.Time
+}
+
+// Shouter shouts out the broadcasters raiding channels in the order they raided. It is
+// safe for concurrent use.
+type Shouter struct {
+	options Options
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mu        sync.Mutex
+	queues    map[string][]queuedRaid
+	draining  map[string]bool
+	cooldowns map[string]time.Time
+	onError   func(err error)
+	workers   sync.WaitGroup
+
+	helix helix.Client
+}
+
+func New(options Options) *Shouter {
+	if options.HelixURL == "" {
+		options.HelixURL = DefaultHelixURL
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	if options.Clock == nil {
+		options.Clock = twitch.SystemClock{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Shouter{
+		options:   options,
+		ctx:       ctx,
+		cancel:    cancel,
+		queues:    make(map[string][]queuedRaid),
+		draining:  make(map[string]bool),
+		cooldowns: make(map[string]time.Time),
+		helix: helix.Client{
+			URL:         options.HelixURL,
+			ClientID:    options.ClientID,
+			AccessToken: options.AccessToken,
+			HTTPClient:  options.HTTPClient,
+		},
+	}
+}
+
+// OnError is called with the shoutouts and messages which failed.
+func (s *Shouter) OnError(callback func(err error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = callback
+}
+
+func (s *Shouter) reportError(err error) {
+	// Requests in flight fail once closed.
+	if s.ctx.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	onError := s.onError
+	s.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// Close stops giving the queued shoutouts.
+func (s *Shouter) Close() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+	s.workers.Wait()
+}
+
+// HandleRaid queues the shoutout of the raiding broadcaster. Broadcasters shouted out
+// in the last 60 minutes are skipped. It is an OnEventChannelRaid callback, for
+// subscriptions to raids of the channel.
+func (s *Shouter) HandleRaid(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
+	if event.Viewers < s.options.MinViewers {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return
+	}
+	channel := event.ToBroadcasterUserId
+	s.queues[channel] = append(s.queues[channel], queuedRaid{raid: event, queuedAt: s.options.Clock.Now()})
+	if !s.draining[channel] {
+		s.draining[channel] = true
+		s.workers.Add(1)
+		go s.drain(channel)
+	}
+}
+
+// HandleShoutout follows the cooldowns of the shoutouts given by anyone in the channel.
+// It is an OnEventChannelShoutoutCreate callback.
+func (s *Shouter) HandleShoutout(event twitch.EventChannelShoutoutCreate, _ twitch.PayloadContext) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cooldowns[event.BroadcasterUserId] = event.CooldownEndsAt
+	s.cooldowns[event.BroadcasterUserId+"/"+event.ToBroadcasterUserId] = event.TargetCooldownEndsAt
+}
+
+// CooldownRemaining returns how long until the channel can give another shoutout.
+func (s *Shouter) CooldownRemaining(broadcasterID string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remaining(broadcasterID, s.options.Clock.Now())
+}
+
+func (s *Shouter) remaining(key string, now time.Time) time.Duration {
+	if endsAt := s.cooldowns[key]; endsAt.After(now) {
+		return endsAt.Sub(now)
+	}
+	return 0
+}
+
+// drain gives the queued shoutouts of the channel as the cooldowns allow.
+func (s *Shouter) drain(channel string) {
+	defer s.workers.Done()
+
+	for {
+		s.mu.Lock()
+		queue := s.queues[channel]
+		if len(queue) == 0 || s.ctx.Err() != nil {
+			delete(s.queues, channel)
+			delete(s.draining, channel)
+			s.mu.Unlock()
+			return
+		}
+		next := queue[0]
+		now := s.options.Clock.Now()
+		wait := s.remaining(channel, now)
+		if delay := next.queuedAt.Add(s.options.Delay).Sub(now); delay > wait {
+			wait = delay
+		}
+		if wait > 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.options.Clock.After(wait):
+			case <-s.ctx.Done():
+			}
+			continue
+		}
+		s.queues[channel] = queue[1:]
+		target := channel + "/" + next.raid.FromBroadcasterUserId
+		skip := s.remaining(target, now) > 0
+		if !skip {
+			// Until the shoutout.create event tells the actual cooldowns.
+			s.cooldowns[channel] = now.Add(Cooldown)
+			s.cooldowns[target] = now.Add(TargetCooldown)
+		}
+		s.mu.Unlock()
+
+		if !skip {
+			s.shoutout(next.raid)
+		}
+	}
+}
+
+func (s *Shouter) shoutout(raid twitch.EventChannelRaid) {
+	query := url.Values{
+		"from_broadcaster_id": {raid.ToBroadcasterUserId},
+		"to_broadcaster_id":   {raid.FromBroadcasterUserId},
+		"moderator_id":        {s.options.ModeratorID},
+	}
+	err := s.helix.Do(s.ctx, http.MethodPost, "/chat/shoutouts?"+query.Encode(), nil, nil)
+	if err != nil {
+		s.reportError(fmt.Errorf("could not shout out %s: %w", raid.FromBroadcasterUserLogin, err))
+	}
+
+	if s.options.Message == nil {
+		return
+	}
+	message := s.options.Message(raid)
+	if message == "" {
+		return
+	}
+	request := struct {
+		BroadcasterID string `json:"broadcaster_id"`
+		SenderID      string `json:"sender_id"`
+		Message       string `json:"message"`
+	}{raid.ToBroadcasterUserId, s.options.ModeratorID, message}
+	err = s.helix.Do(s.ctx, http.MethodPost, "/chat/messages", request, nil)
+	if err != nil {
+		s.reportError(fmt.Errorf("could not send shoutout message: %w", err))
+	}
+}
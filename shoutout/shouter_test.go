@@ -0,0 +1,83 @@
+package shoutout_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/shoutout"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func raid(from string, viewers int) twitch.EventChannelRaid {
+	return twitch.EventChannelRaid{
+		FromBroadcasterUserId:    from,
+		FromBroadcasterUserLogin: "raider" + from,
+		ToBroadcasterUserId:      "1",
+		Viewers:                  viewers,
+	}
+}
+
+func TestShouter(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	helix := twitchtest.NewHelix(t)
+	receive := func(path string) string {
+		request, err := helix.WaitForRequest(ctx, http.MethodPost, path)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", request.Authorization)
+		return request.String()
+	}
+
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	shouter := shoutout.New(shoutout.Options{
+		ClientID:    "client",
+		AccessToken: "token",
+		ModeratorID: "mod",
+		MinViewers:  5,
+		Message: func(raid twitch.EventChannelRaid) string {
+			return "go follow " + raid.FromBroadcasterUserLogin
+		},
+		HelixURL: helix.URL,
+		Clock:    clock,
+	})
+	defer shouter.Close()
+	shouter.OnError(func(err error) {
+		t.Error(err)
+	})
+
+	shouter.HandleRaid(raid("2", 10), twitch.PayloadContext{})
+	assert.Equal(t, "POST /chat/shoutouts?from_broadcaster_id=1&moderator_id=mod&to_broadcaster_id=2", receive("/chat/shoutouts"))
+	assert.Equal(t, `POST /chat/messages {"broadcaster_id":"1","sender_id":"mod","message":"go follow raider2"}`, receive("/chat/messages"))
+	assert.Len(t, helix.Requests(), 2)
+
+	// Too small, then shouted out in the last hour, then on the cooldown of the channel.
+	shouter.HandleRaid(raid("3", 1), twitch.PayloadContext{})
+	shouter.HandleRaid(raid("2", 10), twitch.PayloadContext{})
+	shouter.HandleRaid(raid("4", 10), twitch.PayloadContext{})
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, helix.Requests(), "requests during the cooldown")
+	assert.Equal(t, 2*time.Minute, shouter.CooldownRemaining("1"))
+
+	// A moderator gave a shoutout by hand, which Twitch reports the cooldowns of.
+	now := clock.Now()
+	shouter.HandleShoutout(twitch.EventChannelShoutoutCreate{
+		Broadcaster:          twitch.Broadcaster{BroadcasterUserId: "1"},
+		ToBroadcasterUserId:  "5",
+		CooldownEndsAt:       now.Add(3 * time.Minute),
+		TargetCooldownEndsAt: now.Add(time.Hour),
+	}, twitch.PayloadContext{})
+	clock.Advance(2 * time.Minute)
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, helix.Requests(), "requests during the cooldown")
+	clock.Advance(time.Minute)
+	assert.Equal(t, "POST /chat/shoutouts?from_broadcaster_id=1&moderator_id=mod&to_broadcaster_id=4", receive("/chat/shoutouts"))
+	assert.Equal(t, `POST /chat/messages {"broadcaster_id":"1","sender_id":"mod","message":"go follow raider4"}`, receive("/chat/messages"))
+	require.Equal(t, 2*time.Minute, shouter.CooldownRemaining("1"))
+}
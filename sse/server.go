@@ -0,0 +1,191 @@
+// Package sse rebroadcasts EventSub notifications to browsers as server-sent events, so
+// overlays can listen to the events of a bot without their own Twitch credentials.
+//
+//	server := sse.NewServer()
+//	client.SetPublishBridge(server.Bridge())
+//	http.Handle("/events", server)
+//
+// Browsers pick the subscription types they want with the types query parameter, like
+// /events?types=channel.follow,channel.raid, and get every type without it.
+package sse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+type message struct {
+	event string
+	data  []byte
+}
+
+type subscriber struct {
+	types    map[string]bool
+	messages chan message
+}
+
+func (s *subscriber) wants(event string) bool {
+	return len(s.types) == 0 || s.types[event]
+}
+
+// Server is an http.Handler streaming the published events to every connection. It
+// implements twitch.Publisher, using the subject as the name of the event.
+type Server struct {
+	// Buffer is how many events are queued for a slow connection before new events
+	// are dropped for it. Defaults to 64.
+	Buffer int
+	// KeepAlive is how often a comment is sent to idle connections so proxies do not
+	// close them. Defaults to 15 seconds.
+	KeepAlive time.Duration
+	// Format is the format of the notifications Bridge sends.
+	Format twitch.PublishFormat
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	dropped     int64
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewServer() *Server {
+	return &Server{
+		subscribers: make(map[*subscriber]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Bridge returns a publish bridge sending notifications in the Format of the server,
+// named after their subscription type.
+func (s *Server) Bridge() *twitch.PublishBridge {
+	return &twitch.PublishBridge{
+		Publisher: s,
+		Format:    s.Format,
+		Subject: func(subscription twitch.PayloadSubscription) string {
+			return string(subscription.Type)
+		},
+	}
+}
+
+// Publish queues the event for every connection listening to it. It never blocks.
+func (s *Server) Publish(_ context.Context, subject string, data []byte) error {
+	msg := message{event: subject, data: append([]byte(nil), data...)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if !sub.wants(subject) {
+			continue
+		}
+		select {
+		case sub.messages <- msg:
+		default:
+			s.dropped++
+		}
+	}
+	return nil
+}
+
+// Dropped returns how many events were dropped for slow connections.
+func (s *Server) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropped
+}
+
+// Connections returns how many connections are listening.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subscribers)
+}
+
+// Close ends every connection.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	buffer := s.Buffer
+	if buffer <= 0 {
+		buffer = 64
+	}
+	sub := &subscriber{messages: make(chan message, buffer)}
+	for _, types := range r.URL.Query()["types"] {
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				if sub.types == nil {
+					sub.types = make(map[string]bool)
+				}
+				sub.types[t] = true
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := s.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 15 * time.Second
+	}
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-sub.messages:
+			writeEvent(w, msg)
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes the message as an event, splitting the data on newlines since each
+// line needs its own data field.
+func writeEvent(w http.ResponseWriter, msg message) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "event: %s\n", msg.event)
+	for _, line := range bytes.Split(msg.data, []byte("\n")) {
+		b.WriteString("data: ")
+		b.Write(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	w.Write(b.Bytes())
+}
@@ -0,0 +1,59 @@
+package sse_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/sse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	server := sse.NewServer()
+	defer server.Close()
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	res, err := http.Get(httpServer.URL + "?types=channel.raid")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for server.Connections() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	client := twitch.NewClient()
+	client.SetPublishBridge(server.Bridge())
+	require.NoError(t, client.InjectNotification(twitch.SubChannelFollow, twitch.EventChannelFollow{}))
+	require.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42}))
+
+	var lines []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		if line == "" {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	require.Len(t, lines, 2)
+	assert.Equal(t, "event: channel.raid", lines[0])
+	assert.Contains(t, lines[1], `"viewers":42`)
+}
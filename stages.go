@@ -0,0 +1,114 @@
+package twitch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StageTiming aggregates the time spent in one stage of handling messages.
+type StageTiming struct {
+	Count   int64
+	Total   time.Duration
+	Average time.Duration
+	Max     time.Duration
+
+	// last is the duration of the last message, which extend can add to.
+	last time.Duration
+}
+
+// StageTimings breaks down where the read loop spends its time. Read is reading a frame
+// once it started arriving, Parse is decoding the message metadata, Decode is decoding
+// the payload and event, and Dispatch is handing the event to handlers, not running
+// them, which Stats covers. Decode and Dispatch are only timed for notifications.
+type StageTimings struct {
+	Read     StageTiming
+	Parse    StageTiming
+	Decode   StageTiming
+	Dispatch StageTiming
+}
+
+func (t StageTimings) String() string {
+	return fmt.Sprintf("read=%s parse=%s decode=%s dispatch=%s", t.Read.Average, t.Parse.Average, t.Decode.Average, t.Dispatch.Average)
+}
+
+// SetStageTiming times the stages of handling every message, readable with
+// StageTimings. It is disabled by default since it reads the clock several times per
+// message. Enabling it again resets the timings.
+func (c *Client) SetStageTiming(enabled bool) {
+	if enabled {
+		c.stageTimer = &stageTimer{}
+	} else {
+		c.stageTimer = nil
+	}
+}
+
+// StageTimings returns the stage timings aggregated since SetStageTiming was enabled.
+func (c *Client) StageTimings() StageTimings {
+	return c.stageTimer.snapshot()
+}
+
+type stageTimer struct {
+	mu      sync.Mutex
+	timings StageTimings
+}
+
+// The methods of a nil stageTimer do nothing, so timing disabled costs no clock reads.
+func (s *stageTimer) start() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe records the time since start for a message in the stage.
+func (s *stageTimer) observe(stage func(t *StageTimings) *StageTiming, start time.Time) {
+	if s != nil {
+		s.add(stage, time.Since(start), 1)
+	}
+}
+
+// extend adds the time since start to the last message observed in the stage.
+func (s *stageTimer) extend(stage func(t *StageTimings) *StageTiming, start time.Time) {
+	if s != nil {
+		s.add(stage, time.Since(start), 0)
+	}
+}
+
+func (s *stageTimer) add(stage func(t *StageTimings) *StageTiming, duration time.Duration, count int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timing := stage(&s.timings)
+	if count > 0 {
+		timing.last = 0
+	}
+	timing.Count += count
+	timing.Total += duration
+	timing.last += duration
+	if timing.last > timing.Max {
+		timing.Max = timing.last
+	}
+}
+
+func (s *stageTimer) snapshot() StageTimings {
+	if s == nil {
+		return StageTimings{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timings := s.timings
+	for _, timing := range []*StageTiming{&timings.Read, &timings.Parse, &timings.Decode, &timings.Dispatch} {
+		if timing.Count > 0 {
+			timing.Average = timing.Total / time.Duration(timing.Count)
+		}
+		timing.last = 0
+	}
+	return timings
+}
+
+func stageRead(t *StageTimings) *StageTiming     { return &t.Read }
+func stageParse(t *StageTimings) *StageTiming    { return &t.Parse }
+func stageDecode(t *StageTimings) *StageTiming   { return &t.Decode }
+func stageDispatch(t *StageTimings) *StageTiming { return &t.Dispatch }
@@ -0,0 +1,32 @@
+package twitch_test
+
+import (
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageTimings(t *testing.T) {
+	t.Parallel()
+
+	client := twitch.NewClient()
+	client.SetSynchronousDispatch(true)
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {})
+
+	assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 1}))
+	assert.Zero(t, client.StageTimings(), "stage timing is disabled by default")
+
+	client.SetStageTiming(true)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, client.InjectNotification(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: i}))
+	}
+
+	timings := client.StageTimings()
+	assert.EqualValues(t, 3, timings.Parse.Count)
+	assert.EqualValues(t, 3, timings.Decode.Count)
+	assert.EqualValues(t, 3, timings.Dispatch.Count)
+	assert.Zero(t, timings.Read.Count, "injected messages are not read")
+	assert.LessOrEqual(t, timings.Decode.Average, timings.Decode.Max)
+	assert.Equal(t, timings.Decode.Total/3, timings.Decode.Average)
+}
@@ -0,0 +1,114 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+)
+
+// sessions are the connected clients by the ID of their session, so the subscriptions
+// created and deleted with SubscribeEvent, UnsubscribeEvent, and their variants are
+// tracked by the client of the session.
+var (
+	sessionsMu sync.Mutex
+	sessions   = make(map[string]*Client)
+)
+
+func (c *Client) setSession(session PayloadSession) {
+	c.mu.Lock()
+	previous := c.session.ID
+	c.session = session
+	c.mu.Unlock()
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if sessions[previous] == c {
+		delete(sessions, previous)
+	}
+	if session.ID != "" {
+		sessions[session.ID] = c
+	}
+}
+
+// forgetSession stops tracking subscriptions for the session once the connection ends.
+func (c *Client) forgetSession() {
+	id := c.sessionID()
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if sessions[id] == c {
+		delete(sessions, id)
+	}
+}
+
+// trackSessionSubscriptions tracks created subscriptions with the client of their
+// session, or of the session of the request when the response has none.
+func trackSessionSubscriptions(subscriptions []PayloadSubscription, sessionID string) {
+	for _, subscription := range subscriptions {
+		id := subscription.Transport.SessionID
+		if id == "" {
+			id = sessionID
+		}
+
+		sessionsMu.Lock()
+		client := sessions[id]
+		sessionsMu.Unlock()
+		if client != nil {
+			client.trackSubscription(subscription)
+		}
+	}
+}
+
+// untrackSessionSubscription stops tracking a deleted subscription.
+func untrackSessionSubscription(id string) {
+	sessionsMu.Lock()
+	clients := make([]*Client, 0, len(sessions))
+	for _, client := range sessions {
+		clients = append(clients, client)
+	}
+	sessionsMu.Unlock()
+
+	for _, client := range clients {
+		client.removeSubscription(id)
+	}
+}
+
+func (c *Client) sessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session.ID
+}
+
+func (c *Client) Session() PayloadSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
+}
+
+func (c *Client) trackSubscription(subscription PayloadSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscriptions[subscription.ID] = subscription
+}
+
+func (c *Client) removeSubscription(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subscriptions, id)
+}
+
+// Subscriptions returns the subscriptions created for the sessions of the client, with
+// Subscribe or SubscribeEvent and its variants, and those seen in notifications, which
+// have not been deleted or revoked.
+func (c *Client) Subscriptions() []PayloadSubscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subscriptions := make([]PayloadSubscription, 0, len(c.subscriptions))
+	for _, subscription := range c.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions
+}
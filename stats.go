@@ -0,0 +1,93 @@
+package twitch
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Stats struct {
+	Messages       int64
+	Notifications  int64
+	KeepAlives     int64
+	Reconnects     int64
+	Revocations    int64
+	Errors         int64
+	LastLatency    time.Duration
+	AverageLatency time.Duration
+	MaxLatency     time.Duration
+
+	HandlerCalls           int64
+	AverageHandlerDuration time.Duration
+	MaxHandlerDuration     time.Duration
+	// HandlersRunning is how many handler calls were dispatched and have not returned.
+	HandlersRunning int64
+}
+
+type clientStats struct {
+	mu sync.Mutex
+
+	stats                Stats
+	totalLatency         time.Duration
+	totalHandlerDuration time.Duration
+	// running counts the handler calls in progress without taking mu.
+	running atomic.Int64
+}
+
+func (s *clientStats) incr(field func(stats *Stats) *int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*field(&s.stats)++
+}
+
+func (s *clientStats) addNotification(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Notifications++
+	s.stats.LastLatency = latency
+	s.totalLatency += latency
+	if latency > s.stats.MaxLatency {
+		s.stats.MaxLatency = latency
+	}
+}
+
+func (s *clientStats) addHandlerDuration(duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.HandlerCalls++
+	s.totalHandlerDuration += duration
+	if duration > s.stats.MaxHandlerDuration {
+		s.stats.MaxHandlerDuration = duration
+	}
+}
+
+func (s *clientStats) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.HandlersRunning = s.running.Load()
+	if stats.Notifications > 0 {
+		stats.AverageLatency = s.totalLatency / time.Duration(stats.Notifications)
+	}
+	if stats.HandlerCalls > 0 {
+		stats.AverageHandlerDuration = s.totalHandlerDuration / time.Duration(stats.HandlerCalls)
+	}
+	return stats
+}
+
+func statMessages(s *Stats) *int64    { return &s.Messages }
+func statKeepAlives(s *Stats) *int64  { return &s.KeepAlives }
+func statReconnects(s *Stats) *int64  { return &s.Reconnects }
+func statRevocations(s *Stats) *int64 { return &s.Revocations }
+func statErrors(s *Stats) *int64      { return &s.Errors }
+
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// WebhookStats returns the stats of the callbacks handled by a WebhookRelay, which are
+// kept out of Stats so they do not skew the websocket latency.
+func (c *Client) WebhookStats() Stats {
+	return c.webhookStats.snapshot()
+}
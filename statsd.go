@@ -0,0 +1,99 @@
+package twitch
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+type StatsDConfig struct {
+	// Prefix is prepended to every metric name, e.g. "twitch."
+	Prefix string
+	// DogStatsD sends the subscription type as a tag instead of as part of the metric name.
+	DogStatsD bool
+	// Tags are added to every metric. Only used with DogStatsD.
+	Tags []string
+
+	// Sample rates between 0 and 1. A rate of 0 is treated as 1.
+	EventSampleRate   float64
+	LatencySampleRate float64
+}
+
+type StatsD struct {
+	config StatsDConfig
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStatsD(address string, config StatsDConfig) (*StatsD, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial statsd %s: %w", address, err)
+	}
+	return NewStatsDWriter(conn, config), nil
+}
+
+func NewStatsDWriter(w io.Writer, config StatsDConfig) *StatsD {
+	return &StatsD{
+		config: config,
+		w:      w,
+	}
+}
+
+func (s *StatsD) Notification(subscriptionType EventSubscription, latency time.Duration) {
+	name, tags := "events", []string(nil)
+	if s.config.DogStatsD {
+		tags = []string{"type:" + string(subscriptionType)}
+	} else {
+		name += "." + strings.ReplaceAll(string(subscriptionType), ".", "_")
+	}
+
+	s.send(name, "1", "c", s.config.EventSampleRate, tags)
+	s.send("latency", fmt.Sprintf("%d", latency.Milliseconds()), "ms", s.config.LatencySampleRate, tags)
+}
+
+func (s *StatsD) Reconnect() {
+	s.send("reconnects", "1", "c", 1, nil)
+}
+
+func (s *StatsD) Error() {
+	s.send("errors", "1", "c", 1, nil)
+}
+
+func (s *StatsD) Close() error {
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *StatsD) send(name, value, metricType string, rate float64, tags []string) {
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	if rate < 1 && rand.Float64() >= rate {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s:%s|%s", s.config.Prefix, name, value, metricType)
+	if rate < 1 {
+		fmt.Fprintf(&b, "|@%g", rate)
+	}
+	if s.config.DogStatsD {
+		tags = append(append([]string(nil), s.config.Tags...), tags...)
+		if len(tags) > 0 {
+			fmt.Fprintf(&b, "|#%s", strings.Join(tags, ","))
+		}
+	}
+	b.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write([]byte(b.String()))
+}
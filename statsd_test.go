@@ -0,0 +1,39 @@
+package twitch
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestStatsD(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Config   StatsDConfig
+		Expected string
+	}{
+		{
+			"StatsD",
+			StatsDConfig{Prefix: "twitch."},
+			"twitch.events.channel_follow:1|c\ntwitch.latency:150|ms\ntwitch.reconnects:1|c\n",
+		},
+		{
+			"DogStatsD",
+			StatsDConfig{DogStatsD: true, Tags: []string{"env:test"}},
+			"events:1|c|#env:test,type:channel.follow\nlatency:150|ms|#env:test,type:channel.follow\nreconnects:1|c|#env:test\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			statsd := NewStatsDWriter(&buf, tc.Config)
+			statsd.Notification(SubChannelFollow, 150*time.Millisecond)
+			statsd.Reconnect()
+
+			if buf.String() != tc.Expected {
+				t.Errorf("expected %q got %q", tc.Expected, buf.String())
+			}
+		})
+	}
+}
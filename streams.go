@@ -0,0 +1,59 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// StreamSession is a stream from its stream.online event to its stream.offline event.
+type StreamSession struct {
+	Online  EventStreamOnline
+	EndedAt time.Time
+}
+
+func (s StreamSession) Duration() time.Duration {
+	return s.EndedAt.Sub(s.Online.StartedAt)
+}
+
+// StreamSessions pairs the stream.online and stream.offline events of broadcasters.
+// stream.offline events do not carry a timestamp, so the end of a stream is the
+// timestamp of the notification. It is safe to use from concurrent callbacks.
+type StreamSessions struct {
+	mu     sync.Mutex
+	online map[string]EventStreamOnline
+}
+
+func NewStreamSessions() *StreamSessions {
+	return &StreamSessions{online: make(map[string]EventStreamOnline)}
+}
+
+// Online starts the session of the broadcaster, replacing any session it did not end.
+func (s *StreamSessions) Online(event EventStreamOnline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.online[event.BroadcasterUserId] = event
+}
+
+// Offline ends the session of the broadcaster at the timestamp of the notification. The
+// bool is false if the broadcaster was not online.
+func (s *StreamSessions) Offline(event EventStreamOffline, payloadContext PayloadContext) (StreamSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	online, ok := s.online[event.BroadcasterUserId]
+	if !ok {
+		return StreamSession{}, false
+	}
+	delete(s.online, event.BroadcasterUserId)
+	return StreamSession{Online: online, EndedAt: payloadContext.Metadata.MessageTimestamp}, true
+}
+
+// Live returns the stream.online event of the broadcaster if it is online.
+func (s *StreamSessions) Live(broadcasterID string) (EventStreamOnline, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	online, ok := s.online[broadcasterID]
+	return online, ok
+}
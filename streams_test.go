@@ -0,0 +1,36 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamSessions(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := NewStreamSessions()
+	sessions.Online(EventStreamOnline{
+		Broadcaster: Broadcaster{BroadcasterUserId: "1"},
+		Type:        StreamTypeLive,
+		StartedAt:   startedAt,
+	})
+
+	online, ok := sessions.Live("1")
+	if !ok || !online.IsLive() {
+		t.Fatalf("expected broadcaster 1 to be live got %v", online)
+	}
+	if uptime := online.Uptime(startedAt.Add(time.Minute)); uptime != time.Minute {
+		t.Errorf("expected an uptime of 1m got %s", uptime)
+	}
+
+	payloadContext := PayloadContext{Metadata: MessageMetadata{MessageTimestamp: startedAt.Add(2 * time.Hour)}}
+	session, ok := sessions.Offline(EventStreamOffline{BroadcasterUserId: "1"}, payloadContext)
+	if !ok {
+		t.Fatal("expected the session to end")
+	}
+	if session.Duration() != 2*time.Hour {
+		t.Errorf("expected a duration of 2h got %s", session.Duration())
+	}
+	if _, ok := sessions.Offline(EventStreamOffline{BroadcasterUserId: "1"}, payloadContext); ok {
+		t.Error("expected no session for an offline broadcaster")
+	}
+}
@@ -0,0 +1,418 @@
+// Code generated by specgen from eventsub.json. DO NOT EDIT.
+
+package twitch
+
+var (
+	SubChannelUpdate EventSubscription = "channel.update"
+	SubChannelFollow EventSubscription = "channel.follow"
+
+	SubChannelSubscribe           EventSubscription = "channel.subscribe"
+	SubChannelSubscriptionEnd     EventSubscription = "channel.subscription.end"
+	SubChannelSubscriptionGift    EventSubscription = "channel.subscription.gift"
+	SubChannelSubscriptionMessage EventSubscription = "channel.subscription.message"
+
+	SubChannelCheer EventSubscription = "channel.cheer"
+	SubChannelRaid  EventSubscription = "channel.raid"
+	SubChannelBan   EventSubscription = "channel.ban"
+	SubChannelUnban EventSubscription = "channel.unban"
+
+	SubChannelModeratorAdd    EventSubscription = "channel.moderator.add"
+	SubChannelModeratorRemove EventSubscription = "channel.moderator.remove"
+	SubChannelVIPAdd          EventSubscription = "channel.vip.add"
+	SubChannelVIPRemove       EventSubscription = "channel.vip.remove"
+
+	SubChannelChannelPointsCustomRewardAdd              EventSubscription = "channel.channel_points_custom_reward.add"
+	SubChannelChannelPointsCustomRewardUpdate           EventSubscription = "channel.channel_points_custom_reward.update"
+	SubChannelChannelPointsCustomRewardRemove           EventSubscription = "channel.channel_points_custom_reward.remove"
+	SubChannelChannelPointsCustomRewardRedemptionAdd    EventSubscription = "channel.channel_points_custom_reward_redemption.add"
+	SubChannelChannelPointsCustomRewardRedemptionUpdate EventSubscription = "channel.channel_points_custom_reward_redemption.update"
+	SubChannelChannelPointsAutomaticRewardRedemptionAdd EventSubscription = "channel.channel_points_automatic_reward_redemption.add"
+
+	SubChannelPollBegin    EventSubscription = "channel.poll.begin"
+	SubChannelPollProgress EventSubscription = "channel.poll.progress"
+	SubChannelPollEnd      EventSubscription = "channel.poll.end"
+
+	SubChannelPredictionBegin    EventSubscription = "channel.prediction.begin"
+	SubChannelPredictionProgress EventSubscription = "channel.prediction.progress"
+	SubChannelPredictionLock     EventSubscription = "channel.prediction.lock"
+	SubChannelPredictionEnd      EventSubscription = "channel.prediction.end"
+
+	SubDropEntitlementGrant           EventSubscription = "drop.entitlement.grant"
+	SubExtensionBitsTransactionCreate EventSubscription = "extension.bits_transaction.create"
+
+	SubChannelGoalBegin    EventSubscription = "channel.goal.begin"
+	SubChannelGoalProgress EventSubscription = "channel.goal.progress"
+	SubChannelGoalEnd      EventSubscription = "channel.goal.end"
+
+	SubChannelHypeTrainBegin    EventSubscription = "channel.hype_train.begin"
+	SubChannelHypeTrainProgress EventSubscription = "channel.hype_train.progress"
+	SubChannelHypeTrainEnd      EventSubscription = "channel.hype_train.end"
+
+	SubStreamOnline  EventSubscription = "stream.online"
+	SubStreamOffline EventSubscription = "stream.offline"
+
+	SubUserAuthorizationGrant  EventSubscription = "user.authorization.grant"
+	SubUserAuthorizationRevoke EventSubscription = "user.authorization.revoke"
+	SubUserUpdate              EventSubscription = "user.update"
+
+	SubChannelCharityCampaignDonate   EventSubscription = "channel.charity_campaign.donate"
+	SubChannelCharityCampaignStart    EventSubscription = "channel.charity_campaign.start"
+	SubChannelCharityCampaignProgress EventSubscription = "channel.charity_campaign.progress"
+	SubChannelCharityCampaignStop     EventSubscription = "channel.charity_campaign.stop"
+
+	SubChannelShieldModeBegin EventSubscription = "channel.shield_mode.begin"
+	SubChannelShieldModeEnd   EventSubscription = "channel.shield_mode.end"
+
+	SubChannelShoutoutCreate  EventSubscription = "channel.shoutout.create"
+	SubChannelShoutoutReceive EventSubscription = "channel.shoutout.receive"
+
+	SubChannelModerate EventSubscription = "channel.moderate"
+
+	SubChannelAdBreakBegin EventSubscription = "channel.ad_break.begin"
+
+	SubChannelWarningAcknowledge EventSubscription = "channel.warning.acknowledge"
+	SubChannelWarningSend        EventSubscription = "channel.warning.send"
+
+	SubChannelUnbanRequestCreate  EventSubscription = "channel.unban_request.create"
+	SubChannelUnbanRequestResolve EventSubscription = "channel.unban_request.resolve"
+
+	SubAutomodMessageHold           EventSubscription = "automod.message.hold"
+	SubAutomodMessageUpdate         EventSubscription = "automod.message.update"
+	SubAutomodSettingsUpdate        EventSubscription = "automod.settings.update"
+	SubAutomodTermsUpdate           EventSubscription = "automod.terms.update"
+	SubChannelChatUserMessageHold   EventSubscription = "channel.chat.user_message_hold"
+	SubChannelChatUserMessageUpdate EventSubscription = "channel.chat.user_message_update"
+
+	SubChannelChatClear             EventSubscription = "channel.chat.clear"
+	SubChannelChatClearUserMessages EventSubscription = "channel.chat.clear_user_messages"
+	SubChannelChatMessage           EventSubscription = "channel.chat.message"
+	SubChannelChatMessageDelete     EventSubscription = "channel.chat.message_delete"
+	SubChannelChatNotification      EventSubscription = "channel.chat.notification"
+	SubChannelChatSettingsUpdate    EventSubscription = "channel.chat_settings.update"
+	SubChannelSuspiciousUserMessage EventSubscription = "channel.suspicious_user.message"
+	SubChannelSuspiciousUserUpdate  EventSubscription = "channel.suspicious_user.update"
+
+	SubChannelSharedChatBegin  EventSubscription = "channel.shared_chat.begin"
+	SubChannelSharedChatUpdate EventSubscription = "channel.shared_chat.update"
+	SubChannelSharedChatEnd    EventSubscription = "channel.shared_chat.end"
+
+	SubChannelGuestStarSessionBegin   EventSubscription = "channel.guest_star_session.begin"
+	SubChannelGuestStarSessionEnd     EventSubscription = "channel.guest_star_session.end"
+	SubChannelGuestStarGuestUpdate    EventSubscription = "channel.guest_star_guest.update"
+	SubChannelGuestStarSettingsUpdate EventSubscription = "channel.guest_star_settings.update"
+
+	SubUserWhisperMessage EventSubscription = "user.whisper.message"
+
+	SubConduitShardDisabled EventSubscription = "conduit.shard.disabled"
+)
+
+var subMetadata = map[EventSubscription]subscriptionMetadata{
+	SubChannelUpdate: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelUpdate](),
+	},
+	SubChannelFollow: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelFollow](),
+	},
+	SubChannelSubscribe: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSubscribe](),
+	},
+	SubChannelSubscriptionEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSubscriptionEnd](),
+	},
+	SubChannelSubscriptionGift: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSubscriptionGift](),
+	},
+	SubChannelSubscriptionMessage: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSubscriptionMessage](),
+	},
+	SubChannelCheer: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelCheer](),
+	},
+	SubChannelRaid: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelRaid](),
+	},
+	SubChannelBan: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelBan](),
+	},
+	SubChannelUnban: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelUnban](),
+	},
+	SubChannelModeratorAdd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelModeratorAdd](),
+	},
+	SubChannelModeratorRemove: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelModeratorRemove](),
+	},
+	SubChannelVIPAdd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelVIPAdd](),
+	},
+	SubChannelVIPRemove: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelVIPRemove](),
+	},
+	SubChannelChannelPointsCustomRewardAdd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsCustomRewardAdd](),
+	},
+	SubChannelChannelPointsCustomRewardUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsCustomRewardUpdate](),
+	},
+	SubChannelChannelPointsCustomRewardRemove: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsCustomRewardRemove](),
+	},
+	SubChannelChannelPointsCustomRewardRedemptionAdd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsCustomRewardRedemptionAdd](),
+	},
+	SubChannelChannelPointsCustomRewardRedemptionUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsCustomRewardRedemptionUpdate](),
+	},
+	SubChannelChannelPointsAutomaticRewardRedemptionAdd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChannelPointsAutomaticRewardRedemptionAdd](),
+	},
+	SubChannelPollBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPollBegin](),
+	},
+	SubChannelPollProgress: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPollProgress](),
+	},
+	SubChannelPollEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPollEnd](),
+	},
+	SubChannelPredictionBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPredictionBegin](),
+	},
+	SubChannelPredictionProgress: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPredictionProgress](),
+	},
+	SubChannelPredictionLock: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPredictionLock](),
+	},
+	SubChannelPredictionEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelPredictionEnd](),
+	},
+	SubDropEntitlementGrant: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventDropEntitlementGrantBatch](),
+	},
+	SubExtensionBitsTransactionCreate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventExtensionBitsTransactionCreate](),
+	},
+	SubChannelGoalBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelGoalBegin](),
+	},
+	SubChannelGoalProgress: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelGoalProgress](),
+	},
+	SubChannelGoalEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelGoalEnd](),
+	},
+	SubChannelHypeTrainBegin: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelHypeTrainBegin](),
+	},
+	SubChannelHypeTrainProgress: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelHypeTrainProgress](),
+	},
+	SubChannelHypeTrainEnd: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelHypeTrainEnd](),
+	},
+	SubStreamOnline: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventStreamOnline](),
+	},
+	SubStreamOffline: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventStreamOffline](),
+	},
+	SubUserAuthorizationGrant: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventUserAuthorizationGrant](),
+	},
+	SubUserAuthorizationRevoke: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventUserAuthorizationRevoke](),
+	},
+	SubUserUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventUserUpdate](),
+	},
+	SubChannelCharityCampaignDonate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelCharityCampaignDonate](),
+	},
+	SubChannelCharityCampaignStart: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelCharityCampaignStart](),
+	},
+	SubChannelCharityCampaignProgress: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelCharityCampaignProgress](),
+	},
+	SubChannelCharityCampaignStop: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelCharityCampaignStop](),
+	},
+	SubChannelShieldModeBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelShieldModeBegin](),
+	},
+	SubChannelShieldModeEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelShieldModeEnd](),
+	},
+	SubChannelShoutoutCreate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelShoutoutCreate](),
+	},
+	SubChannelShoutoutReceive: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelShoutoutReceive](),
+	},
+	SubChannelModerate: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventChannelModerate](),
+	},
+	SubChannelAdBreakBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelAdBreakBegin](),
+	},
+	SubChannelWarningAcknowledge: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelWarningAcknowledge](),
+	},
+	SubChannelWarningSend: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelWarningSend](),
+	},
+	SubChannelUnbanRequestCreate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelUnbanRequestCreate](),
+	},
+	SubChannelUnbanRequestResolve: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelUnbanRequestResolve](),
+	},
+	SubAutomodMessageHold: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventAutomodMessageHold](),
+	},
+	SubAutomodMessageUpdate: {
+		Version:  "2",
+		EventGen: zeroPtrGen[EventAutomodMessageUpdate](),
+	},
+	SubAutomodSettingsUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventAutomodSettingsUpdate](),
+	},
+	SubAutomodTermsUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventAutomodTermsUpdate](),
+	},
+	SubChannelChatUserMessageHold: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatUserMessageHold](),
+	},
+	SubChannelChatUserMessageUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatUserMessageUpdate](),
+	},
+	SubChannelChatClear: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatClear](),
+	},
+	SubChannelChatClearUserMessages: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatClearUserMessages](),
+	},
+	SubChannelChatMessage: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatMessage](),
+	},
+	SubChannelChatMessageDelete: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatMessageDelete](),
+	},
+	SubChannelChatNotification: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatNotification](),
+	},
+	SubChannelChatSettingsUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelChatSettingsUpdate](),
+	},
+	SubChannelSuspiciousUserMessage: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSuspiciousUserMessage](),
+	},
+	SubChannelSuspiciousUserUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSuspiciousUserUpdate](),
+	},
+	SubChannelSharedChatBegin: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSharedChatBegin](),
+	},
+	SubChannelSharedChatUpdate: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSharedChatUpdate](),
+	},
+	SubChannelSharedChatEnd: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventChannelSharedChatEnd](),
+	},
+	SubChannelGuestStarSessionBegin: {
+		Version:  "beta",
+		EventGen: zeroPtrGen[EventChannelGuestStarSessionBegin](),
+	},
+	SubChannelGuestStarSessionEnd: {
+		Version:  "beta",
+		EventGen: zeroPtrGen[EventChannelGuestStarSessionEnd](),
+	},
+	SubChannelGuestStarGuestUpdate: {
+		Version:  "beta",
+		EventGen: zeroPtrGen[EventChannelGuestStarGuestUpdate](),
+	},
+	SubChannelGuestStarSettingsUpdate: {
+		Version:  "beta",
+		EventGen: zeroPtrGen[EventChannelGuestStarSettingsUpdate](),
+	},
+	SubUserWhisperMessage: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventUserWhisperMessage](),
+	},
+	SubConduitShardDisabled: {
+		Version:  "1",
+		EventGen: zeroPtrGen[EventConduitShardDisabled](),
+	},
+}
@@ -1,11 +1,14 @@
 package twitch_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/isabelcoolaf/go-twitch-eventsub"
@@ -60,3 +63,55 @@ func TestEventVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestSpecIsGenerated(t *testing.T) {
+	data, err := os.ReadFile("eventsub.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec struct {
+		Subscriptions [][]struct {
+			Type    string `json:"type"`
+			Version string `json:"version"`
+		} `json:"subscriptions"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range spec.Subscriptions {
+		for _, sub := range group {
+			event := twitch.EventSubscription(sub.Type)
+			if event.Version() != sub.Version {
+				t.Errorf("%s: expected version %q got %q, run go generate", sub.Type, sub.Version, event.Version())
+			}
+		}
+	}
+}
+
+func TestUnsubscribeEvent(t *testing.T) {
+	status := http.StatusNoContent
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	request := twitch.UnsubscribeRequest{ClientID: "client", AccessToken: "token", ID: "a b"}
+	if err := twitch.UnsubscribeEventUrlWithContext(context.Background(), request, server.URL); err != nil {
+		t.Error(err)
+	}
+	status = http.StatusNotFound
+	if err := twitch.UnsubscribeEventUrlWithContext(context.Background(), request, server.URL); err != nil {
+		t.Errorf("expected a missing subscription to be deleted, got %v", err)
+	}
+	status = http.StatusUnauthorized
+	if err := twitch.UnsubscribeEventUrlWithContext(context.Background(), request, server.URL); err == nil {
+		t.Error("expected an error")
+	}
+	if len(requests) != 3 || requests[0] != "DELETE /?id=a+b Bearer token" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
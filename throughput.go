@@ -0,0 +1,25 @@
+package twitch
+
+import "runtime"
+
+const (
+	highThroughputMaxHandlerGoroutines = 1024
+	highThroughputMaxReadBuffer        = 256 * 1024
+)
+
+// WithHighThroughput tunes the client for high event rates: frames are decoded on one
+// worker per CPU into pooled structs, handlers run on a larger bounded worker pool, and
+// bigger read buffers are kept between frames. Generated decoders are left off, since
+// they allocate more than encoding/json; enable them with SetGeneratedDecoders to trade
+// allocations for decoding time. Events without a registered handler are never decoded
+// regardless of profile. It returns the client so it can be chained:
+//
+//	client := twitch.NewClient().WithHighThroughput()
+func (c *Client) WithHighThroughput() *Client {
+	c.SetEventPooling(true)
+	c.SetMaxHandlerGoroutines(highThroughputMaxHandlerGoroutines)
+	c.SetMaxReadBuffer(highThroughputMaxReadBuffer)
+	c.SetDecodeWorkers(runtime.GOMAXPROCS(0))
+	c.SetSynchronousDispatch(false)
+	return c
+}
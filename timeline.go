@@ -0,0 +1,97 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// MessageID returns the ID of the chat message an event is about, for chat messages,
+// deletions, automod holds, and suspicious user messages. The event is the value type,
+// like the events passed to OnEvent.
+func MessageID(event any) (string, bool) {
+	var id string
+	switch event := event.(type) {
+	case EventChannelChatMessage:
+		id = event.MessageId
+	case EventChannelChatMessageDelete:
+		id = event.MessageId
+	case EventAutomodMessageHold:
+		id = event.MessageId
+	case EventAutomodMessageUpdate:
+		id = event.MessageId
+	case EventChannelChatUserMessageHold:
+		id = event.MessageId
+	case EventChannelChatUserMessageUpdate:
+		id = event.MessageId
+	case EventChannelSuspiciousUserMessage:
+		id = event.Message.MessageId
+	}
+	return id, id != ""
+}
+
+type MessageTimelineEntry struct {
+	Subscription EventSubscription
+	Event        any
+	At           time.Time
+}
+
+// MessageTimeline indexes the events about chat messages by message ID, so what happened
+// to a message can be looked up after it was sent, held, or deleted. It keeps the events
+// of the most recent messages up to its capacity. It is safe to use from concurrent
+// callbacks.
+//
+//	timeline := twitch.NewMessageTimeline(10000)
+//	client.OnEvent(timeline.Add)
+type MessageTimeline struct {
+	mu       sync.Mutex
+	capacity int
+	messages map[string][]MessageTimelineEntry
+	order    []string
+}
+
+func NewMessageTimeline(capacity int) *MessageTimeline {
+	return &MessageTimeline{
+		capacity: capacity,
+		messages: make(map[string][]MessageTimelineEntry),
+	}
+}
+
+// Add records the event under the message it is about, ignoring events about no message.
+func (t *MessageTimeline) Add(event any, payloadContext PayloadContext) {
+	id, ok := MessageID(event)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries, ok := t.messages[id]
+	if !ok {
+		t.order = append(t.order, id)
+		if t.capacity > 0 && len(t.order) > t.capacity {
+			delete(t.messages, t.order[0])
+			t.order = t.order[1:]
+		}
+	}
+	t.messages[id] = append(entries, MessageTimelineEntry{
+		Subscription: payloadContext.Subscription.Type,
+		Event:        event,
+		At:           payloadContext.Metadata.MessageTimestamp,
+	})
+}
+
+// Get returns the events about the message in the order they were added.
+func (t *MessageTimeline) Get(messageID string) []MessageTimelineEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]MessageTimelineEntry(nil), t.messages[messageID]...)
+}
+
+func (t *MessageTimeline) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.messages)
+}
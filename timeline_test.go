@@ -0,0 +1,38 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessageTimeline(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	context := func(sub EventSubscription, offset time.Duration) PayloadContext {
+		payloadContext := PayloadContext{Metadata: MessageMetadata{MessageTimestamp: at.Add(offset)}}
+		payloadContext.Subscription.Type = sub
+		return payloadContext
+	}
+
+	timeline := NewMessageTimeline(2)
+	timeline.Add(EventChannelChatMessage{MessageId: "a"}, context(SubChannelChatMessage, 0))
+	timeline.Add(EventChannelSuspiciousUserMessage{Message: SuspiciousUserChatMessage{MessageId: "a"}}, context(SubChannelSuspiciousUserMessage, time.Second))
+	timeline.Add(EventChannelChatMessageDelete{MessageId: "a"}, context(SubChannelChatMessageDelete, 2*time.Second))
+	timeline.Add(EventChannelFollow{}, context(SubChannelFollow, 0))
+
+	entries := timeline.Get("a")
+	expected := []EventSubscription{SubChannelChatMessage, SubChannelSuspiciousUserMessage, SubChannelChatMessageDelete}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries got %v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry.Subscription != expected[i] || !entry.At.Equal(at.Add(time.Duration(i)*time.Second)) {
+			t.Errorf("unexpected entry %d %v", i, entry)
+		}
+	}
+
+	timeline.Add(EventAutomodMessageHold{MessageId: "b"}, context(SubAutomodMessageHold, 0))
+	timeline.Add(EventChannelChatMessage{MessageId: "c"}, context(SubChannelChatMessage, 0))
+	if timeline.Len() != 2 || len(timeline.Get("a")) != 0 {
+		t.Errorf("expected the oldest message to be evicted")
+	}
+}
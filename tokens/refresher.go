@@ -0,0 +1,181 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// DefaultTokenURL is the endpoint tokens are refreshed with.
+const DefaultTokenURL = "https://id.twitch.tv/oauth2/token"
+
+// ErrRevoked is returned when Twitch refuses to refresh a token, like when the user
+// disconnected the application. The user has to authorize it again.
+var ErrRevoked = errors.New("tokens: refresh token invalid or revoked")
+
+// Refresher returns the access tokens of a store, refreshing the ones about to expire
+// and storing the refreshed tokens. It is safe for concurrent use, and refreshes the
+// token of a user once when it is asked for it concurrently.
+type Refresher struct {
+	Store        Store
+	ClientID     string
+	ClientSecret string
+
+	// Margin is how long before they expire tokens are refreshed. Defaults to 5 minutes.
+	Margin time.Duration
+	// URL defaults to DefaultTokenURL.
+	URL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock defaults to the system clock.
+	Clock twitch.Clock
+
+	mu    sync.Mutex
+	users map[string]*sync.Mutex
+}
+
+func NewRefresher(store Store, clientID, clientSecret string) *Refresher {
+	return &Refresher{Store: store, ClientID: clientID, ClientSecret: clientSecret}
+}
+
+// AccessToken returns the access token of the user, refreshed if it is about to expire.
+func (r *Refresher) AccessToken(ctx context.Context, userID string) (string, error) {
+	token, err := r.Token(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// Token returns the token of the user, refreshed if it is about to expire.
+func (r *Refresher) Token(ctx context.Context, userID string) (Token, error) {
+	lock := r.lock(userID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	token, err := r.Store.Get(ctx, userID)
+	if err != nil {
+		return Token{}, err
+	}
+	margin := r.Margin
+	if margin <= 0 {
+		margin = 5 * time.Minute
+	}
+	if !token.ExpiresWithin(r.now(), margin) {
+		return token, nil
+	}
+	return r.refresh(ctx, token)
+}
+
+// Refresh refreshes the token of the user whether it is about to expire or not, like
+// when the API rejected it.
+func (r *Refresher) Refresh(ctx context.Context, userID string) (Token, error) {
+	lock := r.lock(userID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	token, err := r.Store.Get(ctx, userID)
+	if err != nil {
+		return Token{}, err
+	}
+	return r.refresh(ctx, token)
+}
+
+func (r *Refresher) lock(userID string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.users == nil {
+		r.users = make(map[string]*sync.Mutex)
+	}
+	lock, ok := r.users[userID]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.users[userID] = lock
+	}
+	return lock
+}
+
+func (r *Refresher) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
+}
+
+func (r *Refresher) refresh(ctx context.Context, token Token) (Token, error) {
+	if token.RefreshToken == "" {
+		return Token{}, fmt.Errorf("could not refresh token of %s: no refresh token", token.UserID)
+	}
+
+	tokenURL := r.URL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+		"client_id":     {r.ClientID},
+		"client_secret": {r.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, fmt.Errorf("could not create new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("could not refresh token of %s: %w", token.UserID, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return Token{}, fmt.Errorf("could not refresh token of %s: %w: %s", token.UserID, ErrRevoked, body)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("could not refresh token of %s: %s: %s", token.UserID, resp.Status, body)
+	}
+
+	var refreshed struct {
+		AccessToken  string   `json:"access_token"`
+		RefreshToken string   `json:"refresh_token"`
+		ExpiresIn    int      `json:"expires_in"`
+		Scope        []string `json:"scope"`
+	}
+	err = json.Unmarshal(body, &refreshed)
+	if err != nil {
+		return Token{}, fmt.Errorf("could not unmarshal refreshed token: %w", err)
+	}
+
+	token.AccessToken = refreshed.AccessToken
+	if refreshed.RefreshToken != "" {
+		token.RefreshToken = refreshed.RefreshToken
+	}
+	if refreshed.Scope != nil {
+		token.Scopes = refreshed.Scope
+	}
+	token.ExpiresAt = time.Time{}
+	if refreshed.ExpiresIn > 0 {
+		token.ExpiresAt = r.now().Add(time.Duration(refreshed.ExpiresIn) * time.Second)
+	}
+	err = r.Store.Put(ctx, token)
+	if err != nil {
+		return Token{}, err
+	}
+	return token, nil
+}
@@ -0,0 +1,117 @@
+package tokens
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Table is the table SQLStore keeps the tokens in.
+const Table = "eventsub_tokens"
+
+// SQLStore keeps the tokens in a table of a PostgreSQL or SQLite database. It uses
+// database/sql, so the application registers the driver it prefers. Tokens are
+// credentials: restrict access to the table, or encrypt the database.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// OpenSQL creates the table if it does not exist.
+func OpenSQL(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+	user_id TEXT PRIMARY KEY,
+	login TEXT NOT NULL,
+	access_token TEXT NOT NULL,
+	refresh_token TEXT NOT NULL,
+	scopes TEXT NOT NULL,
+	expires_at TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not create tokens table: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+const columns = `user_id, login, access_token, refresh_token, scopes, expires_at`
+
+func (s *SQLStore) Get(ctx context.Context, userID string) (Token, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+columns+` FROM `+Table+` WHERE user_id = $1`, userID)
+	token, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Token{}, ErrNotFound
+	}
+	if err != nil {
+		return Token{}, fmt.Errorf("could not get token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *SQLStore) Put(ctx context.Context, token Token) error {
+	var expiresAt *time.Time
+	if !token.ExpiresAt.IsZero() {
+		utc := token.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO `+Table+` (`+columns+`, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id) DO UPDATE SET
+	login = excluded.login,
+	access_token = excluded.access_token,
+	refresh_token = excluded.refresh_token,
+	scopes = excluded.scopes,
+	expires_at = excluded.expires_at,
+	updated_at = excluded.updated_at`,
+		token.UserID, token.Login, token.AccessToken, token.RefreshToken, strings.Join(token.Scopes, " "), expiresAt, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("could not put token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, userID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM `+Table+` WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("could not delete token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]Token, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+columns+` FROM `+Table+` ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []Token
+	for rows.Next() {
+		token, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not list tokens: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not list tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func scan(row interface{ Scan(dest ...any) error }) (Token, error) {
+	var token Token
+	var scopes string
+	var expiresAt sql.NullTime
+	err := row.Scan(&token.UserID, &token.Login, &token.AccessToken, &token.RefreshToken, &scopes, &expiresAt)
+	if err != nil {
+		return Token{}, err
+	}
+	token.Scopes = strings.Fields(scopes)
+	if expiresAt.Valid {
+		token.ExpiresAt = expiresAt.Time
+	}
+	return token, nil
+}
@@ -0,0 +1,114 @@
+// Package tokens stores the OAuth tokens of the users who authorized the application,
+// like the broadcasters it subscribes to the events of, and refreshes them before they
+// expire. A Store keeps the tokens in memory or in an SQL database, and a Refresher
+// hands out access tokens from it.
+//
+//	store, err := tokens.OpenSQL(ctx, db)
+//	refresher := tokens.NewRefresher(store, clientID, clientSecret)
+//	// After the authorization code flow of a broadcaster:
+//	err = store.Put(ctx, tokens.Token{UserID: userID, AccessToken: access, RefreshToken: refresh, ExpiresAt: expiry})
+//	...
+//	accessToken, err := refresher.AccessToken(ctx, userID)
+package tokens
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for users without a token.
+var ErrNotFound = errors.New("tokens: no token for user")
+
+type Token struct {
+	UserID       string
+	Login        string
+	AccessToken  string
+	RefreshToken string
+	Scopes       []string
+	// ExpiresAt is zero for tokens which do not expire.
+	ExpiresAt time.Time
+}
+
+// ExpiresWithin reports whether the token expires before now plus d.
+func (t Token) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return !t.ExpiresAt.IsZero() && t.ExpiresAt.Before(now.Add(d))
+}
+
+// HasScope reports whether the token was granted the scope.
+func (t Token) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps a token per user. Implementations are safe for concurrent use.
+type Store interface {
+	// Get returns the token of the user, or ErrNotFound.
+	Get(ctx context.Context, userID string) (Token, error)
+	// Put adds or replaces the token of its user.
+	Put(ctx context.Context, token Token) error
+	// Delete removes the token of the user, if any, like when they revoke the
+	// authorization.
+	Delete(ctx context.Context, userID string) error
+	// List returns the tokens of every user, ordered by user ID.
+	List(ctx context.Context) ([]Token, error)
+}
+
+// MemoryStore keeps the tokens in memory, for tests and processes which load them from
+// elsewhere on start.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: make(map[string]Token)}
+}
+
+func (s *MemoryStore) Get(_ context.Context, userID string) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[userID]
+	if !ok {
+		return Token{}, ErrNotFound
+	}
+	return token, nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.Scopes = append([]string(nil), token.Scopes...)
+	s.tokens[token.UserID] = token
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, userID)
+	return nil
+}
+
+func (s *MemoryStore) List(_ context.Context) ([]Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := make([]Token, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].UserID < tokens[j].UserID
+	})
+	return tokens, nil
+}
@@ -0,0 +1,150 @@
+package tokens_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub/internal/sqltest"
+	"github.com/isabelcoolaf/go-twitch-eventsub/tokens"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := tokens.NewMemoryStore()
+	_, err := store.Get(ctx, "1")
+	assert.ErrorIs(t, err, tokens.ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, tokens.Token{UserID: "2", AccessToken: "b"}))
+	require.NoError(t, store.Put(ctx, tokens.Token{UserID: "1", AccessToken: "a", Scopes: []string{"channel:read:subscriptions"}}))
+	token, err := store.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "a", token.AccessToken)
+	assert.True(t, token.HasScope("channel:read:subscriptions"))
+	assert.False(t, token.HasScope("bits:read"))
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "1", list[0].UserID)
+
+	require.NoError(t, store.Delete(ctx, "1"))
+	_, err = store.Get(ctx, "1")
+	assert.ErrorIs(t, err, tokens.ErrNotFound)
+}
+
+func TestSQLStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, r := sqltest.Open(t)
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.Rows = func(query string, args []driver.Value) ([]string, [][]driver.Value) {
+		if args[0] != "1337" {
+			return nil, nil
+		}
+		return []string{"user_id", "login", "access_token", "refresh_token", "scopes", "expires_at"},
+			[][]driver.Value{{"1337", "streamer", "access", "refresh", "bits:read moderator:read:chatters", expiresAt}}
+	}
+	store, err := tokens.OpenSQL(ctx, db)
+	require.NoError(t, err)
+	assert.Contains(t, r.Statements()[0], "CREATE TABLE IF NOT EXISTS eventsub_tokens")
+
+	token, err := store.Get(ctx, "1337")
+	require.NoError(t, err)
+	assert.Equal(t, tokens.Token{
+		UserID:       "1337",
+		Login:        "streamer",
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Scopes:       []string{"bits:read", "moderator:read:chatters"},
+		ExpiresAt:    expiresAt,
+	}, token)
+	_, err = store.Get(ctx, "42")
+	assert.ErrorIs(t, err, tokens.ErrNotFound)
+
+	require.NoError(t, store.Put(ctx, token))
+	statements, args := r.Statements(), r.Args()
+	put := len(statements) - 1
+	assert.Contains(t, statements[put], "ON CONFLICT (user_id) DO UPDATE")
+	assert.Equal(t, "bits:read moderator:read:chatters", args[put][4])
+	assert.Equal(t, expiresAt, args[put][5])
+}
+
+func TestRefresher(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&refreshes, 1)
+		r.ParseForm()
+		if r.PostForm.Get("refresh_token") != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":400,"message":"Invalid refresh token"}`))
+			return
+		}
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+		w.Write([]byte(`{"access_token":"new","refresh_token":"refresh","expires_in":3600,"scope":["bits:read"]}`))
+	}))
+	defer server.Close()
+
+	clock := twitchtest.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	store := tokens.NewMemoryStore()
+	require.NoError(t, store.Put(ctx, tokens.Token{
+		UserID:       "1337",
+		AccessToken:  "old",
+		RefreshToken: "refresh",
+		ExpiresAt:    clock.Now().Add(time.Hour),
+	}))
+	require.NoError(t, store.Put(ctx, tokens.Token{
+		UserID:       "42",
+		AccessToken:  "old",
+		RefreshToken: "revoked",
+		ExpiresAt:    clock.Now(),
+	}))
+	refresher := tokens.NewRefresher(store, "client", "secret")
+	refresher.URL = server.URL
+	refresher.Clock = clock
+
+	accessToken, err := refresher.AccessToken(ctx, "1337")
+	require.NoError(t, err)
+	assert.Equal(t, "old", accessToken)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&refreshes))
+
+	// Tokens about to expire are refreshed once.
+	clock.Advance(56 * time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accessToken, err := refresher.AccessToken(ctx, "1337")
+			assert.NoError(t, err)
+			assert.Equal(t, "new", accessToken)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(&refreshes))
+	token, err := store.Get(ctx, "1337")
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now().Add(time.Hour), token.ExpiresAt)
+	assert.Equal(t, []string{"bits:read"}, token.Scopes)
+
+	_, err = refresher.AccessToken(ctx, "42")
+	assert.ErrorIs(t, err, tokens.ErrRevoked)
+	_, err = refresher.AccessToken(ctx, "7")
+	assert.ErrorIs(t, err, tokens.ErrNotFound)
+}
@@ -0,0 +1,144 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const trafficBuckets = 60
+
+var broadcasterConditionKeys = []string{
+	"broadcaster_user_id",
+	"to_broadcaster_user_id",
+	"from_broadcaster_user_id",
+	"user_id",
+}
+
+type TrafficCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+type TrafficReport struct {
+	Window        time.Duration  `json:"window"`
+	Total         int64          `json:"total"`
+	ByType        []TrafficCount `json:"by_type"`
+	ByBroadcaster []TrafficCount `json:"by_broadcaster"`
+}
+
+type trafficBucket struct {
+	start         time.Time
+	byType        map[string]int64
+	byBroadcaster map[string]int64
+}
+
+type trafficTracker struct {
+	mu sync.Mutex
+
+	window     time.Duration
+	bucketSize time.Duration
+	buckets    [trafficBuckets]trafficBucket
+}
+
+func newTrafficTracker(window time.Duration) *trafficTracker {
+	bucketSize := window / trafficBuckets
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+	return &trafficTracker{
+		window:     window,
+		bucketSize: bucketSize,
+	}
+}
+
+func (t *trafficTracker) add(now time.Time, subscription PayloadSubscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := now.Truncate(t.bucketSize)
+	bucket := &t.buckets[(start.UnixNano()/int64(t.bucketSize))%trafficBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = trafficBucket{
+			start:         start,
+			byType:        make(map[string]int64),
+			byBroadcaster: make(map[string]int64),
+		}
+	}
+
+	bucket.byType[string(subscription.Type)]++
+	if broadcaster := subscriptionBroadcaster(subscription); broadcaster != "" {
+		bucket.byBroadcaster[broadcaster]++
+	}
+}
+
+func (t *trafficTracker) report(now time.Time, top int) TrafficReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := TrafficReport{Window: t.window}
+	byType := make(map[string]int64)
+	byBroadcaster := make(map[string]int64)
+
+	cutoff := now.Add(-t.window)
+	for _, bucket := range t.buckets {
+		if bucket.start.IsZero() || !bucket.start.After(cutoff) {
+			continue
+		}
+
+		for key, count := range bucket.byType {
+			byType[key] += count
+			report.Total += count
+		}
+		for key, count := range bucket.byBroadcaster {
+			byBroadcaster[key] += count
+		}
+	}
+
+	report.ByType = topTraffic(byType, top)
+	report.ByBroadcaster = topTraffic(byBroadcaster, top)
+	return report
+}
+
+func topTraffic(counts map[string]int64, top int) []TrafficCount {
+	traffic := make([]TrafficCount, 0, len(counts))
+	for key, count := range counts {
+		traffic = append(traffic, TrafficCount{Key: key, Count: count})
+	}
+
+	sort.Slice(traffic, func(i, j int) bool {
+		if traffic[i].Count == traffic[j].Count {
+			return traffic[i].Key < traffic[j].Key
+		}
+		return traffic[i].Count > traffic[j].Count
+	})
+
+	if top > 0 && len(traffic) > top {
+		traffic = traffic[:top]
+	}
+	return traffic
+}
+
+func subscriptionBroadcaster(subscription PayloadSubscription) string {
+	for _, key := range broadcasterConditionKeys {
+		if id := subscription.Condition[key]; id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// EnableTrafficTracking counts notifications per subscription type and broadcaster over
+// a sliding window. It must be called before connecting.
+func (c *Client) EnableTrafficTracking(window time.Duration) {
+	c.traffic = newTrafficTracker(window)
+}
+
+// TrafficReport returns the busiest subscription types and broadcasters within the
+// tracking window, limited to top entries each. A top of 0 returns every entry.
+func (c *Client) TrafficReport(top int) TrafficReport {
+	if c.traffic == nil {
+		return TrafficReport{}
+	}
+	return c.traffic.report(c.now(), top)
+}
@@ -0,0 +1,35 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrafficReport(t *testing.T) {
+	tracker := newTrafficTracker(time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	subscription := func(event EventSubscription, broadcaster string) PayloadSubscription {
+		return PayloadSubscription{SubscriptionRequest: SubscriptionRequest{
+			Type:      event,
+			Condition: map[string]string{"broadcaster_user_id": broadcaster},
+		}}
+	}
+
+	tracker.add(now.Add(-2*time.Minute), subscription(SubChannelChatMessage, "old"))
+	for i := 0; i < 3; i++ {
+		tracker.add(now.Add(-time.Duration(i)*time.Second), subscription(SubChannelChatMessage, "1"))
+	}
+	tracker.add(now, subscription(SubChannelCheer, "2"))
+
+	report := tracker.report(now, 1)
+	if report.Total != 4 {
+		t.Errorf("expected 4 events in window got %d", report.Total)
+	}
+	if len(report.ByType) != 1 || report.ByType[0] != (TrafficCount{string(SubChannelChatMessage), 3}) {
+		t.Errorf("unexpected top types %v", report.ByType)
+	}
+	if len(report.ByBroadcaster) != 1 || report.ByBroadcaster[0] != (TrafficCount{"1", 3}) {
+		t.Errorf("unexpected top broadcasters %v", report.ByBroadcaster)
+	}
+}
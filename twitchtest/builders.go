@@ -0,0 +1,127 @@
+package twitchtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+type Option[T any] func(event *T)
+
+// NewEvent builds an event of type T from the payload fixture for the subscription
+// type and applies the options in order.
+func NewEvent[T any](subscription twitch.EventSubscription, opts ...Option[T]) T {
+	var event T
+	err := json.Unmarshal(Payload(subscription), &event)
+	if err != nil {
+		panic(fmt.Sprintf("twitchtest: could not decode %s payload into %T: %v", subscription, event, err))
+	}
+
+	for _, opt := range opts {
+		opt(&event)
+	}
+	return event
+}
+
+// NewNotificationFor wraps an event struct in a notification message.
+func NewNotificationFor(subscription twitch.EventSubscription, event any) twitch.NotificationMessage {
+	data, err := json.Marshal(event)
+	if err != nil {
+		panic(fmt.Sprintf("twitchtest: could not marshal %T: %v", event, err))
+	}
+	return NewNotification(subscription, data)
+}
+
+// WithBroadcaster sets the embedded broadcaster fields of any event with a
+// twitch.Broadcaster.
+func WithBroadcaster[T any](id, login, name string) Option[T] {
+	return func(event *T) {
+		setFields(event, map[string]string{
+			"BroadcasterUserId":    id,
+			"BroadcasterUserLogin": login,
+			"BroadcasterUserName":  name,
+		})
+	}
+}
+
+// WithUser sets the embedded user fields of any event with a twitch.User.
+func WithUser[T any](id, login, name string) Option[T] {
+	return func(event *T) {
+		setFields(event, map[string]string{
+			"UserID":    id,
+			"UserLogin": login,
+			"UserName":  name,
+		})
+	}
+}
+
+// WithChatter sets the embedded chatter fields of any event with a twitch.Chatter.
+func WithChatter[T any](id, login, name string) Option[T] {
+	return func(event *T) {
+		setFields(event, map[string]string{
+			"ChatterUserId":    id,
+			"ChatterUserLogin": login,
+			"ChatterUserName":  name,
+		})
+	}
+}
+
+func setFields(event any, values map[string]string) {
+	v := reflect.ValueOf(event).Elem()
+	for name, value := range values {
+		field := v.FieldByName(name)
+		if !field.IsValid() || field.Kind() != reflect.String {
+			panic(fmt.Sprintf("twitchtest: %s has no string field %s", v.Type(), name))
+		}
+		field.SetString(value)
+	}
+}
+
+func WithChatText(text string) Option[twitch.EventChannelChatMessage] {
+	return func(event *twitch.EventChannelChatMessage) {
+		event.Message = twitch.ChatMessage{
+			Text: text,
+			Fragments: []twitch.ChatMessageFragment{
+				{Type: "text", Text: text},
+			},
+		}
+	}
+}
+
+func NewChannelChatMessage(opts ...Option[twitch.EventChannelChatMessage]) twitch.EventChannelChatMessage {
+	return NewEvent(twitch.SubChannelChatMessage, opts...)
+}
+
+func NewChannelChatNotification(opts ...Option[twitch.EventChannelChatNotification]) twitch.EventChannelChatNotification {
+	return NewEvent(twitch.SubChannelChatNotification, opts...)
+}
+
+func NewChannelFollow(opts ...Option[twitch.EventChannelFollow]) twitch.EventChannelFollow {
+	return NewEvent(twitch.SubChannelFollow, opts...)
+}
+
+func NewChannelSubscribe(opts ...Option[twitch.EventChannelSubscribe]) twitch.EventChannelSubscribe {
+	return NewEvent(twitch.SubChannelSubscribe, opts...)
+}
+
+func NewChannelSubscriptionGift(opts ...Option[twitch.EventChannelSubscriptionGift]) twitch.EventChannelSubscriptionGift {
+	return NewEvent(twitch.SubChannelSubscriptionGift, opts...)
+}
+
+func NewChannelCheer(opts ...Option[twitch.EventChannelCheer]) twitch.EventChannelCheer {
+	return NewEvent(twitch.SubChannelCheer, opts...)
+}
+
+func NewChannelRaid(opts ...Option[twitch.EventChannelRaid]) twitch.EventChannelRaid {
+	return NewEvent(twitch.SubChannelRaid, opts...)
+}
+
+func NewChannelPointsCustomRewardRedemptionAdd(opts ...Option[twitch.EventChannelChannelPointsCustomRewardRedemptionAdd]) twitch.EventChannelChannelPointsCustomRewardRedemptionAdd {
+	return NewEvent(twitch.SubChannelChannelPointsCustomRewardRedemptionAdd, opts...)
+}
+
+func NewStreamOnline(opts ...Option[twitch.EventStreamOnline]) twitch.EventStreamOnline {
+	return NewEvent(twitch.SubStreamOnline, opts...)
+}
@@ -0,0 +1,28 @@
+package twitchtest_test
+
+import (
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChannelChatMessage(t *testing.T) {
+	message := twitchtest.NewChannelChatMessage(
+		twitchtest.WithChatText("!hello"),
+		twitchtest.WithBroadcaster[twitch.EventChannelChatMessage]("1", "streamer", "Streamer"),
+		twitchtest.WithChatter[twitch.EventChannelChatMessage]("2", "viewer", "Viewer"),
+	)
+
+	assert.Equal(t, "!hello", message.Message.Text)
+	assert.Equal(t, "1", message.BroadcasterUserId)
+	assert.Equal(t, "viewer", message.ChatterUserLogin)
+	assert.NotEmpty(t, message.MessageId, "defaults should come from the fixture")
+}
+
+func TestWithFieldsPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		twitchtest.NewChannelRaid(twitchtest.WithUser[twitch.EventChannelRaid]("1", "a", "A"))
+	})
+}
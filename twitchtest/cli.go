@@ -0,0 +1,264 @@
+package twitchtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+const (
+	defaultCLIBinary = "twitch"
+	defaultCLIHost   = "127.0.0.1"
+	defaultCLIPort   = 8080
+)
+
+// CLI runs against the Twitch CLI websocket mock server started with
+// `twitch event websocket start-server`.
+type CLI struct {
+	// Binary is the path to the twitch CLI, defaulting to "twitch" on the PATH.
+	Binary string
+	Host   string
+	Port   int
+}
+
+func (c CLI) binary() string {
+	if c.Binary == "" {
+		return defaultCLIBinary
+	}
+	return c.Binary
+}
+
+func (c CLI) address() string {
+	host, port := c.Host, c.Port
+	if host == "" {
+		host = defaultCLIHost
+	}
+	if port == 0 {
+		port = defaultCLIPort
+	}
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+func (c CLI) Available() bool {
+	_, err := exec.LookPath(c.binary())
+	return err == nil
+}
+
+func (c CLI) WebsocketUrl() string {
+	return fmt.Sprintf("ws://%s/ws", c.address())
+}
+
+func (c CLI) SubscriptionUrl() string {
+	return fmt.Sprintf("http://%s/eventsub/subscriptions", c.address())
+}
+
+// NewClient creates a client pointed at the mock server. Validation is relaxed since
+// the mock server can send subscription types this library does not support.
+func (c CLI) NewClient() *twitch.Client {
+	client := twitch.NewClientWithUrl(c.WebsocketUrl())
+	client.SubscriptionAddress = c.SubscriptionUrl()
+	client.SetRelaxedValidation(true)
+	return client
+}
+
+// StartServer starts the websocket mock server and waits for it to accept connections.
+// The server is stopped when ctx is done.
+func (c CLI) StartServer(ctx context.Context) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, c.binary(), "event", "websocket", "start-server", "--port", portOf(c.address()))
+	err := cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("could not start twitch cli websocket server: %w", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp", c.address())
+		if err == nil {
+			conn.Close()
+			return cmd, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	cmd.Process.Kill()
+	return nil, fmt.Errorf("twitch cli websocket server did not start listening on %s", c.address())
+}
+
+// Trigger sends a mock event to the websocket session with `twitch event trigger`.
+// Extra args are passed to the CLI as is, e.g. "--count", "5".
+func (c CLI) Trigger(ctx context.Context, event twitch.EventSubscription, sessionID string, args ...string) error {
+	cliArgs := []string{"event", "trigger", string(event), "--transport=websocket"}
+	if sessionID != "" {
+		cliArgs = append(cliArgs, "--session", sessionID)
+	}
+	cliArgs = append(cliArgs, args...)
+
+	output, err := exec.CommandContext(ctx, c.binary(), cliArgs...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not trigger %s: %w: %s", event, err, string(output))
+	}
+	return nil
+}
+
+func portOf(address string) string {
+	_, port, _ := net.SplitHostPort(address)
+	return port
+}
+
+// MockAPI runs against the Twitch CLI mock of the Helix API started with
+// `twitch mock-api start`, whose clients and users come from `twitch mock-api
+// generate`.
+type MockAPI struct {
+	// Binary is the path to the twitch CLI, defaulting to "twitch" on the PATH.
+	Binary string
+	Host   string
+	// Port defaults to 8080, like the websocket mock server, so running both needs
+	// another port for one of them.
+	Port int
+}
+
+// MockClient is a client application of the mock API.
+type MockClient struct {
+	ID          string `json:"ID"`
+	Secret      string `json:"Secret"`
+	Name        string `json:"Name"`
+	IsExtension bool   `json:"IsExtension"`
+}
+
+// MockUser is a user of the mock API.
+type MockUser struct {
+	ID          string `json:"id"`
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+func (m MockAPI) cli() CLI {
+	return CLI{Binary: m.Binary, Host: m.Host, Port: m.Port}
+}
+
+func (m MockAPI) Available() bool {
+	return m.cli().Available()
+}
+
+// HelixUrl is the URL of the mocked Helix API, to set the HelixURL options of the
+// packages of this module to.
+func (m MockAPI) HelixUrl() string {
+	return fmt.Sprintf("http://%s/mock", m.cli().address())
+}
+
+// Generate creates the mock clients and count users with `twitch mock-api generate`.
+func (m MockAPI) Generate(ctx context.Context, count int) error {
+	output, err := exec.CommandContext(ctx, m.cli().binary(), "mock-api", "generate", "--count", strconv.Itoa(count)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not generate mock api data: %w: %s", err, string(output))
+	}
+	return nil
+}
+
+// StartServer starts the mock API and waits for it to accept connections. The server
+// is stopped when ctx is done.
+func (m MockAPI) StartServer(ctx context.Context) (*exec.Cmd, error) {
+	address := m.cli().address()
+	cmd := exec.CommandContext(ctx, m.cli().binary(), "mock-api", "start", "--port", portOf(address))
+	err := cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("could not start twitch cli mock api: %w", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp", address)
+		if err == nil {
+			conn.Close()
+			return cmd, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	cmd.Process.Kill()
+	return nil, fmt.Errorf("twitch cli mock api did not start listening on %s", address)
+}
+
+// Clients returns the client applications of the mock API.
+func (m MockAPI) Clients(ctx context.Context) ([]MockClient, error) {
+	var response struct {
+		Data []MockClient `json:"data"`
+	}
+	err := m.do(ctx, http.MethodGet, "/units/clients", nil, &response)
+	return response.Data, err
+}
+
+// Users returns the users of the mock API.
+func (m MockAPI) Users(ctx context.Context) ([]MockUser, error) {
+	var response struct {
+		Data []MockUser `json:"data"`
+	}
+	err := m.do(ctx, http.MethodGet, "/units/users", nil, &response)
+	return response.Data, err
+}
+
+// AppAccessToken returns an app access token of the client from the mock
+// authorization server.
+func (m MockAPI) AppAccessToken(ctx context.Context, client MockClient) (string, error) {
+	return m.authorize(ctx, url.Values{
+		"client_id":     {client.ID},
+		"client_secret": {client.Secret},
+		"grant_type":    {"client_credentials"},
+	})
+}
+
+// UserAccessToken returns a user access token with the scopes of the user for the
+// client from the mock authorization server.
+func (m MockAPI) UserAccessToken(ctx context.Context, client MockClient, userID string, scopes ...string) (string, error) {
+	return m.authorize(ctx, url.Values{
+		"client_id":     {client.ID},
+		"client_secret": {client.Secret},
+		"grant_type":    {"user_token"},
+		"user_id":       {userID},
+		"scope":         {strings.Join(scopes, " ")},
+	})
+}
+
+func (m MockAPI) authorize(ctx context.Context, query url.Values) (string, error) {
+	var response struct {
+		AccessToken string `json:"access_token"`
+	}
+	err := m.do(ctx, http.MethodPost, "/auth/authorize", query, &response)
+	if err == nil && response.AccessToken == "" {
+		err = errors.New("mock api returned no access token")
+	}
+	return response.AccessToken, err
+}
+
+func (m MockAPI) do(ctx context.Context, method, path string, query url.Values, v any) error {
+	target := fmt.Sprintf("http://%s%s", m.cli().address(), path)
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not reach mock api: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("mock api %s %s: %s: %s", method, path, response.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
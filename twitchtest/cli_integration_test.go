@@ -0,0 +1,103 @@
+//go:build integration
+
+package twitchtest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+)
+
+func TestCLITrigger(t *testing.T) {
+	cli := twitchtest.CLI{Port: 18080}
+	if !cli.Available() {
+		t.Skip("twitch cli is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := cli.StartServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := cli.NewClient()
+	client.OnError(func(err error) {
+		t.Errorf("client registered an error: %v", err)
+	})
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {
+		_, err := client.Subscribe(ctx, twitch.SubscribeRequest{
+			Event:     twitch.SubChannelFollow,
+			Condition: map[string]string{"broadcaster_user_id": "1234", "moderator_user_id": "1234"},
+		})
+		if err != nil {
+			t.Errorf("could not subscribe: %v", err)
+			return
+		}
+
+		err = cli.Trigger(ctx, twitch.SubChannelFollow, message.Payload.Session.ID)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	client.OnEventChannelFollow(func(event twitch.EventChannelFollow, _ twitch.PayloadContext) {
+		client.Close()
+	})
+
+	err = client.ConnectWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMockAPI(t *testing.T) {
+	mock := twitchtest.MockAPI{Port: 18081}
+	if !mock.Available() {
+		t.Skip("twitch cli is not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := mock.Generate(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mock.StartServer(ctx); err != nil {
+		t.Fatal(err)
+	}
+	clients, err := mock.Clients(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := mock.Users(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) == 0 || len(users) == 0 {
+		t.Fatalf("mock api has %d clients and %d users", len(clients), len(users))
+	}
+	token, err := mock.UserAccessToken(ctx, clients[0], users[0].ID, "user:read:email")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, mock.HelixUrl()+"/users?id="+users[0].ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Client-Id", clients[0].ID)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("mock api answered %s", response.Status)
+	}
+}
@@ -0,0 +1,30 @@
+package twitchtest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClockAfter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := twitchtest.NewFakeClock(start)
+
+	ch := clock.After(time.Minute)
+	clock.Advance(30 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("fired before deadline")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-ch:
+		assert.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("did not fire at deadline")
+	}
+}
@@ -0,0 +1,228 @@
+package twitchtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// Helix is a mock of the Helix API, to point the HelixURL options of the packages of
+// this module at. It records the requests it serves, and answers the EventSub
+// subscription and conduit endpoints like Twitch, keeping the subscriptions created.
+// Other endpoints answer with an empty data list, or the handler set with Handle.
+type Helix struct {
+	URL string
+
+	httpServer *httptest.Server
+
+	mu       sync.Mutex
+	requests []HelixRequest
+	// read is how many requests Requests returned, and waited how many
+	// WaitForRequest looked at.
+	read, waited  int
+	served        chan struct{}
+	handlers      map[string]http.HandlerFunc
+	statuses      map[string][]int
+	subscriptions []twitch.PayloadSubscription
+}
+
+// HelixRequest is a request served by Helix.
+type HelixRequest struct {
+	Method string
+	// URI is the path and query of the request, without the prefix of the URL.
+	URI           string
+	Authorization string
+	ClientID      string
+	Body          []byte
+}
+
+// String returns the method, the URI, and the body of the request, as in "POST
+// /chat/messages {...}".
+func (r HelixRequest) String() string {
+	s := r.Method + " " + r.URI
+	if len(r.Body) > 0 {
+		s += " " + string(r.Body)
+	}
+	return s
+}
+
+// NewHelix starts a mock of the Helix API, closed when the test ends.
+func NewHelix(t testing.TB) *Helix {
+	h := &Helix{
+		served:   make(chan struct{}),
+		handlers: make(map[string]http.HandlerFunc),
+		statuses: make(map[string][]int),
+	}
+	h.httpServer = httptest.NewServer(http.HandlerFunc(h.serve))
+	h.URL = h.httpServer.URL
+	t.Cleanup(h.Close)
+	return h
+}
+
+func (h *Helix) Close() {
+	h.httpServer.CloseClientConnections()
+	h.httpServer.Close()
+}
+
+// Handle serves the requests with the method and path, like "POST" and
+// "/chat/messages", with handler instead of the default answer.
+func (h *Helix) Handle(method, path string, handler http.HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[method+" "+path] = handler
+}
+
+// Respond answers the next requests with the method and path with the statuses, one
+// request each, before serving them again. Error statuses are answered with a Helix
+// error body, and others with an empty data list.
+func (h *Helix) Respond(method, path string, statuses ...int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	route := method + " " + path
+	h.statuses[route] = append(h.statuses[route], statuses...)
+}
+
+// Requests returns the requests served since the last call, as their String.
+func (h *Helix) Requests() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var requests []string
+	for _, request := range h.requests[h.read:] {
+		requests = append(requests, request.String())
+	}
+	h.read = len(h.requests)
+	return requests
+}
+
+// WaitForRequest waits for a request with the method and path not waited for yet, and
+// returns it.
+func (h *Helix) WaitForRequest(ctx context.Context, method, path string) (HelixRequest, error) {
+	for {
+		h.mu.Lock()
+		for i := h.waited; i < len(h.requests); i++ {
+			request := h.requests[i]
+			if request.Method == method && strings.SplitN(request.URI, "?", 2)[0] == path {
+				// Requests before the match stay to wait for with other paths.
+				h.waited = i + 1
+				h.mu.Unlock()
+				return request, nil
+			}
+		}
+		served := h.served
+		h.mu.Unlock()
+
+		select {
+		case <-served:
+		case <-ctx.Done():
+			return HelixRequest{}, fmt.Errorf("no %s %s request: %w", method, path, ctx.Err())
+		}
+	}
+}
+
+// Subscriptions returns the EventSub subscriptions created and not deleted.
+func (h *Helix) Subscriptions() []twitch.PayloadSubscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]twitch.PayloadSubscription(nil), h.subscriptions...)
+}
+
+func (h *Helix) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	request := HelixRequest{
+		Method:        r.Method,
+		URI:           r.URL.RequestURI(),
+		Authorization: r.Header.Get("Authorization"),
+		ClientID:      r.Header.Get("Client-Id"),
+		Body:          bytes.TrimSpace(body),
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	h.mu.Lock()
+	h.requests = append(h.requests, request)
+	close(h.served)
+	h.served = make(chan struct{})
+	route := r.Method + " " + r.URL.Path
+	status := 0
+	if statuses := h.statuses[route]; len(statuses) > 0 {
+		status, h.statuses[route] = statuses[0], statuses[1:]
+	}
+	handler := h.handlers[route]
+	h.mu.Unlock()
+
+	switch {
+	case status >= http.StatusBadRequest:
+		writeHelixError(w, status)
+	case status != 0:
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"data":[]}`)
+	case handler != nil:
+		handler(w, r)
+	case r.URL.Path == "/eventsub/subscriptions":
+		h.serveSubscriptions(w, r)
+	case r.Method == http.MethodPatch && r.URL.Path == "/eventsub/conduits/shards":
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"data":[],"errors":[]}`)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fmt.Fprint(w, `{"data":[]}`)
+	}
+}
+
+func (h *Helix) serveSubscriptions(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		var request twitch.SubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Type == "" {
+			writeHelixError(w, http.StatusBadRequest)
+			return
+		}
+		if request.Version == "" {
+			request.Version = request.Type.Version()
+		}
+		subscription := twitch.PayloadSubscription{
+			SubscriptionRequest: request,
+			ID:                  uuid.NewString(),
+			Status:              "enabled",
+			CreatedAt:           time.Now().UTC(),
+		}
+		h.subscriptions = append(h.subscriptions, subscription)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(twitch.SubscribeResponse{Data: []twitch.PayloadSubscription{subscription}, Total: len(h.subscriptions)})
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(twitch.SubscribeResponse{Data: append([]twitch.PayloadSubscription{}, h.subscriptions...), Total: len(h.subscriptions)})
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		for i, subscription := range h.subscriptions {
+			if subscription.ID == id {
+				h.subscriptions = append(h.subscriptions[:i], h.subscriptions[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeHelixError(w, http.StatusNotFound)
+	default:
+		writeHelixError(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func writeHelixError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q,"status":%d,"message":"mock error"}`, http.StatusText(status), status)
+}
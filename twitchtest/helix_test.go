@@ -0,0 +1,81 @@
+package twitchtest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelix(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	helix := twitchtest.NewHelix(t)
+
+	response, err := twitch.SubscribeEventUrlWithContext(ctx, twitch.SubscribeRequest{
+		ClientID:    "client",
+		AccessToken: "token",
+		Event:       twitch.SubChannelRaid,
+		Condition:   map[string]string{"to_broadcaster_user_id": "1"},
+		ConduitID:   "conduit",
+	}, helix.URL+"/eventsub/subscriptions")
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+	subscriptions := helix.Subscriptions()
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, response.Data[0].ID, subscriptions[0].ID)
+	assert.Equal(t, "conduit", subscriptions[0].Transport.ConduitID)
+	assert.Equal(t, twitch.SubChannelRaid.Version(), subscriptions[0].Version)
+
+	request, err := helix.WaitForRequest(ctx, http.MethodPost, "/eventsub/subscriptions")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", request.Authorization)
+	assert.Equal(t, "client", request.ClientID)
+	require.NoError(t, twitch.UnsubscribeEventUrlWithContext(ctx, twitch.UnsubscribeRequest{ID: response.Data[0].ID}, helix.URL+"/eventsub/subscriptions"))
+	assert.Empty(t, helix.Subscriptions())
+
+	helix.Handle(http.MethodPost, "/chat/messages", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"message_id":"sent","is_sent":true}]}`))
+	})
+	body := post(t, ctx, helix.URL+"/chat/messages", `{"message":"hi"}`, http.StatusOK)
+	assert.Contains(t, body, `"is_sent":true`)
+
+	helix.Respond(http.MethodPost, "/chat/messages", http.StatusTooManyRequests)
+	post(t, ctx, helix.URL+"/chat/messages", "", http.StatusTooManyRequests)
+	post(t, ctx, helix.URL+"/moderation/bans", "", http.StatusOK)
+
+	requests := helix.Requests()
+	require.Len(t, requests, 5)
+	assert.Equal(t, []string{`POST /chat/messages {"message":"hi"}`, "POST /chat/messages", "POST /moderation/bans"}, requests[2:])
+	assert.Empty(t, helix.Requests())
+
+	request, err = helix.WaitForRequest(ctx, http.MethodPost, "/moderation/bans")
+	require.NoError(t, err)
+	assert.Equal(t, "/moderation/bans", request.URI)
+	shortCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = helix.WaitForRequest(shortCtx, http.MethodPost, "/moderation/bans")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func post(t *testing.T, ctx context.Context, url, body string, status int) string {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, status, response.StatusCode)
+	var b strings.Builder
+	_, err = io.Copy(&b, response.Body)
+	require.NoError(t, err)
+	return b.String()
+}
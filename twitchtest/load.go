@@ -0,0 +1,154 @@
+package twitchtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+type LoadConfig struct {
+	// Rate is the number of notifications sent per second. Zero sends as fast as
+	// possible.
+	Rate int
+	// Count stops the run after this many notifications. Either Count or Duration
+	// must be set.
+	Count int
+	// Duration stops the run after this long.
+	Duration time.Duration
+	// Events are sent round-robin. Defaults to channel.chat.message.
+	Events []twitch.EventSubscription
+	// Conn sends notifications over a mock server connection instead of injecting
+	// them into the client.
+	Conn *ServerConn
+	// DrainTimeout is how long to wait for the handlers to return, and over a Conn for
+	// the sent notifications to be received, before taking the report. Defaults to one
+	// second.
+	DrainTimeout time.Duration
+}
+
+type LoadReport struct {
+	Sent    int64
+	Handled int64
+	Errors  int64
+	// Dropped is how many notifications sent over a Conn were not received within the
+	// drain timeout. Injected notifications are received before they are injected, so
+	// it is only counted over a Conn.
+	Dropped int64
+	Elapsed time.Duration
+
+	Throughput       float64
+	Allocs           uint64
+	AllocBytes       uint64
+	AllocsPerMessage float64
+	BytesPerMessage  float64
+}
+
+func (r LoadReport) String() string {
+	return fmt.Sprintf("sent=%d handled=%d errors=%d dropped=%d elapsed=%s throughput=%.0f/s allocs/msg=%.1f bytes/msg=%.0f",
+		r.Sent, r.Handled, r.Errors, r.Dropped, r.Elapsed, r.Throughput, r.AllocsPerMessage, r.BytesPerMessage)
+}
+
+// RunLoad pumps notifications through the client at the configured rate and reports
+// throughput, allocations and, over a Conn, how many notifications were dropped. The
+// report is taken once the handlers of the notifications return. It stops with an error
+// when a notification cannot be sent or injected.
+func RunLoad(ctx context.Context, client *twitch.Client, config LoadConfig) (LoadReport, error) {
+	if config.Count <= 0 && config.Duration <= 0 {
+		return LoadReport{}, fmt.Errorf("load config needs a Count or Duration")
+	}
+	events := config.Events
+	if len(events) == 0 {
+		events = []twitch.EventSubscription{twitch.SubChannelChatMessage}
+	}
+	drainTimeout := config.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = time.Second
+	}
+
+	frames := make([][]byte, len(events))
+	for i, event := range events {
+		if _, ok := LookupPayload(event); !ok {
+			return LoadReport{}, fmt.Errorf("no payload for %s", event)
+		}
+		frames[i] = NotificationJSON(event)
+	}
+
+	if config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Duration)
+		defer cancel()
+	}
+
+	before := client.Stats()
+	var memBefore runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+
+	var report LoadReport
+	start := time.Now()
+	for config.Count <= 0 || report.Sent < int64(config.Count) {
+		if config.Rate > 0 {
+			next := start.Add(time.Duration(report.Sent) * time.Second / time.Duration(config.Rate))
+			if wait := time.Until(next); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+				case <-timer.C:
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		frame := frames[report.Sent%int64(len(frames))]
+		if config.Conn != nil {
+			err := config.Conn.Send(ctx, json.RawMessage(frame))
+			if err != nil {
+				return report, fmt.Errorf("could not send notification: %w", err)
+			}
+		} else {
+			// The client handles injected notifications before returning, so an error
+			// is the client failing, not backpressure.
+			err := client.InjectMessage(frame)
+			if err != nil {
+				return report, fmt.Errorf("could not inject notification: %w", err)
+			}
+		}
+		report.Sent++
+	}
+
+	deadline := time.Now().Add(drainTimeout)
+	var after twitch.Stats
+	for {
+		after = client.Stats()
+		processed := after.Notifications - before.Notifications + after.Errors - before.Errors
+		received := config.Conn == nil || processed >= report.Sent
+		if (received && after.HandlersRunning == 0) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	report.Elapsed = time.Since(start)
+
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+
+	report.Handled = after.Notifications - before.Notifications
+	report.Errors = after.Errors - before.Errors
+	if config.Conn != nil {
+		report.Dropped = report.Sent - report.Handled - report.Errors
+	}
+	report.Throughput = float64(report.Handled) / report.Elapsed.Seconds()
+	report.Allocs = memAfter.Mallocs - memBefore.Mallocs
+	report.AllocBytes = memAfter.TotalAlloc - memBefore.TotalAlloc
+	if report.Sent > 0 {
+		report.AllocsPerMessage = float64(report.Allocs) / float64(report.Sent)
+		report.BytesPerMessage = float64(report.AllocBytes) / float64(report.Sent)
+	}
+	return report, nil
+}
@@ -0,0 +1,70 @@
+package twitchtest_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLoad(t *testing.T) {
+	client := twitch.NewClient()
+
+	report, err := twitchtest.RunLoad(context.Background(), client, twitchtest.LoadConfig{
+		Count:  200,
+		Events: []twitch.EventSubscription{twitch.SubChannelChatMessage, twitch.SubChannelFollow},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 200, report.Sent)
+	assert.EqualValues(t, 200, report.Handled)
+	assert.Zero(t, report.Dropped)
+	assert.NotZero(t, report.AllocsPerMessage)
+	t.Log(report)
+}
+
+func TestRunLoadWaitsForHandlers(t *testing.T) {
+	client := twitch.NewClient()
+	var handled int32
+	client.OnEventChannelFollow(func(twitch.EventChannelFollow, twitch.PayloadContext) {
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+	})
+
+	report, err := twitchtest.RunLoad(context.Background(), client, twitchtest.LoadConfig{
+		Count:  50,
+		Events: []twitch.EventSubscription{twitch.SubChannelFollow},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 50, atomic.LoadInt32(&handled), "the report is taken before the handlers return")
+	assert.Zero(t, client.Stats().HandlersRunning)
+	assert.Zero(t, report.Dropped)
+}
+
+func TestRunLoadOverServer(t *testing.T) {
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	client := twitch.NewClientWithUrl(server.URL)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go client.ConnectWithContext(ctx)
+
+	conn, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+
+	report, err := twitchtest.RunLoad(ctx, client, twitchtest.LoadConfig{
+		Rate:  1000,
+		Count: 50,
+		Conn:  conn,
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 50, report.Handled)
+}
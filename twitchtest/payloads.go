@@ -0,0 +1,132 @@
+package twitchtest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+//go:embed payloads.json
+var payloadData []byte
+
+var payloads map[string]json.RawMessage
+
+func init() {
+	err := json.Unmarshal(payloadData, &payloads)
+	if err != nil {
+		panic(fmt.Sprintf("could not parse payload fixtures: %v", err))
+	}
+}
+
+func payloadKey(event twitch.EventSubscription, variants ...string) string {
+	return strings.Join(append([]string{string(event)}, variants...), "-")
+}
+
+// LookupPayload returns the example event payload for the subscription type. Variants
+// select alternative payloads, e.g. LookupPayload(twitch.SubChannelCheer, "anon").
+func LookupPayload(event twitch.EventSubscription, variants ...string) (json.RawMessage, bool) {
+	payload, ok := payloads[payloadKey(event, variants...)]
+	if !ok {
+		return nil, false
+	}
+	return append(json.RawMessage(nil), payload...), true
+}
+
+// Payload is like LookupPayload but panics if the payload does not exist.
+func Payload(event twitch.EventSubscription, variants ...string) json.RawMessage {
+	payload, ok := LookupPayload(event, variants...)
+	if !ok {
+		panic(fmt.Sprintf("twitchtest: no payload for %s", payloadKey(event, variants...)))
+	}
+	return payload
+}
+
+// Variants returns the payload variants available for the subscription type. The
+// default payload is the empty string.
+func Variants(event twitch.EventSubscription) []string {
+	var variants []string
+	for key := range payloads {
+		if key == string(event) {
+			variants = append(variants, "")
+		} else if variant, ok := cutPrefix(key, string(event)+"-"); ok {
+			variants = append(variants, variant)
+		}
+	}
+	sort.Strings(variants)
+	return variants
+}
+
+// Events returns every subscription type with a payload fixture.
+func Events() []twitch.EventSubscription {
+	seen := make(map[string]bool)
+	var events []twitch.EventSubscription
+	for key := range payloads {
+		event, _, _ := strings.Cut(key, "-")
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, twitch.EventSubscription(event))
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// Notification wraps an event payload fixture in a notification message.
+func Notification(event twitch.EventSubscription, variants ...string) twitch.NotificationMessage {
+	return NewNotification(event, Payload(event, variants...))
+}
+
+// NewNotification wraps an event payload in a notification message.
+func NewNotification(event twitch.EventSubscription, payload json.RawMessage) twitch.NotificationMessage {
+	var message twitch.NotificationMessage
+	message.Metadata = NewMetadata("notification")
+	message.Metadata.SubscriptionType = event
+	message.Metadata.SubscriptionVersion = event.Version()
+	message.Payload.Event = &payload
+	message.Payload.Subscription = twitch.PayloadSubscription{
+		SubscriptionRequest: twitch.SubscriptionRequest{
+			Type:      event,
+			Version:   event.Version(),
+			Condition: map[string]string{},
+			Transport: twitch.SubscriptionTransport{
+				Method: "websocket",
+			},
+		},
+		ID:        uuid.NewString(),
+		Status:    "enabled",
+		Cost:      1,
+		CreatedAt: time.Now(),
+	}
+	return message
+}
+
+// NotificationJSON is like Notification but returns the message as it would be sent
+// over the websocket.
+func NotificationJSON(event twitch.EventSubscription, variants ...string) []byte {
+	data, err := json.Marshal(Notification(event, variants...))
+	if err != nil {
+		panic(fmt.Sprintf("twitchtest: could not marshal notification: %v", err))
+	}
+	return data
+}
+
+func NewMetadata(messageType string) twitch.MessageMetadata {
+	return twitch.MessageMetadata{
+		MessageID:        uuid.NewString(),
+		MessageType:      messageType,
+		MessageTimestamp: time.Now(),
+	}
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,29 @@
+package twitchtest_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayload(t *testing.T) {
+	var cheer twitch.EventChannelCheer
+	err := json.Unmarshal(twitchtest.Payload(twitch.SubChannelCheer, "anon"), &cheer)
+	assert.NoError(t, err)
+	assert.True(t, cheer.IsAnonymous)
+
+	assert.Equal(t, []string{"", "anon"}, twitchtest.Variants(twitch.SubChannelCheer))
+	assert.Contains(t, twitchtest.Events(), twitch.SubChannelChatMessage)
+	assert.Panics(t, func() { twitchtest.Payload(twitch.SubChannelCheer, "missing") })
+}
+
+func TestNotification(t *testing.T) {
+	message := twitchtest.Notification(twitch.SubChannelUpdate)
+	assert.Equal(t, "notification", message.Metadata.MessageType)
+	assert.Equal(t, twitch.SubChannelUpdate, message.Payload.Subscription.Type)
+	assert.Equal(t, "2", message.Payload.Subscription.Version)
+	assert.JSONEq(t, string(twitchtest.Payload(twitch.SubChannelUpdate)), string(*message.Payload.Event))
+}
@@ -0,0 +1,155 @@
+package twitchtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"nhooyr.io/websocket"
+)
+
+// Server is a mock EventSub websocket server. Every connection is sent a welcome
+// message and is then controlled through its ServerConn.
+type Server struct {
+	URL string
+
+	// KeepaliveTimeoutSeconds is sent in the welcome message of new connections.
+	KeepaliveTimeoutSeconds int
+
+	httpServer  *httptest.Server
+	connections chan *ServerConn
+}
+
+type ServerConn struct {
+	Session twitch.PayloadSession
+
+	ws   *websocket.Conn
+	done chan struct{}
+}
+
+func NewServer() *Server {
+	server := &Server{
+		KeepaliveTimeoutSeconds: 10,
+		connections:             make(chan *ServerConn, 16),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", server.handleWebsocket)
+	server.httpServer = httptest.NewServer(mux)
+	server.URL = "ws://" + strings.TrimPrefix(server.httpServer.URL, "http://") + "/ws"
+	return server
+}
+
+func (s *Server) Close() {
+	s.httpServer.CloseClientConnections()
+	s.httpServer.Close()
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	conn := &ServerConn{
+		Session: twitch.PayloadSession{
+			ID:                      strings.ReplaceAll(uuid.NewString(), "-", ""),
+			Status:                  "connected",
+			ConnectedAt:             time.Now(),
+			KeepaliveTimeoutSeconds: s.KeepaliveTimeoutSeconds,
+		},
+		ws:   ws,
+		done: make(chan struct{}),
+	}
+
+	var welcome twitch.WelcomeMessage
+	welcome.Metadata = NewMetadata("session_welcome")
+	welcome.Payload.Session = conn.Session
+	if err := conn.Send(r.Context(), welcome); err != nil {
+		ws.Close(websocket.StatusInternalError, "could not send welcome")
+		return
+	}
+	s.connections <- conn
+
+	for {
+		_, _, err := ws.Read(context.Background())
+		if err != nil {
+			close(conn.done)
+			return
+		}
+	}
+}
+
+// WaitForConnection returns the next connection made to the server after it was
+// sent its welcome message.
+func (s *Server) WaitForConnection(ctx context.Context) (*ServerConn, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no connection to mock server: %w", ctx.Err())
+	case conn := <-s.connections:
+		return conn, nil
+	}
+}
+
+func (c *ServerConn) Send(ctx context.Context, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("could not marshal message: %w", err)
+	}
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+func (c *ServerConn) SendKeepAlive(ctx context.Context) error {
+	return c.Send(ctx, twitch.KeepAliveMessage{Metadata: NewMetadata("session_keepalive")})
+}
+
+func (c *ServerConn) SendNotification(ctx context.Context, event twitch.EventSubscription, variants ...string) error {
+	return c.Send(ctx, Notification(event, variants...))
+}
+
+func (c *ServerConn) SendReconnect(ctx context.Context, reconnectUrl string) error {
+	var message twitch.ReconnectMessage
+	message.Metadata = NewMetadata("session_reconnect")
+	message.Payload.Session = c.Session
+	message.Payload.Session.Status = "reconnecting"
+	message.Payload.Session.ReconnectUrl = reconnectUrl
+	return c.Send(ctx, message)
+}
+
+// Done is closed when the client closes the connection.
+func (c *ServerConn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *ServerConn) Close() error {
+	return c.ws.Close(websocket.StatusNormalClosure, "closing")
+}
+
+// Reconnect runs a full reconnect from the connection to the server: it sends
+// session_reconnect pointing at the server, waits for the client to connect and
+// receive its welcome, then waits for the client to close the old connection.
+func Reconnect(ctx context.Context, from *ServerConn, to *Server) (*ServerConn, error) {
+	err := from.SendReconnect(ctx, to.URL)
+	if err != nil {
+		return nil, fmt.Errorf("could not send reconnect: %w", err)
+	}
+
+	conn, err := to.WaitForConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		from.Close()
+		return nil, fmt.Errorf("client did not close the old connection: %w", ctx.Err())
+	case <-from.Done():
+	}
+	return conn, nil
+}
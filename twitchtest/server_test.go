@@ -0,0 +1,103 @@
+package twitchtest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverA, serverB := twitchtest.NewServer(), twitchtest.NewServer()
+	defer serverA.Close()
+	defer serverB.Close()
+
+	var mu sync.Mutex
+	var lifecycle []twitch.LifecycleEventType
+
+	client := twitch.NewClientWithUrl(serverA.URL)
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {})
+	client.OnLifecycle(func(event twitch.LifecycleEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		lifecycle = append(lifecycle, event.Type)
+	})
+	client.OnKeepAlive(func(message twitch.KeepAliveMessage, _ twitch.MessageMetadata) {
+		client.Close()
+	})
+
+	errs := make(chan error, 1)
+	go func() { errs <- client.ConnectWithContext(ctx) }()
+
+	connA, err := serverA.WaitForConnection(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connB, err := twitchtest.Reconnect(ctx, connA, serverB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, connB.SendKeepAlive(ctx))
+	assert.NoError(t, <-errs)
+
+	assert.Equal(t, serverB.URL, client.Address)
+	assert.Equal(t, connB.Session.ID, client.Session().ID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, lifecycle, twitch.LifecycleReconnectCompleted)
+}
+
+func TestDecodeWorkersKeepOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	const count = 200
+	var viewers []int
+	done := make(chan struct{})
+
+	client := twitch.NewClientWithUrl(server.URL)
+	client.SetDecodeWorkers(4)
+	client.SetSynchronousDispatch(true)
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {})
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, _ twitch.PayloadContext) {
+		viewers = append(viewers, event.Viewers)
+		if len(viewers) == count {
+			close(done)
+		}
+	})
+	go client.ConnectWithContext(ctx)
+
+	conn, err := server.WaitForConnection(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < count; i++ {
+		raid := twitchtest.NewNotificationFor(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: i})
+		assert.NoError(t, conn.Send(ctx, raid))
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+	for i, v := range viewers {
+		if !assert.Equal(t, i, v, "events must be delivered in the order they were read") {
+			break
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package twitchtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+type SoakConfig struct {
+	// Duration is how long the run lasts.
+	Duration time.Duration
+	// Rate is the number of notifications sent per second between bursts. Defaults to
+	// 10.
+	Rate int
+	// Events are sent round-robin. Defaults to channel.chat.message.
+	Events []twitch.EventSubscription
+	// KeepaliveInterval is how often keepalives are sent outside of gaps. Defaults to 5
+	// seconds.
+	KeepaliveInterval time.Duration
+
+	// ReconnectEvery runs a session_reconnect to a new connection at this interval.
+	ReconnectEvery time.Duration
+	// GapEvery silences the connection for Gap at this interval: no notifications and
+	// no keepalives are sent, like a stalled EventSub session.
+	GapEvery time.Duration
+	Gap      time.Duration
+	// BurstEvery sends Burst notifications at once at this interval.
+	BurstEvery time.Duration
+	Burst      int
+	// SampleEvery records the goroutines and heap of the process at this interval.
+	// Defaults to 1 minute.
+	SampleEvery time.Duration
+
+	// NewClient returns the client under test for the URL of the mock server, with
+	// the callbacks of the application. Defaults to a client without callbacks. The
+	// harness sets its OnWelcome and OnLatency callbacks.
+	NewClient func(url string) *twitch.Client
+	// Progress is called with the report so far after every sample.
+	Progress func(report SoakReport)
+}
+
+type SoakSample struct {
+	Elapsed    time.Duration
+	Goroutines int
+	HeapAlloc  uint64
+	Handled    int64
+}
+
+type SoakReport struct {
+	Elapsed time.Duration
+	Sent    int64
+	Handled int64
+	Errors  int64
+	Dropped int64
+
+	Reconnects int
+	Gaps       int
+	Bursts     int
+	// Failures are the scripted steps which failed, like reconnects the client did not
+	// follow, and the connections the client dropped.
+	Failures []string
+
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+
+	Samples []SoakSample
+	// LeakedGoroutines is how many more goroutines the process runs after the client
+	// and the mock server stopped than before they started.
+	LeakedGoroutines int
+	// HeapGrowth is how much the heap grew from the first sample to the last, after
+	// garbage collection.
+	HeapGrowth int64
+}
+
+// Passed reports whether no notification was dropped, no step failed, and no goroutine
+// leaked.
+func (r SoakReport) Passed() bool {
+	return r.Dropped == 0 && len(r.Failures) == 0 && r.LeakedGoroutines <= 0
+}
+
+func (r SoakReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed=%s sent=%d handled=%d errors=%d dropped=%d\n", r.Elapsed.Round(time.Millisecond), r.Sent, r.Handled, r.Errors, r.Dropped)
+	fmt.Fprintf(&b, "reconnects=%d gaps=%d bursts=%d\n", r.Reconnects, r.Gaps, r.Bursts)
+	fmt.Fprintf(&b, "latency p50=%s p99=%s max=%s\n", r.LatencyP50, r.LatencyP99, r.LatencyMax)
+	fmt.Fprintf(&b, "leaked goroutines=%d heap growth=%d bytes\n", r.LeakedGoroutines, r.HeapGrowth)
+	for _, failure := range r.Failures {
+		fmt.Fprintf(&b, "FAIL: %s\n", failure)
+	}
+	return b.String()
+}
+
+// latencies keeps a uniform sample of the latencies of a run, so long runs report
+// percentiles in bounded memory.
+type latencies struct {
+	mu     sync.Mutex
+	seen   int64
+	sample []time.Duration
+	max    time.Duration
+	rand   *rand.Rand
+}
+
+const latencySampleSize = 10000
+
+func (l *latencies) add(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seen++
+	if latency > l.max {
+		l.max = latency
+	}
+	if len(l.sample) < latencySampleSize {
+		l.sample = append(l.sample, latency)
+	} else if i := l.rand.Int63n(l.seen); i < latencySampleSize {
+		l.sample[i] = latency
+	}
+}
+
+func (l *latencies) percentiles() (p50, p99, max time.Duration) {
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.sample...)
+	max = l.max
+	l.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0, 0, max
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2], sorted[len(sorted)*99/100], max
+}
+
+// RunSoak runs a client against a mock server for the duration of the config,
+// following a script of reconnects, keepalive gaps, and bursts, and reports the
+// notifications the client dropped, its latency, and whether it leaked goroutines or
+// memory. It is meant to run for hours before a release. When the context is canceled,
+// the report so far is returned with the error of the context.
+func RunSoak(ctx context.Context, config SoakConfig) (SoakReport, error) {
+	if config.Duration <= 0 {
+		return SoakReport{}, fmt.Errorf("soak config needs a Duration")
+	}
+	if config.Rate <= 0 {
+		config.Rate = 10
+	}
+	events := config.Events
+	if len(events) == 0 {
+		events = []twitch.EventSubscription{twitch.SubChannelChatMessage}
+	}
+	for _, event := range events {
+		if _, ok := LookupPayload(event); !ok {
+			return SoakReport{}, fmt.Errorf("no payload for %s", event)
+		}
+	}
+	if config.KeepaliveInterval <= 0 {
+		config.KeepaliveInterval = 5 * time.Second
+	}
+	if config.SampleEvery <= 0 {
+		config.SampleEvery = time.Minute
+	}
+	newClient := config.NewClient
+	if newClient == nil {
+		newClient = twitch.NewClientWithUrl
+	}
+
+	baseline := runtime.NumGoroutine()
+	var report SoakReport
+
+	server := NewServer()
+	client := newClient(server.URL)
+	latency := &latencies{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	client.OnLatency(func(d time.Duration, _ twitch.PayloadContext) {
+		latency.add(d)
+	})
+	client.OnWelcome(func(twitch.WelcomeMessage, twitch.MessageMetadata) {})
+
+	clientCtx, stopClient := context.WithCancel(ctx)
+	connected := make(chan error, 1)
+	go func() { connected <- client.ConnectWithContext(clientCtx) }()
+
+	stop := func() {
+		stopClient()
+		<-connected
+		server.Close()
+	}
+	conn, err := server.WaitForConnection(ctx)
+	if err != nil {
+		stop()
+		return report, err
+	}
+
+	before := client.Stats()
+	start := time.Now()
+	sample := func() {
+		runtime.GC()
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		stats := client.Stats()
+		report.Samples = append(report.Samples, SoakSample{
+			Elapsed:    time.Since(start),
+			Goroutines: runtime.NumGoroutine(),
+			HeapAlloc:  mem.HeapAlloc,
+			Handled:    stats.Notifications - before.Notifications,
+		})
+		report.Handled = stats.Notifications - before.Notifications
+		report.Errors = stats.Errors - before.Errors
+		report.Elapsed = time.Since(start)
+		report.LatencyP50, report.LatencyP99, report.LatencyMax = latency.percentiles()
+	}
+	sample()
+
+	send := func(ctx context.Context) error {
+		event := events[report.Sent%int64(len(events))]
+		err := conn.Send(ctx, json.RawMessage(NotificationJSON(event)))
+		if err == nil {
+			report.Sent++
+		}
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+	next := func(every time.Duration) time.Time {
+		if every <= 0 {
+			return time.Time{}
+		}
+		return start.Add(every)
+	}
+	due := func(at, now time.Time) bool {
+		return !at.IsZero() && !now.Before(at)
+	}
+	nextReconnect, nextGap, nextBurst := next(config.ReconnectEvery), next(config.GapEvery), next(config.BurstEvery)
+	nextKeepalive, nextSample := start.Add(config.KeepaliveInterval), start.Add(config.SampleEvery)
+	var silentUntil time.Time
+
+	ticker := time.NewTicker(time.Second / time.Duration(config.Rate))
+	defer ticker.Stop()
+loop:
+	for {
+		var now time.Time
+		select {
+		case <-runCtx.Done():
+			break loop
+		case <-conn.Done():
+			report.Failures = append(report.Failures, fmt.Sprintf("client closed the connection after %s", time.Since(start).Round(time.Second)))
+			break loop
+		case now = <-ticker.C:
+		}
+
+		if due(nextSample, now) {
+			sample()
+			nextSample = now.Add(config.SampleEvery)
+			if config.Progress != nil {
+				config.Progress(report)
+			}
+		}
+		if now.Before(silentUntil) {
+			continue
+		}
+		if due(nextGap, now) {
+			report.Gaps++
+			silentUntil = now.Add(config.Gap)
+			nextGap = now.Add(config.GapEvery)
+			continue
+		}
+		if due(nextReconnect, now) {
+			nextReconnect = now.Add(config.ReconnectEvery)
+			reconnectCtx, cancel := context.WithTimeout(runCtx, 10*time.Second)
+			to, err := Reconnect(reconnectCtx, conn, server)
+			cancel()
+			if err != nil {
+				if runCtx.Err() == nil {
+					report.Failures = append(report.Failures, fmt.Sprintf("reconnect after %s: %v", time.Since(start).Round(time.Second), err))
+				}
+				break loop
+			}
+			conn = to
+			report.Reconnects++
+		}
+
+		var err error
+		if due(nextBurst, now) {
+			report.Bursts++
+			nextBurst = now.Add(config.BurstEvery)
+			for i := 0; i < config.Burst && err == nil; i++ {
+				err = send(runCtx)
+			}
+		}
+		if err == nil && due(nextKeepalive, now) {
+			nextKeepalive = now.Add(config.KeepaliveInterval)
+			err = conn.SendKeepAlive(runCtx)
+		}
+		if err == nil {
+			err = send(runCtx)
+		}
+		if err != nil {
+			if runCtx.Err() == nil {
+				report.Failures = append(report.Failures, fmt.Sprintf("send after %s: %v", time.Since(start).Round(time.Second), err))
+			}
+			break loop
+		}
+	}
+
+	// Wait for the notifications in flight and their handlers.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		stats := client.Stats()
+		if stats.Notifications-before.Notifications+stats.Errors-before.Errors >= report.Sent && stats.HandlersRunning == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	sample()
+	report.Dropped = report.Sent - report.Handled - report.Errors
+	first, last := report.Samples[0], report.Samples[len(report.Samples)-1]
+	report.HeapGrowth = int64(last.HeapAlloc) - int64(first.HeapAlloc)
+
+	stop()
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		report.LeakedGoroutines = runtime.NumGoroutine() - baseline
+		if report.LeakedGoroutines <= 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return report, ctx.Err()
+}
@@ -0,0 +1,41 @@
+package twitchtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSoak(t *testing.T) {
+	var progress int
+	report, err := twitchtest.RunSoak(context.Background(), twitchtest.SoakConfig{
+		Duration:          1500 * time.Millisecond,
+		Rate:              100,
+		Events:            []twitch.EventSubscription{twitch.SubChannelChatMessage, twitch.SubChannelFollow},
+		KeepaliveInterval: 100 * time.Millisecond,
+		ReconnectEvery:    400 * time.Millisecond,
+		GapEvery:          500 * time.Millisecond,
+		Gap:               200 * time.Millisecond,
+		BurstEvery:        300 * time.Millisecond,
+		Burst:             50,
+		SampleEvery:       250 * time.Millisecond,
+		Progress:          func(twitchtest.SoakReport) { progress++ },
+	})
+	require.NoError(t, err)
+	t.Log(report)
+
+	assert.True(t, report.Passed(), report.Failures)
+	assert.NotZero(t, report.Sent)
+	assert.Equal(t, report.Sent, report.Handled)
+	assert.NotZero(t, report.Reconnects)
+	assert.NotZero(t, report.Gaps)
+	assert.NotZero(t, report.Bursts)
+	assert.NotZero(t, report.LatencyMax)
+	assert.NotZero(t, progress)
+	assert.Greater(t, len(report.Samples), 2)
+}
@@ -0,0 +1,51 @@
+package twitchtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+// WaitForEvent returns the next event of type T the client decodes, failing the test if
+// none arrives within timeout. It adds an event listener to the client for the duration
+// of the call, leaving its callbacks alone. Only events decoded after the call are
+// returned, so the event has to be sent from another goroutine; otherwise use
+// ExpectEvent.
+func WaitForEvent[T any](t testing.TB, client *twitch.Client, timeout time.Duration) T {
+	t.Helper()
+	events, remove := expectEvent[T](client)
+	defer remove()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(timeout):
+		var zero T
+		t.Fatalf("timed out after %s waiting for %T", timeout, zero)
+		return zero
+	}
+}
+
+// ExpectEvent adds an event listener to the client and returns a channel receiving the
+// events of type T it decodes, until the test ends. Use it instead of WaitForEvent when
+// the event is sent from the same goroutine that waits for it, or register it before
+// connecting to not miss early events.
+func ExpectEvent[T any](t testing.TB, client *twitch.Client) <-chan T {
+	events, remove := expectEvent[T](client)
+	t.Cleanup(remove)
+	return events
+}
+
+func expectEvent[T any](client *twitch.Client) (<-chan T, func()) {
+	events := make(chan T, 16)
+	remove := client.AddEventListener(func(event any, _ twitch.PayloadContext) {
+		if event, ok := event.(T); ok {
+			select {
+			case events <- event:
+			default:
+			}
+		}
+	})
+	return events, remove
+}
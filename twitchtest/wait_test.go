@@ -0,0 +1,61 @@
+package twitchtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForEvent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := twitchtest.NewServer()
+	defer server.Close()
+
+	client := twitch.NewClientWithUrl(server.URL)
+	client.OnWelcome(func(message twitch.WelcomeMessage, _ twitch.MessageMetadata) {})
+	follows := make(chan twitch.EventChannelFollow, 1)
+	client.OnEvent(func(event any, _ twitch.PayloadContext) {
+		if follow, ok := event.(twitch.EventChannelFollow); ok {
+			follows <- follow
+		}
+	})
+	go client.ConnectWithContext(ctx)
+
+	conn, err := server.WaitForConnection(ctx)
+	require.NoError(t, err)
+
+	go func() {
+		conn.SendNotification(ctx, twitch.SubChannelFollow)
+		conn.SendNotification(ctx, twitch.SubChannelRaid)
+	}()
+
+	raid := twitchtest.WaitForEvent[twitch.EventChannelRaid](t, client, time.Second)
+	assert.NotZero(t, raid.Viewers)
+
+	select {
+	case <-follows:
+	case <-ctx.Done():
+		t.Fatal("the OnEvent callback of the client was not called")
+	}
+}
+
+func TestExpectEvent(t *testing.T) {
+	client := twitch.NewClient()
+	events := twitchtest.ExpectEvent[twitch.EventChannelCheer](t, client)
+
+	require.NoError(t, client.InjectMessage(twitchtest.NotificationJSON(twitch.SubChannelCheer, "anon")))
+
+	select {
+	case cheer := <-events:
+		assert.True(t, cheer.IsAnonymous)
+	case <-time.After(time.Second):
+		t.Fatal("no cheer received")
+	}
+}
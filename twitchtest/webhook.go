@@ -0,0 +1,224 @@
+package twitchtest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+)
+
+const (
+	WebhookNotificationType = "notification"
+	WebhookVerificationType = "webhook_callback_verification"
+	WebhookRevocationType   = "revocation"
+)
+
+// WebhookMessage is an EventSub webhook callback as Twitch would send it.
+type WebhookMessage struct {
+	ID           string
+	Type         string
+	Timestamp    time.Time
+	Retry        int
+	Subscription twitch.PayloadSubscription
+	Body         []byte
+}
+
+// WebhookNotification builds a notification callback from the event payload fixture.
+func WebhookNotification(event twitch.EventSubscription, variants ...string) WebhookMessage {
+	subscription := webhookSubscription(event, "enabled")
+	return newWebhookMessage(WebhookNotificationType, subscription, map[string]any{
+		"subscription": subscription,
+		"event":        Payload(event, variants...),
+	})
+}
+
+func WebhookChallenge(event twitch.EventSubscription, challenge string) WebhookMessage {
+	subscription := webhookSubscription(event, "webhook_callback_verification_pending")
+	return newWebhookMessage(WebhookVerificationType, subscription, map[string]any{
+		"challenge":    challenge,
+		"subscription": subscription,
+	})
+}
+
+func WebhookRevocation(event twitch.EventSubscription, status string) WebhookMessage {
+	subscription := webhookSubscription(event, status)
+	return newWebhookMessage(WebhookRevocationType, subscription, map[string]any{
+		"subscription": subscription,
+	})
+}
+
+func webhookSubscription(event twitch.EventSubscription, status string) twitch.PayloadSubscription {
+	return twitch.PayloadSubscription{
+		SubscriptionRequest: twitch.SubscriptionRequest{
+			Type:      event,
+			Version:   event.Version(),
+			Condition: map[string]string{},
+			Transport: twitch.SubscriptionTransport{
+				Method: "webhook",
+			},
+		},
+		ID:        uuid.NewString(),
+		Status:    status,
+		Cost:      1,
+		CreatedAt: time.Now(),
+	}
+}
+
+func newWebhookMessage(messageType string, subscription twitch.PayloadSubscription, body any) WebhookMessage {
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("twitchtest: could not marshal webhook body: %v", err))
+	}
+	return WebhookMessage{
+		ID:           uuid.NewString(),
+		Type:         messageType,
+		Timestamp:    time.Now(),
+		Subscription: subscription,
+		Body:         data,
+	}
+}
+
+// Stale returns a copy of the message sent age ago, as seen in a replay attack.
+func (m WebhookMessage) Stale(age time.Duration) WebhookMessage {
+	m.Timestamp = m.Timestamp.Add(-age)
+	return m
+}
+
+// Request builds the signed callback request.
+func (m WebhookMessage) Request(target, secret string) *http.Request {
+	return m.RequestWithSignature(target, SignWebhook(secret, m.ID, m.Timestamp, m.Body))
+}
+
+// RequestWithSignature is like Request but sends the given signature header as is.
+func (m WebhookMessage) RequestWithSignature(target, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(m.Body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(twitch.WebhookMessageIDHeader, m.ID)
+	req.Header.Set(twitch.WebhookMessageRetryHeader, fmt.Sprint(m.Retry))
+	req.Header.Set(twitch.WebhookMessageTypeHeader, m.Type)
+	req.Header.Set(twitch.WebhookMessageSignatureHeader, signature)
+	req.Header.Set(twitch.WebhookMessageTimestampHeader, m.Timestamp.UTC().Format(time.RFC3339Nano))
+	req.Header.Set(twitch.WebhookSubscriptionTypeHeader, string(m.Subscription.Type))
+	req.Header.Set(twitch.WebhookSubscriptionVersionHeader, m.Subscription.Version)
+	return req
+}
+
+// SignWebhook returns the Twitch-Eventsub-Message-Signature header value for a callback.
+func SignWebhook(secret, messageID string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(messageID))
+	mac.Write([]byte(timestamp.UTC().Format(time.RFC3339Nano)))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookHarness drives a webhook handler with signed callbacks.
+type WebhookHarness struct {
+	Handler http.Handler
+	Secret  string
+	// Path is the callback path requests are sent to. Defaults to "/".
+	Path string
+}
+
+func (h WebhookHarness) target() string {
+	if h.Path == "" {
+		return "/"
+	}
+	return h.Path
+}
+
+func (h WebhookHarness) Send(m WebhookMessage) *httptest.ResponseRecorder {
+	return h.serve(m.Request(h.target(), h.Secret))
+}
+
+func (h WebhookHarness) SendWithSignature(m WebhookMessage, signature string) *httptest.ResponseRecorder {
+	return h.serve(m.RequestWithSignature(h.target(), signature))
+}
+
+func (h WebhookHarness) serve(req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	h.Handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// Run checks the handler against the cases every webhook handler must get right:
+// challenges are answered, notifications are accepted, and unsigned, wrongly signed
+// and stale callbacks are rejected. If processed is not nil it must return how many
+// notifications the handler has acted on, and redelivered message IDs are checked to
+// be acknowledged without being processed again.
+func (h WebhookHarness) Run(t *testing.T, processed func() int) {
+	t.Run("challenge", func(t *testing.T) {
+		res := h.Send(WebhookChallenge(twitch.SubChannelFollow, "pogchamp-kappa-360noscope-vohiyo"))
+		if res.Code != http.StatusOK || res.Body.String() != "pogchamp-kappa-360noscope-vohiyo" {
+			t.Errorf("challenge: got %d %q", res.Code, res.Body.String())
+		}
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		if res := h.Send(WebhookNotification(twitch.SubChannelFollow)); !success(res.Code) {
+			t.Errorf("notification: got %d", res.Code)
+		}
+	})
+
+	t.Run("revocation", func(t *testing.T) {
+		if res := h.Send(WebhookRevocation(twitch.SubChannelFollow, "authorization_revoked")); !success(res.Code) {
+			t.Errorf("revocation: got %d", res.Code)
+		}
+	})
+
+	rejected := map[string]func() *httptest.ResponseRecorder{
+		"missing signature": func() *httptest.ResponseRecorder {
+			return h.SendWithSignature(WebhookNotification(twitch.SubChannelFollow), "")
+		},
+		"bad signature": func() *httptest.ResponseRecorder {
+			message := WebhookNotification(twitch.SubChannelFollow)
+			return h.SendWithSignature(message, SignWebhook(h.Secret+"wrong", message.ID, message.Timestamp, message.Body))
+		},
+		"tampered body": func() *httptest.ResponseRecorder {
+			message := WebhookNotification(twitch.SubChannelFollow)
+			signature := SignWebhook(h.Secret, message.ID, message.Timestamp, message.Body)
+			message.Body = Payload(twitch.SubChannelFollow)
+			return h.SendWithSignature(message, signature)
+		},
+		"stale timestamp": func() *httptest.ResponseRecorder {
+			return h.Send(WebhookNotification(twitch.SubChannelFollow).Stale(11 * time.Minute))
+		},
+	}
+	for name, send := range rejected {
+		send := send
+		t.Run(name, func(t *testing.T) {
+			if res := send(); res.Code < 400 || res.Code >= 500 {
+				t.Errorf("%s: expected a 4xx response, got %d", name, res.Code)
+			}
+		})
+	}
+
+	if processed != nil {
+		t.Run("redelivery", func(t *testing.T) {
+			message := WebhookNotification(twitch.SubChannelFollow)
+			h.Send(message)
+			before := processed()
+
+			message.Retry = 1
+			if res := h.Send(message); !success(res.Code) {
+				t.Errorf("redelivery: got %d", res.Code)
+			}
+			if processed() != before {
+				t.Error("redelivered message was processed twice")
+			}
+		})
+	}
+}
+
+func success(code int) bool {
+	return code >= 200 && code < 300
+}
@@ -0,0 +1,66 @@
+package twitchtest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/isabelcoolaf/go-twitch-eventsub/twitchtest"
+)
+
+type exampleWebhookHandler struct {
+	secret string
+	guard  *twitch.WebhookReplayGuard
+
+	mu        sync.Mutex
+	processed int
+}
+
+func (h *exampleWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if err := twitch.VerifyEventSubSignature(r.Header, body, h.secret); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	err := h.guard.Check(r.Header, time.Now())
+	if errors.Is(err, twitch.ErrWebhookDuplicate) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Header.Get(twitch.WebhookMessageTypeHeader) {
+	case twitchtest.WebhookVerificationType:
+		var challenge struct {
+			Challenge string `json:"challenge"`
+		}
+		json.Unmarshal(body, &challenge)
+		w.Write([]byte(challenge.Challenge))
+	case twitchtest.WebhookNotificationType:
+		h.mu.Lock()
+		h.processed++
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestWebhookHarness(t *testing.T) {
+	handler := &exampleWebhookHandler{secret: "s3cre7", guard: twitch.NewWebhookReplayGuard(0)}
+	harness := twitchtest.WebhookHarness{Handler: handler, Secret: "s3cre7"}
+
+	harness.Run(t, func() int {
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		return handler.processed
+	})
+}
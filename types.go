@@ -8,12 +8,34 @@ import (
 type PayloadContext struct {
 	Metadata     MessageMetadata
 	Subscription PayloadSubscription
+
+	// CorrelationID identifies the notification across logs, metrics, and errors.
+	CorrelationID string
+}
+
+// RaidDirection returns the direction of channel.raid notifications from the condition
+// of the subscription: raids to the broadcaster of a to_broadcaster_user_id condition
+// are incoming and raids from the broadcaster of a from_broadcaster_user_id condition
+// are outgoing.
+func (p PayloadContext) RaidDirection() RaidDirection {
+	if p.Subscription.Type != SubChannelRaid {
+		return RaidUnknown
+	}
+	if p.Subscription.Condition["to_broadcaster_user_id"] != "" {
+		return RaidIncoming
+	}
+	if p.Subscription.Condition["from_broadcaster_user_id"] != "" {
+		return RaidOutgoing
+	}
+	return RaidUnknown
 }
 
 type MessageMetadata struct {
-	MessageID        string    `json:"message_id"`
-	MessageType      string    `json:"message_type"`
-	MessageTimestamp time.Time `json:"message_timestamp"`
+	MessageID           string            `json:"message_id"`
+	MessageType         string            `json:"message_type"`
+	MessageTimestamp    time.Time         `json:"message_timestamp"`
+	SubscriptionType    EventSubscription `json:"subscription_type,omitempty"`
+	SubscriptionVersion string            `json:"subscription_version,omitempty"`
 }
 
 type PayloadSession struct {
@@ -0,0 +1,81 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+)
+
+// ResolvedUnbanRequest links an unban request to its resolution.
+type ResolvedUnbanRequest struct {
+	Request    EventChannelUnbanRequestCreate
+	Resolution EventChannelUnbanRequestResolve
+}
+
+func (r ResolvedUnbanRequest) IsApproved() bool {
+	return r.Resolution.Status == UnbanRequestStatusApproved
+}
+
+// UnbanRequestQueue keeps the pending unban requests of channel.unban_request.create
+// events until a channel.unban_request.resolve event with the same request ID resolves
+// them. It is safe to use from concurrent callbacks.
+type UnbanRequestQueue struct {
+	mu      sync.Mutex
+	pending map[string]EventChannelUnbanRequestCreate
+}
+
+func NewUnbanRequestQueue() *UnbanRequestQueue {
+	return &UnbanRequestQueue{pending: make(map[string]EventChannelUnbanRequestCreate)}
+}
+
+// Add queues the request until it is resolved.
+func (q *UnbanRequestQueue) Add(event EventChannelUnbanRequestCreate) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending[event.Id] = event
+}
+
+// Resolve removes the request the event resolves from the queue and returns both. The
+// bool is false if the request was never added, in which case only the resolution is set.
+func (q *UnbanRequestQueue) Resolve(event EventChannelUnbanRequestResolve) (ResolvedUnbanRequest, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, ok := q.pending[event.Id]
+	delete(q.pending, event.Id)
+	return ResolvedUnbanRequest{Request: request, Resolution: event}, ok
+}
+
+// Get returns the pending request with the ID.
+func (q *UnbanRequestQueue) Get(id string) (EventChannelUnbanRequestCreate, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, ok := q.pending[id]
+	return request, ok
+}
+
+// Pending returns the requests waiting for a resolution, oldest first.
+func (q *UnbanRequestQueue) Pending() []EventChannelUnbanRequestCreate {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests := make([]EventChannelUnbanRequestCreate, 0, len(q.pending))
+	for _, request := range q.pending {
+		requests = append(requests, request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].CreatedAt.Equal(requests[j].CreatedAt) {
+			return requests[i].Id < requests[j].Id
+		}
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests
+}
+
+func (q *UnbanRequestQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
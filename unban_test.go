@@ -0,0 +1,37 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnbanRequestQueue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	queue := NewUnbanRequestQueue()
+	queue.Add(EventChannelUnbanRequestCreate{Id: "second", CreatedAt: now.Add(time.Minute)})
+	queue.Add(EventChannelUnbanRequestCreate{Id: "first", CreatedAt: now, Text: "sorry"})
+
+	pending := queue.Pending()
+	if len(pending) != 2 || pending[0].Id != "first" || pending[1].Id != "second" {
+		t.Fatalf("expected first and second pending got %v", pending)
+	}
+
+	resolved, ok := queue.Resolve(EventChannelUnbanRequestResolve{Id: "first", Status: UnbanRequestStatusApproved})
+	if !ok {
+		t.Fatal("expected the first request to be linked")
+	}
+	if resolved.Request.Text != "sorry" || !resolved.IsApproved() {
+		t.Errorf("unexpected resolved request %v", resolved)
+	}
+	if _, ok := queue.Get("first"); ok {
+		t.Error("expected the first request to be removed")
+	}
+	if queue.Len() != 1 {
+		t.Errorf("expected 1 pending request got %d", queue.Len())
+	}
+
+	resolved, ok = queue.Resolve(EventChannelUnbanRequestResolve{Id: "unknown", Status: UnbanRequestStatusDenied})
+	if ok || resolved.Resolution.Id != "unknown" {
+		t.Errorf("expected an unlinked resolution got %v %v", resolved, ok)
+	}
+}
@@ -0,0 +1,9 @@
+package twitch
+
+// SetRelaxedValidation ignores unknown message and subscription types instead of
+// reporting them as errors. Unknown subscriptions are still passed to OnRawEvent.
+// This is useful against mock servers such as the Twitch CLI which may send types
+// this library does not know about.
+func (c *Client) SetRelaxedValidation(relaxed bool) {
+	c.relaxedValidation = relaxed
+}
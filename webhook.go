@@ -0,0 +1,144 @@
+package twitch
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of EventSub webhook callbacks.
+const (
+	WebhookMessageIDHeader           = "Twitch-Eventsub-Message-Id"
+	WebhookMessageRetryHeader        = "Twitch-Eventsub-Message-Retry"
+	WebhookMessageTypeHeader         = "Twitch-Eventsub-Message-Type"
+	WebhookMessageSignatureHeader    = "Twitch-Eventsub-Message-Signature"
+	WebhookMessageTimestampHeader    = "Twitch-Eventsub-Message-Timestamp"
+	WebhookSubscriptionTypeHeader    = "Twitch-Eventsub-Subscription-Type"
+	WebhookSubscriptionVersionHeader = "Twitch-Eventsub-Subscription-Version"
+)
+
+// WebhookMaxMessageAge is how old Twitch recommends accepting callbacks, older ones may
+// be replayed.
+const WebhookMaxMessageAge = 10 * time.Minute
+
+// WebhookMaxClockSkew is how far in the future callbacks are accepted, for clocks
+// running behind the clock of Twitch.
+const WebhookMaxClockSkew = time.Minute
+
+var (
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	ErrWebhookStale     = errors.New("stale webhook message")
+	ErrWebhookFuture    = errors.New("webhook message from the future")
+	ErrWebhookDuplicate = errors.New("duplicate webhook message")
+)
+
+// VerifyEventSubSignature checks the signature of a webhook callback against the secret
+// given when subscribing. body must be the raw request body.
+func VerifyEventSubSignature(headers http.Header, body []byte, secret string) error {
+	signature := headers.Get(WebhookMessageSignatureHeader)
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("%w: missing sha256 signature", ErrWebhookSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(headers.Get(WebhookMessageIDHeader)))
+	mac.Write([]byte(headers.Get(WebhookMessageTimestampHeader)))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// WebhookTimestamp returns when the callback was sent.
+func WebhookTimestamp(headers http.Header) (time.Time, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, headers.Get(WebhookMessageTimestampHeader))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse webhook timestamp: %w", err)
+	}
+	return timestamp, nil
+}
+
+// WebhookReplayGuard rejects callbacks older than its max age, callbacks sent more than
+// WebhookMaxClockSkew in the future, and callbacks with a message ID it already
+// accepted, which Twitch sends again when it did not get a response in time. It
+// remembers message IDs for the max age. It is safe to use from concurrent requests.
+type WebhookReplayGuard struct {
+	mu     sync.Mutex
+	maxAge time.Duration
+	// seen is when the accepted message IDs expire, and expiries the same in the order
+	// they were accepted, so expired IDs are forgotten from the front.
+	seen     map[string]time.Time
+	expiries []seenMessage
+}
+
+type seenMessage struct {
+	id        string
+	expiresAt time.Time
+}
+
+// NewWebhookReplayGuard returns a guard accepting callbacks up to maxAge old, or
+// WebhookMaxMessageAge if it is not positive.
+func NewWebhookReplayGuard(maxAge time.Duration) *WebhookReplayGuard {
+	if maxAge <= 0 {
+		maxAge = WebhookMaxMessageAge
+	}
+	return &WebhookReplayGuard{
+		maxAge: maxAge,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Check returns ErrWebhookStale, ErrWebhookFuture, or ErrWebhookDuplicate for callbacks
+// which should not be processed, and remembers the message ID of the rest. Duplicates
+// should still be acknowledged with a 2xx response so Twitch stops retrying them.
+func (g *WebhookReplayGuard) Check(headers http.Header, now time.Time) error {
+	timestamp, err := WebhookTimestamp(headers)
+	if err != nil {
+		return err
+	}
+	if now.Sub(timestamp) > g.maxAge {
+		return fmt.Errorf("%w: sent at %s", ErrWebhookStale, timestamp.Format(time.RFC3339))
+	}
+	if timestamp.Sub(now) > WebhookMaxClockSkew {
+		return fmt.Errorf("%w: sent at %s", ErrWebhookFuture, timestamp.Format(time.RFC3339))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.expireLocked(now)
+	id := headers.Get(WebhookMessageIDHeader)
+	if _, ok := g.seen[id]; ok {
+		return ErrWebhookDuplicate
+	}
+	// A replay of the message is stale once its timestamp is older than the max age,
+	// which is at most the max age and the clock skew from now.
+	expiresAt := now.Add(g.maxAge + WebhookMaxClockSkew)
+	g.seen[id] = expiresAt
+	g.expiries = append(g.expiries, seenMessage{id: id, expiresAt: expiresAt})
+	return nil
+}
+
+// expireLocked forgets the message IDs expired at now. The caller holds g.mu.
+func (g *WebhookReplayGuard) expireLocked(now time.Time) {
+	n := 0
+	for n < len(g.expiries) && !now.Before(g.expiries[n].expiresAt) {
+		expired := g.expiries[n]
+		// The ID was accepted again after expiring if its expiry changed.
+		if g.seen[expired.id].Equal(expired.expiresAt) {
+			delete(g.seen, expired.id)
+		}
+		n++
+	}
+	// Appending reallocates the queue once it reaches the end of its array, dropping
+	// the expired front.
+	g.expiries = g.expiries[n:]
+}
@@ -0,0 +1,56 @@
+package twitch_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/isabelcoolaf/go-twitch-eventsub"
+	"github.com/stretchr/testify/assert"
+)
+
+func webhookHeaders(id string, timestamp time.Time) http.Header {
+	headers := http.Header{}
+	headers.Set(twitch.WebhookMessageIDHeader, id)
+	headers.Set(twitch.WebhookMessageTimestampHeader, timestamp.Format(time.RFC3339Nano))
+	return headers
+}
+
+func TestWebhookReplayGuard(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := twitch.NewWebhookReplayGuard(10 * time.Minute)
+
+	assert.NoError(t, guard.Check(webhookHeaders("a", now), now))
+	assert.ErrorIs(t, guard.Check(webhookHeaders("a", now), now.Add(time.Second)), twitch.ErrWebhookDuplicate)
+	assert.NoError(t, guard.Check(webhookHeaders("b", now.Add(-9*time.Minute)), now))
+
+	assert.ErrorIs(t, guard.Check(webhookHeaders("c", now.Add(-11*time.Minute)), now), twitch.ErrWebhookStale)
+	assert.NoError(t, guard.Check(webhookHeaders("d", now.Add(30*time.Second)), now))
+	assert.ErrorIs(t, guard.Check(webhookHeaders("e", now.Add(2*time.Minute)), now), twitch.ErrWebhookFuture)
+
+	assert.Error(t, guard.Check(http.Header{}, now))
+}
+
+func TestWebhookReplayGuardExpires(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := twitch.NewWebhookReplayGuard(time.Minute)
+
+	assert.NoError(t, guard.Check(webhookHeaders("a", now), now))
+	later := now.Add(30 * time.Second)
+	assert.NoError(t, guard.Check(webhookHeaders("b", later), later))
+
+	// A replay of a is stale before the guard forgets it.
+	replay := now.Add(time.Minute + time.Second)
+	assert.ErrorIs(t, guard.Check(webhookHeaders("a", now), replay), twitch.ErrWebhookStale)
+	assert.ErrorIs(t, guard.Check(webhookHeaders("b", later), replay), twitch.ErrWebhookDuplicate)
+
+	// A new message reusing the ID of a forgotten one is accepted.
+	forgotten := now.Add(2*time.Minute + time.Second)
+	assert.NoError(t, guard.Check(webhookHeaders("a", forgotten), forgotten))
+	assert.ErrorIs(t, guard.Check(webhookHeaders("a", forgotten), forgotten), twitch.ErrWebhookDuplicate)
+	assert.ErrorIs(t, guard.Check(webhookHeaders("b", later), forgotten), twitch.ErrWebhookStale)
+}